@@ -0,0 +1,262 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/cli"
+	mempl "github.com/tendermint/tendermint/mempool"
+	"github.com/tendermint/tendermint/p2p"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+var (
+	blocks int64
+
+	flagBlocks = "blocks"
+)
+
+var sidecarCmd = &cobra.Command{
+	Use:   "sidecar",
+	Short: "Print the status of a running node's sidecar",
+	Long: `Print the status of a running node's sidecar in human-readable form, for
+on-call debugging: whether its relay and sidecar peers are connected, whether
+the MEV pipeline stalled, the bundles pending in the sidecar, and the outcome of
+the auctions of the last blocks.
+
+The relay and sidecar peers are read from the [sidecar] section of the config
+file under --home. Pending bundles are read from the node's debug server, and
+only shown if --pprof-laddr is set.`,
+	Args: cobra.NoArgs,
+	RunE: sidecarCmdHandler,
+}
+
+func init() {
+	sidecarCmd.Flags().StringVar(
+		&profAddr,
+		flagProfAddr,
+		"",
+		"the profiling server address (<host>:<port>)",
+	)
+
+	sidecarCmd.Flags().Int64Var(
+		&blocks,
+		flagBlocks,
+		10,
+		"the number of blocks to show the auctions of",
+	)
+}
+
+func sidecarCmdHandler(cmd *cobra.Command, _ []string) error {
+	if blocks <= 0 {
+		return errors.New("blocks must be positive")
+	}
+
+	rpc, err := rpchttp.New(nodeRPCAddr, "/websocket")
+	if err != nil {
+		return fmt.Errorf("failed to create new http client: %w", err)
+	}
+	ctx := context.Background()
+	status, err := rpc.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get node status: %w", err)
+	}
+	netInfo, err := rpc.NetInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get node network information: %w", err)
+	}
+
+	w := cmd.OutOrStdout()
+	fmt.Fprintf(w, "Node %s (%s) at height %d", status.NodeInfo.ID(), status.NodeInfo.Moniker,
+		status.SyncInfo.LatestBlockHeight)
+	if status.SyncInfo.CatchingUp {
+		fmt.Fprint(w, ", catching up")
+	}
+	if status.NodeInfo.Other.MEVDisabled {
+		fmt.Fprint(w, ", opted out of MEV auctions")
+	}
+	fmt.Fprintln(w)
+	if _, err := rpc.Health(ctx); err != nil {
+		fmt.Fprintf(w, "Health: %v\n", err)
+	} else {
+		fmt.Fprintln(w, "Health: ok")
+	}
+
+	fmt.Fprintln(w)
+	home := viper.GetString(cli.HomeFlag)
+	if sidecarConfig, err := cfg.LoadSidecarConfig(home, status.NodeInfo.Network); err != nil {
+		fmt.Fprintf(w, "Relay and sidecar peers unknown: %v\n", err)
+	} else {
+		printSidecarPeers(w, sidecarConfig, netInfo)
+	}
+
+	fmt.Fprintln(w)
+	if profAddr == "" {
+		fmt.Fprintf(w, "Pending bundles unknown: set --%s to read them from the debug server\n", flagProfAddr)
+	} else if dump, err := getSidecarDump(profAddr); err != nil {
+		fmt.Fprintf(w, "Pending bundles unknown: %v\n", err)
+	} else {
+		printPendingBundles(w, dump)
+	}
+
+	fmt.Fprintln(w)
+	printAuctions(ctx, w, rpc, status.SyncInfo.LatestBlockHeight)
+	return nil
+}
+
+// printSidecarPeers prints whether the relay and personal peers configured
+// are connected.
+func printSidecarPeers(w io.Writer, config *cfg.SidecarConfig, netInfo *ctypes.ResultNetInfo) {
+	connected := make(map[p2p.ID]ctypes.Peer, len(netInfo.Peers))
+	for _, peer := range netInfo.Peers {
+		connected[peer.NodeInfo.ID()] = peer
+	}
+	peerStatus := func(id string) string {
+		peer, ok := connected[p2p.ID(id)]
+		if !ok {
+			return "not connected"
+		}
+		s := fmt.Sprintf("connected to %s for %s", peer.RemoteIP,
+			peer.ConnectionStatus.Duration.Round(time.Second))
+		if peer.NodeInfo.Other.MEVDisabled {
+			s += ", opted out of MEV auctions"
+		}
+		return s
+	}
+
+	if relayerID := config.RelayPeerID(); relayerID == "" {
+		fmt.Fprintln(w, "Relay: not configured")
+	} else {
+		fmt.Fprintf(w, "Relay %s: %s\n", relayerID, peerStatus(relayerID))
+	}
+	var personalPeers []string
+	for _, id := range strings.Split(config.PersonalPeerIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			personalPeers = append(personalPeers, id)
+		}
+	}
+	if len(personalPeers) == 0 {
+		fmt.Fprintln(w, "Sidecar peers: none configured")
+		return
+	}
+	fmt.Fprintln(w, "Sidecar peers:")
+	for _, id := range personalPeers {
+		fmt.Fprintf(w, "  %s: %s\n", id, peerStatus(id))
+	}
+}
+
+// getSidecarDump gets the dump of the sidecar's internals from the node's
+// debug server at addr.
+func getSidecarDump(addr string) (*mempl.SidecarDump, error) {
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	resp, err := http.Get(addr + "/debug/sidecar") // nolint: gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to query for the sidecar dump: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query for the sidecar dump: %s", resp.Status)
+	}
+
+	dump := new(mempl.SidecarDump)
+	if err := json.NewDecoder(resp.Body).Decode(dump); err != nil {
+		return nil, fmt.Errorf("failed to decode the sidecar dump: %w", err)
+	}
+	return dump, nil
+}
+
+// printPendingBundles prints the bundles held by the sidecar, and how far
+// they were reassembled.
+func printPendingBundles(w io.Writer, dump *mempl.SidecarDump) {
+	fmt.Fprintf(w, "Sidecar: %d txs (%d bytes) in %d bundles, auction for height %d",
+		dump.NumTxs, dump.TxsBytes, len(dump.Bundles), dump.HeightForFiringAuction)
+	if !dump.AuctionDeadline.IsZero() {
+		fmt.Fprintf(w, " taking bundles until %s", dump.AuctionDeadline.Format(time.RFC3339Nano))
+	}
+	fmt.Fprintln(w)
+	if len(dump.Bundles) == 0 {
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "  HEIGHT\tBUNDLE\tTXS\tBID\tAGE\tSTATUS")
+	for _, bundle := range dump.Bundles {
+		var flags []string
+		if len(bundle.Missing) > 0 {
+			flags = append(flags, fmt.Sprintf("missing %v", bundle.Missing))
+		}
+		for _, flag := range []struct {
+			set  bool
+			name string
+		}{
+			{bundle.Late, "late"},
+			{bundle.Vetoed, "vetoed"},
+			{bundle.Auctioned, "auctioned"},
+			{bundle.Evicted, "evicted"},
+		} {
+			if flag.set {
+				flags = append(flags, flag.name)
+			}
+		}
+		if len(flags) == 0 {
+			flags = append(flags, "complete")
+		}
+		fmt.Fprintf(tw, "  %d\t%d\t%d/%d\t%d\t%s\t%s\n", bundle.Height, bundle.BundleID,
+			bundle.CurrSize, bundle.EnforcedSize, bundle.Bid,
+			time.Since(bundle.FirstReceived).Round(time.Millisecond), strings.Join(flags, ", "))
+	}
+	tw.Flush()
+}
+
+// printAuctions prints the outcome of the auctions of the last blocks up to
+// height: the MEV stats of the bundles received, and the receipts of those
+// included in blocks this node proposed.
+func printAuctions(ctx context.Context, w io.Writer, rpc *rpchttp.HTTP, height int64) {
+	stats, err := rpc.MEVStats(ctx, &blocks)
+	if err != nil {
+		fmt.Fprintf(w, "Auctions unknown: %v\n", err)
+	} else {
+		fmt.Fprintf(w, "Auctions of heights %d to %d: %d bundles received, %d included (win rate %.1f%%), "+
+			"total bids %d, average latency %s\n", stats.FromHeight, stats.ToHeight, stats.BundlesReceived,
+			stats.BundlesIncluded, 100*stats.WinRate, stats.TotalBids, stats.AvgLatency)
+	}
+
+	var lines []string
+	for h := height; h > 0 && h > height-blocks; h-- {
+		h := h
+		res, err := rpc.BundleReceipts(ctx, &h)
+		if err != nil {
+			fmt.Fprintf(w, "Bundles included unknown: %v\n", err)
+			return
+		}
+		for _, receipt := range res.Receipts {
+			lines = append(lines, fmt.Sprintf("  %d\t%d\t%d\t%d\t%X", receipt.Height, receipt.BundleID,
+				receipt.Size, receipt.Position, receipt.BundleHash))
+		}
+	}
+	if len(lines) == 0 {
+		fmt.Fprintln(w, "Bundles included in blocks this node proposed: none")
+		return
+	}
+	fmt.Fprintln(w, "Bundles included in blocks this node proposed:")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "  HEIGHT\tBUNDLE\tTXS\tPOSITION\tHASH")
+	for _, line := range lines {
+		fmt.Fprintln(tw, line)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,48 @@
+package debug
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	mempl "github.com/tendermint/tendermint/mempool"
+)
+
+var sidecarDumpCmd = &cobra.Command{
+	Use:   "sidecar-dump [output-file]",
+	Short: "Dump a running node's sidecar to a file",
+	Long: `Dump the internals of a running node's sidecar, its txs and bundles included,
+to a JSON file, read from the node's debug server at --pprof-laddr.
+
+The dump can be loaded into the empty sidecar of a test node with the
+unsafe_load_sidecar RPC, to reproduce ordering bugs offline against the same
+bundle set.`,
+	Args: cobra.ExactArgs(1),
+	RunE: sidecarDumpCmdHandler,
+}
+
+func init() {
+	sidecarDumpCmd.Flags().StringVar(
+		&profAddr,
+		flagProfAddr,
+		"",
+		"the profiling server address (<host>:<port>)",
+	)
+}
+
+func sidecarDumpCmdHandler(cmd *cobra.Command, args []string) error {
+	if profAddr == "" {
+		return errors.New("the profiling server address is required")
+	}
+
+	dump, err := getSidecarDump(profAddr)
+	if err != nil {
+		return err
+	}
+	if err := mempl.WriteSidecarDumpFile(args[0], *dump); err != nil {
+		return fmt.Errorf("failed to write the sidecar dump: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Dumped %d txs in %d bundles to %s\n", len(dump.Txs), len(dump.Bundles), args[0])
+	return nil
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmos "github.com/tendermint/tendermint/libs/os"
+	mempl "github.com/tendermint/tendermint/mempool"
+)
+
+// GenRelayKeyCmd allows the generation of the key signing the node's
+// registrations with the relay. It prints the key's public key to the
+// standard output.
+var GenRelayKeyCmd = &cobra.Command{
+	Use:   "gen-relay-key",
+	Short: "Generate the key this node signs its registrations with the relay with, and print its public key",
+	Long: `Generate the key this node signs its registrations with the relay with, in
+the [sidecar] relay_key_file, and print its public key, for the relay to
+authenticate the node by.
+
+To rotate the key, point relay_key_file to a new file, generate the key, and
+reload the [sidecar] config: the relay is registered with again, with the new
+key.`,
+	RunE: genRelayKey,
+}
+
+func genRelayKey(cmd *cobra.Command, args []string) error {
+	keyFile := config.Sidecar.RelayKeyFilePath()
+	if keyFile == "" {
+		return errors.New("no relay key file set, see [sidecar] relay_key_file")
+	}
+	if tmos.FileExists(keyFile) {
+		return fmt.Errorf("relay key at %s already exists", keyFile)
+	}
+
+	key := mempl.GenFileRelayKey()
+	if err := key.SaveAs(keyFile); err != nil {
+		return err
+	}
+	bz, err := tmjson.Marshal(key.PubKey())
+	if err != nil {
+		return fmt.Errorf("failed to marshal relay pubkey: %w", err)
+	}
+	fmt.Println(string(bz))
+	return nil
+}
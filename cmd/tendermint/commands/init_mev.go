@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	cfg "github.com/tendermint/tendermint/config"
+	tmos "github.com/tendermint/tendermint/libs/os"
+)
+
+// InitMEVCmd adds the [sidecar] section to the config file of a node set up
+// before MEV.
+var InitMEVCmd = &cobra.Command{
+	Use:   "init-mev",
+	Short: "Add the [sidecar] section, configuring MEV, to config.toml",
+	Long: `Append the [sidecar] section, configuring MEV, to config.toml, with every
+setting documented and set to its default, or to the value of its TM_SIDECAR_*
+environment variable. The rest of the file is left untouched. Fails if the file
+already has a [sidecar] section.
+
+Then set relayer_id or relay_addr, and personal_peer_ids, to peer with the relay.`,
+	RunE: initMEV,
+}
+
+func initMEV(cmd *cobra.Command, args []string) error {
+	configFile := filepath.Join(config.RootDir, "config", "config.toml")
+	if !tmos.FileExists(configFile) {
+		return fmt.Errorf("no config file at %s, run init first", configFile)
+	}
+	if err := cfg.AppendSidecarConfig(configFile, config); err != nil {
+		return err
+	}
+	logger.Info("Added the [sidecar] section", "path", configFile)
+	return nil
+}
@@ -31,6 +31,7 @@ func registerFlagsRootCmd(cmd *cobra.Command) {
 // sets up the Tendermint root and ensures that the root exists
 func ParseConfig() (*cfg.Config, error) {
 	conf := cfg.DefaultConfig()
+	cfg.BindSidecarEnv(viper.GetViper())
 	err := viper.Unmarshal(conf)
 	if err != nil {
 		return nil, err
@@ -41,16 +42,17 @@ func ParseConfig() (*cfg.Config, error) {
 		return nil, fmt.Errorf("error in config file: %v", err)
 	}
 	// Add auction relayer to config if not present and set
-	if len(conf.Sidecar.RelayerID) > 0 && !strings.Contains(conf.P2P.PrivatePeerIDs, conf.Sidecar.RelayerID) {
+	relayerID := conf.Sidecar.RelayPeerID()
+	if len(relayerID) > 0 && !strings.Contains(conf.P2P.PrivatePeerIDs, relayerID) {
 		// safety check to not blow away existing private peers if any
 		if len(conf.P2P.PrivatePeerIDs) > 0 {
 			if conf.P2P.PrivatePeerIDs[len(conf.P2P.PrivatePeerIDs)-1:] == "," {
-				conf.P2P.PrivatePeerIDs = conf.P2P.PrivatePeerIDs + conf.Sidecar.RelayerID
+				conf.P2P.PrivatePeerIDs = conf.P2P.PrivatePeerIDs + relayerID
 			} else {
-				conf.P2P.PrivatePeerIDs = conf.P2P.PrivatePeerIDs + "," + conf.Sidecar.RelayerID
+				conf.P2P.PrivatePeerIDs = conf.P2P.PrivatePeerIDs + "," + relayerID
 			}
 		} else {
-			conf.P2P.PrivatePeerIDs = conf.Sidecar.RelayerID
+			conf.P2P.PrivatePeerIDs = relayerID
 		}
 	}
 	return conf, nil
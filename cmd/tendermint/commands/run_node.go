@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
@@ -116,6 +118,13 @@ func NewRunNodeCmd(nodeProvider nm.Provider) *cobra.Command {
 
 			logger.Info("Started node", "nodeInfo", n.Switch().NodeInfo())
 
+			// Reload the sidecar peers, relay and bid floor upon SIGHUP.
+			trapSighup(func() {
+				if err := n.ReloadSidecarConfig(); err != nil {
+					logger.Error("Failed to reload sidecar config", "err", err)
+				}
+			})
+
 			// Stop upon receiving SIGTERM or CTRL-C.
 			tmos.TrapSignal(logger, func() {
 				if n.IsRunning() {
@@ -134,6 +143,17 @@ func NewRunNodeCmd(nodeProvider nm.Provider) *cobra.Command {
 	return cmd
 }
 
+// trapSighup calls cb every time the process receives SIGHUP.
+func trapSighup(cb func()) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	go func() {
+		for range c {
+			cb()
+		}
+	}()
+}
+
 func checkGenesisHash(config *cfg.Config) error {
 	if len(genesisHash) == 0 || config.Genesis == "" {
 		return nil
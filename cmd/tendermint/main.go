@@ -16,6 +16,7 @@ func main() {
 	rootCmd.AddCommand(
 		cmd.GenValidatorCmd,
 		cmd.InitFilesCmd,
+		cmd.InitMEVCmd,
 		cmd.ProbeUpnpCmd,
 		cmd.LightCmd,
 		cmd.ReplayCmd,
@@ -27,6 +28,7 @@ func main() {
 		cmd.TestnetFilesCmd,
 		cmd.ShowNodeIDCmd,
 		cmd.GenNodeKeyCmd,
+		cmd.GenRelayKeyCmd,
 		cmd.VersionCmd,
 		cmd.RollbackStateCmd,
 		debug.DebugCmd,
@@ -1,13 +1,24 @@
 package config
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/libs/tempfile"
 )
 
 const (
@@ -42,16 +53,20 @@ var (
 	defaultPrivValKeyName   = "priv_validator_key.json"
 	defaultPrivValStateName = "priv_validator_state.json"
 
-	defaultNodeKeyName  = "node_key.json"
-	defaultAddrBookName = "addrbook.json"
+	defaultNodeKeyName         = "node_key.json"
+	defaultAddrBookName        = "addrbook.json"
+	defaultSidecarAddrBookName = "sidecar_addrbook.json"
+	defaultMEVProfilesName     = "mev_profiles.toml"
 
 	defaultConfigFilePath   = filepath.Join(defaultConfigDir, defaultConfigFileName)
 	defaultGenesisJSONPath  = filepath.Join(defaultConfigDir, defaultGenesisJSONName)
 	defaultPrivValKeyPath   = filepath.Join(defaultConfigDir, defaultPrivValKeyName)
 	defaultPrivValStatePath = filepath.Join(defaultDataDir, defaultPrivValStateName)
 
-	defaultNodeKeyPath  = filepath.Join(defaultConfigDir, defaultNodeKeyName)
-	defaultAddrBookPath = filepath.Join(defaultConfigDir, defaultAddrBookName)
+	defaultNodeKeyPath         = filepath.Join(defaultConfigDir, defaultNodeKeyName)
+	defaultAddrBookPath        = filepath.Join(defaultConfigDir, defaultAddrBookName)
+	defaultSidecarAddrBookPath = filepath.Join(defaultConfigDir, defaultSidecarAddrBookName)
+	defaultMEVProfilesFilePath = filepath.Join(defaultConfigDir, defaultMEVProfilesName)
 
 	minSubscriptionBufferSize     = 100
 	defaultSubscriptionBufferSize = 200
@@ -114,6 +129,7 @@ func (cfg *Config) SetRoot(root string) *Config {
 	cfg.Mempool.RootDir = root
 	cfg.Consensus.RootDir = root
 	cfg.Sidecar.RootDir = root
+	cfg.Instrumentation.RootDir = root
 	return cfg
 }
 
@@ -144,8 +160,43 @@ func (cfg *Config) ValidateBasic() error {
 	if err := cfg.Instrumentation.ValidateBasic(); err != nil {
 		return fmt.Errorf("error in [instrumentation] section: %w", err)
 	}
+	return cfg.ValidateMEV()
+}
+
+// ValidateMEV validates the [sidecar] section, and how it fits with the other
+// sections, so a node misconfigured for MEV fails to start instead of its
+// auctions silently doing nothing.
+func (cfg *Config) ValidateMEV() error {
 	if err := cfg.Sidecar.ValidateBasic(); err != nil {
-		return fmt.Errorf("error in [Sidecar] section: %w", err)
+		return fmt.Errorf("error in [sidecar] section: %w", err)
+	}
+	if cfg.Mempool.ReplaceByFee && cfg.Sidecar.SelfBuild &&
+		cfg.Mempool.FeeAttribute != cfg.Sidecar.SelfBuildFeeAttribute {
+		return errors.New("mempool fee_attribute and sidecar self_build_fee_attribute must match")
+	}
+	s, timeoutCommit := cfg.Sidecar, cfg.Consensus.TimeoutCommit
+	if s.IncludesBundles() && (s.AuctionCutoff > 0 || s.AuctionWindow > 0) {
+		if cfg.Consensus.SkipTimeoutCommit {
+			return errors.New("sidecar auction_cutoff and auction_window are timed against consensus " +
+				"timeout_commit, which skip_timeout_commit skips: unset either")
+		}
+		if s.AuctionWindow > 0 && s.AuctionWindow >= timeoutCommit {
+			return fmt.Errorf("sidecar auction_window (%v) must be shorter than consensus timeout_commit (%v), "+
+				"or this node proposes before its auctions close", s.AuctionWindow, timeoutCommit)
+		}
+		if s.AuctionWindow == 0 && s.AuctionCutoff >= timeoutCommit {
+			return fmt.Errorf("sidecar auction_cutoff (%v) must be shorter than consensus timeout_commit (%v), "+
+				"or every bundle arrives past it", s.AuctionCutoff, timeoutCommit)
+		}
+	}
+	if s.StallTimeout > 0 && !cfg.Consensus.SkipTimeoutCommit && s.StallTimeout <= timeoutCommit {
+		return fmt.Errorf("sidecar stall_timeout (%v) must be longer than consensus timeout_commit (%v), "+
+			"or the MEV pipeline is flagged as stalled between every block", s.StallTimeout, timeoutCommit)
+	}
+	if s.BundleTTLNumBlocks > 0 && s.MaxBundleHeightsAhead > s.BundleTTLNumBlocks {
+		return fmt.Errorf("sidecar max_bundle_heights_ahead (%d) can't be over bundle_ttl_num_blocks (%d), "+
+			"or bundles for the furthest heights expire before their auction",
+			s.MaxBundleHeightsAhead, s.BundleTTLNumBlocks)
 	}
 	return nil
 }
@@ -333,6 +384,24 @@ type RPCConfig struct {
 	// Activate unsafe RPC commands like /dial_persistent_peers and /unsafe_flush_mempool
 	Unsafe bool `mapstructure:"unsafe"`
 
+	// Activate eth_sendBundle, taking bundles in the format of Flashbots'
+	// eth_sendBundle into the sidecar, for searcher bots written for Ethereum
+	EthSendBundle bool `mapstructure:"eth_send_bundle"`
+
+	// Activate broadcast_bundle, taking bundle envelopes signed by a relay
+	// into the sidecar
+	BroadcastBundle bool `mapstructure:"broadcast_bundle"`
+
+	// TCP or UNIX socket address for the bundle submission endpoints,
+	// eth_sendBundle and broadcast_bundle, to be served on apart from the
+	// others, so they can be kept off a public listener
+	BundleListenAddress string `mapstructure:"bundle_laddr"`
+
+	// Serve the bundle submission endpoints on laddr too. Required to enable
+	// them without bundle_laddr, so a public RPC node doesn't expose them by
+	// accident
+	BundleRoutesOnListenAddress bool `mapstructure:"bundle_routes_on_laddr"`
+
 	// Maximum number of simultaneous connections (including WebSocket).
 	// Does not include gRPC connections. See grpc_max_open_connections
 	// If you want to accept a larger number than the default, make sure
@@ -406,6 +475,7 @@ type RPCConfig struct {
 	TLSKeyFile string `mapstructure:"tls_key_file"`
 
 	// pprof listen address (https://golang.org/pkg/net/http/pprof)
+	// A JSON dump of the sidecar's internals is also served at /debug/sidecar
 	PprofListenAddress string `mapstructure:"pprof_laddr"`
 }
 
@@ -420,8 +490,13 @@ func DefaultRPCConfig() *RPCConfig {
 		GRPCMaxOpenConnections: 900,
 
 		Unsafe:             false,
+		EthSendBundle:      false,
+		BroadcastBundle:    false,
 		MaxOpenConnections: 900,
 
+		BundleListenAddress:         "",
+		BundleRoutesOnListenAddress: false,
+
 		MaxSubscriptionClients:    100,
 		MaxSubscriptionsPerClient: 5,
 		SubscriptionBufferSize:    defaultSubscriptionBufferSize,
@@ -481,6 +556,11 @@ func (cfg *RPCConfig) ValidateBasic() error {
 	if cfg.MaxHeaderBytes < 0 {
 		return errors.New("max_header_bytes can't be negative")
 	}
+	if (cfg.EthSendBundle || cfg.BroadcastBundle) && cfg.BundleListenAddress == "" &&
+		!cfg.BundleRoutesOnListenAddress {
+		return errors.New("eth_send_bundle and broadcast_bundle need bundle_laddr to serve them on, " +
+			"or bundle_routes_on_laddr to serve them on laddr")
+	}
 	return nil
 }
 
@@ -695,6 +775,9 @@ type MempoolConfig struct {
 	MaxTxsBytes int64 `mapstructure:"max_txs_bytes"`
 	// Size of the cache (used to filter transactions we saw earlier) in transactions
 	CacheSize int `mapstructure:"cache_size"`
+	// Number of shards the cache is split into, each with its own lock, to
+	// reduce contention between concurrent CheckTx calls. 1 doesn't split it.
+	CacheShards int `mapstructure:"cache_shards"`
 	// Do not remove invalid transactions from the cache (default: false)
 	// Set to true if it's not possible for any invalid transaction to become
 	// valid again in the future.
@@ -706,6 +789,104 @@ type MempoolConfig struct {
 	// Including space needed by encoding (one varint per transaction).
 	// XXX: Unused due to https://github.com/tendermint/tendermint/issues/5796
 	MaxBatchBytes int `mapstructure:"max_batch_bytes"`
+	// Number of workers admitting txs received from peers (CheckTx for the
+	// mempool, AddTx for the sidecar), off the p2p receive routines. Txs of
+	// each peer are admitted in order. 0 admits them on the receive routines.
+	CheckTxWorkers int `mapstructure:"check_tx_workers"`
+	// Number of received txs queued per worker before receiving blocks
+	CheckTxQueueSize int `mapstructure:"check_tx_queue_size"`
+	// Drop the mempool txs received from a peer whose worker's queue is full,
+	// instead of blocking. Sidecar txs are never dropped. Needs CheckTxWorkers.
+	ShedGossipTxs bool `mapstructure:"shed_gossip_txs"`
+	// Number of CheckTx calls awaiting the app's response past which txs
+	// received from peers are refused. Txs submitted through the RPC are
+	// always checked. 0 doesn't limit pending calls.
+	MaxPendingCheckTxs int `mapstructure:"max_pending_check_txs"`
+	// Time the app is given to check the txs received from a peer, or to
+	// accept a received bundle, before they're refused so they may be sent
+	// again. 0 waits for the app however long it takes.
+	CheckTxTimeout time.Duration `mapstructure:"check_tx_timeout"`
+	// Number of successful CheckTx results kept by tx hash, so a tx re-added
+	// at the same height, or seen both alone and in a bundle, is only checked
+	// once. 0 disables the cache.
+	CheckTxResultCacheSize int `mapstructure:"check_tx_result_cache_size"`
+	// Check the txs of each received message in a single ABCI query on
+	// CheckTxBatchQueryPath, if the app answers on it, instead of one CheckTx
+	// call per tx.
+	CheckTxBatch          bool   `mapstructure:"check_tx_batch"`
+	CheckTxBatchQueryPath string `mapstructure:"check_tx_batch_query_path"`
+	// Recheck the txs after a commit in a single batched CheckTx query,
+	// instead of one CheckTx call per tx on the mempool connection. Needs
+	// CheckTxBatch.
+	RecheckBatch bool `mapstructure:"recheck_batch"`
+	// Number of txs per second each peer may submit through the mempool
+	// channel, up to PeerCheckTxBurst at once. Peers going over are muted for
+	// PeerMuteDuration, their txs being dropped. 0 doesn't limit peers.
+	PeerCheckTxRate  float64       `mapstructure:"peer_check_tx_rate"`
+	PeerCheckTxBurst int           `mapstructure:"peer_check_tx_burst"`
+	PeerMuteDuration time.Duration `mapstructure:"peer_mute_duration"`
+	// TTLDuration, if non-zero, defines the maximum amount of time a tx can
+	// exist in the mempool before it's removed on the next block commit.
+	TTLDuration time.Duration `mapstructure:"ttl-duration"`
+	// TTLNumBlocks, if non-zero, defines the maximum number of blocks a tx can
+	// exist in the mempool before it's removed on the next block commit.
+	TTLNumBlocks int64 `mapstructure:"ttl-num-blocks"`
+	// Time reaping the txs of a proposal may take. Once spent, no more public
+	// txs are added to the proposal. 0 means no limit.
+	ReapTimeBudget time.Duration `mapstructure:"reap_time_budget"`
+	// Break ties between txs by hash rather than arrival order, so the same
+	// txs are always reaped in the same order, and keep the hash of the last
+	// reaped set. Incompatible with ReapTimeBudget.
+	DeterministicReap bool `mapstructure:"deterministic_reap"`
+	// CheckTx event attributes ("type.key") holding the sender of a tx and its
+	// sequence. If both are set, the txs of each sender are reaped in sequence
+	// order instead of arrival order.
+	SenderAttribute   string `mapstructure:"sender_attribute"`
+	SequenceAttribute string `mapstructure:"sequence_attribute"`
+	// Let a tx replace the pending tx of the same sender and sequence if it
+	// declares a higher fee in the FeeAttribute ("type.key") CheckTx event
+	// attribute. Needs SenderAttribute and SequenceAttribute.
+	ReplaceByFee bool   `mapstructure:"replace_by_fee"`
+	FeeAttribute string `mapstructure:"fee_attribute"`
+	// Maximum number of pending txs per sender, read from SenderAttribute.
+	// Txs over it are rejected. 0 doesn't limit senders.
+	MaxTxsPerSender int `mapstructure:"max_txs_per_sender"`
+	// CheckTx event attribute ("type.key") holding the priority of a tx. If
+	// set, txs are reaped in descending priority order instead of arrival
+	// order.
+	PriorityAttribute string `mapstructure:"priority_attribute"`
+	// Lanes of txs, as comma separated name:priority[:max_txs] entries, txs
+	// being reaped by descending lane priority. The lane of a tx is read from
+	// the LaneAttribute ("type.key") CheckTx event attribute, txs declaring no
+	// known lane going to the built-in "default" lane. The built-in "mev" lane
+	// holds the sidecar bundles. See LaneList.
+	Lanes         string `mapstructure:"lanes"`
+	LaneAttribute string `mapstructure:"lane_attribute"`
+	// Origins whose txs are never gossiped, as comma separated names among
+	// TxOrigins. See PrivateOriginList.
+	PrivateOrigins string `mapstructure:"private_origins"`
+}
+
+// TxOrigins are the names of the origins a tx can be received from: the RPC,
+// the mempool channel, the sidecar channel, and the relay.
+var TxOrigins = []string{"rpc", "p2p", "sidecar", "relay"}
+
+// Built-in mempool lanes, whose priority may be set in MempoolConfig.Lanes.
+const (
+	DefaultLane = "default" // txs declaring no other lane, priority 0 by default
+	MEVLane     = "mev"     // the sidecar bundles, priority 1 by default
+
+	// MaxCustomLanes is the maximum number of lanes besides the built-in ones.
+	MaxCustomLanes = 7
+)
+
+var laneNameRegexp = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// LaneConfig is a lane of txs, see MempoolConfig.Lanes.
+type LaneConfig struct {
+	Name     string
+	Priority int
+	MaxTxs   int // maximum number of txs pending in the lane, 0 if unlimited
 }
 
 // DefaultMempoolConfig returns a default configuration for the Tendermint mempool
@@ -719,7 +900,35 @@ func DefaultMempoolConfig() *MempoolConfig {
 		Size:        5000,
 		MaxTxsBytes: 1024 * 1024 * 1024, // 1GB
 		CacheSize:   10000,
+		CacheShards: 1,
 		MaxTxBytes:  1024 * 1024, // 1MB
+
+		CheckTxWorkers:         0,
+		CheckTxQueueSize:       1000,
+		ShedGossipTxs:          false,
+		MaxPendingCheckTxs:     0,
+		CheckTxTimeout:         0,
+		CheckTxResultCacheSize: 0,
+		CheckTxBatch:           false,
+		CheckTxBatchQueryPath:  "/mev/check_tx_batch",
+		RecheckBatch:           false,
+		PeerCheckTxRate:        0,
+		PeerCheckTxBurst:       1000,
+		PeerMuteDuration:       30 * time.Second,
+		TTLDuration:            0 * time.Second,
+		TTLNumBlocks:           0,
+		ReapTimeBudget:         0,
+		DeterministicReap:      false,
+
+		SenderAttribute:   "",
+		SequenceAttribute: "",
+		ReplaceByFee:      false,
+		FeeAttribute:      "tx.fee",
+		MaxTxsPerSender:   0,
+		PriorityAttribute: "",
+		Lanes:             "",
+		LaneAttribute:     "tx.lane",
+		PrivateOrigins:    "",
 	}
 }
 
@@ -752,12 +961,173 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.CacheSize < 0 {
 		return errors.New("cache_size can't be negative")
 	}
+	if cfg.CacheShards < 1 {
+		return errors.New("cache_shards must be at least 1")
+	}
 	if cfg.MaxTxBytes < 0 {
 		return errors.New("max_tx_bytes can't be negative")
 	}
+	if cfg.CheckTxWorkers < 0 {
+		return errors.New("check_tx_workers can't be negative")
+	}
+	if cfg.CheckTxWorkers > 0 && cfg.CheckTxQueueSize <= 0 {
+		return errors.New("check_tx_queue_size must be positive when check_tx_workers is set")
+	}
+	if cfg.ShedGossipTxs && cfg.CheckTxWorkers == 0 {
+		return errors.New("shed_gossip_txs needs check_tx_workers")
+	}
+	if cfg.MaxPendingCheckTxs < 0 {
+		return errors.New("max_pending_check_txs can't be negative")
+	}
+	if cfg.CheckTxTimeout < 0 {
+		return errors.New("check_tx_timeout can't be negative")
+	}
+	if cfg.CheckTxResultCacheSize < 0 {
+		return errors.New("check_tx_result_cache_size can't be negative")
+	}
+	if cfg.CheckTxBatch && !strings.HasPrefix(cfg.CheckTxBatchQueryPath, "/") {
+		return fmt.Errorf("check_tx_batch_query_path must start with /, got %q", cfg.CheckTxBatchQueryPath)
+	}
+	if cfg.RecheckBatch && !cfg.CheckTxBatch {
+		return errors.New("recheck_batch needs check_tx_batch")
+	}
+	if cfg.PeerCheckTxRate < 0 {
+		return errors.New("peer_check_tx_rate can't be negative")
+	}
+	if cfg.PeerCheckTxRate > 0 && cfg.PeerCheckTxBurst <= 0 {
+		return errors.New("peer_check_tx_burst must be positive when peer_check_tx_rate is set")
+	}
+	if cfg.PeerMuteDuration < 0 {
+		return errors.New("peer_mute_duration can't be negative")
+	}
+	if cfg.TTLDuration < 0 {
+		return errors.New("ttl-duration can't be negative")
+	}
+	if cfg.TTLNumBlocks < 0 {
+		return errors.New("ttl-num-blocks can't be negative")
+	}
+	if cfg.DeterministicReap && cfg.ReapTimeBudget > 0 {
+		return errors.New("deterministic_reap can't be used with reap_time_budget")
+	}
+	if cfg.ReapTimeBudget < 0 {
+		return errors.New("reap_time_budget can't be negative")
+	}
+	if (cfg.SenderAttribute == "") != (cfg.SequenceAttribute == "") {
+		return errors.New("sender_attribute and sequence_attribute must be set together")
+	}
+	for _, attr := range []string{cfg.SenderAttribute, cfg.SequenceAttribute} {
+		if attr != "" && !strings.Contains(attr, ".") {
+			return fmt.Errorf("sender and sequence attributes must be of the form type.key, got %q", attr)
+		}
+	}
+	if cfg.PriorityAttribute != "" && !strings.Contains(cfg.PriorityAttribute, ".") {
+		return fmt.Errorf("priority_attribute must be of the form type.key, got %q", cfg.PriorityAttribute)
+	}
+	lanes, err := cfg.LaneList()
+	if err != nil {
+		return err
+	}
+	if len(lanes) > 2 && !strings.Contains(cfg.LaneAttribute, ".") {
+		return fmt.Errorf("lane_attribute must be of the form type.key, got %q", cfg.LaneAttribute)
+	}
+	if _, err := cfg.PrivateOriginList(); err != nil {
+		return err
+	}
+	if cfg.MaxTxsPerSender < 0 {
+		return errors.New("max_txs_per_sender can't be negative")
+	}
+	if cfg.MaxTxsPerSender > 0 && cfg.SenderAttribute == "" {
+		return errors.New("max_txs_per_sender needs sender_attribute and sequence_attribute")
+	}
+	if cfg.ReplaceByFee {
+		if cfg.SenderAttribute == "" {
+			return errors.New("replace_by_fee needs sender_attribute and sequence_attribute")
+		}
+		if !strings.Contains(cfg.FeeAttribute, ".") {
+			return fmt.Errorf("fee_attribute must be of the form type.key, got %q", cfg.FeeAttribute)
+		}
+	}
 	return nil
 }
 
+// LaneList returns the decoded Lanes, in order, followed by the built-in
+// lanes not among them.
+func (cfg *MempoolConfig) LaneList() ([]LaneConfig, error) {
+	var (
+		lanes       = make([]LaneConfig, 0)
+		seen        = make(map[string]bool)
+		customLanes int
+	)
+	for _, entry := range strings.Split(cfg.Lanes, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("lanes: expected name:priority[:max_txs], got %q", entry)
+		}
+		lane := LaneConfig{Name: parts[0]}
+		if !laneNameRegexp.MatchString(lane.Name) {
+			return nil, fmt.Errorf("lanes: invalid lane name %q", lane.Name)
+		}
+		if seen[lane.Name] {
+			return nil, fmt.Errorf("lanes: duplicate lane %q", lane.Name)
+		}
+		seen[lane.Name] = true
+		priority, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("lanes: invalid priority for lane %q: %w", lane.Name, err)
+		}
+		lane.Priority = priority
+		if len(parts) == 3 {
+			if lane.Name == MEVLane {
+				return nil, errors.New("lanes: the mev lane is limited by [sidecar] max_txs")
+			}
+			maxTxs, err := strconv.Atoi(parts[2])
+			if err != nil || maxTxs < 0 {
+				return nil, fmt.Errorf("lanes: invalid max_txs for lane %q", lane.Name)
+			}
+			lane.MaxTxs = maxTxs
+		}
+		if lane.Name != DefaultLane && lane.Name != MEVLane {
+			customLanes++
+		}
+		lanes = append(lanes, lane)
+	}
+	if customLanes > MaxCustomLanes {
+		return nil, fmt.Errorf("lanes: at most %d lanes besides %q and %q, got %d",
+			MaxCustomLanes, DefaultLane, MEVLane, customLanes)
+	}
+	if !seen[DefaultLane] {
+		lanes = append(lanes, LaneConfig{Name: DefaultLane, Priority: 0})
+	}
+	if !seen[MEVLane] {
+		lanes = append(lanes, LaneConfig{Name: MEVLane, Priority: 1})
+	}
+	return lanes, nil
+}
+
+// PrivateOriginList returns the decoded PrivateOrigins.
+func (cfg *MempoolConfig) PrivateOriginList() ([]string, error) {
+	origins := make([]string, 0)
+	for _, origin := range strings.Split(cfg.PrivateOrigins, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+		known := false
+		for _, o := range TxOrigins {
+			known = known || o == origin
+		}
+		if !known {
+			return nil, fmt.Errorf("private_origins: unknown origin %q, expected one of %v", origin, TxOrigins)
+		}
+		origins = append(origins, origin)
+	}
+	return origins, nil
+}
+
 //-----------------------------------------------------------------------------
 // StateSyncConfig
 
@@ -1088,37 +1458,773 @@ func TestTxIndexConfig() *TxIndexConfig {
 // SidecarConfig
 
 // Sidecar defines configuration for gossiping the private sidecar
-// mempool among the relayer and the nodes that belong to a particular proposer
+// mempool among the relayer and the nodes that belong to a particular proposer,
+// and for the auctions of the bundles it holds. It's the [sidecar] section of
+// config.toml.
 type SidecarConfig struct {
 	RootDir         string `mapstructure:"home"`
 	RelayerID       string `mapstructure:"relayer_id"`
 	PersonalPeerIDs string `mapstructure:"personal_peer_ids"`
-}
+
+	// Address of the relay, as id@host:port, which this node then dials and
+	// keeps connected to, as to a persistent peer. RelayerID may be left
+	// empty, and must match the address's ID if it isn't.
+	RelayAddr string `mapstructure:"relay_addr"`
+
+	// API key the relay issued to this validator, sent to the relay only, once
+	// connected, to register with it. The relay authenticates the node itself
+	// by its node key, through the p2p handshake.
+	RelayAPIKey string `mapstructure:"relay_api_key"`
+
+	// File holding the key this node signs its registrations with the relay
+	// with, relative to the home directory, kept apart from the node and
+	// validator keys so it can be rotated on its own. Empty sends the
+	// registrations unsigned.
+	RelayKeyFile string `mapstructure:"relay_key_file"`
+
+	// Node public keys of the relays this node may take as its relay, as a
+	// comma separated list of base64-encoded ed25519 keys, guarding against a
+	// rogue relay being configured. If set, the relay, by RelayerID or
+	// RelayAddr, must be one of them: sidecar messages from any other are
+	// refused, and it isn't registered with. Empty allows any relay.
+	RelayPubKeys string `mapstructure:"relay_pub_keys"`
+
+	// Sidecar peers, the relay included, speaking Skip Protocol's
+	// mev-tendermint message format rather than this node's, as a comma
+	// separated list of node IDs, for validators bridging the two MEV
+	// networks during a migration. Their bundles are translated into this
+	// node's, at a bid of 0, and they're sent txs in their format only: they
+	// aren't pinged, nor sent receipts or registrations.
+	SkipFormatPeerIDs string `mapstructure:"skip_format_peer_ids"`
+
+	// TCP address the BundleSubmission gRPC service listens on, for the relay
+	// to submit bundle envelopes directly rather than gossip them, getting
+	// back whether each was added. Only envelopes signed by a relay in
+	// RelayPubKeys are taken, which must be set. Empty disables it.
+	GRPCListenAddress string `mapstructure:"grpc_laddr"`
+
+	// File keeping the addresses of the sidecar peers and the relay, relative
+	// to the home directory, apart from the P2P address book so PEX never
+	// prunes them. The peers whose addresses are known are redialed whenever
+	// disconnected, with their own backoff, never given up on. Empty disables
+	// it, leaving sidecar peers to be dialed as any other.
+	AddrBookFile string `mapstructure:"addr_book_file"`
+
+	// How long before the proposal timer fires (ie. before timeout_commit
+	// elapses) bundles for the upcoming height stop being accepted into this
+	// node's auction. Late bundles are still gossiped. 0 disables the cutoff.
+	AuctionCutoff time.Duration `mapstructure:"auction_cutoff"`
+
+	// How long after a block is committed the auction for the next height
+	// takes new bundles, regardless of the consensus timeouts. Overrides
+	// AuctionCutoff when set.
+	AuctionWindow time.Duration `mapstructure:"auction_window"`
+
+	// How many heights past the upcoming auction bundles may target. Bundles
+	// for later heights are refused. Sent to the relay on registering with
+	// it, so it knows the window to submit bundles in. 0 means no limit.
+	MaxBundleHeightsAhead int64 `mapstructure:"max_bundle_heights_ahead"`
+
+	// Lowest bid a bundle may make to enter this node's auctions. Bundle txs
+	// bidding less are refused. 0 means no floor.
+	MinBid int64 `mapstructure:"min_bid"`
+
+	// How many heights after the one they targeted unused bundles are kept
+	// before being purged. 0 purges them as soon as that height is committed.
+	BundleRetainHeights int64 `mapstructure:"bundle_retain_heights"`
+
+	// If non-zero, the maximum amount of time, or number of blocks, a bundle
+	// can wait in the sidecar for its height before it's purged on the next
+	// block commit, counted from its first tx.
+	BundleTTLDuration  time.Duration `mapstructure:"bundle_ttl_duration"`
+	BundleTTLNumBlocks int64         `mapstructure:"bundle_ttl_num_blocks"`
+
+	// If non-zero, bundles past their BundleTTLDuration are also purged this
+	// often between commits, so chains with long block times don't hold
+	// expired bundles until the next block. 0 only purges them on commit.
+	BundlePurgeInterval time.Duration `mapstructure:"bundle_purge_interval"`
+
+	// Size of the sidecar's own cache of seen txs, used to drop duplicates,
+	// separate from the mempool's. With CacheScope "height", it's cleared on
+	// every commit, so txs may be sent again for a later height. With
+	// "retain", entries are only evicted when the cache is full.
+	CacheSize  int    `mapstructure:"cache_size"`
+	CacheScope string `mapstructure:"cache_scope"`
+
+	// Capacity of the sidecar, separate from the mempool's: the maximum
+	// number of txs, their total size in bytes, and the number of bundles it
+	// holds. Txs past any of them are refused. 0 means no limit.
+	MaxTxs     int   `mapstructure:"max_txs"`
+	MaxBytes   int64 `mapstructure:"max_bytes"`
+	MaxBundles int   `mapstructure:"max_bundles"`
+
+	// Smallest and largest number of txs a bundle may declare. Txs of bundles
+	// declaring a size out of range are refused, and dropped on receipt.
+	// MaxBundleSize 0 means no limit.
+	MinBundleSize int64 `mapstructure:"min_bundle_size"`
+	MaxBundleSize int64 `mapstructure:"max_bundle_size"`
+
+	// Memory the sidecar may take, in bytes: its txs plus an estimate of the
+	// bookkeeping of each tx and bundle, counting the txs of bundles still
+	// held after leaving the txs list, and its cache of seen txs, whose room
+	// for CacheSize entries is set aside, see CacheMemBytes. Past it,
+	// incomplete bundles for later heights than a new tx's are evicted to
+	// make room, or else the tx is refused. 0 means no limit.
+	MaxMemory int64 `mapstructure:"max_memory"`
+
+	// Turn off all MEV functionality, so the node behaves like one without
+	// MEV: sidecar messages are dropped, bundle txs refused, and no auction
+	// is run nor bundle self-built. This is advertised to peers so they stop
+	// gossiping sidecar txs to us. It can be changed while the node runs, for
+	// an emergency rollback, though peers only see the change once they
+	// reconnect.
+	MEVDisabled bool `mapstructure:"mev_disabled"`
+
+	// Run MEV in shadow mode: bundles are received, checked and auctioned,
+	// and the auctions logged, counted in the metrics and published as
+	// events as if their winners were included, but proposals are made
+	// without any bundle. For validators to evaluate MEV before enabling it.
+	DryRun bool `mapstructure:"dry_run"`
+
+	// Role of this node in the MEV pipeline, which sets what it does with the
+	// bundles it receives: NodeModeValidator includes them in its proposals,
+	// NodeModeSentry only passes them on to its sidecar peers, and
+	// NodeModeRelay also takes them from any peer, ie. submissions, and fans
+	// them out to its sidecar peers.
+	Mode string `mapstructure:"mode"`
+
+	// File of MEV profiles, relative to the home directory, holding the
+	// [sidecar] settings suited to each network, by chain ID. The settings
+	// left at their defaults are set to those of the profile of the node's
+	// chain, read from this file, or else compiled in (see
+	// RegisterMEVProfile). The file is optional; "" only uses the compiled in
+	// profiles.
+	ProfilesFile string `mapstructure:"profiles_file"`
+
+	// Re-check every proposal this node assembles for bundle contiguity,
+	// ordering, duplicate txs and block budget compliance. Violations are
+	// logged and counted in the state metrics.
+	CheckProposalInvariants bool `mapstructure:"check_proposal_invariants"`
+
+	// When proposing without any complete bundle from a relay, build one from
+	// the public mempool instead, putting the SelfBuildMaxTxs txs declaring
+	// the highest fee at the top of the block. The fee is read from the
+	// SelfBuildFeeAttribute ("type.key") CheckTx event attribute.
+	SelfBuild             bool   `mapstructure:"self_build"`
+	SelfBuildMaxTxs       int    `mapstructure:"self_build_max_txs"`
+	SelfBuildFeeAttribute string `mapstructure:"self_build_fee_attribute"`
+
+	// Classes of txs always placed ahead of bundles in this node's proposals,
+	// so critical protocol traffic (eg. oracle votes, IBC packets) is never
+	// displaced by an auction: txs starting with any of the comma separated
+	// hex ProtectedTxPrefixes, and txs whose CheckTx events carry the
+	// ProtectedTxAttribute ("type.key") attribute set to "true".
+	ProtectedTxPrefixes  string `mapstructure:"protected_tx_prefixes"`
+	ProtectedTxAttribute string `mapstructure:"protected_tx_attribute"`
+
+	// Simulate each proposal carrying bundles against the app before making
+	// it, through an ABCI query on SimulationQueryPath the app must support.
+	// If the simulation fails, the proposal is made without bundles.
+	SimulateProposals   bool   `mapstructure:"simulate_proposals"`
+	SimulationQueryPath string `mapstructure:"simulation_query_path"`
+
+	// Have the app accept each bundle once all its txs are in, through an
+	// ABCI query on CheckBundleQueryPath the app must support. Txs of
+	// refused bundles are dropped from the sidecar.
+	CheckBundles         bool   `mapstructure:"check_bundles"`
+	CheckBundleQueryPath string `mapstructure:"check_bundle_query_path"`
+
+	// Sign a receipt with the node key for each bundle included in a block
+	// this node proposed, served over RPC for the last ReceiptRetainHeights
+	// heights. With PushReceipts, receipts are also sent back towards the
+	// relay once the block is committed.
+	BundleReceipts       bool  `mapstructure:"bundle_receipts"`
+	PushReceipts         bool  `mapstructure:"push_receipts"`
+	ReceiptRetainHeights int64 `mapstructure:"receipt_retain_heights"`
+
+	// Flag the MEV pipeline as stalled, failing /health, when no sidecar
+	// message arrived for StallBlocks blocks while sidecar peers are
+	// connected, or when the auction height didn't advance for StallTimeout.
+	// 0 disables either check.
+	StallBlocks  int64         `mapstructure:"stall_blocks"`
+	StallTimeout time.Duration `mapstructure:"stall_timeout"`
+
+	// Log each of the lines logged for every sidecar tx (receiving, adding,
+	// gossiping or dropping it as a duplicate) at most LogSampleBurst times
+	// per LogSampleInterval, summing up the lines dropped once the interval
+	// is over. 0 logs every line.
+	LogSampleInterval time.Duration `mapstructure:"log_sample_interval"`
+	LogSampleBurst    int           `mapstructure:"log_sample_burst"`
+
+	// Append a JSON line recording every bundle received, and each decision
+	// taken on it, to AuditLogPath, relative to the home directory, as a
+	// local record for disputes with relays. The file is rotated once past
+	// AuditLogMaxFileSize bytes, and the oldest ones removed once all of them
+	// take more than AuditLogMaxTotalSize bytes (0 keeps them all). Empty
+	// disables the log.
+	AuditLogPath         string `mapstructure:"audit_log_path"`
+	AuditLogMaxFileSize  int64  `mapstructure:"audit_log_max_file_size"`
+	AuditLogMaxTotalSize int64  `mapstructure:"audit_log_max_total_size"`
+
+	// Send a copy of every bundle completed to MirrorAddr, for analytics
+	// run outside the node: a socket, "unix://" or "tcp://", sent a JSON
+	// line per bundle, or an HTTP endpoint, "http://" or "https://", POSTed
+	// a JSON object per bundle. If MirrorRedactTxs, only the hashes of the
+	// bundles' txs are sent, not the txs. Empty disables the mirror.
+	MirrorAddr      string `mapstructure:"mirror_addr"`
+	MirrorRedactTxs bool   `mapstructure:"mirror_redact_txs"`
+}
+
+// Scopes of the sidecar's cache of seen txs.
+const (
+	SidecarCacheScopeHeight = "height" // cleared on every commit
+	SidecarCacheScopeRetain = "retain" // kept until evicted
+)
+
+// nodeIDByteLength is the length of a node ID, as p2p.IDByteLength, which
+// can't be imported here.
+const nodeIDByteLength = 20
 
 func DefaultSidecarConfig() *SidecarConfig {
 	return &SidecarConfig{
-		RelayerID:       "",
-		PersonalPeerIDs: "",
+		RelayerID:         "",
+		PersonalPeerIDs:   "",
+		RelayAddr:         "",
+		RelayAPIKey:       "",
+		RelayKeyFile:      "",
+		RelayPubKeys:      "",
+		SkipFormatPeerIDs: "",
+		GRPCListenAddress: "",
+		AddrBookFile:      defaultSidecarAddrBookPath,
+		AuctionCutoff:     0,
+		MEVDisabled:       false,
+		DryRun:            false,
+		Mode:              NodeModeValidator,
+		ProfilesFile:      defaultMEVProfilesFilePath,
+
+		AuctionWindow:         0,
+		MaxBundleHeightsAhead: 0,
+		MinBid:                0,
+		BundleRetainHeights:   0,
+		BundleTTLDuration:     0,
+		BundleTTLNumBlocks:    0,
+		BundlePurgeInterval:   0,
+
+		CacheSize:  10000,
+		CacheScope: SidecarCacheScopeHeight,
+
+		MaxTxs:     5000,
+		MaxBytes:   1024 * 1024 * 1024, // 1GB
+		MaxBundles: 1000,
+		MaxMemory:  2 * 1024 * 1024 * 1024, // 2GB
+
+		MinBundleSize: 1,
+		MaxBundleSize: 0,
+
+		CheckProposalInvariants: false,
+
+		SelfBuild:             false,
+		SelfBuildMaxTxs:       10,
+		SelfBuildFeeAttribute: "tx.fee",
+
+		ProtectedTxPrefixes:  "",
+		ProtectedTxAttribute: "",
+
+		SimulateProposals:   false,
+		SimulationQueryPath: "/mev/simulate_proposal",
+
+		CheckBundles:         false,
+		CheckBundleQueryPath: "/mev/check_bundle",
+
+		BundleReceipts:       false,
+		PushReceipts:         false,
+		ReceiptRetainHeights: 1000,
+
+		StallBlocks:  0,
+		StallTimeout: 0,
+
+		LogSampleInterval: time.Second,
+		LogSampleBurst:    100,
+
+		AuditLogPath:         "",
+		AuditLogMaxFileSize:  10 * 1024 * 1024,   // 10MB
+		AuditLogMaxTotalSize: 1024 * 1024 * 1024, // 1GB
+		MirrorAddr:           "",
+		MirrorRedactTxs:      false,
 	}
 }
 
 func TestSidecarConfig() *SidecarConfig {
 	return &SidecarConfig{
-		RelayerID:       "",
-		PersonalPeerIDs: "",
+		RelayerID:         "",
+		PersonalPeerIDs:   "",
+		RelayAddr:         "",
+		RelayAPIKey:       "",
+		RelayKeyFile:      "",
+		RelayPubKeys:      "",
+		SkipFormatPeerIDs: "",
+		GRPCListenAddress: "",
+		AddrBookFile:      defaultSidecarAddrBookPath,
+		AuctionCutoff:     0,
+		MEVDisabled:       false,
+		DryRun:            false,
+		Mode:              NodeModeValidator,
+		ProfilesFile:      defaultMEVProfilesFilePath,
+
+		AuctionWindow:         0,
+		MaxBundleHeightsAhead: 0,
+		MinBid:                0,
+		BundleRetainHeights:   0,
+		BundleTTLDuration:     0,
+		BundleTTLNumBlocks:    0,
+		BundlePurgeInterval:   0,
+
+		CacheSize:  10000,
+		CacheScope: SidecarCacheScopeHeight,
+
+		MaxTxs:     5000,
+		MaxBytes:   1024 * 1024 * 1024, // 1GB
+		MaxBundles: 1000,
+		MaxMemory:  2 * 1024 * 1024 * 1024, // 2GB
+
+		MinBundleSize: 1,
+		MaxBundleSize: 0,
+
+		CheckProposalInvariants: true,
+
+		SelfBuild:             false,
+		SelfBuildMaxTxs:       10,
+		SelfBuildFeeAttribute: "tx.fee",
+
+		ProtectedTxPrefixes:  "",
+		ProtectedTxAttribute: "",
+
+		SimulateProposals:   false,
+		SimulationQueryPath: "/mev/simulate_proposal",
+
+		CheckBundles:         false,
+		CheckBundleQueryPath: "/mev/check_bundle",
+
+		BundleReceipts:       false,
+		PushReceipts:         false,
+		ReceiptRetainHeights: 1000,
+
+		StallBlocks:  0,
+		StallTimeout: 0,
+
+		LogSampleInterval: time.Second,
+		LogSampleBurst:    0,
+
+		AuditLogPath:         "",
+		AuditLogMaxFileSize:  10 * 1024 * 1024,   // 10MB
+		AuditLogMaxTotalSize: 1024 * 1024 * 1024, // 1GB
+		MirrorAddr:           "",
+		MirrorRedactTxs:      false,
 	}
 }
 
-// no-op validation
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
 func (s *SidecarConfig) ValidateBasic() error {
+	if s.RelayerID != "" {
+		if err := validateNodeID(s.RelayerID); err != nil {
+			return fmt.Errorf("relayer_id: %w", err)
+		}
+	}
+	for _, id := range strings.Split(s.PersonalPeerIDs, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if err := validateNodeID(id); err != nil {
+			return fmt.Errorf("personal_peer_ids: %w", err)
+		}
+	}
+	if s.RelayAddr != "" {
+		id, err := validateNodeAddr(s.RelayAddr)
+		if err != nil {
+			return fmt.Errorf("relay_addr: %w", err)
+		}
+		if s.RelayerID != "" && id != s.RelayerID {
+			return fmt.Errorf("relay_addr: node ID %s doesn't match relayer_id %s", id, s.RelayerID)
+		}
+	}
+	if s.RelayAPIKey != "" && s.RelayPeerID() == "" {
+		return errors.New("relay_api_key is set, but neither relayer_id nor relay_addr is")
+	}
+	allowlist, err := s.RelayAllowlist()
+	if err != nil {
+		return fmt.Errorf("relay_pub_keys: %w", err)
+	}
+	if relayerID := s.RelayPeerID(); relayerID != "" && len(allowlist) > 0 {
+		allowed := false
+		for _, id := range allowlist {
+			allowed = allowed || id == relayerID
+		}
+		if !allowed {
+			return fmt.Errorf("the relay %s isn't any of relay_pub_keys", relayerID)
+		}
+	}
+	if s.RelayKeyFile != "" && s.RelayPeerID() == "" {
+		return errors.New("relay_key_file is set, but neither relayer_id nor relay_addr is")
+	}
+	for _, id := range s.SkipFormatPeerIDList() {
+		if err := validateNodeID(id); err != nil {
+			return fmt.Errorf("skip_format_peer_ids: %w", err)
+		}
+		if id != s.RelayPeerID() && !s.isPersonalPeer(id) {
+			return fmt.Errorf("skip_format_peer_ids: %s is neither the relay nor in personal_peer_ids", id)
+		}
+	}
+	if s.GRPCListenAddress != "" && len(allowlist) == 0 {
+		return errors.New("grpc_laddr is set, but relay_pub_keys isn't")
+	}
+	switch s.Mode {
+	case NodeModeValidator, NodeModeSentry, NodeModeRelay:
+	default:
+		return fmt.Errorf("unknown mode %q, expected %q, %q or %q",
+			s.Mode, NodeModeValidator, NodeModeSentry, NodeModeRelay)
+	}
+	if s.DryRun && !s.IncludesBundles() {
+		return fmt.Errorf("dry_run only applies to nodes in %q mode", NodeModeValidator)
+	}
+	if s.AuctionCutoff < 0 {
+		return errors.New("auction_cutoff can't be negative")
+	}
+	if s.AuctionWindow < 0 {
+		return errors.New("auction_window can't be negative")
+	}
+	if s.MaxBundleHeightsAhead < 0 {
+		return errors.New("max_bundle_heights_ahead can't be negative")
+	}
+	if s.MinBid < 0 {
+		return errors.New("min_bid can't be negative")
+	}
+	if s.BundleRetainHeights < 0 {
+		return errors.New("bundle_retain_heights can't be negative")
+	}
+	if s.BundleTTLDuration < 0 {
+		return errors.New("bundle_ttl_duration can't be negative")
+	}
+	if s.BundleTTLNumBlocks < 0 {
+		return errors.New("bundle_ttl_num_blocks can't be negative")
+	}
+	if s.BundlePurgeInterval < 0 {
+		return errors.New("bundle_purge_interval can't be negative")
+	}
+	if s.CacheSize < 0 {
+		return errors.New("cache_size can't be negative")
+	}
+	switch s.CacheScope {
+	case SidecarCacheScopeHeight, SidecarCacheScopeRetain:
+	default:
+		return fmt.Errorf("unknown cache_scope %q, expected %q or %q",
+			s.CacheScope, SidecarCacheScopeHeight, SidecarCacheScopeRetain)
+	}
+	if s.MaxTxs < 0 {
+		return errors.New("max_txs can't be negative")
+	}
+	if s.MaxBytes < 0 {
+		return errors.New("max_bytes can't be negative")
+	}
+	if s.MaxBundles < 0 {
+		return errors.New("max_bundles can't be negative")
+	}
+	if s.MinBundleSize < 1 {
+		return errors.New("min_bundle_size must be positive")
+	}
+	if s.MaxBundleSize < 0 {
+		return errors.New("max_bundle_size can't be negative")
+	}
+	if s.MaxBundleSize > 0 && s.MaxBundleSize < s.MinBundleSize {
+		return fmt.Errorf("max_bundle_size (%d) can't be under min_bundle_size (%d)", s.MaxBundleSize, s.MinBundleSize)
+	}
+	if s.MaxMemory < 0 {
+		return errors.New("max_memory can't be negative")
+	}
+	if s.MaxMemory > 0 && s.CacheMemBytes() >= s.MaxMemory {
+		return fmt.Errorf("max_memory (%d) must exceed the %d bytes set aside for the cache of cache_size (%d) txs",
+			s.MaxMemory, s.CacheMemBytes(), s.CacheSize)
+	}
+	if s.SelfBuildMaxTxs < 0 {
+		return errors.New("self_build_max_txs can't be negative")
+	}
+	if s.SelfBuild && !strings.Contains(s.SelfBuildFeeAttribute, ".") {
+		return fmt.Errorf("self_build_fee_attribute must be of the form type.key, got %q", s.SelfBuildFeeAttribute)
+	}
+	if _, err := s.ProtectedTxPrefixList(); err != nil {
+		return err
+	}
+	if s.ProtectedTxAttribute != "" && !strings.Contains(s.ProtectedTxAttribute, ".") {
+		return fmt.Errorf("protected_tx_attribute must be of the form type.key, got %q", s.ProtectedTxAttribute)
+	}
+	if s.SimulateProposals && !strings.HasPrefix(s.SimulationQueryPath, "/") {
+		return fmt.Errorf("simulation_query_path must start with /, got %q", s.SimulationQueryPath)
+	}
+	if s.CheckBundles && !strings.HasPrefix(s.CheckBundleQueryPath, "/") {
+		return fmt.Errorf("check_bundle_query_path must start with /, got %q", s.CheckBundleQueryPath)
+	}
+	if s.ReceiptRetainHeights < 0 {
+		return errors.New("receipt_retain_heights can't be negative")
+	}
+	if s.StallBlocks < 0 {
+		return errors.New("stall_blocks can't be negative")
+	}
+	if s.StallTimeout < 0 {
+		return errors.New("stall_timeout can't be negative")
+	}
+	if s.LogSampleBurst < 0 {
+		return errors.New("log_sample_burst can't be negative")
+	}
+	if s.LogSampleBurst > 0 && s.LogSampleInterval <= 0 {
+		return errors.New("log_sample_interval must be positive when sampling logs")
+	}
+	if s.AuditLogMaxFileSize <= 0 {
+		return errors.New("audit_log_max_file_size must be positive")
+	}
+	if s.AuditLogMaxTotalSize < 0 {
+		return errors.New("audit_log_max_total_size can't be negative")
+	}
+	if s.MirrorAddr != "" {
+		switch protocol := strings.SplitN(s.MirrorAddr, "://", 2)[0]; protocol {
+		case "unix", "tcp", "http", "https":
+		default:
+			return fmt.Errorf("mirror_addr must start with unix://, tcp://, http:// or https://, got %q", s.MirrorAddr)
+		}
+	}
+	return nil
+}
+
+// sidecarCacheEntryBytes is a rough estimate of the memory taken by each
+// entry of the sidecar's cache of seen txs: the tx hash, its list element and
+// its map entry.
+const sidecarCacheEntryBytes = 160
+
+// CacheMemBytes returns the memory the sidecar's cache of seen txs takes once
+// full, in bytes, which is set aside from MaxMemory.
+func (s *SidecarConfig) CacheMemBytes() int64 {
+	return int64(s.CacheSize) * sidecarCacheEntryBytes
+}
+
+// SidecarEnvPrefix prefixes the environment variables overriding the
+// settings of the [sidecar] section, eg. TM_SIDECAR_RELAY_API_KEY overrides
+// relay_api_key.
+const SidecarEnvPrefix = "TM_SIDECAR_"
+
+// BindSidecarEnv binds every setting of the [sidecar] section read by v to
+// its environment variable, see SidecarEnvPrefix, so a variable set overrides
+// the config file, even if the setting is missing from it. This lets
+// deployments which can't template config.toml, eg. in containers, configure
+// the sidecar.
+func BindSidecarEnv(v *viper.Viper) {
+	for key := range sidecarConfigFields() {
+		if key == "home" {
+			continue
+		}
+		// BindEnv only fails without a key
+		_ = v.BindEnv("sidecar."+key, SidecarEnvPrefix+strings.ToUpper(key))
+	}
+}
+
+// LoadSidecarConfig reads the [sidecar] section of the config file under
+// rootDir, and the environment variables overriding it, over the defaults and
+// the MEV profile of the chain chainID, so it can be reloaded while the node
+// runs.
+func LoadSidecarConfig(rootDir, chainID string) (*SidecarConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(filepath.Join(rootDir, defaultConfigFilePath))
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	BindSidecarEnv(v)
+	conf := DefaultConfig()
+	if err := v.Unmarshal(conf); err != nil {
+		return nil, fmt.Errorf("failed to decode config file: %w", err)
+	}
+	conf.SetRoot(rootDir)
+	if _, err := conf.Sidecar.ApplyProfile(chainID); err != nil {
+		return nil, err
+	}
+	if err := conf.Sidecar.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("error in [sidecar] config: %w", err)
+	}
+	return conf.Sidecar, nil
+}
+
+// UpdateSidecarConfigFile applies update to the [sidecar] section of the
+// config file under rootDir, and writes the file back if it's still valid, so
+// changes made while the node runs outlive it. The file is rewritten from the
+// template, so comments and settings unknown to this version are lost.
+// Settings overridden by environment variables keep being overridden.
+func UpdateSidecarConfigFile(rootDir string, update func(*SidecarConfig) error) error {
+	path := filepath.Join(rootDir, defaultConfigFilePath)
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	conf := DefaultConfig()
+	if err := v.Unmarshal(conf); err != nil {
+		return fmt.Errorf("failed to decode config file: %w", err)
+	}
+	conf.SetRoot(rootDir)
+	if err := update(conf.Sidecar); err != nil {
+		return err
+	}
+	if err := conf.Sidecar.ValidateBasic(); err != nil {
+		return fmt.Errorf("error in [sidecar] config: %w", err)
+	}
+
+	var buffer bytes.Buffer
+	if err := configTemplate.Execute(&buffer, conf); err != nil {
+		return fmt.Errorf("failed to render config file: %w", err)
+	}
+	if err := tempfile.WriteFileAtomic(path, buffer.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// validateNodeID returns an error if id isn't a hex encoded node ID, see
+// p2p.ID.
+func validateNodeID(id string) error {
+	if len(id) != 2*nodeIDByteLength {
+		return fmt.Errorf("invalid node ID %q, expected %d hex characters", id, 2*nodeIDByteLength)
+	}
+	if _, err := hex.DecodeString(id); err != nil {
+		return fmt.Errorf("invalid node ID %q: %w", id, err)
+	}
 	return nil
 }
 
+// validateNodeAddr returns the ID of addr, or an error if it isn't of the form
+// id@host:port, see p2p.NetAddress.
+func validateNodeAddr(addr string) (string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(addr, "tcp://"), "@", 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid address %q, expected id@host:port", addr)
+	}
+	if err := validateNodeID(parts[0]); err != nil {
+		return "", err
+	}
+	host, port, err := net.SplitHostPort(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if host == "" || port == "" {
+		return "", fmt.Errorf("invalid address %q, expected id@host:port", addr)
+	}
+	return parts[0], nil
+}
+
+// IncludesBundles returns true if this node includes bundles in its
+// proposals, ie. it's in NodeModeValidator.
+func (s *SidecarConfig) IncludesBundles() bool {
+	return s.Mode == NodeModeValidator
+}
+
+// RelayPeerID returns the ID of the relay: RelayerID, or the ID of RelayAddr
+// if it's left empty, or "" if no relay is configured.
+func (s *SidecarConfig) RelayPeerID() string {
+	if s.RelayerID != "" || s.RelayAddr == "" {
+		return s.RelayerID
+	}
+	id, err := validateNodeAddr(s.RelayAddr)
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+// RelayAllowlist returns the node IDs of the relays allowed by RelayPubKeys,
+// nil if any relay is.
+func (s *SidecarConfig) RelayAllowlist() ([]string, error) {
+	var ids []string
+	for _, key := range strings.Split(s.RelayPubKeys, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		bz, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %w", key, err)
+		}
+		if len(bz) != ed25519.PubKeySize {
+			return nil, fmt.Errorf("invalid public key %q: expected %d bytes, got %d", key, ed25519.PubKeySize, len(bz))
+		}
+		ids = append(ids, hex.EncodeToString(ed25519.PubKey(bz).Address()))
+	}
+	return ids, nil
+}
+
+// SkipFormatPeerIDList returns the node IDs of SkipFormatPeerIDs.
+func (s *SidecarConfig) SkipFormatPeerIDList() []string {
+	var ids []string
+	for _, id := range strings.Split(s.SkipFormatPeerIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// isPersonalPeer returns true if id is in PersonalPeerIDs.
+func (s *SidecarConfig) isPersonalPeer(id string) bool {
+	for _, peerID := range strings.Split(s.PersonalPeerIDs, ",") {
+		if strings.TrimSpace(peerID) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// RelayKeyFilePath returns the full path to the relay key file, "" if there's
+// none.
+func (s *SidecarConfig) RelayKeyFilePath() string {
+	if s.RelayKeyFile == "" {
+		return ""
+	}
+	return rootify(s.RelayKeyFile, s.RootDir)
+}
+
+// AddrBookFilePath returns the full path to the sidecar address book, "" if
+// there's none.
+func (s *SidecarConfig) AddrBookFilePath() string {
+	if s.AddrBookFile == "" {
+		return ""
+	}
+	return rootify(s.AddrBookFile, s.RootDir)
+}
+
+// AuditLogFile returns the full path to the bundle audit log.
+func (s *SidecarConfig) AuditLogFile() string {
+	return rootify(s.AuditLogPath, s.RootDir)
+}
+
+// ProtectedTxPrefixList returns the decoded ProtectedTxPrefixes.
+func (s *SidecarConfig) ProtectedTxPrefixList() ([][]byte, error) {
+	prefixes := make([][]byte, 0)
+	for _, prefix := range strings.Split(s.ProtectedTxPrefixes, ",") {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" {
+			continue
+		}
+		bz, err := hex.DecodeString(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("protected_tx_prefixes: invalid hex prefix %q: %w", prefix, err)
+		}
+		prefixes = append(prefixes, bz)
+	}
+	return prefixes, nil
+}
+
 //-----------------------------------------------------------------------------
 // InstrumentationConfig
 
 // InstrumentationConfig defines the configuration for metrics reporting.
 type InstrumentationConfig struct {
+	RootDir string `mapstructure:"home"`
+
 	// When true, Prometheus metrics are served under /metrics on
 	// PrometheusListenAddr.
 	// Check out the documentation for the list of available metrics.
@@ -1135,8 +2241,61 @@ type InstrumentationConfig struct {
 
 	// Instrumentation namespace.
 	Namespace string `mapstructure:"namespace"`
+
+	// OpenTelemetry collector the spans tracing sidecar bundles, from their
+	// txs being received to their inclusion in a proposal, are exported to,
+	// as host:port of its OTLP/HTTP receiver. Tracing is disabled if empty.
+	TracingEndpoint string `mapstructure:"tracing_endpoint"`
+
+	// Export spans over plain HTTP instead of HTTPS.
+	TracingInsecure bool `mapstructure:"tracing_insecure"`
+
+	// Fraction of the bundles traced, between 0 and 1.
+	TracingSampleRate float64 `mapstructure:"tracing_sample_rate"`
+
+	// Role of the node in the MEV pipeline, one of NodeModeValidator,
+	// NodeModeSentry or NodeModeRelay, set as the node_mode label of the
+	// mempool and state metrics. If empty, it's SidecarConfig.Mode for
+	// sentries and relays, and otherwise the node is a validator if its
+	// validator key is in the validator set, a sentry otherwise.
+	NodeMode string `mapstructure:"node_mode"`
+
+	// When reaping the txs of a proposal and running its auction takes longer
+	// than ProfileThreshold, a heap profile and a CPU profile of the next
+	// ProfileCPUDuration are written to ProfileDir. 0 disables profiling.
+	ProfileThreshold   time.Duration `mapstructure:"profile_threshold"`
+	ProfileCPUDuration time.Duration `mapstructure:"profile_cpu_duration"`
+	ProfileDir         string        `mapstructure:"profile_dir"`
+
+	// Address (host:port) of a statsd or DogStatsD agent the mempool and
+	// state metrics, which include the sidecar's and the auctions', are
+	// pushed to over UDP, for nodes whose metrics can't be scraped. Pushing
+	// is disabled if empty.
+	StatsdAddr string `mapstructure:"statsd_addr"`
+
+	// Protocol spoken by the agent, StatsdProtocolStatsd or
+	// StatsdProtocolDogStatsd. DogStatsD carries the metrics' labels as tags,
+	// plain statsd appends their values to the metrics' names.
+	StatsdProtocol string `mapstructure:"statsd_protocol"`
+
+	// How often metrics are pushed to StatsdAddr.
+	StatsdInterval time.Duration `mapstructure:"statsd_interval"`
 }
 
+// Roles of the node in the MEV pipeline, see SidecarConfig.Mode and
+// InstrumentationConfig.NodeMode.
+const (
+	NodeModeValidator = "validator"
+	NodeModeSentry    = "sentry"
+	NodeModeRelay     = "relay"
+)
+
+// Protocols of the metrics agent, see InstrumentationConfig.StatsdProtocol.
+const (
+	StatsdProtocolStatsd    = "statsd"
+	StatsdProtocolDogStatsd = "dogstatsd"
+)
+
 // DefaultInstrumentationConfig returns a default configuration for metrics
 // reporting.
 func DefaultInstrumentationConfig() *InstrumentationConfig {
@@ -1145,6 +2304,16 @@ func DefaultInstrumentationConfig() *InstrumentationConfig {
 		PrometheusListenAddr: ":26660",
 		MaxOpenConnections:   3,
 		Namespace:            "tendermint",
+		TracingEndpoint:      "",
+		TracingInsecure:      false,
+		TracingSampleRate:    1,
+		NodeMode:             "",
+		ProfileThreshold:     0,
+		ProfileCPUDuration:   10 * time.Second,
+		ProfileDir:           "debug",
+		StatsdAddr:           "",
+		StatsdProtocol:       StatsdProtocolStatsd,
+		StatsdInterval:       10 * time.Second,
 	}
 }
 
@@ -1160,9 +2329,39 @@ func (cfg *InstrumentationConfig) ValidateBasic() error {
 	if cfg.MaxOpenConnections < 0 {
 		return errors.New("max_open_connections can't be negative")
 	}
+	if cfg.TracingSampleRate < 0 || cfg.TracingSampleRate > 1 {
+		return errors.New("tracing_sample_rate must be between 0 and 1")
+	}
+	switch cfg.NodeMode {
+	case "", NodeModeValidator, NodeModeSentry, NodeModeRelay:
+	default:
+		return fmt.Errorf("unknown node_mode %q, expected %q, %q or %q",
+			cfg.NodeMode, NodeModeValidator, NodeModeSentry, NodeModeRelay)
+	}
+	if cfg.ProfileThreshold < 0 {
+		return errors.New("profile_threshold can't be negative")
+	}
+	if cfg.ProfileCPUDuration <= 0 {
+		return errors.New("profile_cpu_duration must be positive")
+	}
+	switch cfg.StatsdProtocol {
+	case StatsdProtocolStatsd, StatsdProtocolDogStatsd:
+	default:
+		return fmt.Errorf("unknown statsd_protocol %q, expected %q or %q",
+			cfg.StatsdProtocol, StatsdProtocolStatsd, StatsdProtocolDogStatsd)
+	}
+	if cfg.StatsdInterval <= 0 {
+		return errors.New("statsd_interval must be positive")
+	}
 	return nil
 }
 
+// ProfileDirPath returns the full path to the directory profiles are written
+// to.
+func (cfg *InstrumentationConfig) ProfileDirPath() string {
+	return rootify(cfg.ProfileDir, cfg.RootDir)
+}
+
 //-----------------------------------------------------------------------------
 // Utils
 
@@ -1,12 +1,19 @@
 package config
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -35,11 +42,55 @@ func TestConfigValidateBasic(t *testing.T) {
 	cfg := DefaultConfig()
 	assert.NoError(t, cfg.ValidateBasic())
 
+	// replace-by-fee and self-build read fees from different attributes
+	cfg.Mempool.SenderAttribute, cfg.Mempool.SequenceAttribute = "tx.sender", "tx.sequence"
+	cfg.Mempool.ReplaceByFee = true
+	cfg.Mempool.FeeAttribute = "tx.tip"
+	cfg.Sidecar.SelfBuild = true
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.Mempool.FeeAttribute = cfg.Sidecar.SelfBuildFeeAttribute
+	assert.NoError(t, cfg.ValidateBasic())
+
 	// tamper with timeout_propose
 	cfg.Consensus.TimeoutPropose = -10 * time.Second
 	assert.Error(t, cfg.ValidateBasic())
 }
 
+func TestConfigValidateMEV(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Consensus.TimeoutCommit = time.Second
+	assert.NoError(t, cfg.ValidateMEV())
+
+	// auctions are timed against timeout_commit
+	cfg.Sidecar.AuctionCutoff = time.Second
+	assert.Error(t, cfg.ValidateMEV())
+	cfg.Sidecar.AuctionCutoff = 200 * time.Millisecond
+	assert.NoError(t, cfg.ValidateMEV())
+	cfg.Sidecar.AuctionWindow = 2 * time.Second
+	assert.Error(t, cfg.ValidateMEV())
+	cfg.Sidecar.AuctionWindow = 800 * time.Millisecond
+	assert.NoError(t, cfg.ValidateMEV())
+	cfg.Consensus.SkipTimeoutCommit = true
+	assert.Error(t, cfg.ValidateMEV())
+	// which only matters to nodes including bundles
+	cfg.Sidecar.Mode = NodeModeSentry
+	assert.NoError(t, cfg.ValidateMEV())
+	cfg.Consensus.SkipTimeoutCommit = false
+
+	// the pipeline can't stall between blocks
+	cfg.Sidecar.StallTimeout = time.Second
+	assert.Error(t, cfg.ValidateMEV())
+	cfg.Sidecar.StallTimeout = time.Minute
+	assert.NoError(t, cfg.ValidateMEV())
+
+	// bundles can't expire before their height
+	cfg.Sidecar.MaxBundleHeightsAhead = 5
+	cfg.Sidecar.BundleTTLNumBlocks = 4
+	assert.Error(t, cfg.ValidateMEV())
+	cfg.Sidecar.BundleTTLNumBlocks = 5
+	assert.NoError(t, cfg.ValidateMEV())
+}
+
 func TestTLSConfiguration(t *testing.T) {
 	assert := assert.New(t)
 	cfg := DefaultConfig()
@@ -84,6 +135,15 @@ func TestRPCConfigValidateBasic(t *testing.T) {
 		assert.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	// bundle submission is served on laddr only if opted in
+	cfg.EthSendBundle = true
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.BundleRoutesOnListenAddress = true
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.BundleRoutesOnListenAddress = false
+	cfg.BundleListenAddress = "tcp://127.0.0.1:36659"
+	assert.NoError(t, cfg.ValidateBasic())
 }
 
 func TestP2PConfigValidateBasic(t *testing.T) {
@@ -115,6 +175,15 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 		"MaxTxsBytes",
 		"CacheSize",
 		"MaxTxBytes",
+		"CheckTxWorkers",
+		"CheckTxResultCacheSize",
+		"MaxPendingCheckTxs",
+		"CheckTxTimeout",
+		"PeerMuteDuration",
+		"MaxTxsPerSender",
+		"TTLDuration",
+		"TTLNumBlocks",
+		"ReapTimeBudget",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -122,6 +191,117 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 		assert.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	cfg.CheckTxWorkers = 4
+	cfg.CheckTxQueueSize = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.CheckTxQueueSize = 1
+	cfg.DeterministicReap = true
+	cfg.ReapTimeBudget = time.Second
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.DeterministicReap = false
+	cfg.ShedGossipTxs = true
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.CheckTxWorkers = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.ShedGossipTxs = false
+
+	cfg.CacheShards = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.CacheShards = 4
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.PeerCheckTxRate = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.PeerCheckTxRate = 100
+	cfg.PeerCheckTxBurst = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.PeerCheckTxBurst = 100
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.CheckTxBatch = true
+	cfg.CheckTxBatchQueryPath = "mev/check_tx_batch"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.CheckTxBatchQueryPath = "/mev/check_tx_batch"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.RecheckBatch = true
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.CheckTxBatch = false
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RecheckBatch = false
+
+	cfg.SenderAttribute = "tx.sender"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.SequenceAttribute = "sequence"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.SequenceAttribute = "tx.sequence"
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.MaxTxsPerSender = 16
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.PriorityAttribute = "priority"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.PriorityAttribute = "tx.priority"
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.ReplaceByFee = true
+	cfg.FeeAttribute = "fee"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.FeeAttribute = "tx.fee"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.SenderAttribute, cfg.SequenceAttribute = "", ""
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.ReplaceByFee = false
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MaxTxsPerSender = 0
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.Lanes = "oracle:5,default:0:100"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.LaneAttribute = "lane"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.LaneAttribute = "tx.lane"
+	for _, lanes := range []string{"oracle", "oracle:high", "Oracle:1", "oracle:1,oracle:2", "mev:1:10", "bulk:1:-1"} {
+		cfg.Lanes = lanes
+		assert.Error(t, cfg.ValidateBasic(), lanes)
+	}
+	cfg.Lanes = "a:1,b:1,c:1,d:1,e:1,f:1,g:1,h:1"
+	assert.Error(t, cfg.ValidateBasic())
+}
+
+func TestMempoolConfigLaneList(t *testing.T) {
+	cfg := TestMempoolConfig()
+	lanes, err := cfg.LaneList()
+	require.NoError(t, err)
+	assert.Equal(t, []LaneConfig{{Name: DefaultLane}, {Name: MEVLane, Priority: 1}}, lanes)
+
+	cfg.Lanes = " oracle:5 , mev:3, bulk:-1:50"
+	lanes, err = cfg.LaneList()
+	require.NoError(t, err)
+	assert.Equal(t, []LaneConfig{
+		{Name: "oracle", Priority: 5},
+		{Name: MEVLane, Priority: 3},
+		{Name: "bulk", Priority: -1, MaxTxs: 50},
+		{Name: DefaultLane},
+	}, lanes)
+}
+
+func TestMempoolConfigPrivateOriginList(t *testing.T) {
+	cfg := TestMempoolConfig()
+	origins, err := cfg.PrivateOriginList()
+	require.NoError(t, err)
+	assert.Empty(t, origins)
+
+	cfg.PrivateOrigins = " rpc, relay"
+	origins, err = cfg.PrivateOriginList()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rpc", "relay"}, origins)
+
+	cfg.PrivateOrigins = "rpc,wal"
+	_, err = cfg.PrivateOriginList()
+	assert.Error(t, err)
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 func TestStateSyncConfigValidateBasic(t *testing.T) {
@@ -190,4 +370,309 @@ func TestInstrumentationConfigValidateBasic(t *testing.T) {
 	// tamper with maximum open connections
 	cfg.MaxOpenConnections = -1
 	assert.Error(t, cfg.ValidateBasic())
+
+	// tamper with the tracing sample rate
+	cfg = TestInstrumentationConfig()
+	cfg.TracingSampleRate = 1.5
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.TracingSampleRate = -0.1
+	assert.Error(t, cfg.ValidateBasic())
+
+	// tamper with the node mode
+	cfg = TestInstrumentationConfig()
+	cfg.NodeMode = "builder"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.NodeMode = NodeModeSentry
+	assert.NoError(t, cfg.ValidateBasic())
+
+	// tamper with the profiling settings
+	cfg = TestInstrumentationConfig()
+	cfg.ProfileThreshold = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.ProfileThreshold = time.Second
+	cfg.ProfileCPUDuration = 0
+	assert.Error(t, cfg.ValidateBasic())
+
+	// tamper with the statsd settings
+	cfg = TestInstrumentationConfig()
+	cfg.StatsdProtocol = "graphite"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.StatsdProtocol = StatsdProtocolDogStatsd
+	cfg.StatsdInterval = 0
+	assert.Error(t, cfg.ValidateBasic())
+}
+
+func TestSidecarConfigValidateBasic(t *testing.T) {
+	cfg := TestSidecarConfig()
+	assert.NoError(t, cfg.ValidateBasic())
+
+	// tamper with relay peers
+	cfg.RelayerID = "relayer"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RelayerID = "7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e"
+	cfg.PersonalPeerIDs = "d2b7b8a9c6e0f4f5e1a0c9d8b7a6f5e4d3c2b1a0, sentry"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.PersonalPeerIDs = "d2b7b8a9c6e0f4f5e1a0c9d8b7a6f5e4d3c2b1a0, "
+	assert.NoError(t, cfg.ValidateBasic())
+
+	// tamper with the relay registration
+	cfg.RelayAddr = "7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e@relay.example.com"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RelayAddr = "d2b7b8a9c6e0f4f5e1a0c9d8b7a6f5e4d3c2b1a0@relay.example.com:26656"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RelayAddr = "7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e@relay.example.com:26656"
+	cfg.RelayAPIKey = "key"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.RelayerID = ""
+	assert.NoError(t, cfg.ValidateBasic())
+	assert.Equal(t, "7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e", cfg.RelayPeerID())
+	cfg.RelayAddr = ""
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RelayAPIKey = ""
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.RelayKeyFile = "config/relay_key.json"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RelayKeyFile = ""
+
+	// tamper with the relay allowlist
+	relayKey := ed25519.GenPrivKey().PubKey()
+	otherKey := ed25519.GenPrivKey().PubKey()
+	cfg.RelayPubKeys = "not base64"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RelayPubKeys = base64.StdEncoding.EncodeToString([]byte("too short"))
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RelayPubKeys = base64.StdEncoding.EncodeToString(otherKey.Bytes())
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.RelayerID = hex.EncodeToString(relayKey.Address())
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RelayPubKeys += ", " + base64.StdEncoding.EncodeToString(relayKey.Bytes())
+	assert.NoError(t, cfg.ValidateBasic())
+	allowlist, err := cfg.RelayAllowlist()
+	require.NoError(t, err)
+	assert.Equal(t, []string{hex.EncodeToString(otherKey.Address()), cfg.RelayerID}, allowlist)
+	cfg.RelayPubKeys = ""
+	cfg.RelayerID = ""
+
+	// tamper with the peers speaking Skip's format
+	cfg.SkipFormatPeerIDs = "not an id"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.SkipFormatPeerIDs = "7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e"
+	assert.Error(t, cfg.ValidateBasic())
+	personalPeerIDs := cfg.PersonalPeerIDs
+	cfg.PersonalPeerIDs += ", 7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e"
+	assert.NoError(t, cfg.ValidateBasic())
+	assert.Equal(t, []string{"7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e"}, cfg.SkipFormatPeerIDList())
+	cfg.SkipFormatPeerIDs = ""
+	cfg.PersonalPeerIDs = personalPeerIDs
+
+	// tamper with the gRPC submission service
+	cfg.GRPCListenAddress = "tcp://127.0.0.1:26660"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RelayPubKeys = base64.StdEncoding.EncodeToString(relayKey.Bytes())
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.RelayPubKeys = ""
+	cfg.GRPCListenAddress = ""
+
+	// tamper with the mode
+	cfg.Mode = ""
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.Mode = NodeModeRelay
+	assert.NoError(t, cfg.ValidateBasic())
+	assert.False(t, cfg.IncludesBundles())
+	cfg.DryRun = true
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.Mode = NodeModeValidator
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.DryRun = false
+
+	// tamper with auction cutoff
+	cfg.AuctionCutoff = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.AuctionCutoff = 0
+
+	// tamper with auction timing settings
+	cfg.AuctionWindow = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.AuctionWindow = 0
+	cfg.MaxBundleHeightsAhead = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MaxBundleHeightsAhead = 0
+	cfg.MinBid = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MinBid = 0
+	cfg.BundleRetainHeights = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.BundleRetainHeights = 0
+	cfg.BundleTTLDuration = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.BundleTTLDuration = 0
+	cfg.BundleTTLNumBlocks = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.BundleTTLNumBlocks = 0
+	cfg.BundlePurgeInterval = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.BundlePurgeInterval = 0
+
+	// tamper with cache settings
+	cfg.CacheSize = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.CacheSize = 0
+	cfg.CacheScope = "forever"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.CacheScope = SidecarCacheScopeRetain
+	assert.NoError(t, cfg.ValidateBasic())
+
+	// tamper with capacity settings
+	cfg.MaxTxs = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MaxTxs = 0
+	cfg.MaxBytes = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MaxBytes = 0
+	cfg.MaxBundles = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MaxBundles = 0
+	cfg.MaxMemory = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.CacheSize = 100
+	cfg.MaxMemory = cfg.CacheMemBytes()
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MaxMemory = cfg.CacheMemBytes() + 1
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.CacheSize = 0
+	cfg.MaxMemory = 0
+	assert.NoError(t, cfg.ValidateBasic())
+
+	// tamper with bundle size settings
+	cfg.MinBundleSize = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MinBundleSize = 2
+	cfg.MaxBundleSize = 1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MaxBundleSize = 2
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.MinBundleSize, cfg.MaxBundleSize = 1, 0
+
+	// tamper with self-build settings
+	cfg.SelfBuildMaxTxs = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.SelfBuildMaxTxs = 1
+	cfg.SelfBuild = true
+	cfg.SelfBuildFeeAttribute = "fee"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.SelfBuildFeeAttribute = "tx.fee"
+
+	// tamper with protected tx settings
+	cfg.ProtectedTxPrefixes = "0a01, zz"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.ProtectedTxPrefixes = "0a01, 0a02"
+	cfg.ProtectedTxAttribute = "protected"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.ProtectedTxAttribute = "tx.protected"
+
+	// tamper with simulation settings
+	cfg.SimulateProposals = true
+	cfg.SimulationQueryPath = "simulate"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.SimulationQueryPath = "/simulate"
+
+	// tamper with bundle check settings
+	cfg.CheckBundles = true
+	cfg.CheckBundleQueryPath = "check"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.CheckBundleQueryPath = "/check"
+
+	// tamper with receipt settings
+	cfg.ReceiptRetainHeights = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.ReceiptRetainHeights = 0
+
+	// tamper with stall detection settings
+	cfg.StallBlocks = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.StallBlocks = 10
+	cfg.StallTimeout = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.StallTimeout = time.Minute
+	assert.NoError(t, cfg.ValidateBasic())
+
+	// tamper with log sampling settings
+	cfg.LogSampleBurst = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.LogSampleBurst = 10
+	cfg.LogSampleInterval = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.LogSampleInterval = time.Second
+	assert.NoError(t, cfg.ValidateBasic())
+
+	// tamper with audit log settings
+	cfg.AuditLogMaxFileSize = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.AuditLogMaxFileSize = 1024
+	cfg.AuditLogMaxTotalSize = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.AuditLogMaxTotalSize = 0
+	assert.NoError(t, cfg.ValidateBasic())
+
+	// tamper with the mirror sink
+	cfg.MirrorAddr = "udp://127.0.0.1:9000"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MirrorAddr = "unix:///var/run/mev-mirror.sock"
+	assert.NoError(t, cfg.ValidateBasic())
+}
+
+func TestSidecarConfigApplyProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mev_profile_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	relayAddr := "7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e@relay.example.com:26656"
+	RegisterMEVProfile("compiled-chain", MEVProfile{"relay_addr": relayAddr, "max_txs": 500})
+	defer delete(mevProfiles, "compiled-chain")
+
+	cfg := TestSidecarConfig()
+	cfg.RootDir = dir
+	ok, err := cfg.ApplyProfile("unknown-chain")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, TestSidecarConfig().RelayAddr, cfg.RelayAddr)
+
+	// the compiled in profile fills in the settings left at their defaults
+	cfg.MaxTxs = 100
+	ok, err = cfg.ApplyProfile("compiled-chain")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, relayAddr, cfg.RelayAddr)
+	assert.EqualValues(t, 100, cfg.MaxTxs)
+
+	// a profile in the file replaces the compiled in one
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, defaultConfigDir), 0700))
+	require.NoError(t, ioutil.WriteFile(cfg.ProfilesFilePath(), []byte(`
+["compiled-chain"]
+auction_window = "3s"
+
+["file-chain.1"]
+min_bid = 10
+`), 0600))
+	cfg = TestSidecarConfig()
+	cfg.RootDir = dir
+	ok, err = cfg.ApplyProfile("compiled-chain")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, cfg.AuctionWindow)
+	assert.Empty(t, cfg.RelayAddr)
+	ok, err = cfg.ApplyProfile("file-chain.1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 10, cfg.MinBid)
+
+	// unknown settings are refused, as are malformed files
+	require.NoError(t, ioutil.WriteFile(cfg.ProfilesFilePath(), []byte(`
+["file-chain"]
+relay = "relay.example.com"
+`), 0600))
+	_, err = cfg.ApplyProfile("file-chain")
+	assert.Error(t, err)
+	require.NoError(t, ioutil.WriteFile(cfg.ProfilesFilePath(), []byte(`["file-chain"`), 0600))
+	_, err = cfg.ApplyProfile("file-chain")
+	assert.Error(t, err)
 }
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/viper"
+)
+
+// MEVProfile holds the [sidecar] settings suited to a network, by their keys
+// in config.toml, eg. "relay_addr" or "auction_cutoff".
+type MEVProfile map[string]interface{}
+
+// mevProfiles are the profiles compiled in, by chain ID.
+var mevProfiles = make(map[string]MEVProfile)
+
+// RegisterMEVProfile compiles in profile for the network chainID, for builds
+// made for it. A profile for chainID in SidecarConfig.ProfilesFile replaces
+// it. Not safe for concurrent use: it's meant to be called from init.
+func RegisterMEVProfile(chainID string, profile MEVProfile) {
+	mevProfiles[chainID] = profile
+}
+
+// ProfilesFilePath returns the full path to the file of MEV profiles, or ""
+// if there's none.
+func (s *SidecarConfig) ProfilesFilePath() string {
+	if s.ProfilesFile == "" {
+		return ""
+	}
+	return rootify(s.ProfilesFile, s.RootDir)
+}
+
+// ApplyProfile sets the settings left at their defaults to those of the MEV
+// profile of the network chainID, if there's one: read from ProfilesFile, or
+// else compiled in. Settings changed from their defaults win, so the profile
+// only fills in what the operator didn't tune. It returns false if there's no
+// profile for chainID.
+func (s *SidecarConfig) ApplyProfile(chainID string) (bool, error) {
+	v, err := s.loadProfile(chainID)
+	if v == nil || err != nil {
+		return false, err
+	}
+
+	profile := DefaultSidecarConfig()
+	if err := v.Unmarshal(profile); err != nil {
+		return false, fmt.Errorf("invalid MEV profile for chain %s: %w", chainID, err)
+	}
+	defaults := DefaultSidecarConfig()
+	fields := sidecarConfigFields()
+	for _, key := range v.AllKeys() {
+		i, ok := fields[key]
+		if !ok || key == "home" || key == "profiles_file" {
+			return false, fmt.Errorf("invalid MEV profile for chain %s: unknown setting %q", chainID, key)
+		}
+		current := reflect.ValueOf(s).Elem().Field(i)
+		if reflect.DeepEqual(current.Interface(), reflect.ValueOf(defaults).Elem().Field(i).Interface()) {
+			current.Set(reflect.ValueOf(profile).Elem().Field(i))
+		}
+	}
+	return true, nil
+}
+
+// loadProfile returns the profile of chainID, or nil if there's none.
+func (s *SidecarConfig) loadProfile(chainID string) (*viper.Viper, error) {
+	profile, ok := mevProfiles[chainID]
+	if path := s.ProfilesFilePath(); path != "" {
+		var profiles map[string]MEVProfile
+		_, err := toml.DecodeFile(path, &profiles)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			// the file is optional
+		case err != nil:
+			return nil, fmt.Errorf("failed to read MEV profiles file: %w", err)
+		case profiles[chainID] != nil:
+			profile, ok = profiles[chainID], true
+		}
+	}
+	if !ok {
+		return nil, nil
+	}
+	v := viper.New()
+	if err := v.MergeConfigMap(profile); err != nil {
+		return nil, fmt.Errorf("invalid MEV profile for chain %s: %w", chainID, err)
+	}
+	return v, nil
+}
+
+// sidecarConfigFields returns the index of the fields of SidecarConfig, by
+// their keys in config.toml.
+func sidecarConfigFields() map[string]int {
+	t := reflect.TypeOf(SidecarConfig{})
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("mapstructure"), ",")[0]
+		fields[key] = i
+	}
+	return fields
+}
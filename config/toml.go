@@ -8,13 +8,18 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/BurntSushi/toml"
+
 	tmos "github.com/tendermint/tendermint/libs/os"
 )
 
 // DefaultDirPerm is the default permissions used when creating directories.
 const DefaultDirPerm = 0700
 
-var configTemplate *template.Template
+var (
+	configTemplate         *template.Template
+	sidecarSectionTemplate *template.Template
+)
 
 func init() {
 	var err error
@@ -24,6 +29,9 @@ func init() {
 	if configTemplate, err = tmpl.Parse(defaultConfigTemplate); err != nil {
 		panic(err)
 	}
+	if sidecarSectionTemplate, err = template.New("sidecarSectionTemplate").Parse(sidecarConfigTemplate); err != nil {
+		panic(err)
+	}
 }
 
 /****** these are for production settings ***********/
@@ -66,6 +74,34 @@ func WriteConfigFile(configFilePath string, config *Config) {
 	tmos.MustWriteFile(configFilePath, buffer.Bytes(), 0644)
 }
 
+// AppendSidecarConfig appends the [sidecar] section, with its comments and
+// the settings of config, to the config file at configFilePath, so nodes set
+// up before MEV can be given one without rewriting the rest of the file. It
+// fails if the file already has a [sidecar] section.
+func AppendSidecarConfig(configFilePath string, config *Config) error {
+	bz, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		return err
+	}
+	var sections map[string]interface{}
+	if err := toml.Unmarshal(bz, &sections); err != nil {
+		return fmt.Errorf("failed to decode config file: %w", err)
+	}
+	if _, ok := sections["sidecar"]; ok {
+		return fmt.Errorf("%s already has a [sidecar] section", configFilePath)
+	}
+
+	buffer := bytes.NewBuffer(bz)
+	if len(bz) > 0 && !bytes.HasSuffix(bz, []byte("\n")) {
+		buffer.WriteString("\n")
+	}
+	buffer.WriteString("\n")
+	if err := sidecarSectionTemplate.Execute(buffer, config); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(configFilePath, buffer.Bytes(), 0644)
+}
+
 // Note: any changes to the comments/variables/mapstructure
 // must be reflected in the appropriate struct in config/config.go
 const defaultConfigTemplate = `# This is a TOML config file.
@@ -187,6 +223,26 @@ grpc_max_open_connections = {{ .RPC.GRPCMaxOpenConnections }}
 # Activate unsafe RPC commands like /dial_seeds and /unsafe_flush_mempool
 unsafe = {{ .RPC.Unsafe }}
 
+# Activate eth_sendBundle, taking bundles in the format of Flashbots'
+# eth_sendBundle (hex-encoded txs, a hex blockNumber for the auction height,
+# and optional min/max unix timestamps) into the sidecar, so searcher bots
+# written for Ethereum can submit bundles with few changes
+eth_send_bundle = {{ .RPC.EthSendBundle }}
+
+# Activate broadcast_bundle, taking bundle envelopes (proto-encoded
+# tendermint.mempool.BundleEnvelope) into the sidecar. Envelopes must be
+# signed, by a relay in [sidecar] relay_pub_keys if set
+broadcast_bundle = {{ .RPC.BroadcastBundle }}
+
+# TCP or UNIX socket address for the bundle submission endpoints above to be
+# served on, apart from the others, so they can be kept off a public listener
+bundle_laddr = "{{ .RPC.BundleListenAddress }}"
+
+# Serve the bundle submission endpoints on laddr too. One of bundle_laddr and
+# bundle_routes_on_laddr must be set to enable them, so a public RPC node
+# doesn't expose them by accident
+bundle_routes_on_laddr = {{ .RPC.BundleRoutesOnListenAddress }}
+
 # Maximum number of simultaneous connections (including WebSocket).
 # Does not include gRPC connections. See grpc_max_open_connections
 # If you want to accept a larger number than the default, make sure
@@ -261,6 +317,7 @@ tls_cert_file = "{{ .RPC.TLSCertFile }}"
 tls_key_file = "{{ .RPC.TLSKeyFile }}"
 
 # pprof listen address (https://golang.org/pkg/net/http/pprof)
+# A JSON dump of the sidecar's internals is also served at /debug/sidecar
 pprof_laddr = "{{ .RPC.PprofListenAddress }}"
 
 #######################################################
@@ -344,6 +401,10 @@ dial_timeout = "{{ .P2P.DialTimeout }}"
 
 recheck = {{ .Mempool.Recheck }}
 broadcast = {{ .Mempool.Broadcast }}
+
+# Directory of the mempool WAL, where accepted txs are persisted so they're
+# checked again and kept pending after a restart. The WAL of earlier releases
+# is renamed to wal.old on start. Leave empty to disable.
 wal_dir = "{{ js .Mempool.WalPath }}"
 
 # Maximum number of transactions in the mempool
@@ -357,6 +418,11 @@ max_txs_bytes = {{ .Mempool.MaxTxsBytes }}
 # Size of the cache (used to filter transactions we saw earlier) in transactions
 cache_size = {{ .Mempool.CacheSize }}
 
+# Number of shards the cache is split into, each with its own lock, so cache
+# checks don't become a point of contention under heavy CheckTx load. Each
+# shard holds cache_size / cache_shards txs. 1 doesn't split the cache.
+cache_shards = {{ .Mempool.CacheShards }}
+
 # Do not remove invalid transactions from the cache (default: false)
 # Set to true if it's not possible for any invalid transaction to become valid
 # again in the future.
@@ -371,6 +437,145 @@ max_tx_bytes = {{ .Mempool.MaxTxBytes }}
 # XXX: Unused due to https://github.com/tendermint/tendermint/issues/5796
 max_batch_bytes = {{ .Mempool.MaxBatchBytes }}
 
+# Number of workers admitting txs received from peers (CheckTx for the mempool,
+# bundle admission for the sidecar), so a slow app doesn't hold up the p2p
+# receive routines. Txs from each peer are still admitted in the order they
+# were received. 0 admits txs on the receive routines, one at a time.
+check_tx_workers = {{ .Mempool.CheckTxWorkers }}
+
+# Number of received txs queued per worker. Receiving from a peer blocks while
+# its worker's queue is full.
+check_tx_queue_size = {{ .Mempool.CheckTxQueueSize }}
+
+# Drop the mempool txs received from a peer while its worker's queue is full,
+# rather than blocking the peer's receive routine, so gossip is shed first when
+# the app is slow. Bundle txs on the sidecar channel are never dropped. Needs
+# check_tx_workers.
+shed_gossip_txs = {{ .Mempool.ShedGossipTxs }}
+
+# Number of CheckTx calls awaiting the app's response past which txs received
+# from peers are refused, instead of piling up on the mempool connection. Txs
+# submitted through the RPC are still checked. 0 doesn't limit pending calls.
+max_pending_check_txs = {{ .Mempool.MaxPendingCheckTxs }}
+
+# Time the app is given to check the txs received from a peer, or to accept a
+# received bundle, before they're refused and dropped from the cache so they
+# may be received again. This keeps a hung app from holding up the workers
+# admitting txs. 0 waits for the app however long it takes.
+check_tx_timeout = "{{ .Mempool.CheckTxTimeout }}"
+
+# Number of successful CheckTx results (gas wanted, priority) kept by tx hash.
+# A tx re-added while the state hasn't changed is not checked again, and bundle
+# txs already checked by the mempool get their gas wanted from here. 0 disables
+# the cache.
+check_tx_result_cache_size = {{ .Mempool.CheckTxResultCacheSize }}
+
+# Check the txs of each message received from a peer in a single round trip to
+# the app, instead of one CheckTx call per tx. The app must answer ABCI queries
+# on check_tx_batch_query_path, with the txs as a tendermint.types.Data message
+# in the query data, running CheckTx on each in order and returning the
+# varint-delimited ResponseCheckTx messages as the query value. Batching is only
+# used if the app answers an empty batch on start.
+check_tx_batch = {{ .Mempool.CheckTxBatch }}
+check_tx_batch_query_path = "{{ .Mempool.CheckTxBatchQueryPath }}"
+
+# Recheck the txs after a commit in a single round trip to the app, so large
+# mempools don't delay the next height, queried on check_tx_batch_query_path
+# followed by "/recheck" (eg. "/mev/check_tx_batch/recheck"). The commit waits
+# for it. Only batching speeds the recheck up: the app's connections are
+# served one request at a time, so rechecking in concurrent queries wouldn't.
+# Needs check_tx_batch.
+recheck_batch = {{ .Mempool.RecheckBatch }}
+
+# Number of txs per second each peer may submit through the mempool channel, so
+# one peer can't saturate the app's mempool connection. Peers may submit up to
+# peer_check_tx_burst txs at once. A peer going over its limit is muted for
+# peer_mute_duration: all txs it sends meanwhile are dropped. 0 doesn't limit
+# peers.
+peer_check_tx_rate = {{ .Mempool.PeerCheckTxRate }}
+peer_check_tx_burst = {{ .Mempool.PeerCheckTxBurst }}
+peer_mute_duration = "{{ .Mempool.PeerMuteDuration }}"
+
+# ttl-duration, if non-zero, defines the maximum amount of time a transaction
+# can exist for in the mempool.
+#
+# Note, if ttl-num-blocks is also defined, a transaction will be removed if it
+# has existed in the mempool at least ttl-num-blocks number of blocks or if it's
+# insertion time into the mempool is beyond ttl-duration.
+ttl-duration = "{{ .Mempool.TTLDuration }}"
+
+# ttl-num-blocks, if non-zero, defines the maximum number of blocks a transaction
+# can exist for in the mempool.
+#
+# Note, if ttl-duration is also defined, a transaction will be removed if it
+# has existed in the mempool at least ttl-num-blocks number of blocks or if
+# it's insertion time into the mempool is beyond ttl-duration.
+ttl-num-blocks = {{ .Mempool.TTLNumBlocks }}
+
+# Time reaping the txs of a proposal may take, so enormous mempools can't delay
+# the proposal past its round. Once spent, no more public txs are added to the
+# proposal; protected and bundle txs are reaped first and never left out. The
+# time taken is reported in the reap_duration_seconds metric. 0 means no limit.
+reap_time_budget = "{{ .Mempool.ReapTimeBudget }}"
+
+# Reap txs in an order depending only on the mempool's contents: txs otherwise
+# reaped in arrival order (eg. of equal priority) are ordered by hash instead,
+# so any node holding the same txs and bundles reaps the same proposal. The
+# hash of the last reaped set of txs (as a block's data hash) is logged, so
+# proposals can be reproduced and audited. Can't be used with
+# reap_time_budget, which cuts reaps short at varying points.
+deterministic_reap = {{ .Mempool.DeterministicReap }}
+
+# Reap the txs of each sender in sequence (nonce) order rather than in arrival
+# order, so blocks don't carry txs that revert for being out of order. The
+# sender and sequence of a tx are read from the sender_attribute and
+# sequence_attribute ("type.key") event attributes returned by CheckTx. Txs
+# without both keep their place. Leave both empty to disable.
+sender_attribute = "{{ .Mempool.SenderAttribute }}"
+sequence_attribute = "{{ .Mempool.SequenceAttribute }}"
+
+# Let a tx replace the pending tx of the same sender and sequence if it declares
+# a higher fee, instead of keeping both. The fee of a tx is read from the
+# fee_attribute ("type.key") event attribute returned by CheckTx (only its
+# leading integer is used). Replacements are gossiped like any new tx. Needs
+# sender_attribute and sequence_attribute, and the app to accept replacements in
+# CheckTx. If the sidecar self-builds blocks, fee_attribute must match
+# self_build_fee_attribute.
+replace_by_fee = {{ .Mempool.ReplaceByFee }}
+fee_attribute = "{{ .Mempool.FeeAttribute }}"
+
+# Maximum number of txs each sender may have pending in the mempool, so one
+# account can't take it all. Txs over it are rejected once checked, and may be
+# resubmitted when the sender's txs are committed. Needs sender_attribute and
+# sequence_attribute; txs without a sender aren't limited. 0 disables the limit.
+max_txs_per_sender = {{ .Mempool.MaxTxsPerSender }}
+
+# Reap txs in descending priority order rather than in arrival order, so the
+# block space left by bundles goes to the txs paying the most. The priority of
+# a tx is read from the priority_attribute ("type.key") integer event attribute
+# returned by CheckTx. Txs without it have priority 0. With sender_attribute and
+# sequence_attribute set, each sender's txs are still reaped in sequence order.
+# Leave empty to disable.
+priority_attribute = "{{ .Mempool.PriorityAttribute }}"
+
+# Lanes of txs, as comma separated name:priority[:max_txs] entries (eg.
+# "oracle:100:500,free:-1:1000"). Blocks are filled by descending lane
+# priority, each lane holding at most max_txs pending txs (0 or none for no
+# limit), and txs of each lane are gossiped on a channel of their own. The app
+# puts a tx in a lane by returning its name in the lane_attribute ("type.key")
+# event attribute from CheckTx; other txs go to the built-in "default" lane
+# (priority 0). The built-in "mev" lane (priority 1) holds the sidecar bundles,
+# whose limits are set in [sidecar]. Both may be listed to change their
+# priority. Protected txs are always reaped first. Up to 7 lanes can be added.
+lanes = "{{ .Mempool.Lanes }}"
+lane_attribute = "{{ .Mempool.LaneAttribute }}"
+
+# Origins whose txs are kept to this node, never gossiped to peers, as comma
+# separated names among "rpc" (submitted through the RPC), "p2p" (received on
+# the mempool channel), "sidecar" (received on the sidecar channel) and "relay"
+# (submitted by the relay). Eg. "rpc" keeps RPC submitted txs private.
+private_origins = "{{ .Mempool.PrivateOrigins }}"
+
 #######################################################
 ###         State Sync Configuration Options        ###
 #######################################################
@@ -497,9 +702,110 @@ max_open_connections = {{ .Instrumentation.MaxOpenConnections }}
 # Instrumentation namespace
 namespace = "{{ .Instrumentation.Namespace }}"
 
-#######################################################
+# OpenTelemetry collector the spans tracing sidecar bundles, from their txs
+# being received to their inclusion in a proposal, are exported to, as
+# host:port of its OTLP/HTTP receiver (eg. "localhost:4318").
+# Tracing is disabled if empty.
+tracing_endpoint = "{{ .Instrumentation.TracingEndpoint }}"
+
+# Export spans over plain HTTP instead of HTTPS.
+tracing_insecure = {{ .Instrumentation.TracingInsecure }}
+
+# Fraction of the bundles traced, between 0 and 1.
+tracing_sample_rate = {{ .Instrumentation.TracingSampleRate }}
+
+# Role of the node in the MEV pipeline: "validator", "sentry" or "relay". The
+# mempool and state metrics, which include the sidecar's and the auctions', are
+# labeled with it, and with the validator address of validators. If empty, the
+# node is a validator if its validator key is in the validator set, a sentry
+# otherwise.
+node_mode = "{{ .Instrumentation.NodeMode }}"
+
+# When reaping the txs of a proposal and running its auction takes longer than
+# profile_threshold, a heap profile, and a CPU profile of the next
+# profile_cpu_duration, are written to profile_dir, to diagnose latency spikes
+# at proposal time. 0 disables profiling.
+profile_threshold = "{{ .Instrumentation.ProfileThreshold }}"
+profile_cpu_duration = "{{ .Instrumentation.ProfileCPUDuration }}"
+profile_dir = "{{ js .Instrumentation.ProfileDir }}"
+
+# Address (host:port) of a statsd or DogStatsD agent the mempool and state
+# metrics, which include the sidecar's and the auctions', are pushed to over UDP
+# every statsd_interval, for nodes whose metrics can't be scraped, eg.
+# validators behind sentries. Works whether prometheus is enabled or not.
+# Pushing is disabled if empty.
+statsd_addr = "{{ .Instrumentation.StatsdAddr }}"
+
+# Protocol spoken by the agent: "statsd", which appends the values of the
+# metrics' labels to their names, or "dogstatsd", which sends them as tags.
+statsd_protocol = "{{ .Instrumentation.StatsdProtocol }}"
+statsd_interval = "{{ .Instrumentation.StatsdInterval }}"
+
+` + sidecarConfigTemplate
+
+// sidecarConfigTemplate is the [sidecar] section of the config file, which
+// AppendSidecarConfig adds to config files lacking it.
+const sidecarConfigTemplate = `#######################################################
 ###       Sidecar Configuration Options          ###
 #######################################################
+[sidecar]
+
+# The sidecar holds the bundles relays send for this node's MEV auctions, apart
+# from the mempool. Where bundles go in this node's proposals, relative to the
+# other lanes of txs, is set by the priority of the "mev" lane in [mempool]
+# lanes; the room they may take here by the capacity options below.
+#
+# personal_peer_ids, the relay options, min_bid and mev_disabled can be changed
+# without restarting the node: they're re-read from this file on SIGHUP, or
+# when the unsafe_reload_sidecar RPC endpoint is called. The other options only
+# take effect on restart. The unsafe_add_sidecar_peer,
+# unsafe_remove_sidecar_peer, unsafe_set_relay and unsafe_set_mev_disabled RPC
+# endpoints change them by rewriting this file, which drops any comments added
+# to it.
+#
+# Each option is overridden by the environment variable named after it in upper
+# case, prefixed with TM_SIDECAR_, eg. TM_SIDECAR_RELAY_API_KEY, if set, also
+# on reloads.
+
+##### enablement #####
+
+# Kill switch turning off all MEV functionality, so the node behaves like one
+# without MEV: sidecar messages are dropped, bundle txs refused, and no auction
+# is run nor bundle self-built. The setting is advertised to peers in the node
+# info, so relays and sentries stop sending bundles to this node. For an
+# emergency rollback without swapping binaries, it can be turned on while the
+# node runs, by the unsafe_set_mev_disabled RPC endpoint or a reload; peers only
+# see the change once they reconnect.
+mev_disabled = {{ .Sidecar.MEVDisabled }}
+
+# Run MEV in shadow mode, to evaluate it before enabling it: bundles are
+# received, checked and auctioned, and the auctions logged, counted in the
+# metrics and published as events as if their winners were included, but this
+# node's proposals are made without any bundle, nor receipts signed. Only for
+# validators.
+dry_run = {{ .Sidecar.DryRun }}
+
+# Role of this node in the MEV pipeline, which sets what it does with bundles:
+#   1) "validator" (default) - auctions them and includes the winners in its
+#     proposals
+#   2) "sentry" - passes them on to its sidecar peers, without checking them
+#     with the app nor ever including them
+#   3) "relay" - takes them from any peer, ie. searchers' submissions, not only
+#     its sidecar peers, and fans them out to its sidecar peers; validators'
+#     nodes register with it as set by their relay_api_key
+mode = "{{ .Sidecar.Mode }}"
+
+# File of MEV profiles, holding the settings of this section suited to each
+# network, as a table per chain ID, eg.
+#   ["my-chain-1"]
+#   relay_addr = "id@host:port"
+#   auction_window = "3s"
+# The options left at their defaults here are set to those of the profile of
+# the node's chain, read from this file, or else compiled in the binary. Options
+# changed here win. The file is optional; "" only uses the compiled in profiles.
+profiles_file = "{{ js .Sidecar.ProfilesFile }}"
+
+##### relay peers #####
 
 # comma separated list of peer ids that represent the nodes
 # you run that this node is aware of / can communicate with
@@ -507,7 +813,218 @@ namespace = "{{ .Instrumentation.Namespace }}"
 # nodes in your network receive auction-winning
 # txs when when your validator is the proposer)
 personal_peer_ids = "{{ .Sidecar.PersonalPeerIDs }}"
+
+# Node ID of the relay sending bundles to this node. It's kept private, like
+# p2p.private_peer_ids, and the bundle feed from it is monitored.
 relayer_id = "{{ .Sidecar.RelayerID }}"
+
+# Address of the relay, as id@host:port. If set, this node dials the relay and
+# keeps connected to it like to a persistent peer, and relayer_id may be left
+# empty (it must match the address's ID otherwise).
+relay_addr = "{{ .Sidecar.RelayAddr }}"
+
+# API key the relay issued to this validator. It's sent to the relay only, once
+# connected, to register with it; the relay authenticates the node itself by
+# its node key.
+relay_api_key = "{{ .Sidecar.RelayAPIKey }}"
+
+# File holding the key this node signs its registrations with the relay with,
+# eg. "config/relay_key.json", as generated by "tendermint gen-relay-key". The
+# key is used for nothing else, so it can be rotated apart from the node and
+# validator keys: generate a new file and reload this section. "" sends the
+# registrations unsigned.
+relay_key_file = "{{ js .Sidecar.RelayKeyFile }}"
+
+# Comma separated list of the node public keys of the relays this node may take
+# as its relay, base64-encoded ed25519 keys as published by the relay operator.
+# If set, relayer_id or relay_addr must be the ID of one of them, checked on
+# startup and on reload: sidecar messages from any other relay are refused, and
+# the API key isn't sent to it, guarding against a rogue relay impersonating
+# the official one. On validators behind sentries, set it on the sentries. ""
+# allows any relay.
+relay_pub_keys = "{{ .Sidecar.RelayPubKeys }}"
+
+# Comma separated list of the sidecar peers, the relay included, speaking Skip
+# Protocol's mev-tendermint message format rather than this node's, for
+# validators bridging the two MEV networks during a migration. Bundles from
+# them are translated into this node's, at a bid of 0 as that format carries
+# none, and they're gossiped txs in their format only: they're never pinged,
+# nor sent receipts or registrations. Each must be the relay or in
+# personal_peer_ids.
+skip_format_peer_ids = "{{ .Sidecar.SkipFormatPeerIDs }}"
+
+# TCP address the BundleSubmission gRPC service listens on, for the relay to
+# submit bundle envelopes directly rather than gossip them, as an alternative
+# transport with a reply for each bundle: added, already received, or refused,
+# and why. Only envelopes signed by a relay in relay_pub_keys are taken, which
+# must be set. Keep it reachable by the relay only, e.g. through the sentry.
+# Changing it requires a restart. "" disables it.
+grpc_laddr = "{{ .Sidecar.GRPCListenAddress }}"
+
+# File keeping the addresses of the sidecar peers and the relay, apart from the
+# P2P addr_book_file, so PEX never prunes them. Peers are recorded once
+# connected, and redialed whenever disconnected, backing off up to a minute
+# between dials but never giving up. "" disables it.
+addr_book_file = "{{ js .Sidecar.AddrBookFile }}"
+
+##### auctions #####
+
+# How long before the proposal timer fires (ie. before timeout_commit elapses)
+# bundles for the upcoming height stop being accepted into this node's auction.
+# Bundles arriving later are still gossiped, but are never reaped by this node
+# for that height, which keeps auction results stable. "0s" disables the cutoff.
+auction_cutoff = "{{ .Sidecar.AuctionCutoff }}"
+
+# Auction timing independent of the consensus timeouts, which must be left as
+# they are. auction_window is how long after a block is committed the auction
+# for the next height takes new bundles; once it elapses the selection is
+# locked. When set, it overrides auction_cutoff. "0s" keeps auction_cutoff.
+auction_window = "{{ .Sidecar.AuctionWindow }}"
+
+# How many heights past the upcoming auction bundles may target, eg. 5; bundles
+# for later heights are refused, bounding the bundles held. The limit is sent
+# to the relay when registering with it, so it knows the window to submit
+# bundles in. 0 means no limit.
+max_bundle_heights_ahead = {{ .Sidecar.MaxBundleHeightsAhead }}
+
+# Lowest bid a bundle may make to enter this node's auctions; bundle txs
+# bidding less are refused. 0 means no floor.
+min_bid = {{ .Sidecar.MinBid }}
+
+# How many heights after the one they targeted unused bundles are kept before
+# being purged. 0 purges them as soon as that height is committed.
+bundle_retain_heights = {{ .Sidecar.BundleRetainHeights }}
+
+# If non-zero, the maximum amount of time, or number of blocks, a bundle can
+# wait in the sidecar for its height, counted from its first tx. Expired bundles
+# are purged on the next block commit, like mempool txs past their ttl-duration
+# or ttl-num-blocks.
+bundle_ttl_duration = "{{ .Sidecar.BundleTTLDuration }}"
+bundle_ttl_num_blocks = {{ .Sidecar.BundleTTLNumBlocks }}
+
+# If non-zero, bundles past their bundle_ttl_duration are also purged this
+# often between block commits, which suits chains with long block times.
+# "0s" only purges them on commit.
+bundle_purge_interval = "{{ .Sidecar.BundlePurgeInterval }}"
+
+##### capacity #####
+
+# Size of the sidecar's cache of seen txs, used to drop duplicate bundle txs.
+# It's separate from the mempool cache, so private bundle txs never enter the
+# mempool's, and public gossip can't evict them. 0 disables the cache.
+cache_size = {{ .Sidecar.CacheSize }}
+
+# "height" clears the cache on every commit, so bundle txs may be sent again
+# for a later height. "retain" keeps entries until the cache is full.
+cache_scope = "{{ .Sidecar.CacheScope }}"
+
+# Limits of the sidecar, separate from the [mempool] ones, so bundles can't
+# take the mempool's room nor the other way round: the maximum number of txs,
+# their total size in bytes, and the number of bundles held across heights.
+# Bundle txs past any of them are refused until there's room again. 0 means no
+# limit.
+max_txs = {{ .Sidecar.MaxTxs }}
+max_bytes = {{ .Sidecar.MaxBytes }}
+max_bundles = {{ .Sidecar.MaxBundles }}
+
+# Smallest and largest number of txs a bundle may declare, as the bundle size
+# relays send is otherwise taken on trust. Txs of bundles declaring a size out
+# of range are refused, and dropped as soon as they're received. 0 means no
+# maximum.
+min_bundle_size = {{ .Sidecar.MinBundleSize }}
+max_bundle_size = {{ .Sidecar.MaxBundleSize }}
+
+# Memory the sidecar may take, in bytes, counting its txs plus an estimate of
+# the bookkeeping of each tx and bundle, and the room its cache takes once
+# full (about 160 bytes per cache_size entry), so a relay streaming giant or
+# never completed bundles can't exhaust the node's memory, and memory-bound
+# sentries can budget for MEV. Past it, incomplete bundles for later heights
+# than a new tx's are evicted to make room for it, or else the tx is refused.
+# 0 means no limit.
+max_memory = {{ .Sidecar.MaxMemory }}
+
+##### proposals #####
+
+# Re-check every proposal this node assembles: sidecar bundles must sit whole and
+# in order at the top of the block, no tx may appear twice, and the block must
+# stay within its byte and gas budget. Violations are logged and counted in the
+# state_proposal_invariant_violations metric. Costs a pass over the block txs.
+check_proposal_invariants = {{ .Sidecar.CheckProposalInvariants }}
+
+# When proposing without any complete bundle from a relay, build one from this
+# node's public mempool instead: up to self_build_max_txs txs, highest declared
+# fee first, go at the top of the block. The fee is the leading integer of the
+# self_build_fee_attribute ("type.key") event attribute returned by CheckTx.
+self_build = {{ .Sidecar.SelfBuild }}
+self_build_max_txs = {{ .Sidecar.SelfBuildMaxTxs }}
+self_build_fee_attribute = "{{ .Sidecar.SelfBuildFeeAttribute }}"
+
+# Classes of txs always placed ahead of bundles in this node's proposals, so
+# critical protocol traffic (eg. oracle votes, IBC relay packets) is never
+# displaced by an auction. A tx is protected if it starts with any of the
+# comma separated hex protected_tx_prefixes, or if the protected_tx_attribute
+# ("type.key") event attribute returned by CheckTx for it is "true".
+protected_tx_prefixes = "{{ .Sidecar.ProtectedTxPrefixes }}"
+protected_tx_attribute = "{{ .Sidecar.ProtectedTxAttribute }}"
+
+# Simulate each proposal carrying bundles against the app before making it. The
+# app must answer ABCI queries on simulation_query_path, executing the proposal
+# txs (a tendermint.types.Data message in the query data) on a scratch copy of
+# its last committed state. If the simulation fails, the proposal is made
+# without bundles.
+simulate_proposals = {{ .Sidecar.SimulateProposals }}
+simulation_query_path = "{{ .Sidecar.SimulationQueryPath }}"
+
+# Have the app accept or refuse each bundle once all its txs are in, so chains
+# can enforce their own rules on bundles (eg. no front-running of oracle txs).
+# The app must answer ABCI queries on check_bundle_query_path, with the bundle
+# txs as a tendermint.types.Data message in the query data, returning a non-zero
+# code to refuse the bundle. Txs of refused bundles are dropped from the sidecar.
+check_bundles = {{ .Sidecar.CheckBundles }}
+check_bundle_query_path = "{{ .Sidecar.CheckBundleQueryPath }}"
+
+##### receipts and monitoring #####
+
+# Sign a receipt with the node key for each bundle included in a block this node
+# proposed (bundle hash, height, block hash and position in the block), served
+# by the bundle_receipts RPC endpoint for the last receipt_retain_heights heights.
+# With push_receipts, receipts are also sent back towards the relay, through the
+# sentries, once the block is committed.
+bundle_receipts = {{ .Sidecar.BundleReceipts }}
+push_receipts = {{ .Sidecar.PushReceipts }}
+receipt_retain_heights = {{ .Sidecar.ReceiptRetainHeights }}
+
+# Flag the MEV pipeline as stalled, failing the /health RPC endpoint and setting
+# the sidecar_stalled metric, when no sidecar message arrived for stall_blocks
+# blocks while sidecar peers are connected, or when the auction height didn't
+# advance for stall_timeout. 0 disables either check.
+stall_blocks = {{ .Sidecar.StallBlocks }}
+stall_timeout = "{{ .Sidecar.StallTimeout }}"
+
+# Log each of the lines logged for every sidecar tx (receiving, adding, gossiping
+# or dropping it as a duplicate) at most log_sample_burst times per
+# log_sample_interval, so debug logging stays usable on busy validators. The
+# lines dropped are summed up once the interval is over. 0 logs every line.
+log_sample_interval = "{{ .Sidecar.LogSampleInterval }}"
+log_sample_burst = {{ .Sidecar.LogSampleBurst }}
+
+# Append a JSON line recording every bundle received, and each decision taken on
+# it (accepted, vetoed, auctioned, included or dropped), with timestamps, to
+# audit_log_path (relative to the home directory), as a local record for
+# disputes with relays. The file is rotated once past audit_log_max_file_size
+# bytes, and the oldest ones removed once all of them take more than
+# audit_log_max_total_size bytes (0 keeps them all). Empty disables the log.
+audit_log_path = "{{ js .Sidecar.AuditLogPath }}"
+audit_log_max_file_size = {{ .Sidecar.AuditLogMaxFileSize }}
+audit_log_max_total_size = {{ .Sidecar.AuditLogMaxTotalSize }}
+
+# Send a copy of every bundle completed, as JSON, to mirror_addr, for MEV
+# analytics run outside the node: a socket ("unix://" or "tcp://"), sent a line
+# per bundle, or an HTTP endpoint ("http://" or "https://"), POSTed each bundle.
+# Bundles are dropped rather than holding the node back if the sink lags. With
+# mirror_redact_txs, only the hashes of the txs are sent. Empty disables it.
+mirror_addr = "{{ js .Sidecar.MirrorAddr }}"
+mirror_redact_txs = {{ .Sidecar.MirrorRedactTxs }}
 `
 
 /****** these are for test settings ***********/
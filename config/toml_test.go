@@ -6,7 +6,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -64,6 +66,92 @@ func TestEnsureTestRoot(t *testing.T) {
 	ensureFiles(t, rootDir, defaultDataDir, baseConfig.Genesis, baseConfig.PrivValidatorKey, baseConfig.PrivValidatorState)
 }
 
+func TestSidecarConfigFile(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "config-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	cfg := DefaultConfig()
+	cfg.Sidecar.RelayerID = "7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e"
+	cfg.Sidecar.MEVDisabled = true
+	cfg.Sidecar.AuctionWindow = 800 * time.Millisecond
+	cfg.Sidecar.MaxBundles = 42
+	configFile := filepath.Join(tmpDir, "config.toml")
+	WriteConfigFile(configFile, cfg)
+
+	// the sidecar options are read back from their own section
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	require.NoError(t, v.ReadInConfig())
+	read := DefaultConfig()
+	require.NoError(t, v.Unmarshal(read))
+	assert.Equal(t, cfg.Sidecar, read.Sidecar)
+	assert.NoError(t, read.ValidateBasic())
+}
+
+func TestAppendSidecarConfig(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "config-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	configFile := filepath.Join(tmpDir, "config.toml")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte("moniker = \"node\"\n\n[p2p]\nladdr = \"tcp://0.0.0.0:26656\""), 0600))
+	cfg := DefaultConfig()
+	cfg.Sidecar.MaxBundles = 42
+	require.NoError(t, AppendSidecarConfig(configFile, cfg))
+
+	// the rest of the file is kept, and the section read back
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	require.NoError(t, v.ReadInConfig())
+	assert.Equal(t, "node", v.GetString("moniker"))
+	assert.Equal(t, "tcp://0.0.0.0:26656", v.GetString("p2p.laddr"))
+	read := DefaultConfig()
+	require.NoError(t, v.Unmarshal(read))
+	assert.Equal(t, cfg.Sidecar, read.Sidecar)
+
+	// it's only ever added once
+	assert.Error(t, AppendSidecarConfig(configFile, cfg))
+}
+
+func TestSidecarConfigEnv(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "config-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	// a config file with most sidecar settings missing
+	configFile := filepath.Join(tmpDir, defaultConfigFilePath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(configFile), 0700))
+	require.NoError(t, ioutil.WriteFile(configFile, []byte("[sidecar]\nmin_bid = 5\nmax_bundles = 7\n"), 0600))
+
+	env := map[string]string{
+		"TM_SIDECAR_MIN_BID":        "42",
+		"TM_SIDECAR_AUCTION_WINDOW": "800ms",
+		"TM_SIDECAR_MEV_DISABLED":   "true",
+		"TM_SIDECAR_RELAYER_ID":     "7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e",
+		"TM_SIDECAR_RELAY_API_KEY":  "secret",
+	}
+	for k, val := range env {
+		require.NoError(t, os.Setenv(k, val))
+		defer os.Unsetenv(k)
+	}
+
+	// the variables override the file, and settings missing from it
+	sidecar, err := LoadSidecarConfig(tmpDir, "test-chain")
+	require.NoError(t, err)
+	assert.EqualValues(t, 42, sidecar.MinBid)
+	assert.Equal(t, 7, sidecar.MaxBundles)
+	assert.Equal(t, 800*time.Millisecond, sidecar.AuctionWindow)
+	assert.True(t, sidecar.MEVDisabled)
+	assert.Equal(t, "7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e", sidecar.RelayerID)
+	assert.Equal(t, "secret", sidecar.RelayAPIKey)
+
+	// invalid values are caught as in the file
+	require.NoError(t, os.Setenv("TM_SIDECAR_MIN_BID", "-1"))
+	_, err = LoadSidecarConfig(tmpDir, "test-chain")
+	assert.Error(t, err)
+}
+
 func checkConfig(configFile string) bool {
 	var valid bool = true
 	// list of words we expect in the config
@@ -54,12 +54,19 @@ var _ mempl.PriorityTxSidecar = emptySidecar{}
 
 func (emptySidecar) AddTx(_ types.Tx, _ mempl.TxInfo) error { return nil }
 func (emptySidecar) ReapMaxTxs() []*mempl.MempoolTx         { return []*mempl.MempoolTx{} }
+func (emptySidecar) ReapAuction() ([]*mempl.MempoolTx, []types.AuctionBundle) {
+	return []*mempl.MempoolTx{}, []types.AuctionBundle{}
+}
 
 func (emptySidecar) Lock()   {}
 func (emptySidecar) Unlock() {}
 
 func (emptySidecar) HeightForFiringAuction() int64 { return 0 }
 
+func (emptySidecar) GetTxByKey(_ [mempl.TxKeySize]byte) (types.Tx, mempl.TxInfo, bool) {
+	return nil, mempl.TxInfo{}, false
+}
+
 func (emptySidecar) Flush() {}
 func (emptySidecar) Update(
 	blockHeight int64,
@@ -68,6 +75,7 @@ func (emptySidecar) Update(
 ) error {
 	return nil
 }
+func (emptySidecar) Reset(_ int64) {}
 
 func (emptySidecar) TxsAvailable() <-chan struct{} { return make(chan struct{}) }
 func (emptySidecar) EnableTxsAvailable()           {}
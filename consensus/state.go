@@ -314,6 +314,11 @@ func (cs *State) OnStart() error {
 		return err
 	}
 
+	// Whether we got here by state sync, fast sync or a restart, the sidecar
+	// may hold bundles for heights we're already past, which must not be
+	// reaped into our first proposals.
+	cs.blockExec.ResetSidecar(cs.state)
+
 	// We may have lost some votes if the process crashed reload from consensus
 	// log to catchup.
 	if cs.doWALCatchup {
@@ -0,0 +1,114 @@
+package log
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// NewSamplingLogger wraps next so that each message, at each level, is logged
+// at most burst times per interval, however many loggers derived from it
+// with With log it. The lines dropped are summed up once the interval is
+// over, on the next line logged, with one line per message at its level.
+//
+// Meant for lines logged for every tx or message received, to keep debug
+// logging usable on busy nodes.
+func NewSamplingLogger(next Logger, interval time.Duration, burst int) Logger {
+	return &samplingLogger{
+		next: next,
+		sampler: &sampler{
+			summary:  next,
+			interval: interval,
+			burst:    burst,
+			counts:   make(map[sampleKey]int),
+		},
+	}
+}
+
+type samplingLogger struct {
+	next    Logger
+	sampler *sampler
+}
+
+func (l *samplingLogger) Debug(msg string, keyvals ...interface{}) {
+	if l.sampler.allow(levelDebug, msg, time.Now()) {
+		l.next.Debug(msg, keyvals...)
+	}
+}
+
+func (l *samplingLogger) Info(msg string, keyvals ...interface{}) {
+	if l.sampler.allow(levelInfo, msg, time.Now()) {
+		l.next.Info(msg, keyvals...)
+	}
+}
+
+func (l *samplingLogger) Error(msg string, keyvals ...interface{}) {
+	if l.sampler.allow(levelError, msg, time.Now()) {
+		l.next.Error(msg, keyvals...)
+	}
+}
+
+func (l *samplingLogger) With(keyvals ...interface{}) Logger {
+	return &samplingLogger{next: l.next.With(keyvals...), sampler: l.sampler}
+}
+
+type sampleKey struct {
+	level level
+	msg   string
+}
+
+// sampler counts the lines logged per message in the current interval.
+type sampler struct {
+	mtx sync.Mutex
+
+	// logs the summaries of the lines dropped
+	summary  Logger
+	interval time.Duration
+	burst    int
+
+	start  time.Time
+	counts map[sampleKey]int
+}
+
+// allow returns true if the line with msg at level may be logged at now,
+// summing up the lines dropped in the interval before first if it's over.
+func (s *sampler) allow(lvl level, msg string, now time.Time) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if now.Sub(s.start) >= s.interval {
+		s.summarize()
+		s.start = now
+		s.counts = make(map[sampleKey]int, len(s.counts))
+	}
+	key := sampleKey{lvl, msg}
+	s.counts[key]++
+	return s.counts[key] <= s.burst
+}
+
+// summarize logs the number of lines dropped for each message, in order.
+func (s *sampler) summarize() {
+	keys := make([]sampleKey, 0, len(s.counts))
+	for key, n := range s.counts {
+		if n > s.burst {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].level != keys[j].level {
+			return keys[i].level < keys[j].level
+		}
+		return keys[i].msg < keys[j].msg
+	})
+	for _, key := range keys {
+		keyvals := []interface{}{"msg", key.msg, "dropped", s.counts[key] - s.burst, "interval", s.interval}
+		switch key.level {
+		case levelDebug:
+			s.summary.Debug("Dropped log lines", keyvals...)
+		case levelInfo:
+			s.summary.Info("Dropped log lines", keyvals...)
+		default:
+			s.summary.Error("Dropped log lines", keyvals...)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestSamplingLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewSamplingLogger(log.NewTMJSONLoggerNoTS(&buf), 100*time.Millisecond, 2)
+
+	for i := 0; i < 5; i++ {
+		// loggers derived with With share the same budget
+		logger.With("i", i).Debug("received")
+	}
+	logger.Info("received")
+	logger.Error("failed")
+	lines := func() []string {
+		defer buf.Reset()
+		return strings.Split(strings.TrimSpace(buf.String()), "\n")
+	}
+	assert.Equal(t, []string{
+		`{"_msg":"received","i":0,"level":"debug"}`,
+		`{"_msg":"received","i":1,"level":"debug"}`,
+		`{"_msg":"received","level":"info"}`,
+		`{"_msg":"failed","level":"error"}`,
+	}, lines())
+
+	// the next line after the interval sums up the dropped ones first
+	time.Sleep(100 * time.Millisecond)
+	logger.With("i", 5).Debug("received")
+	assert.Equal(t, []string{
+		`{"_msg":"Dropped log lines","dropped":3,"interval":"100ms","level":"debug","msg":"received"}`,
+		`{"_msg":"received","i":5,"level":"debug"}`,
+	}, lines())
+}
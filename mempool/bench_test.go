@@ -2,6 +2,7 @@ package mempool
 
 import (
 	"encoding/binary"
+	"sync/atomic"
 	"testing"
 
 	"github.com/tendermint/tendermint/abci/example/kvstore"
@@ -56,6 +57,29 @@ func BenchmarkCacheInsertTime(b *testing.B) {
 	}
 }
 
+func BenchmarkCacheInsertTimeParallel(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		cache txCache
+	}{
+		{"single", newMapTxCache(100000)},
+		{"sharded", newShardedTxCache(100000, 16)},
+	}
+	for _, bm := range benchmarks {
+		bm := bm
+		b.Run(bm.name, func(b *testing.B) {
+			var next uint64
+			b.RunParallel(func(pb *testing.PB) {
+				tx := make([]byte, 8)
+				for pb.Next() {
+					binary.BigEndian.PutUint64(tx, atomic.AddUint64(&next, 1))
+					bm.cache.Push(tx)
+				}
+			})
+		})
+	}
+}
+
 // This benchmark is probably skewed, since we actually will be removing
 // txs in parallel, which may cause some overhead due to mutex locking.
 func BenchmarkCacheRemoveTime(b *testing.B) {
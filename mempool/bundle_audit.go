@@ -0,0 +1,168 @@
+package mempool
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	auto "github.com/tendermint/tendermint/libs/autofile"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/libs/log"
+	tmos "github.com/tendermint/tendermint/libs/os"
+	"github.com/tendermint/tendermint/libs/service"
+)
+
+// bundleAuditFlushInterval is how often the audit log is flushed to disk.
+const bundleAuditFlushInterval = time.Second
+
+// Events recorded by the bundle audit log.
+const (
+	BundleAuditReceived  = "received"  // its first tx was received
+	BundleAuditAccepted  = "accepted"  // all its txs were received, and the app accepted it if asked
+	BundleAuditVetoed    = "vetoed"    // the app refused it
+	BundleAuditAuctioned = "auctioned" // reaped for its auction, the first time it was
+	BundleAuditIncluded  = "included"  // committed in a block
+	BundleAuditDropped   = "dropped"   // removed from the sidecar without being included
+)
+
+// BundleAuditRecord is a line of the bundle audit log.
+type BundleAuditRecord struct {
+	Time       time.Time        `json:"time"`
+	Event      string           `json:"event"`
+	Height     int64            `json:"height"`
+	BundleID   int64            `json:"bundle_id"`
+	BundleSize int64            `json:"bundle_size"`
+	Bid        int64            `json:"bid"`
+	Late       bool             `json:"late,omitempty"`
+	Hash       tmbytes.HexBytes `json:"hash,omitempty"`
+	// peer the bundle's first tx was received from, and its origin, for
+	// BundleAuditReceived
+	Sender string `json:"sender,omitempty"`
+	Origin string `json:"origin,omitempty"`
+	// why the bundle was vetoed or dropped
+	Reason string `json:"reason,omitempty"`
+}
+
+// BundleAuditLog appends a JSON line to a file for every bundle the sidecar
+// receives, and for each decision taken on it, as a local record of what
+// relays sent and what became of it, for disputes with them. Being a plain
+// file, it's only as trustworthy as the node writing it.
+//
+// The file is rotated once past a size, the rotated ones being suffixed with
+// their index as by autofile.Group. Records are buffered, and flushed to disk
+// every second and once stopped.
+//
+// Write is safe for concurrent use, and a no-op on a nil log.
+type BundleAuditLog struct {
+	service.BaseService
+
+	group *auto.Group
+}
+
+// NewBundleAuditLog returns an audit log appending to the file at path,
+// rotated once past maxFileSize bytes, the oldest files being removed once
+// they all take more than maxTotalSize bytes, unless 0.
+func NewBundleAuditLog(path string, maxFileSize, maxTotalSize int64) (*BundleAuditLog, error) {
+	if err := tmos.EnsureDir(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to ensure bundle audit log directory is in place: %w", err)
+	}
+	group, err := auto.OpenGroup(path,
+		auto.GroupHeadSizeLimit(maxFileSize),
+		auto.GroupTotalSizeLimit(maxTotalSize),
+	)
+	if err != nil {
+		return nil, err
+	}
+	a := &BundleAuditLog{group: group}
+	a.BaseService = *service.NewBaseService(nil, "BundleAuditLog", a)
+	return a, nil
+}
+
+// SetLogger implements service.Service.
+func (a *BundleAuditLog) SetLogger(l log.Logger) {
+	a.BaseService.SetLogger(l)
+	a.group.SetLogger(l)
+}
+
+// OnStart implements service.Service.
+func (a *BundleAuditLog) OnStart() error {
+	if err := a.group.Start(); err != nil {
+		return err
+	}
+	go a.flushRoutine()
+	return nil
+}
+
+// OnStop implements service.Service, flushing the records left.
+func (a *BundleAuditLog) OnStop() {
+	if err := a.group.Stop(); err != nil {
+		a.Logger.Error("Error stopping bundle audit log", "err", err)
+	}
+	a.group.Wait()
+	a.group.Close()
+}
+
+func (a *BundleAuditLog) flushRoutine() {
+	ticker := time.NewTicker(bundleAuditFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := a.group.FlushAndSync(); err != nil {
+				a.Logger.Error("Failed flushing bundle audit log", "err", err)
+			}
+		case <-a.Quit():
+			return
+		}
+	}
+}
+
+// Write appends record to the log.
+func (a *BundleAuditLog) Write(record BundleAuditRecord) {
+	if a == nil {
+		return
+	}
+	bz, err := json.Marshal(record)
+	if err == nil {
+		err = a.group.WriteLine(string(bz))
+	}
+	if err != nil {
+		a.Logger.Error("Failed writing bundle audit log", "height", record.Height,
+			"bundle_id", record.BundleID, "event", record.Event, "err", err)
+	}
+}
+
+// WithBundleAuditLog sets the log every bundle received, and each decision
+// taken on it, is recorded to.
+func WithBundleAuditLog(auditLog *BundleAuditLog) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.auditLog = auditLog }
+}
+
+// bundleAuditRecord returns the record of event, at t, for bundle.
+//
+// sc.memMtx must be held by the caller, or the sidecar locked.
+func bundleAuditRecord(bundle *Bundle, event string, t time.Time) BundleAuditRecord {
+	return BundleAuditRecord{
+		Time:       t,
+		Event:      event,
+		Height:     bundle.desiredHeight,
+		BundleID:   bundle.bundleId,
+		BundleSize: bundle.enforcedSize,
+		Bid:        bundle.bid,
+		Late:       bundle.late,
+		Hash:       bundle.hash,
+	}
+}
+
+// auditBundle records event, happening now, for bundle, with reason if any.
+//
+// sc.memMtx must be held by the caller, or the sidecar locked.
+func (sc *CListPriorityTxSidecar) auditBundle(bundle *Bundle, event, reason string) {
+	if sc.auditLog == nil {
+		return
+	}
+	record := bundleAuditRecord(bundle, event, time.Now())
+	record.Reason = reason
+	sc.auditLog.Write(record)
+}
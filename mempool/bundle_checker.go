@@ -0,0 +1,62 @@
+package mempool
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
+)
+
+// BundleChecker lets the app veto bundles before they enter the sidecar, so
+// chains can enforce their own rules on bundles (eg. no front-running of
+// oracle txs) on the node itself.
+type BundleChecker interface {
+	// CheckBundle returns an error if the bundle, made of txs in order and
+	// asking to be included at height, must not be auctioned.
+	CheckBundle(height, bundleID int64, txs types.Txs) error
+}
+
+// QueryBundleChecker checks bundles through an ABCI query on a dedicated
+// path. The query data is the bundle's txs as a tendermint.types.Data
+// message, and the query is made against the latest state. Apps supporting it
+// return a non-zero code for bundles they refuse.
+type QueryBundleChecker struct {
+	proxyApp proxy.AppConnQuery
+	path     string
+}
+
+var _ BundleChecker = (*QueryBundleChecker)(nil)
+
+// NewQueryBundleChecker returns a QueryBundleChecker querying path.
+func NewQueryBundleChecker(proxyApp proxy.AppConnQuery, path string) *QueryBundleChecker {
+	return &QueryBundleChecker{
+		proxyApp: proxyApp,
+		path:     path,
+	}
+}
+
+// CheckBundle implements BundleChecker.
+func (bc *QueryBundleChecker) CheckBundle(height, bundleID int64, txs types.Txs) error {
+	data := tmproto.Data{Txs: make([][]byte, len(txs))}
+	for i, tx := range txs {
+		data.Txs[i] = tx
+	}
+	bz, err := data.Marshal()
+	if err != nil {
+		return err
+	}
+
+	res, err := bc.proxyApp.QuerySync(abci.RequestQuery{
+		Path: bc.path,
+		Data: bz,
+	})
+	if err != nil {
+		return err
+	}
+	if res.IsErr() {
+		return fmt.Errorf("app refused bundle %d for height %d with code %d: %s", bundleID, height, res.Code, res.Log)
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package mempool
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
+	"github.com/tendermint/tendermint/libs/protoio"
+	"github.com/tendermint/tendermint/p2p"
+	protomem "github.com/tendermint/tendermint/proto/tendermint/mempool"
+	"github.com/tendermint/tendermint/types"
+)
+
+var (
+	// ErrBundleEnvelopeInvalidSignature is returned for envelopes not signed,
+	// or whose signature doesn't match.
+	ErrBundleEnvelopeInvalidSignature = errors.New("invalid bundle envelope signature")
+	// ErrBundlePlacementUnsupported is returned for envelopes asking for a
+	// placement other than the "mev" lane's.
+	ErrBundlePlacementUnsupported = errors.New("unsupported bundle placement")
+	// ErrBundleForOtherChain is returned for bundles tagged for a chain other
+	// than the sidecar's, see WithSidecarChainID.
+	ErrBundleForOtherChain = errors.New("bundle for another chain")
+)
+
+// BundleEnvelope is a whole bundle in a single message, signed by the relay
+// that built it, see protomem.BundleEnvelope. Its txs are added to the sidecar
+// as any other's, with the envelope kept to be passed on as it was to peers
+// taking envelopes, see CapabilityEnvelopes.
+type BundleEnvelope struct {
+	Txs       types.Txs
+	MinHeight int64
+	MaxHeight int64
+	Bid       int64
+	Placement protomem.BundlePlacement
+	BundleID  int64
+	PubKey    crypto.PubKey
+	Signature []byte
+	// custom metadata, passed on as it is
+	Extensions []types.BundleExtension
+	// chain the bundle is for, empty for any
+	ChainID string
+
+	msgOnce sync.Once
+	msg     []byte // message gossiping the envelope, see gossipMsg
+}
+
+// BundleEnvelopeSignBytes returns the proto-encoding of the canonicalized
+// envelope, for signing. Panics if the marshaling fails.
+func BundleEnvelopeSignBytes(env *BundleEnvelope) []byte {
+	pb := protomem.CanonicalBundleEnvelope{
+		TxsHash:    env.Txs.Hash(),
+		MinHeight:  env.MinHeight,
+		MaxHeight:  env.MaxHeight,
+		Bid:        env.Bid,
+		Placement:  env.Placement,
+		BundleId:   env.BundleID,
+		Extensions: bundleExtensionsToProto(env.Extensions),
+		ChainId:    env.ChainID,
+	}
+	bz, err := protoio.MarshalDelimited(&pb)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// Sign signs the envelope with signer.
+func (env *BundleEnvelope) Sign(signer RelaySigner) error {
+	sig, err := signer.Sign(BundleEnvelopeSignBytes(env))
+	if err != nil {
+		return err
+	}
+	env.PubKey, env.Signature = signer.PubKey(), sig
+	return nil
+}
+
+// ValidateBasic performs basic validation of the envelope, its signature
+// aside, see Verify.
+func (env *BundleEnvelope) ValidateBasic() error {
+	if len(env.Txs) == 0 {
+		return errors.New("empty bundle")
+	}
+	if env.MinHeight <= 0 {
+		return fmt.Errorf("min height must be positive, got %d", env.MinHeight)
+	}
+	if env.MaxHeight < env.MinHeight {
+		return fmt.Errorf("max height %d below min height %d", env.MaxHeight, env.MinHeight)
+	}
+	if env.Bid < 0 {
+		return fmt.Errorf("negative bid %d", env.Bid)
+	}
+	if env.BundleID < 0 {
+		return fmt.Errorf("negative bundle id %d", env.BundleID)
+	}
+	if _, ok := protomem.BundlePlacement_name[int32(env.Placement)]; !ok {
+		return fmt.Errorf("unknown placement %d", env.Placement)
+	}
+	return types.ValidateBundleExtensions(env.Extensions)
+}
+
+// Verify returns an error if the envelope isn't valid, or isn't signed by
+// its PubKey.
+func (env *BundleEnvelope) Verify() error {
+	if err := env.ValidateBasic(); err != nil {
+		return err
+	}
+	if env.PubKey == nil || !env.PubKey.VerifySignature(BundleEnvelopeSignBytes(env), env.Signature) {
+		return ErrBundleEnvelopeInvalidSignature
+	}
+	return nil
+}
+
+// height returns the height the envelope's bundle is added to the sidecar
+// for, that of the auction at auctionHeight if within the envelope's range,
+// or else the first of the range, if still ahead.
+func (env *BundleEnvelope) height(auctionHeight int64) (int64, error) {
+	if auctionHeight > env.MaxHeight {
+		return 0, ErrWrongHeight{int(env.MaxHeight), int(auctionHeight)}
+	}
+	if auctionHeight < env.MinHeight {
+		return env.MinHeight, nil
+	}
+	return auctionHeight, nil
+}
+
+// ToProto converts the envelope to its protobuf representation.
+func (env *BundleEnvelope) ToProto() (*protomem.BundleEnvelope, error) {
+	pb := &protomem.BundleEnvelope{
+		Txs:        make([][]byte, len(env.Txs)),
+		MinHeight:  env.MinHeight,
+		MaxHeight:  env.MaxHeight,
+		Bid:        env.Bid,
+		Placement:  env.Placement,
+		BundleId:   env.BundleID,
+		Signature:  env.Signature,
+		Extensions: bundleExtensionsToProto(env.Extensions),
+		ChainId:    env.ChainID,
+	}
+	for i, tx := range env.Txs {
+		pb.Txs[i] = tx
+	}
+	if env.PubKey != nil {
+		pk, err := cryptoenc.PubKeyToProto(env.PubKey)
+		if err != nil {
+			return nil, err
+		}
+		pb.PubKey = &pk
+	}
+	return pb, nil
+}
+
+// BundleEnvelopeFromProto converts a protobuf envelope to a BundleEnvelope,
+// and validates it, its signature aside.
+func BundleEnvelopeFromProto(pb *protomem.BundleEnvelope) (*BundleEnvelope, error) {
+	if pb == nil {
+		return nil, errors.New("nil bundle envelope")
+	}
+	env := &BundleEnvelope{
+		Txs:       make(types.Txs, len(pb.Txs)),
+		MinHeight: pb.MinHeight,
+		MaxHeight: pb.MaxHeight,
+		Bid:       pb.Bid,
+		Placement: pb.Placement,
+		BundleID:  pb.BundleId,
+		Signature: pb.Signature,
+		ChainID:   pb.ChainId,
+	}
+	for i, tx := range pb.Txs {
+		env.Txs[i] = tx
+	}
+	if len(pb.Extensions) > 0 {
+		env.Extensions = make([]types.BundleExtension, len(pb.Extensions))
+		for i, ext := range pb.Extensions {
+			env.Extensions[i] = types.BundleExtension{Key: ext.Key, Value: ext.Value}
+		}
+	}
+	if pb.PubKey != nil {
+		pk, err := cryptoenc.PubKeyFromProto(*pb.PubKey)
+		if err != nil {
+			return nil, err
+		}
+		env.PubKey = pk
+	}
+	return env, env.ValidateBasic()
+}
+
+// envelopeExtensions returns the extensions of env, nil if it's nil.
+func envelopeExtensions(env *BundleEnvelope) []types.BundleExtension {
+	if env == nil {
+		return nil
+	}
+	return env.Extensions
+}
+
+// bundleExtensionsToProto converts exts to their protobuf representation.
+func bundleExtensionsToProto(exts []types.BundleExtension) []protomem.BundleExtension {
+	if len(exts) == 0 {
+		return nil
+	}
+	pbs := make([]protomem.BundleExtension, len(exts))
+	for i, ext := range exts {
+		pbs[i] = protomem.BundleExtension{Key: ext.Key, Value: ext.Value}
+	}
+	return pbs
+}
+
+// gossipMsg returns the message gossiping the envelope to sidecar peers.
+func (env *BundleEnvelope) gossipMsg() []byte {
+	env.msgOnce.Do(func() {
+		pb, err := env.ToProto()
+		if err != nil {
+			// the key was decoded from a proto
+			panic(err)
+		}
+		env.msg = marshalMEVMessage(&protomem.MEVMessage{
+			Sum: &protomem.MEVMessage_Envelope{Envelope: pb},
+		})
+	})
+	return env.msg
+}
+
+// SubmitEnvelope adds the txs of the signed envelope to the sidecar, as if
+// received from a peer, and returns the height they're added for, see
+// addEnvelope. Envelopes submitted through the RPC are gossiped as any
+// other, unless OriginRPC is private.
+//
+// Safe for concurrent use by multiple goroutines.
+func (memR *Reactor) SubmitEnvelope(env *BundleEnvelope) (int64, error) {
+	if err := env.Verify(); err != nil {
+		return 0, err
+	}
+	if !memR.envelopeSignerAllowed(env.PubKey) {
+		return 0, errors.New("envelope not signed by a relay in relay_pub_keys")
+	}
+	txInfo := TxInfo{SenderID: UnknownPeerID, Origin: OriginRPC, ReceivedAt: time.Now()}
+	return memR.addEnvelope(env, txInfo)
+}
+
+// receiveEnvelope adds the txs of the envelope received from src to the
+// sidecar.
+func (memR *Reactor) receiveEnvelope(src p2p.Peer, env *BundleEnvelope) {
+	if err := env.Verify(); err != nil {
+		memR.countInvalidSidecarMsg(src, "bad_envelope")
+		memR.Logger.Info("Refusing bundle envelope", "src", src, "err", err)
+		return
+	}
+	if !memR.envelopeSignerAllowed(env.PubKey) {
+		memR.countInvalidSidecarMsg(src, "relay_not_allowed")
+		memR.Logger.Info("Refusing bundle envelope not signed by a relay in relay_pub_keys", "src", src,
+			"signer", env.PubKey.Address())
+		return
+	}
+	if memR.sidecar.forOtherChain(env.ChainID) {
+		memR.countInvalidSidecarMsg(src, "wrong_chain")
+		memR.Logger.Info("Refusing bundle envelope for another chain", "src", src, "chain_id", env.ChainID)
+		return
+	}
+	txInfo := memR.sidecarTxInfo(src)
+	memR.admit(txInfo.SenderID, func() {
+		if height, err := memR.addEnvelope(env, txInfo); errors.Is(err, ErrTxInCache) {
+			memR.sidecarTxLogger.Debug("Bundle envelope already received", "src", src,
+				"bundle_height", height, "bundle_id", env.BundleID)
+		} else if err != nil {
+			memR.sidecarTxLogger.Info("Could not add bundle envelope", "src", src,
+				"bundle_height", height, "bundle_id", env.BundleID, "err", err)
+		}
+	})
+}
+
+// addEnvelope adds the txs of the verified envelope to the sidecar, in
+// order, with txInfo completed by the envelope, for the upcoming auction if
+// within the envelope's range, or else the first height of the range. It
+// returns that height, and the error of the first tx refused, if any, the
+// txs added before it being removed then, or ErrTxInCache if all the txs
+// were received already.
+func (memR *Reactor) addEnvelope(env *BundleEnvelope, txInfo TxInfo) (int64, error) {
+	if memR.sidecar.forOtherChain(env.ChainID) {
+		return 0, fmt.Errorf("%w: %s", ErrBundleForOtherChain, env.ChainID)
+	}
+	if env.Placement != protomem.BundlePlacement_BUNDLE_PLACEMENT_MEV_LANE {
+		return 0, fmt.Errorf("%w: %v", ErrBundlePlacementUnsupported, env.Placement)
+	}
+	height, err := env.height(atomic.LoadInt64(&memR.sidecar.heightForFiringAuction))
+	if err != nil {
+		return 0, err
+	}
+	txInfo.DesiredHeight = height
+	txInfo.BundleId = env.BundleID
+	txInfo.BundleSize = int64(len(env.Txs))
+	txInfo.Bid = env.Bid
+	txInfo.Envelope = env
+	var (
+		inCache int
+		added   = make(types.Txs, 0, len(env.Txs))
+	)
+	for i, tx := range env.Txs {
+		txInfo.BundleOrder = int64(i)
+		ctx, cancel := memR.checkTxContext()
+		err := memR.sidecar.AddTxContext(ctx, tx, txInfo)
+		cancel()
+		if err == ErrTxInCache {
+			inCache++
+			continue
+		}
+		if err != nil {
+			memR.sidecar.RemoveBundleTxs(height, env.BundleID, added, true)
+			return height, fmt.Errorf("tx %d refused: %w", i, err)
+		}
+		added = append(added, tx)
+	}
+	if inCache == len(env.Txs) {
+		return height, ErrTxInCache
+	}
+	return height, nil
+}
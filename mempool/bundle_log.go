@@ -0,0 +1,65 @@
+package mempool
+
+import (
+	cfg "github.com/tendermint/tendermint/config"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Logs about bundles, and their txs, carry the same fields throughout the
+// reactor, the sidecar and the proposal's assembly, so a bundle's path
+// through the node can be followed with a single grep:
+//
+//	bundle_height  height the bundle targets
+//	bundle_id      id of the bundle for its height
+//	bundle_order   order of the tx in its bundle
+//	bundle_hash    merkle root of the bundle's txs, once complete
+
+// The lines logged for every sidecar tx are sampled as configured by
+// SidecarConfig.LogSampleBurst, bundle lines aren't.
+
+// sampledLogger returns l, logging each line at most config.LogSampleBurst
+// times per config.LogSampleInterval if set.
+func sampledLogger(l log.Logger, config *cfg.SidecarConfig) log.Logger {
+	if config.LogSampleBurst == 0 {
+		return l
+	}
+	return log.NewSamplingLogger(l, config.LogSampleInterval, config.LogSampleBurst)
+}
+
+// txLogger returns the sidecar's sampled logger with the fields of tx,
+// received as described by txInfo.
+func (sc *CListPriorityTxSidecar) txLogger(tx types.Tx, txInfo TxInfo) log.Logger {
+	return sc.txLog.With(
+		"tx", txID(tx),
+		"bundle_height", txInfo.DesiredHeight,
+		"bundle_id", txInfo.BundleId,
+		"bundle_order", txInfo.BundleOrder,
+	)
+}
+
+// bundleLogger returns the sidecar's logger with the fields of bundle.
+//
+// sc.memMtx must be held by the caller, or the sidecar locked.
+func (sc *CListPriorityTxSidecar) bundleLogger(bundle *Bundle) log.Logger {
+	if bundle.hash == nil {
+		return sc.logger.With("bundle_height", bundle.desiredHeight, "bundle_id", bundle.bundleId)
+	}
+	return sc.logger.With(
+		"bundle_height", bundle.desiredHeight,
+		"bundle_id", bundle.bundleId,
+		"bundle_hash", tmbytes.HexBytes(bundle.hash),
+	)
+}
+
+// bundleTxs returns the txs of bundle in order, skipping those missing.
+func bundleTxs(bundle *Bundle) types.Txs {
+	txs := make(types.Txs, 0, bundle.enforcedSize)
+	for order := int64(0); order < bundle.enforcedSize; order++ {
+		if scTx, ok := bundle.orderedTxsMap.Load(order); ok {
+			txs = append(txs, scTx.(*SidecarTx).tx)
+		}
+	}
+	return txs
+}
@@ -0,0 +1,187 @@
+package mempool
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmnet "github.com/tendermint/tendermint/libs/net"
+	"github.com/tendermint/tendermint/libs/service"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// bundleMirrorQueueSize is how many records wait to be sent to the sink
+	// before newer ones are dropped.
+	bundleMirrorQueueSize = 1000
+	// bundleMirrorTimeout bounds connecting and sending a record to the sink.
+	bundleMirrorTimeout = 3 * time.Second
+)
+
+// BundleMirrorRecord is a bundle sent to the mirror sink.
+type BundleMirrorRecord struct {
+	Time       time.Time        `json:"time"`
+	Height     int64            `json:"height"`
+	BundleID   int64            `json:"bundle_id"`
+	BundleSize int64            `json:"bundle_size"`
+	Bid        int64            `json:"bid"`
+	Late       bool             `json:"late,omitempty"`
+	Hash       tmbytes.HexBytes `json:"hash"`
+	// hashes of its txs, in order, and the txs themselves unless redacted
+	TxHashes []tmbytes.HexBytes `json:"tx_hashes"`
+	Txs      []tmbytes.HexBytes `json:"txs,omitempty"`
+}
+
+// BundleMirror sends a copy of every bundle the sidecar completes to a local
+// sink, so operators can run MEV analytics without modifying the node. The
+// sink is either a socket, "unix://" or "tcp://", sent a JSON line per
+// bundle, or an HTTP endpoint, "http://" or "https://", POSTed a JSON object
+// per bundle.
+//
+// Mirroring never holds the sidecar back: records wait in a queue, newer
+// ones being dropped while it's full, and are sent in the background. A
+// socket is redialed if the connection fails, the records failing to be
+// sent being dropped.
+//
+// Mirror is safe for concurrent use, and a no-op on a nil mirror.
+type BundleMirror struct {
+	service.BaseService
+
+	protocol string
+	addr     string
+	redact   bool
+	queue    chan BundleMirrorRecord
+
+	client *http.Client
+	conn   net.Conn // to a socket sink, nil until dialed
+}
+
+// NewBundleMirror returns a mirror sending to the sink at addr, with the txs
+// of the bundles left out if redactTxs.
+func NewBundleMirror(addr string, redactTxs bool) (*BundleMirror, error) {
+	protocol, address := tmnet.ProtocolAndAddress(addr)
+	switch protocol {
+	case "unix", "tcp":
+	case "http", "https":
+		address = addr
+	default:
+		return nil, fmt.Errorf("unsupported bundle mirror protocol %q", protocol)
+	}
+	m := &BundleMirror{
+		protocol: protocol,
+		addr:     address,
+		redact:   redactTxs,
+		queue:    make(chan BundleMirrorRecord, bundleMirrorQueueSize),
+		client:   &http.Client{Timeout: bundleMirrorTimeout},
+	}
+	m.BaseService = *service.NewBaseService(nil, "BundleMirror", m)
+	return m, nil
+}
+
+// OnStart implements service.Service.
+func (m *BundleMirror) OnStart() error {
+	go m.sendRoutine()
+	return nil
+}
+
+// Mirror queues record to be sent, dropping it if the queue is full.
+func (m *BundleMirror) Mirror(record BundleMirrorRecord) {
+	if m == nil {
+		return
+	}
+	if m.redact {
+		record.Txs = nil
+	}
+	select {
+	case m.queue <- record:
+	default:
+		m.Logger.Debug("Dropping mirrored bundle, the sink is lagging",
+			"height", record.Height, "bundle_id", record.BundleID)
+	}
+}
+
+func (m *BundleMirror) sendRoutine() {
+	defer func() {
+		if m.conn != nil {
+			m.conn.Close()
+		}
+	}()
+	for {
+		select {
+		case record := <-m.queue:
+			if err := m.send(record); err != nil {
+				m.Logger.Error("Failed mirroring bundle", "sink", m.addr,
+					"height", record.Height, "bundle_id", record.BundleID, "err", err)
+			}
+		case <-m.Quit():
+			return
+		}
+	}
+}
+
+func (m *BundleMirror) send(record BundleMirrorRecord) error {
+	bz, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if m.protocol == "http" || m.protocol == "https" {
+		resp, err := m.client.Post(m.addr, "application/json", bytes.NewReader(bz))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("sink responded %s", resp.Status)
+		}
+		return nil
+	}
+
+	if m.conn == nil {
+		conn, err := net.DialTimeout(m.protocol, m.addr, bundleMirrorTimeout)
+		if err != nil {
+			return err
+		}
+		m.conn = conn
+	}
+	err = m.conn.SetWriteDeadline(time.Now().Add(bundleMirrorTimeout))
+	if err == nil {
+		_, err = m.conn.Write(append(bz, '\n'))
+	}
+	if err != nil {
+		// redialed for the next record
+		m.conn.Close()
+		m.conn = nil
+	}
+	return err
+}
+
+// WithBundleMirror sets the mirror every bundle completed is sent to.
+func WithBundleMirror(mirror *BundleMirror) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.mirror = mirror }
+}
+
+// bundleMirrorRecord returns the record of bundle, completed at t, with txs.
+//
+// sc.memMtx must be held by the caller, or the sidecar locked.
+func bundleMirrorRecord(bundle *Bundle, txs types.Txs, t time.Time) BundleMirrorRecord {
+	record := BundleMirrorRecord{
+		Time:       t,
+		Height:     bundle.desiredHeight,
+		BundleID:   bundle.bundleId,
+		BundleSize: bundle.enforcedSize,
+		Bid:        bundle.bid,
+		Late:       bundle.late,
+		Hash:       bundle.hash,
+		TxHashes:   make([]tmbytes.HexBytes, len(txs)),
+		Txs:        make([]tmbytes.HexBytes, len(txs)),
+	}
+	for i, tx := range txs {
+		record.TxHashes[i] = tx.Hash()
+		record.Txs[i] = tmbytes.HexBytes(tx)
+	}
+	return record
+}
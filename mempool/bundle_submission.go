@@ -0,0 +1,69 @@
+package mempool
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tendermint/tendermint/p2p"
+	protomem "github.com/tendermint/tendermint/proto/tendermint/mempool"
+)
+
+// bundleSubmissionServer serves the BundleSubmission gRPC service, see
+// StartBundleSubmissionServer.
+type bundleSubmissionServer struct {
+	memR *Reactor
+}
+
+var _ protomem.BundleSubmissionServer = (*bundleSubmissionServer)(nil)
+
+// StartBundleSubmissionServer serves the BundleSubmission gRPC service on ln,
+// for the relay to submit bundles to memR's sidecar directly, see
+// SidecarConfig.GRPCListenAddress. Only envelopes signed by a relay on the
+// allowlist are taken, see SetRelayAllowlist, none if it's empty.
+// NOTE: This function blocks - you may want to call it in a go-routine.
+func StartBundleSubmissionServer(ln net.Listener, memR *Reactor) error {
+	grpcServer := grpc.NewServer()
+	protomem.RegisterBundleSubmissionServer(grpcServer, &bundleSubmissionServer{memR: memR})
+	return grpcServer.Serve(ln)
+}
+
+// SubmitBundle implements protomem.BundleSubmissionServer.
+func (s *bundleSubmissionServer) SubmitBundle(
+	ctx context.Context,
+	req *protomem.RequestSubmitBundle,
+) (*protomem.ResponseSubmitBundle, error) {
+	env, err := BundleEnvelopeFromProto(req.GetEnvelope())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := env.Verify(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	relayID := p2p.PubKeyToID(env.PubKey)
+	if !s.memR.relayListed(relayID) {
+		return nil, status.Error(codes.PermissionDenied, "envelope not signed by a relay in relay_pub_keys")
+	}
+
+	txInfo := TxInfo{SenderID: UnknownPeerID, SenderP2PID: relayID, Origin: OriginRelay, ReceivedAt: time.Now()}
+	height, err := s.memR.addEnvelope(env, txInfo)
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrBundleForOtherChain):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrTxInCache):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, ErrMEVDisabled):
+		return nil, status.Error(codes.Unavailable, err.Error())
+	default:
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	s.memR.sidecarTxLogger.Debug("Bundle submitted by the relay", "relay", relayID, "bundle_height", height,
+		"bundle_id", env.BundleID)
+	return &protomem.ResponseSubmitBundle{Height: height, Hash: env.Txs.Hash()}, nil
+}
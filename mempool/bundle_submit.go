@@ -0,0 +1,58 @@
+package mempool
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// SubmitBundle adds txs to the sidecar as a bundle for the auction at height,
+// in order, and returns the ID it's given: the one after the highest the
+// sidecar holds for height. Bundles submitted through the RPC bid nothing, and are
+// gossiped as any other, unless OriginRPC is private. If a tx is refused, the
+// error is returned and the txs added before it are removed, with their
+// bundle unless another tx of it was added meanwhile.
+//
+// Submissions are refused with ErrRelayAssignsBundleIDs if a relay is
+// configured, as the ids assigned here could collide with the relay's for
+// the same height.
+//
+// Safe for concurrent use by multiple goroutines.
+func (memR *Reactor) SubmitBundle(txs types.Txs, height int64) (int64, error) {
+	if len(txs) == 0 {
+		return 0, errors.New("empty bundle")
+	}
+	if memR.relayerID() != "" {
+		return 0, ErrRelayAssignsBundleIDs
+	}
+	memR.submitMtx.Lock()
+	defer memR.submitMtx.Unlock()
+
+	bundleID, ok := memR.sidecar.NextBundleId(height)
+	if !ok {
+		return 0, fmt.Errorf("bundle %d at height %d already held", bundleID, height)
+	}
+	txInfo := TxInfo{
+		SenderID:      UnknownPeerID,
+		DesiredHeight: height,
+		BundleId:      bundleID,
+		BundleSize:    int64(len(txs)),
+		Origin:        OriginRPC,
+		ReceivedAt:    time.Now(),
+	}
+	for i, tx := range txs {
+		txInfo.BundleOrder = int64(i)
+		ctx, cancel := memR.checkTxContext()
+		err := memR.sidecar.AddTxContext(ctx, tx, txInfo)
+		cancel()
+		if err != nil {
+			memR.sidecar.RemoveBundleTxs(height, bundleID, txs[:i], true)
+			return 0, fmt.Errorf("tx %d refused: %w", i, err)
+		}
+	}
+	memR.Logger.Info("Bundle submitted through the RPC", "bundle_height", height, "bundle_id", bundleID,
+		"bundle_size", len(txs))
+	return bundleID, nil
+}
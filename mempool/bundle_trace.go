@@ -0,0 +1,96 @@
+package mempool
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Each bundle is traced by a span, from its first tx being received to its
+// removal from the sidecar, eg. once committed. Its events mark the arrival of
+// each of its txs, its completion, and its reaping for the auction, and it
+// ends with the reason it was removed. Spans carry the same attributes as the
+// bundle's logs (see bundle_log.go), so the spans a bundle left on different
+// nodes, and those of the proposals it was auctioned in, can be put together.
+
+// WithSidecarTracer sets the tracer the bundles' spans are started with.
+func WithSidecarTracer(tracer trace.Tracer) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.tracer = tracer }
+}
+
+// startBundleSpan starts the span of the new bundle, from the time its first
+// tx was received.
+//
+// sc.memMtx must be held by the caller.
+func (sc *CListPriorityTxSidecar) startBundleSpan(bundle *Bundle) {
+	_, bundle.span = sc.tracer.Start(context.Background(), "sidecar.Bundle",
+		trace.WithTimestamp(bundle.firstReceived),
+		trace.WithAttributes(
+			attribute.Int64("bundle_height", bundle.desiredHeight),
+			attribute.Int64("bundle_id", bundle.bundleId),
+			attribute.Int64("bundle_size", bundle.enforcedSize),
+			attribute.Int64("bid", bundle.bid),
+			attribute.Bool("late", bundle.late),
+		))
+}
+
+// traceTxReceived marks tx, received as described by txInfo at receivedAt,
+// being added to bundle.
+//
+// sc.memMtx must be held by the caller.
+func traceTxReceived(bundle *Bundle, tx types.Tx, txInfo TxInfo, receivedAt time.Time) {
+	if bundle.span == nil || !bundle.span.IsRecording() {
+		return
+	}
+	bundle.span.AddEvent("tx received",
+		trace.WithTimestamp(receivedAt),
+		trace.WithAttributes(
+			attribute.String("tx", tmbytes.HexBytes(txID(tx)).String()),
+			attribute.Int64("bundle_order", txInfo.BundleOrder),
+			attribute.String("src", string(txInfo.SenderP2PID)),
+		))
+}
+
+// traceBundleComplete marks bundle getting all its txs.
+//
+// sc.memMtx must be held by the caller.
+func traceBundleComplete(bundle *Bundle) {
+	if bundle.span == nil || !bundle.span.IsRecording() {
+		return
+	}
+	hash := attribute.String("bundle_hash", tmbytes.HexBytes(bundle.hash).String())
+	bundle.span.SetAttributes(hash)
+	bundle.span.AddEvent("bundle complete", trace.WithAttributes(hash))
+}
+
+// traceBundleVetoed marks bundle being vetoed by the app for err.
+func traceBundleVetoed(bundle *Bundle, err error) {
+	if bundle.span == nil {
+		return
+	}
+	bundle.span.AddEvent("bundle vetoed", trace.WithAttributes(attribute.String("err", err.Error())))
+}
+
+// traceBundleAuctioned marks bundle being reaped for its auction.
+//
+// sc.memMtx must be held by the caller.
+func traceBundleAuctioned(bundle *Bundle) {
+	if bundle.span == nil {
+		return
+	}
+	bundle.span.AddEvent("bundle auctioned")
+}
+
+// endBundleSpan ends the span of bundle, removed for reason.
+func endBundleSpan(bundle *Bundle, reason RemovalReason) {
+	if bundle.span == nil {
+		return
+	}
+	bundle.span.SetAttributes(attribute.String("removal_reason", reason.String()))
+	bundle.span.End()
+}
@@ -36,6 +36,31 @@ func TestCacheRemove(t *testing.T) {
 	}
 }
 
+func TestShardedCache(t *testing.T) {
+	cache := newShardedTxCache(8, 4)
+	require.Len(t, cache.shards, 4)
+
+	txs := make([][]byte, 100)
+	for i := range txs {
+		txs[i] = []byte{byte(i)}
+		require.True(t, cache.Push(txs[i]))
+		require.False(t, cache.Push(txs[i]))
+	}
+	// each shard only keeps its share of the most recent txs
+	for _, shard := range cache.shards {
+		require.Equal(t, 2, shard.list.Len())
+	}
+	require.True(t, cache.Push(txs[0]), "the oldest tx should have been evicted")
+
+	cache.Remove(txs[99])
+	require.True(t, cache.Push(txs[99]))
+
+	cache.Reset()
+	for _, shard := range cache.shards {
+		require.Zero(t, shard.list.Len())
+	}
+}
+
 func TestCacheAfterUpdate(t *testing.T) {
 	app := kvstore.NewApplication()
 	cc := proxy.NewLocalClientCreator(app)
@@ -0,0 +1,108 @@
+package mempool
+
+import (
+	"context"
+
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/types"
+)
+
+// checkTxCall tracks the txs of a CheckTxContext or CheckTxsContext call
+// awaiting the app's response, so they can be rejected once the call's
+// context is done. Responses to an abandoned call are ignored.
+type checkTxCall struct {
+	mtx        tmsync.Mutex
+	unanswered map[[TxKeySize]byte]types.Tx
+	sent       bool // all the call's txs were sent to the app, or refused
+	abandoned  bool
+	done       chan struct{} // closed once all the txs sent were answered
+}
+
+func newCheckTxCall() *checkTxCall {
+	return &checkTxCall{
+		unanswered: make(map[[TxKeySize]byte]types.Tx),
+		done:       make(chan struct{}),
+	}
+}
+
+// expect records that tx is about to be sent to the app, returning false if
+// the call was abandoned already. A nil call expects any tx.
+func (call *checkTxCall) expect(tx types.Tx) bool {
+	if call == nil {
+		return true
+	}
+	call.mtx.Lock()
+	defer call.mtx.Unlock()
+	if call.abandoned {
+		return false
+	}
+	call.unanswered[TxKey(tx)] = tx
+	return true
+}
+
+// answer runs handle with the response to tx, unless the call was abandoned,
+// returning false then. A nil call handles any response.
+func (call *checkTxCall) answer(tx types.Tx, handle func()) bool {
+	if call == nil {
+		handle()
+		return true
+	}
+	call.mtx.Lock()
+	defer call.mtx.Unlock()
+	if call.abandoned {
+		return false
+	}
+	handle()
+	delete(call.unanswered, TxKey(tx))
+	call.closeIfDone()
+	return true
+}
+
+// markSent records that no more txs will be sent for the call.
+func (call *checkTxCall) markSent() {
+	call.mtx.Lock()
+	defer call.mtx.Unlock()
+	call.sent = true
+	call.closeIfDone()
+}
+
+func (call *checkTxCall) closeIfDone() {
+	if call.sent && !call.abandoned && len(call.unanswered) == 0 {
+		call.abandoned = true // no more responses are expected
+		close(call.done)
+	}
+}
+
+// abandon stops handling the responses to the call, returning the txs still
+// unanswered.
+func (call *checkTxCall) abandon() types.Txs {
+	call.mtx.Lock()
+	defer call.mtx.Unlock()
+	call.abandoned = true
+	txs := make(types.Txs, 0, len(call.unanswered))
+	for _, tx := range call.unanswered {
+		txs = append(txs, tx)
+	}
+	call.unanswered = nil
+	return txs
+}
+
+// wait waits for the responses to the call's txs, or for ctx to be done.
+// The txs still unanswered then are abandoned, dropped from the cache so
+// they may be sent again, and returned.
+func (mem *CListMempool) wait(ctx context.Context, call *checkTxCall) types.Txs {
+	select {
+	case <-call.done:
+		return nil
+	case <-ctx.Done():
+	}
+	txs := call.abandon()
+	for _, tx := range txs {
+		mem.cache.Remove(tx)
+	}
+	if len(txs) > 0 {
+		mem.logger.Debug("Abandoned CheckTx calls the app didn't answer in time", "txs", len(txs))
+		mem.metrics.ShedTxs.With("reason", "timeout").Add(float64(len(txs)))
+	}
+	return txs
+}
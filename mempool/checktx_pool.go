@@ -0,0 +1,66 @@
+package mempool
+
+// checkTxPool admits the txs received from peers (mempool CheckTx and sidecar
+// AddTx) on a bounded set of workers, so a slow app can't hold up the p2p
+// receive routines behind one tx at a time. Jobs are sharded by sender, so the
+// txs of each peer are still admitted in the order they were received.
+type checkTxPool struct {
+	queues []chan func()
+	quit   chan struct{}
+}
+
+// newCheckTxPool returns a pool of workers, each queueing up to queueSize
+// jobs. It must be started before jobs are submitted.
+func newCheckTxPool(workers, queueSize int) *checkTxPool {
+	pool := &checkTxPool{
+		queues: make([]chan func(), workers),
+		quit:   make(chan struct{}),
+	}
+	for i := range pool.queues {
+		pool.queues[i] = make(chan func(), queueSize)
+	}
+	return pool
+}
+
+// start runs the workers until stop is called.
+func (pool *checkTxPool) start() {
+	for _, queue := range pool.queues {
+		go pool.work(queue)
+	}
+}
+
+func (pool *checkTxPool) work(queue chan func()) {
+	for {
+		select {
+		case job := <-queue:
+			job()
+		case <-pool.quit:
+			return
+		}
+	}
+}
+
+// submit queues job on the worker of sender, blocking while its queue is
+// full. Jobs submitted after stop are dropped.
+func (pool *checkTxPool) submit(sender uint16, job func()) {
+	select {
+	case pool.queues[int(sender)%len(pool.queues)] <- job:
+	case <-pool.quit:
+	}
+}
+
+// trySubmit queues job on the worker of sender, returning false without
+// queueing it if the worker's queue is full.
+func (pool *checkTxPool) trySubmit(sender uint16, job func()) bool {
+	select {
+	case pool.queues[int(sender)%len(pool.queues)] <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop stops the workers. Queued jobs are dropped.
+func (pool *checkTxPool) stop() {
+	close(pool.quit)
+}
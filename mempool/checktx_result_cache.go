@@ -0,0 +1,85 @@
+package mempool
+
+import (
+	"container/list"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/types"
+)
+
+// CheckTxResultCache is a LRU cache of successful CheckTx responses, keyed by
+// tx hash, along with the height of the state each tx was checked against.
+// It spares the ABCI round trip for txs re-added to the mempool while the
+// state hasn't changed, and lets the sidecar learn the gas wanted by bundle
+// txs the mempool already checked.
+type CheckTxResultCache struct {
+	mtx      tmsync.Mutex
+	size     int
+	cacheMap map[[TxKeySize]byte]*list.Element
+	list     *list.List
+}
+
+type checkTxResult struct {
+	txKey  [TxKeySize]byte
+	height int64
+	res    abci.ResponseCheckTx
+}
+
+// NewCheckTxResultCache returns a CheckTxResultCache keeping the results of
+// up to size txs.
+func NewCheckTxResultCache(size int) *CheckTxResultCache {
+	return &CheckTxResultCache{
+		size:     size,
+		cacheMap: make(map[[TxKeySize]byte]*list.Element, size),
+		list:     list.New(),
+	}
+}
+
+// Push keeps res as the result of checking tx against the state at height,
+// replacing any previous one.
+func (cache *CheckTxResultCache) Push(tx types.Tx, height int64, res *abci.ResponseCheckTx) {
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	txKey := TxKey(tx)
+	if e, exists := cache.cacheMap[txKey]; exists {
+		e.Value = &checkTxResult{txKey, height, *res}
+		cache.list.MoveToBack(e)
+		return
+	}
+
+	if cache.list.Len() >= cache.size {
+		if popped := cache.list.Front(); popped != nil {
+			delete(cache.cacheMap, popped.Value.(*checkTxResult).txKey)
+			cache.list.Remove(popped)
+		}
+	}
+	cache.cacheMap[txKey] = cache.list.PushBack(&checkTxResult{txKey, height, *res})
+}
+
+// Get returns the last successful result of checking tx, and the height of
+// the state it was checked against.
+func (cache *CheckTxResultCache) Get(tx types.Tx) (abci.ResponseCheckTx, int64, bool) {
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	e, ok := cache.cacheMap[TxKey(tx)]
+	if !ok {
+		return abci.ResponseCheckTx{}, 0, false
+	}
+	result := e.Value.(*checkTxResult)
+	return result.res, result.height, true
+}
+
+// Remove drops the result of tx, if any.
+func (cache *CheckTxResultCache) Remove(tx types.Tx) {
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	txKey := TxKey(tx)
+	if e, ok := cache.cacheMap[txKey]; ok {
+		delete(cache.cacheMap, txKey)
+		cache.list.Remove(e)
+	}
+}
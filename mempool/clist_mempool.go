@@ -1,12 +1,23 @@
 package mempool
 
 import (
+	"bufio"
 	"bytes"
 	"container/list"
+	"context"
 	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	cfg "github.com/tendermint/tendermint/config"
@@ -16,7 +27,6 @@ import (
 	tmmath "github.com/tendermint/tendermint/libs/math"
 	tmos "github.com/tendermint/tendermint/libs/os"
 	tmsync "github.com/tendermint/tendermint/libs/sync"
-	"github.com/tendermint/tendermint/p2p"
 	"github.com/tendermint/tendermint/proxy"
 	"github.com/tendermint/tendermint/types"
 )
@@ -24,8 +34,6 @@ import (
 // TxKeySize is the size of the transaction key index
 const TxKeySize = sha256.Size
 
-var newline = []byte("\n")
-
 //--------------------------------------------------------------------------------
 
 // CListMempool is an ordered in-memory pool for transactions before they are
@@ -49,8 +57,54 @@ type CListMempool struct {
 	updateMtx tmsync.RWMutex
 	preCheck  PreCheckFunc
 	postCheck PostCheckFunc
-
+	// vet each tx before the app checks it, see WithTxFilters
+	txFilters []TxFilter
+
+	// CheckTx event attribute ("type.key") holding the fee a tx declares
+	feeAttribute string
+
+	// classes of txs reaped ahead of sidecar bundles
+	protectedPrefixes  [][]byte
+	protectedAttribute string
+
+	// CheckTx event attributes ("type.key") holding the sender of a tx and its
+	// sequence, to reap the txs of each sender in sequence order
+	senderAttribute   string
+	sequenceAttribute string
+
+	// replace a sender's pending tx with a tx of the same sequence paying a
+	// higher fee
+	replaceByFee bool
+
+	// CheckTx calls awaiting the app's response (atomic), past which txs from
+	// peers are refused if maxPendingChecks isn't 0
+	pendingChecks    int64
+	maxPendingChecks int64
+
+	// pending txs each declared sender may have, 0 if unlimited
+	maxTxsPerSender int
+	senderTxsMtx    tmsync.Mutex
+	senderTxs       map[string]int // sender -> number of pending txs
+
+	// CheckTx event attribute ("type.key") holding the priority of a tx, to
+	// reap txs by priority
+	priorityAttribute string
+
+	// lanes txs are split into, by name, if any, see WithLanes
+	lanes         map[string]*lane
+	defaultLane   *lane
+	mevPriority   int
+	laneAttribute string
+	lanesMtx      tmsync.Mutex
+
+	// txs sidecar bundles rely on, kept until the height they're pinned to,
+	// see PinTx
+	pinsMtx tmsync.Mutex
+	pins    map[[TxKeySize]byte]int64
+
+	walMtx       tmsync.Mutex
 	wal          *auto.AutoFile // a log of mempool txs
+	walBytes     int64          // written to the WAL since it was last rewritten
 	txs          *clist.CList   // concurrent linked-list of good txs
 	proxyAppConn proxy.AppConnMempool
 
@@ -64,10 +118,38 @@ type CListMempool struct {
 	// txsMap: txKey -> CElement
 	txsMap sync.Map
 
+	// Map of the txs declaring a sender and sequence, for replace-by-fee.
+	// senderSequencesMap: senderSequence -> CElement
+	senderSequencesMap sync.Map
+
 	// Keep a cache of already-seen txs.
 	// This reduces the pressure on the proxyApp.
 	cache txCache
 
+	// results of successful CheckTx calls, if set
+	checkTxResults *CheckTxResultCache
+
+	// checks txs received together in one round trip, if set
+	txBatchChecker TxBatchChecker
+	// recheck txs in a single batch through txBatchChecker, rather than one
+	// by one on proxyAppConn
+	recheckBatch bool
+
+	// streams added and removed txs to subscribers, if set
+	feed *TxFeed
+	// notified of every removed tx
+	removalHooks []RemovalHook
+
+	// time ReapMaxBytesMaxGas may take before returning the txs reaped so
+	// far, 0 if unlimited
+	reapTimeBudget time.Duration
+
+	// order txs by hash rather than arrival, and keep the hash of the last
+	// reaped set, see WithDeterministicReap
+	deterministicReap bool
+	lastReapMtx       tmsync.Mutex
+	lastReapHash      []byte
+
 	logger log.Logger
 
 	metrics *Metrics
@@ -94,8 +176,11 @@ func NewCListMempool(
 		recheckEnd:    nil,
 		logger:        log.NewNopLogger(),
 		metrics:       NopMetrics(),
+		pins:          make(map[[TxKeySize]byte]int64),
 	}
-	if config.CacheSize > 0 {
+	if config.CacheSize > 0 && config.CacheShards > 1 {
+		mempool.cache = newShardedTxCache(config.CacheSize, config.CacheShards)
+	} else if config.CacheSize > 0 {
 		mempool.cache = newMapTxCache(config.CacheSize)
 	} else {
 		mempool.cache = nopTxCache{}
@@ -131,15 +216,147 @@ func WithPostCheck(f PostCheckFunc) CListMempoolOption {
 	return func(mem *CListMempool) { mem.postCheck = f }
 }
 
+// WithFeeAttribute sets the CheckTx event attribute, given as "type.key", whose
+// value declares the fee a tx pays (eg. "tx.fee" = "2500uatom"). Only the
+// leading integer of the value is used. Txs without it declare no fee.
+func WithFeeAttribute(attr string) CListMempoolOption {
+	return func(mem *CListMempool) { mem.feeAttribute = attr }
+}
+
+// WithProtectedTxs sets the classes of txs (eg. oracle votes, IBC packets)
+// always reaped ahead of sidecar bundles: txs starting with any of prefixes,
+// and txs whose CheckTx events carry the attr ("type.key") attribute set to
+// "true".
+func WithProtectedTxs(prefixes [][]byte, attr string) CListMempoolOption {
+	return func(mem *CListMempool) {
+		mem.protectedPrefixes = prefixes
+		mem.protectedAttribute = attr
+	}
+}
+
+// WithSenderOrdering makes the mempool reap the txs of each sender in
+// sequence order, given the CheckTx event attributes ("type.key") holding the
+// sender of a tx and its sequence (eg. "tx.acc_seq"). Txs without both are
+// reaped in arrival order.
+func WithSenderOrdering(senderAttr, sequenceAttr string) CListMempoolOption {
+	return func(mem *CListMempool) {
+		mem.senderAttribute = senderAttr
+		mem.sequenceAttribute = sequenceAttr
+	}
+}
+
+// WithPriorityOrdering makes the mempool reap txs in descending priority
+// order, given the CheckTx event attribute ("type.key") holding the priority
+// of a tx as an integer (eg. the fee it pays per unit of gas). Txs without one
+// have priority 0, and txs of equal priority are reaped in arrival order. With
+// sender ordering too, the txs of each sender still fill that sender's slots
+// in sequence order.
+func WithPriorityOrdering(attr string) CListMempoolOption {
+	return func(mem *CListMempool) { mem.priorityAttribute = attr }
+}
+
+// WithReplaceByFee lets a tx replace the pending tx of the same sender and
+// sequence if it declares a higher fee, rather than both being kept. The
+// replacement is gossiped like any new tx. Txs declaring no higher fee are
+// dropped. It needs WithSenderOrdering and WithFeeAttribute, and the app to
+// accept the replacement in CheckTx.
+func WithReplaceByFee() CListMempoolOption {
+	return func(mem *CListMempool) { mem.replaceByFee = true }
+}
+
+// WithMaxTxsPerSender limits the txs each sender may have pending in the
+// mempool, so one account can't fill it. Txs over the limit are rejected once
+// checked. It needs WithSenderOrdering, txs declaring no sender being
+// unlimited.
+func WithMaxTxsPerSender(max int) CListMempoolOption {
+	return func(mem *CListMempool) {
+		mem.maxTxsPerSender = max
+		mem.senderTxs = make(map[string]int)
+	}
+}
+
+// WithMaxPendingCheckTxs sheds the load of a slow app: txs received from peers
+// are refused while max CheckTx calls are awaiting its response. Txs submitted
+// through the RPC (ie. without sender) are always checked.
+func WithMaxPendingCheckTxs(max int) CListMempoolOption {
+	return func(mem *CListMempool) { mem.maxPendingChecks = int64(max) }
+}
+
+// WithCheckTxResultCache sets a cache for the results of successful CheckTx
+// calls. A tx already checked against the current state is re-added without
+// calling CheckTx again.
+func WithCheckTxResultCache(cache *CheckTxResultCache) CListMempoolOption {
+	return func(mem *CListMempool) { mem.checkTxResults = cache }
+}
+
+// WithTxBatchChecker sets the checker of txs received together, used by
+// CheckTxs instead of one CheckTx call per tx.
+func WithTxBatchChecker(checker TxBatchChecker) CListMempoolOption {
+	return func(mem *CListMempool) { mem.txBatchChecker = checker }
+}
+
+// WithTxFeed sets a feed the txs added to and removed from the mempool are
+// published to.
+func WithTxFeed(feed *TxFeed) CListMempoolOption {
+	return func(mem *CListMempool) { mem.feed = feed }
+}
+
+// WithBatchedRecheck rechecks the txs after a commit in a single batch
+// through the TxBatchChecker (see WithTxBatchChecker), rather than one by one
+// on the mempool connection. Update waits for the recheck to be done. Only
+// the batching saves time: the app serves each of its connections one
+// request at a time, the local client all of them, so concurrent batches
+// wouldn't be checked any faster.
+func WithBatchedRecheck() CListMempoolOption {
+	return func(mem *CListMempool) { mem.recheckBatch = true }
+}
+
+// WithRemovalHook adds a hook notified of every tx removed from the mempool,
+// with the reason why.
+func WithRemovalHook(hook RemovalHook) CListMempoolOption {
+	return func(mem *CListMempool) { mem.removalHooks = append(mem.removalHooks, hook) }
+}
+
+// WithReapTimeBudget bounds the time ReapMaxBytesMaxGas takes. Once it's
+// spent, no more public txs are reaped: the block is made of the protected and
+// sidecar txs, and the public txs reaped so far.
+func WithReapTimeBudget(budget time.Duration) CListMempoolOption {
+	return func(mem *CListMempool) { mem.reapTimeBudget = budget }
+}
+
+// WithDeterministicReap makes reaps depend only on the txs of the mempool (and
+// the sidecar txs given): txs ordered by arrival otherwise, such as those of
+// equal priority, are ordered by ascending hash instead. It also keeps the
+// hash of the txs last returned by ReapMaxBytesMaxGas, see LastReapHash.
+func WithDeterministicReap() CListMempoolOption {
+	return func(mem *CListMempool) { mem.deterministicReap = true }
+}
+
 // WithMetrics sets the metrics.
 func WithMetrics(metrics *Metrics) CListMempoolOption {
 	return func(mem *CListMempool) { mem.metrics = metrics }
 }
 
+// The WAL's txs are prefixed by their length, unlike the txs of the WAL of
+// earlier releases, which were delimited by newlines and never read back.
+// That one is named legacyWALFileName, and set aside on start rather than
+// misread.
+const (
+	walFileName       = "wal.v2"
+	legacyWALFileName = "wal"
+)
+
+// walCompactBytes is how much the WAL may outgrow twice the size of the txs
+// in the mempool before it's rewritten with them, see compactWAL.
+const walCompactBytes = 1 << 20
+
+// InitWAL opens the WAL, where accepted txs are persisted, and checks the txs
+// it holds again, so txs pending before a restart aren't dropped. The WAL is
+// rewritten with the txs accepted again.
 func (mem *CListMempool) InitWAL() error {
 	var (
 		walDir  = mem.config.WalDir()
-		walFile = walDir + "/wal"
+		walFile = filepath.Join(walDir, walFileName)
 	)
 
 	const perm = 0700
@@ -147,16 +364,197 @@ func (mem *CListMempool) InitWAL() error {
 		return err
 	}
 
-	af, err := auto.OpenAutoFile(walFile)
+	legacyFile := filepath.Join(walDir, legacyWALFileName)
+	if _, err := os.Stat(legacyFile); err == nil {
+		if err := os.Rename(legacyFile, legacyFile+".old"); err != nil {
+			return fmt.Errorf("can't set aside legacy WAL %s: %w", legacyFile, err)
+		}
+		mem.logger.Info("Set aside the WAL of an earlier release", "path", legacyFile+".old")
+	}
+
+	txs, err := readWAL(walFile, mem.config.MaxTxBytes)
 	if err != nil {
+		return fmt.Errorf("can't read WAL %s: %w", walFile, err)
+	}
+
+	// the txs accepted again are written to a new file, replacing the WAL
+	// once all are checked, so a crash meanwhile doesn't lose them
+	tmpFile := walFile + ".tmp"
+	if err := os.Truncate(tmpFile, 0); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	af, err := auto.OpenAutoFile(tmpFile)
+	if err != nil {
+		return fmt.Errorf("can't open autofile %s: %w", tmpFile, err)
+	}
+
+	mem.walMtx.Lock()
+	mem.wal, mem.walBytes = af, 0
+	mem.walMtx.Unlock()
+
+	if len(txs) > 0 {
+		mem.logger.Info("Checking txs from the WAL again", "txs", len(txs))
+	}
+	for _, tx := range txs {
+		if err := mem.CheckTx(tx, nil, TxInfo{SenderID: UnknownPeerID}); err != nil && err != ErrTxInCache {
+			mem.logger.Info("Could not check tx from the WAL", "tx", txID(tx), "err", err)
+		}
+	}
+	if err := mem.FlushAppConn(); err != nil {
+		return err
+	}
+
+	mem.walMtx.Lock()
+	defer mem.walMtx.Unlock()
+	if err := mem.wal.Close(); err != nil {
+		return fmt.Errorf("can't close autofile %s: %w", tmpFile, err)
+	}
+	mem.wal = nil
+	if err := os.Rename(tmpFile, walFile); err != nil {
+		return err
+	}
+	if mem.wal, err = auto.OpenAutoFile(walFile); err != nil {
 		return fmt.Errorf("can't open autofile %s: %w", walFile, err)
 	}
+	return nil
+}
 
+// writeWAL persists an accepted tx, prefixed by its length, to the WAL if
+// it's open.
+func (mem *CListMempool) writeWAL(tx types.Tx) {
+	mem.walMtx.Lock()
+	defer mem.walMtx.Unlock()
+	if mem.wal == nil {
+		return
+	}
+	// TODO: Notify administrators when WAL fails
+	n, err := mem.wal.Write(encodeWALTx(tx))
+	mem.walBytes += int64(n)
+	if err != nil {
+		mem.logger.Error("Error writing tx to WAL", "tx", txID(tx), "err", err)
+	}
+}
+
+// compactWAL rewrites the WAL with the txs in the mempool, dropping the
+// committed and removed ones it still holds, once it's outgrown twice their
+// size by walCompactBytes. The WAL is left as it was if that fails.
+//
+// Lock() must be held by the caller during execution.
+func (mem *CListMempool) compactWAL() {
+	mem.walMtx.Lock()
+	defer mem.walMtx.Unlock()
+	if mem.wal == nil || mem.walBytes <= 2*mem.TxsBytes()+walCompactBytes {
+		return
+	}
+
+	walFile, tmpFile := mem.wal.Path, mem.wal.Path+".tmp"
+	written, err := mem.writeWALFile(tmpFile)
+	if err != nil {
+		mem.logger.Error("Error compacting WAL", "err", err)
+		os.Remove(tmpFile)
+		return
+	}
+	if err := mem.wal.Close(); err != nil {
+		mem.logger.Error("Error closing WAL", "err", err)
+	}
+	if err := os.Rename(tmpFile, walFile); err != nil {
+		mem.logger.Error("Error compacting WAL", "err", err)
+		os.Remove(tmpFile)
+	} else {
+		mem.logger.Debug("Compacted WAL", "bytes_before", mem.walBytes, "bytes_after", written)
+		mem.walBytes = written
+	}
+	af, err := auto.OpenAutoFile(walFile)
+	if err != nil {
+		mem.logger.Error("Error reopening WAL, accepted txs are no longer persisted", "err", err)
+		mem.wal = nil
+		return
+	}
 	mem.wal = af
-	return nil
+}
+
+// writeWALFile writes the txs in the mempool, as persisted to the WAL, to a
+// new file at path, and returns the number of bytes written.
+//
+// Lock() must be held by the caller during execution.
+func (mem *CListMempool) writeWALFile(path string) (int64, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	var (
+		w       = bufio.NewWriter(f)
+		written int64
+	)
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		n, err := w.Write(encodeWALTx(e.Value.(*MempoolTx).tx))
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return written, err
+	}
+	return written, f.Sync()
+}
+
+// encodeWALTx returns tx prefixed by its length, as persisted to the WAL.
+func encodeWALTx(tx types.Tx) []byte {
+	bz := make([]byte, binary.MaxVarintLen64+len(tx))
+	n := binary.PutUvarint(bz, uint64(len(tx)))
+	n += copy(bz[n:], tx)
+	return bz[:n]
+}
+
+// readWAL returns the txs persisted to the WAL at path. A tx cut short by a
+// crash ends the WAL. Txs over maxTxBytes, which the mempool would refuse
+// anyway, eg. after max_tx_bytes was lowered, are skipped.
+func readWAL(path string, maxTxBytes int) (types.Txs, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		r   = bufio.NewReader(f)
+		txs types.Txs
+	)
+	for {
+		size, err := binary.ReadUvarint(r)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return txs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if size > uint64(maxTxBytes) {
+			if _, err := io.CopyN(ioutil.Discard, r, int64(size)); err == io.EOF {
+				return txs, nil
+			} else if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		tx := make(types.Tx, size)
+		if _, err := io.ReadFull(r, tx); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return txs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
 }
 
 func (mem *CListMempool) CloseWAL() {
+	mem.walMtx.Lock()
+	defer mem.walMtx.Unlock()
+	if mem.wal == nil {
+		return
+	}
 	if err := mem.wal.Close(); err != nil {
 		mem.logger.Error("Error closing WAL", "err", err)
 	}
@@ -199,12 +597,27 @@ func (mem *CListMempool) Flush() {
 	for e := mem.txs.Front(); e != nil; e = e.Next() {
 		mem.txs.Remove(e)
 		e.DetachPrev()
+		mem.notifyTxRemoved(e.Value.(*MempoolTx), RemovalFlushed)
 	}
 
 	mem.txsMap.Range(func(key, _ interface{}) bool {
 		mem.txsMap.Delete(key)
 		return true
 	})
+	mem.senderSequencesMap.Range(func(key, _ interface{}) bool {
+		mem.senderSequencesMap.Delete(key)
+		return true
+	})
+	if mem.maxTxsPerSender > 0 {
+		mem.senderTxsMtx.Lock()
+		mem.senderTxs = make(map[string]int)
+		mem.senderTxsMtx.Unlock()
+	}
+	mem.lanesMtx.Lock()
+	for _, l := range mem.lanes {
+		l.numTxs = 0
+	}
+	mem.lanesMtx.Unlock()
 }
 
 // TxsFront returns the first transaction in the ordered list for peer
@@ -232,12 +645,191 @@ func (mem *CListMempool) TxsWaitChan() <-chan struct{} {
 //
 // Safe for concurrent use by multiple goroutines.
 func (mem *CListMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo TxInfo) error {
+	return mem.checkTx(tx, cb, txInfo, nil)
+}
+
+// CheckTxContext is CheckTx, but returns once the app answered, so a hung app
+// can't block the caller past ctx being done. The tx is then rejected with
+// ErrCheckTxTimeout: whatever the app answers later is ignored, and cb isn't
+// called.
+//
+// Safe for concurrent use by multiple goroutines.
+func (mem *CListMempool) CheckTxContext(
+	ctx context.Context,
+	tx types.Tx,
+	cb func(*abci.Response),
+	txInfo TxInfo,
+) error {
+	call := newCheckTxCall()
+	errCh := make(chan error, 1)
+	// off the caller's goroutine, as the local client calls the app right away
+	go func() {
+		err := mem.checkTx(tx, cb, txInfo, call)
+		call.markSent()
+		errCh <- err
+	}()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+	}
+	mem.wait(ctx, call)
+	select {
+	case <-call.done:
+		return nil
+	default:
+		return ErrCheckTxTimeout
+	}
+}
+
+func (mem *CListMempool) checkTx(tx types.Tx, cb func(*abci.Response), txInfo TxInfo, call *checkTxCall) error {
 	mem.updateMtx.RLock()
 	// use defer to unlock mutex because application (*local client*) might panic
 	defer mem.updateMtx.RUnlock()
 
+	if err := mem.admitTx(tx, txInfo, call); err != nil {
+		return err
+	}
+	if mem.checkCachedResult(tx, txInfo, cb, call) {
+		return nil
+	}
+
+	reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{Tx: tx})
+	reqRes.SetCallback(mem.reqResCb(tx, txInfo, cb, call))
+
+	return nil
+}
+
+// CheckTxs checks txs received together, like CheckTx without callback. If a
+// TxBatchChecker is set, the txs are checked in a single round trip to the
+// app, falling back to one CheckTx call per tx if the batch fails, or if the
+// mempool was updated before it was answered. Returns the error of each tx
+// that was refused before reaching the app, if any.
+//
+// Safe for concurrent use by multiple goroutines.
+func (mem *CListMempool) CheckTxs(txs types.Txs, txInfo TxInfo) []error {
+	return mem.checkTxs(txs, txInfo, nil)
+}
+
+// CheckTxsContext is CheckTxs, but returns once the app answered, like
+// CheckTxContext. The txs it didn't answer before ctx is done are rejected,
+// with ErrCheckTxTimeout as their error.
+//
+// Safe for concurrent use by multiple goroutines.
+func (mem *CListMempool) CheckTxsContext(ctx context.Context, txs types.Txs, txInfo TxInfo) []error {
+	call := newCheckTxCall()
+	errsCh := make(chan []error, 1)
+	go func() {
+		errs := mem.checkTxs(txs, txInfo, call)
+		call.markSent()
+		errsCh <- errs
+	}()
+	var errs []error
+	select {
+	case errs = <-errsCh:
+	case <-ctx.Done():
+		// the txs not sent yet are refused once abandoned
+		errs = make([]error, len(txs))
+		for i := range errs {
+			errs[i] = ErrCheckTxTimeout
+		}
+	}
+	for _, tx := range mem.wait(ctx, call) {
+		for i := range txs {
+			if bytes.Equal(txs[i], tx) {
+				errs[i] = ErrCheckTxTimeout
+			}
+		}
+	}
+	return errs
+}
+
+func (mem *CListMempool) checkTxs(txs types.Txs, txInfo TxInfo, call *checkTxCall) []error {
+	errs, batch, height := mem.admitTxs(txs, txInfo, call)
+	if len(batch) == 0 {
+		return errs
+	}
+
+	if mem.txBatchChecker != nil && len(batch) > 1 {
+		// sent without holding the lock, so a slow batch doesn't hold up
+		// Update, and the block commit with it
+		responses, err := mem.txBatchChecker.CheckTxBatch(batch)
+		if err == nil && mem.handleTxBatch(batch, responses, height, txInfo, call) {
+			return errs
+		}
+		if err != nil {
+			mem.logger.Error("Batched CheckTx failed, checking txs one by one", "txs", len(batch), "err", err)
+		}
+	}
+
+	mem.updateMtx.RLock()
+	// use defer to unlock mutex because application (*local client*) might panic
+	defer mem.updateMtx.RUnlock()
+	for _, tx := range batch {
+		reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{Tx: tx})
+		reqRes.SetCallback(mem.reqResCb(tx, txInfo, nil, call))
+	}
+	return errs
+}
+
+// admitTxs runs admitTx on each of txs, returning their errors, the txs to
+// send to the app, and the height of the mempool they were admitted at.
+func (mem *CListMempool) admitTxs(txs types.Txs, txInfo TxInfo, call *checkTxCall) ([]error, types.Txs, int64) {
+	mem.updateMtx.RLock()
+	defer mem.updateMtx.RUnlock()
+
+	errs := make([]error, len(txs))
+	batch := make(types.Txs, 0, len(txs))
+	for i, tx := range txs {
+		if errs[i] = mem.admitTx(tx, txInfo, call); errs[i] != nil {
+			continue
+		}
+		if !mem.checkCachedResult(tx, txInfo, nil, call) {
+			batch = append(batch, tx)
+		}
+	}
+	return errs, batch, mem.height
+}
+
+// handleTxBatch handles the responses to batch, checked by the app at
+// height, returning false, without handling them, if the mempool was updated
+// since, their results being stale then.
+func (mem *CListMempool) handleTxBatch(
+	batch types.Txs,
+	responses []abci.ResponseCheckTx,
+	height int64,
+	txInfo TxInfo,
+	call *checkTxCall,
+) bool {
+	mem.updateMtx.RLock()
+	defer mem.updateMtx.RUnlock()
+	if mem.height != height {
+		mem.logger.Debug("Mempool updated during batched CheckTx, checking txs one by one",
+			"txs", len(batch), "height", mem.height)
+		return false
+	}
+	// the flush keeps the results in order with the pending requests
+	mem.proxyAppConn.FlushAsync().SetCallback(func(*abci.Response) {
+		for i, tx := range batch {
+			mem.reqResCb(tx, txInfo, nil, call)(abci.ToResponseCheckTx(responses[i]))
+		}
+	})
+	return true
+}
+
+// admitTx runs the checks made on tx before it's sent to the app, and adds
+// it to the cache if they pass, expecting its response as part of call.
+func (mem *CListMempool) admitTx(tx types.Tx, txInfo TxInfo, call *checkTxCall) error {
 	txSize := len(tx)
 
+	if pending := atomic.LoadInt64(&mem.pendingChecks); mem.maxPendingChecks > 0 &&
+		txInfo.SenderID != UnknownPeerID && pending >= mem.maxPendingChecks {
+		mem.metrics.ShedTxs.With("reason", "overloaded").Add(1)
+		return ErrCheckTxOverloaded{pending, mem.maxPendingChecks}
+	}
+
 	if err := mem.isFull(txSize); err != nil {
 		return err
 	}
@@ -246,24 +838,19 @@ func (mem *CListMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo Tx
 		return ErrTxTooLarge{mem.config.MaxTxBytes, txSize}
 	}
 
+	if err := filterTx(mem.txFilters, tx, txInfo); err != nil {
+		return err
+	}
+
 	if mem.preCheck != nil {
 		if err := mem.preCheck(tx); err != nil {
 			return ErrPreCheck{err}
 		}
 	}
 
-	// NOTE: writing to the WAL and calling proxy must be done before adding tx
-	// to the cache. otherwise, if either of them fails, next time CheckTx is
-	// called with tx, ErrTxInCache will be returned without tx being checked at
-	// all even once.
-	if mem.wal != nil {
-		// TODO: Notify administrators when WAL fails
-		_, err := mem.wal.Write(append([]byte(tx), newline...))
-		if err != nil {
-			return fmt.Errorf("wal.Write: %w", err)
-		}
-	}
-
+	// NOTE: calling proxy must be done before adding tx to the cache.
+	// otherwise, if it fails, next time CheckTx is called with tx,
+	// ErrTxInCache will be returned without tx being checked at all even once.
 	// NOTE: proxyAppConn may error if tx buffer is full
 	if err := mem.proxyAppConn.Error(); err != nil {
 		return err
@@ -285,12 +872,40 @@ func (mem *CListMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo Tx
 		return ErrTxInCache
 	}
 
-	reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{Tx: tx})
-	reqRes.SetCallback(mem.reqResCb(tx, txInfo.SenderID, txInfo.SenderP2PID, cb))
+	if !call.expect(tx) {
+		mem.cache.Remove(tx)
+		return ErrCheckTxTimeout
+	}
+
+	// until the response reaches reqResCb
+	mem.metrics.PendingCheckTxs.Set(float64(atomic.AddInt64(&mem.pendingChecks, 1)))
 
 	return nil
 }
 
+// checkCachedResult re-adds tx with the same result if it was already checked
+// against the current state, returning false if it wasn't. The flush keeps
+// the result in order with the pending requests.
+func (mem *CListMempool) checkCachedResult(
+	tx types.Tx,
+	txInfo TxInfo,
+	cb func(*abci.Response),
+	call *checkTxCall,
+) bool {
+	if mem.checkTxResults == nil {
+		return false
+	}
+	res, height, ok := mem.checkTxResults.Get(tx)
+	if !ok || height != mem.height {
+		return false
+	}
+	resCb := mem.reqResCb(tx, txInfo, cb, call)
+	mem.proxyAppConn.FlushAsync().SetCallback(func(*abci.Response) {
+		resCb(abci.ToResponseCheckTx(res))
+	})
+	return true
+}
+
 // Global callback that will be called after every ABCI response.
 // Having a single global callback avoids needing to set a callback for each request.
 // However, processing the checkTx response requires the peerID (so we can track which txs we heard from who),
@@ -323,17 +938,24 @@ func (mem *CListMempool) globalCb(req *abci.Request, res *abci.Response) {
 // Used in CheckTx to record PeerID who sent us the tx.
 func (mem *CListMempool) reqResCb(
 	tx []byte,
-	peerID uint16,
-	peerP2PID p2p.ID,
+	txInfo TxInfo,
 	externalCb func(*abci.Response),
+	call *checkTxCall,
 ) func(res *abci.Response) {
 	return func(res *abci.Response) {
 		if mem.recheckCursor != nil {
 			// this should never happen
 			panic("recheck cursor is not nil in reqResCb")
 		}
+		mem.metrics.PendingCheckTxs.Set(float64(atomic.AddInt64(&mem.pendingChecks, -1)))
 
-		mem.resCbFirstTime(tx, peerID, peerP2PID, res)
+		answered := call.answer(tx, func() {
+			mem.resCbFirstTime(tx, txInfo, res)
+		})
+		if !answered {
+			// rejected already, see CheckTxContext
+			return
+		}
 
 		// update metrics
 		mem.metrics.Size.Set(float64(mem.Size()))
@@ -350,30 +972,119 @@ func (mem *CListMempool) reqResCb(
 func (mem *CListMempool) addTx(memTx *MempoolTx) {
 	e := mem.txs.PushBack(memTx)
 	mem.txsMap.Store(TxKey(memTx.tx), e)
+	if mem.replaceByFee && memTx.sender != "" {
+		mem.senderSequencesMap.Store(senderSequence{memTx.sender, memTx.sequence}, e)
+	}
+	if mem.maxTxsPerSender > 0 && memTx.sender != "" {
+		mem.senderTxsMtx.Lock()
+		mem.senderTxs[memTx.sender]++
+		mem.senderTxsMtx.Unlock()
+	}
+	mem.countLaneTx(memTx, 1)
 	atomic.AddInt64(&mem.txsBytes, int64(len(memTx.tx)))
 	mem.metrics.TxSizeBytes.Observe(float64(len(memTx.tx)))
+	mem.metrics.AddedTxs.With("origin", memTx.origin.String()).Add(1)
+	if mem.isPinned(memTx.tx) {
+		// a bundle holds the tx already
+		mem.metrics.SidecarCollidingTxs.Add(1)
+	}
+	mem.feed.publishMempoolTx(TxAdded, memTx, 0)
 }
 
 // Called from:
 //  - Update (lock held) if tx was committed
 // 	- resCbRecheck (lock not held) if tx was invalidated
-func (mem *CListMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromCache bool) {
+func (mem *CListMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromCache bool, reason RemovalReason) {
 	mem.txs.Remove(elem)
 	elem.DetachPrev()
 	mem.txsMap.Delete(TxKey(tx))
+	if memTx := elem.Value.(*MempoolTx); mem.replaceByFee && memTx.sender != "" {
+		key := senderSequence{memTx.sender, memTx.sequence}
+		if e, ok := mem.senderSequencesMap.Load(key); ok && e.(*clist.CElement) == elem {
+			mem.senderSequencesMap.Delete(key)
+		}
+	}
+	if memTx := elem.Value.(*MempoolTx); mem.maxTxsPerSender > 0 && memTx.sender != "" {
+		mem.senderTxsMtx.Lock()
+		if mem.senderTxs[memTx.sender]--; mem.senderTxs[memTx.sender] <= 0 {
+			delete(mem.senderTxs, memTx.sender)
+		}
+		mem.senderTxsMtx.Unlock()
+	}
+	mem.countLaneTx(elem.Value.(*MempoolTx), -1)
 	atomic.AddInt64(&mem.txsBytes, int64(-len(tx)))
+	mem.notifyTxRemoved(elem.Value.(*MempoolTx), reason)
 
 	if removeFromCache {
 		mem.cache.Remove(tx)
 	}
 }
 
+func (mem *CListMempool) notifyTxRemoved(memTx *MempoolTx, reason RemovalReason) {
+	if reason == RemovalCommitted {
+		mem.metrics.IncludedTxs.With("origin", memTx.origin.String()).Add(1)
+	}
+	mem.feed.publishMempoolTx(TxRemoved, memTx, reason)
+	for _, hook := range mem.removalHooks {
+		hook.TxRemoved(memTx.tx, false, reason)
+	}
+}
+
+// senderSequence identifies a tx by its declared sender and sequence.
+type senderSequence struct {
+	sender   string
+	sequence uint64
+}
+
+// replacePending removes the pending tx with the sender and sequence of
+// memTx, if any, returning false instead if memTx doesn't declare a higher
+// fee, or if the pending tx is pinned.
+func (mem *CListMempool) replacePending(memTx *MempoolTx) bool {
+	if memTx.sender == "" {
+		return true
+	}
+	e, ok := mem.senderSequencesMap.Load(senderSequence{memTx.sender, memTx.sequence})
+	if !ok {
+		return true
+	}
+	elem := e.(*clist.CElement)
+	pending := elem.Value.(*MempoolTx)
+	if memTx.fee <= pending.fee {
+		return false
+	}
+	if mem.isPinned(pending.tx) {
+		mem.logger.Debug("keeping pinned transaction from being replaced",
+			"tx", txID(pending.tx), "replacement", txID(memTx.tx))
+		return false
+	}
+	mem.logger.Debug("replacing transaction paying a higher fee",
+		"tx", txID(pending.tx), "replacement", txID(memTx.tx), "fee", pending.fee, "new_fee", memTx.fee)
+	// keep the replaced tx in the cache, so it isn't gossiped back in
+	mem.removeTx(pending.tx, elem, false, RemovalReplaced)
+	mem.metrics.ReplacedTxs.Add(1)
+	return true
+}
+
+// isSenderFull returns an error if the sender of memTx has as many pending txs
+// as it may.
+func (mem *CListMempool) isSenderFull(memTx *MempoolTx) error {
+	if mem.maxTxsPerSender <= 0 || memTx.sender == "" {
+		return nil
+	}
+	mem.senderTxsMtx.Lock()
+	defer mem.senderTxsMtx.Unlock()
+	if numTxs := mem.senderTxs[memTx.sender]; numTxs >= mem.maxTxsPerSender {
+		return ErrSenderIsFull{memTx.sender, numTxs, mem.maxTxsPerSender}
+	}
+	return nil
+}
+
 // RemoveTxByKey removes a transaction from the mempool by its TxKey index.
 func (mem *CListMempool) RemoveTxByKey(txKey [TxKeySize]byte, removeFromCache bool) {
 	if e, ok := mem.txsMap.Load(txKey); ok {
 		memTx := e.(*clist.CElement).Value.(*MempoolTx)
 		if memTx != nil {
-			mem.removeTx(memTx.tx, e.(*clist.CElement), removeFromCache)
+			mem.removeTx(memTx.tx, e.(*clist.CElement), removeFromCache, RemovalRequested)
 		}
 	}
 }
@@ -400,10 +1111,10 @@ func (mem *CListMempool) isFull(txSize int) error {
 // handled by the resCbRecheck callback.
 func (mem *CListMempool) resCbFirstTime(
 	tx []byte,
-	peerID uint16,
-	peerP2PID p2p.ID,
+	txInfo TxInfo,
 	res *abci.Response,
 ) {
+	peerID, peerP2PID := txInfo.SenderID, txInfo.SenderP2PID
 	switch r := res.Value.(type) {
 	case *abci.Response_CheckTx:
 		var postCheckErr error
@@ -424,9 +1135,47 @@ func (mem *CListMempool) resCbFirstTime(
 				height:    mem.height,
 				gasWanted: r.CheckTx.GasWanted,
 				tx:        tx,
+				fee:       declaredFee(r.CheckTx, mem.feeAttribute),
+				protected: mem.isProtected(tx, r.CheckTx),
+				timestamp: time.Now(),
+				origin:    txInfo.Origin,
 			}
+			memTx.msg, memTx.tx = encodeTxMsg(tx)
+			memTx.sender, memTx.sequence = mem.declaredSequence(r.CheckTx)
+			memTx.priority = mem.declaredPriority(r.CheckTx)
+			memTx.lane = mem.declaredLane(r.CheckTx)
 			memTx.senders.Store(peerID, true)
+			if mem.replaceByFee && !mem.replacePending(memTx) {
+				mem.logger.Debug("rejected replacement not paying a higher fee",
+					"tx", txID(tx), "peerID", peerP2PID, "sender", memTx.sender, "sequence", memTx.sequence)
+				mem.metrics.FailedTxs.Add(1)
+				// remove from cache (a higher fee might be offered later)
+				mem.cache.Remove(tx)
+				return
+			}
+			if err := mem.isLaneFull(memTx); err != nil {
+				mem.logger.Debug("rejected transaction over its lane's limit",
+					"tx", txID(tx), "peerID", peerP2PID, "err", err)
+				mem.metrics.FailedTxs.Add(1)
+				// remove from cache (the lane might have room later)
+				mem.cache.Remove(tx)
+				return
+			}
+			// checked after any replacement, which doesn't add to the
+			// sender's pending txs
+			if err := mem.isSenderFull(memTx); err != nil {
+				mem.logger.Debug("rejected transaction over its sender's limit",
+					"tx", txID(tx), "peerID", peerP2PID, "err", err)
+				mem.metrics.FailedTxs.Add(1)
+				// remove from cache (the sender might have room later)
+				mem.cache.Remove(tx)
+				return
+			}
 			mem.addTx(memTx)
+			mem.writeWAL(tx)
+			if mem.checkTxResults != nil {
+				mem.checkTxResults.Push(tx, memTx.height, r.CheckTx)
+			}
 			mem.logger.Debug("added good transaction",
 				"tx", txID(tx),
 				"res", r,
@@ -464,18 +1213,7 @@ func (mem *CListMempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 				memTx.tx,
 				tx))
 		}
-		var postCheckErr error
-		if mem.postCheck != nil {
-			postCheckErr = mem.postCheck(tx, r.CheckTx)
-		}
-		if (r.CheckTx.Code == abci.CodeTypeOK) && postCheckErr == nil {
-			// Good, nothing to do.
-		} else {
-			// Tx became invalidated due to newly committed block.
-			mem.logger.Debug("tx is no longer valid", "tx", txID(tx), "res", r, "err", postCheckErr)
-			// NOTE: we remove tx from the cache because it might be good later
-			mem.removeTx(tx, mem.recheckCursor, !mem.config.KeepInvalidTxsInCache)
-		}
+		mem.applyRecheck(mem.recheckCursor, r.CheckTx)
 		if mem.recheckCursor == mem.recheckEnd {
 			mem.recheckCursor = nil
 		} else {
@@ -495,6 +1233,30 @@ func (mem *CListMempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 	}
 }
 
+// applyRecheck keeps the tx of elem if res is still good, and removes it
+// otherwise.
+func (mem *CListMempool) applyRecheck(elem *clist.CElement, res *abci.ResponseCheckTx) {
+	tx := elem.Value.(*MempoolTx).tx
+	var postCheckErr error
+	if mem.postCheck != nil {
+		postCheckErr = mem.postCheck(tx, res)
+	}
+	if (res.Code == abci.CodeTypeOK) && postCheckErr == nil {
+		// Good, only keep the new result.
+		if mem.checkTxResults != nil {
+			mem.checkTxResults.Push(tx, mem.height, res)
+		}
+	} else {
+		// Tx became invalidated due to newly committed block.
+		mem.logger.Debug("tx is no longer valid", "tx", txID(tx), "res", res, "err", postCheckErr)
+		if mem.checkTxResults != nil {
+			mem.checkTxResults.Remove(tx)
+		}
+		// NOTE: we remove tx from the cache because it might be good later
+		mem.removeTx(tx, elem, !mem.config.KeepInvalidTxsInCache, RemovalInvalid)
+	}
+}
+
 // Safe for concurrent use by multiple goroutines.
 func (mem *CListMempool) TxsAvailable() <-chan struct{} {
 	return mem.txsAvailable
@@ -514,14 +1276,25 @@ func (mem *CListMempool) notifyTxsAvailable() {
 	}
 }
 
-// TODO: you can lose atomicity for a bundle from the sidecar that's "cut off" because of
-// max size or max gas. Not a problem now with one bundle, but in the future encode this requirement
-
+// The bundles of sidecarTxs are reaped whole or not at all: the first that
+// doesn't fit in max size or max gas ends the reap, without any of its txs.
+//
 // Safe for concurrent use by multiple goroutines.
-func (mem *CListMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64, sidecarTxs []*MempoolTx) types.Txs {
+func (mem *CListMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64, sidecarTxs []*MempoolTx) (reaped types.Txs) {
 	mem.updateMtx.RLock()
 	defer mem.updateMtx.RUnlock()
 
+	if mem.deterministicReap {
+		defer func() { mem.setLastReap(reaped) }()
+	}
+
+	start := time.Now()
+	defer func() { mem.metrics.ReapDuration.Observe(time.Since(start).Seconds()) }()
+	var deadline time.Time
+	if mem.reapTimeBudget > 0 {
+		deadline = start.Add(mem.reapTimeBudget)
+	}
+
 	var totalGas int64
 
 	// TODO: we will get a performance boost if we have a good estimate of avg
@@ -534,33 +1307,80 @@ func (mem *CListMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64, sidecarTxs [
 	// TODO: should we do this here? or at finalizeCommit?
 
 	var sidecarTxsMap sync.Map
-
 	for _, scMemTx := range sidecarTxs {
-		fmt.Println("REAPING SIDECAR TX")
-		fmt.Println(scMemTx.tx)
-		dataSize := types.ComputeProtoSizeForTxs(append(txs, scMemTx.tx))
+		sidecarTxsMap.Store(TxKey(scMemTx.tx), scMemTx)
+	}
+	// mempool txs left out for being part of the bundles already
+	var deduped int
+	defer func() { mem.metrics.ReapDedupedTxs.Add(float64(deduped)) }()
+
+	memTxs := mem.reapOrder()
+
+	// protected txs, and those of lanes ranking above the mev lane, lead the
+	// block, so no bundle can displace them
+	if mem.hasProtectedTxs() || mem.lanes != nil {
+		for _, memTx := range memTxs {
+			if !mem.leadsBundles(memTx) {
+				continue
+			}
+			if _, ok := sidecarTxsMap.Load(TxKey(memTx.tx)); ok {
+				deduped++
+				continue
+			}
 
-		// Check total size requirement
-		if maxBytes > -1 && dataSize > maxBytes {
-			return txs
+			dataSize := types.ComputeProtoSizeForTxs(append(txs, memTx.tx))
+			if maxBytes > -1 && dataSize > maxBytes {
+				return txs
+			}
+			newTotalGas := totalGas + memTx.gasWanted
+			if maxGas > -1 && newTotalGas > maxGas {
+				return txs
+			}
+			totalGas = newTotalGas
+			txs = append(txs, memTx.tx)
+		}
+	}
+
+	for start, end := 0, 0; start < len(sidecarTxs); start = end {
+		end = start + 1
+		for end < len(sidecarTxs) && sameBundle(sidecarTxs[start], sidecarTxs[end]) {
+			end++
+		}
+		bundleTxs, newTotalGas := txs, totalGas
+		for _, scMemTx := range sidecarTxs[start:end] {
+			mem.logger.Debug("Reaping sidecar tx", "tx", txID(scMemTx.tx),
+				"bundle_height", scMemTx.height+1, "bundle_id", scMemTx.bundleId, "bundle_order", scMemTx.bundleOrder)
+			bundleTxs = append(bundleTxs, scMemTx.tx)
+			newTotalGas += scMemTx.gasWanted
 		}
 
-		newTotalGas := totalGas + scMemTx.gasWanted
+		// Check total size and gas requirements, for the whole bundle
+		if maxBytes > -1 && types.ComputeProtoSizeForTxs(bundleTxs) > maxBytes {
+			return txs
+		}
 		if maxGas > -1 && newTotalGas > maxGas {
 			return txs
 		}
-		totalGas = newTotalGas
-		txs = append(txs, scMemTx.tx)
-		sidecarTxsMap.Store(TxKey(scMemTx.tx), true)
+		txs, totalGas = bundleTxs, newTotalGas
 	}
 
-	for e := mem.txs.Front(); e != nil; e = e.Next() {
-		memTx := e.Value.(*MempoolTx)
-
-		if _, ok := sidecarTxsMap.Load(TxKey(memTx.tx)); ok {
-			// SKIP THIS TRANSACTION, ALREADY SEEN IN SENTINEL
-			fmt.Println("SKIP SIDECAR TX IN REAP, skipping in mempool:")
-			fmt.Println(memTx.tx)
+	for _, memTx := range memTxs {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			mem.logger.Info("Reap ran out of time, leaving the remaining txs out",
+				"budget", mem.reapTimeBudget, "reaped", len(txs), "size", len(memTxs))
+			mem.metrics.TruncatedReaps.Add(1)
+			return txs
+		}
+		if scMemTx, ok := sidecarTxsMap.Load(TxKey(memTx.tx)); ok {
+			// already reaped with its bundle
+			scMemTx := scMemTx.(*MempoolTx)
+			mem.logger.Debug("Skipping mempool tx reaped with its bundle", "tx", txID(memTx.tx),
+				"bundle_height", scMemTx.height+1, "bundle_id", scMemTx.bundleId, "bundle_order", scMemTx.bundleOrder)
+			deduped++
+			continue
+		}
+		if mem.leadsBundles(memTx) {
+			// already reaped ahead of the sidecar txs
 			continue
 		}
 
@@ -581,6 +1401,12 @@ func (mem *CListMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64, sidecarTxs [
 	return txs
 }
 
+// sameBundle returns true if the sidecar txs a and b are part of the same
+// bundle.
+func sameBundle(a, b *MempoolTx) bool {
+	return a.height == b.height && a.bundleId == b.bundleId
+}
+
 // Safe for concurrent use by multiple goroutines.
 func (mem *CListMempool) ReapMaxTxs(max int) types.Txs {
 	mem.updateMtx.RLock()
@@ -591,13 +1417,88 @@ func (mem *CListMempool) ReapMaxTxs(max int) types.Txs {
 	}
 
 	txs := make([]types.Tx, 0, tmmath.MinInt(mem.txs.Len(), max))
-	for e := mem.txs.Front(); e != nil && len(txs) <= max; e = e.Next() {
-		memTx := e.Value.(*MempoolTx)
+	for _, memTx := range mem.reapOrder() {
+		if len(txs) > max {
+			break
+		}
 		txs = append(txs, memTx.tx)
 	}
 	return txs
 }
 
+// LastReapHash returns the hash of the txs last returned by
+// ReapMaxBytesMaxGas, ie. the data hash of a block made of them, with
+// deterministic reaping (nil otherwise).
+func (mem *CListMempool) LastReapHash() []byte {
+	mem.lastReapMtx.Lock()
+	defer mem.lastReapMtx.Unlock()
+	return mem.lastReapHash
+}
+
+func (mem *CListMempool) setLastReap(txs types.Txs) {
+	hash := txs.Hash()
+	mem.lastReapMtx.Lock()
+	mem.lastReapHash = hash
+	mem.lastReapMtx.Unlock()
+	mem.logger.Debug("Reaped txs", "height", mem.height+1, "txs", len(txs), "hash", fmt.Sprintf("%X", hash))
+}
+
+// reapOrder returns the txs of the mempool in the order they're reaped: in
+// arrival order (hash order with deterministic reaping), or descending
+// priority order with priority ordering, except that with sender ordering,
+// the txs of each sender fill the slots of that sender's txs in ascending
+// sequence order.
+func (mem *CListMempool) reapOrder() []*MempoolTx {
+	memTxs := make([]*MempoolTx, 0, mem.txs.Len())
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		memTxs = append(memTxs, e.Value.(*MempoolTx))
+	}
+	var keys map[*MempoolTx][TxKeySize]byte
+	if mem.deterministicReap {
+		keys = make(map[*MempoolTx][TxKeySize]byte, len(memTxs))
+		for _, memTx := range memTxs {
+			keys[memTx] = TxKey(memTx.tx)
+		}
+	}
+	if mem.priorityAttribute != "" || mem.lanes != nil || mem.deterministicReap {
+		sort.SliceStable(memTxs, func(i, j int) bool {
+			if pi, pj := memTxs[i].lanePriority(), memTxs[j].lanePriority(); pi != pj {
+				return pi > pj
+			}
+			if pi, pj := memTxs[i].priority, memTxs[j].priority; pi != pj || keys == nil {
+				return pi > pj
+			}
+			ki, kj := keys[memTxs[i]], keys[memTxs[j]]
+			return bytes.Compare(ki[:], kj[:]) < 0
+		})
+	}
+	if mem.senderAttribute == "" {
+		return memTxs
+	}
+
+	bySender := make(map[string][]*MempoolTx)
+	for _, memTx := range memTxs {
+		if memTx.sender != "" {
+			bySender[memTx.sender] = append(bySender[memTx.sender], memTx)
+		}
+	}
+	for _, senderTxs := range bySender {
+		senderTxs := senderTxs
+		sort.SliceStable(senderTxs, func(i, j int) bool {
+			return senderTxs[i].sequence < senderTxs[j].sequence
+		})
+	}
+	next := make(map[string]int, len(bySender))
+	for i, memTx := range memTxs {
+		if memTx.sender == "" {
+			continue
+		}
+		memTxs[i] = bySender[memTx.sender][next[memTx.sender]]
+		next[memTx.sender]++
+	}
+	return memTxs
+}
+
 // Lock() must be help by the caller during execution.
 func (mem *CListMempool) Update(
 	height int64,
@@ -637,10 +1538,14 @@ func (mem *CListMempool) Update(
 		//   100
 		// https://github.com/tendermint/tendermint/issues/3322.
 		if e, ok := mem.txsMap.Load(TxKey(tx)); ok {
-			mem.removeTx(tx, e.(*clist.CElement), false)
+			mem.removeTx(tx, e.(*clist.CElement), false, RemovalCommitted)
 		}
 	}
 
+	mem.releasePins(height)
+	mem.purgeExpiredTxs(height, time.Now())
+	mem.compactWAL()
+
 	// Either recheck non-committed txs to see if they became invalid
 	// or just notify there're some txs left.
 	if mem.Size() > 0 {
@@ -666,6 +1571,14 @@ func (mem *CListMempool) recheckTxs() {
 		panic("recheckTxs is called, but the mempool is empty")
 	}
 
+	if mem.txBatchChecker != nil && mem.recheckBatch {
+		err := mem.recheckTxsInBatch()
+		if err == nil {
+			return
+		}
+		mem.logger.Error("Batched recheck failed, rechecking txs one by one", "err", err)
+	}
+
 	mem.recheckCursor = mem.txs.Front()
 	mem.recheckEnd = mem.txs.Back()
 
@@ -682,6 +1595,34 @@ func (mem *CListMempool) recheckTxs() {
 	mem.proxyAppConn.FlushAsync()
 }
 
+// recheckTxsInBatch rechecks the txs in a single batch, and applies the
+// results in mempool order. Nothing is applied if the batch fails.
+func (mem *CListMempool) recheckTxsInBatch() error {
+	var (
+		elems = make([]*clist.CElement, 0, mem.Size())
+		txs   = make(types.Txs, 0, mem.Size())
+	)
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		elems = append(elems, e)
+		txs = append(txs, e.Value.(*MempoolTx).tx)
+	}
+	responses, err := mem.txBatchChecker.RecheckTxBatch(txs)
+	if err != nil {
+		return err
+	}
+
+	mem.metrics.RecheckTimes.Add(float64(len(txs)))
+	for i, e := range elems {
+		mem.applyRecheck(e, &responses[i])
+	}
+	mem.logger.Debug("done rechecking txs", "numtxs", len(txs))
+	mem.metrics.Size.Set(float64(mem.Size()))
+	if mem.Size() > 0 {
+		mem.notifyTxsAvailable()
+	}
+	return nil
+}
+
 //--------------------------------------------------------------------------------
 
 // Height returns the height for this transaction
@@ -689,6 +1630,143 @@ func (memTx *MempoolTx) Height() int64 {
 	return atomic.LoadInt64(&memTx.height)
 }
 
+// Tx returns the transaction itself
+func (memTx *MempoolTx) Tx() types.Tx {
+	return memTx.tx
+}
+
+// GasWanted returns the amount of gas this transaction states it will require
+func (memTx *MempoolTx) GasWanted() int64 {
+	return memTx.gasWanted
+}
+
+// Fee returns the fee this transaction declared when it was checked
+func (memTx *MempoolTx) Fee() int64 {
+	return memTx.fee
+}
+
+// purgeExpiredTxs removes the txs that were added more than TTLNumBlocks
+// blocks or TTLDuration before blockHeight was committed at now. They're
+// removed from the cache too, so they can be resubmitted.
+//
+// Lock() must be held by the caller during execution.
+func (mem *CListMempool) purgeExpiredTxs(blockHeight int64, now time.Time) {
+	ttlNumBlocks, ttlDuration := mem.config.TTLNumBlocks, mem.config.TTLDuration
+	if ttlNumBlocks == 0 && ttlDuration == 0 {
+		return
+	}
+
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		memTx := e.Value.(*MempoolTx)
+		if ((ttlNumBlocks > 0 && blockHeight-memTx.Height() > ttlNumBlocks) ||
+			(ttlDuration > 0 && now.Sub(memTx.timestamp) > ttlDuration)) && !mem.isPinned(memTx.tx) {
+			mem.removeTx(memTx.tx, e, true, RemovalExpired)
+			mem.metrics.ExpiredTxs.Add(1)
+		}
+	}
+}
+
+// Protected returns true if this transaction is always reaped ahead of
+// sidecar bundles
+func (memTx *MempoolTx) Protected() bool {
+	return memTx.protected
+}
+
+// Priority returns the priority this transaction declared when it was checked
+func (memTx *MempoolTx) Priority() int64 {
+	return memTx.priority
+}
+
+// hasProtectedTxs returns true if any class of txs is protected.
+func (mem *CListMempool) hasProtectedTxs() bool {
+	return len(mem.protectedPrefixes) > 0 || mem.protectedAttribute != ""
+}
+
+// isProtected returns true if tx, checked with res, belongs to one of the
+// protected classes of txs.
+func (mem *CListMempool) isProtected(tx types.Tx, res *abci.ResponseCheckTx) bool {
+	for _, prefix := range mem.protectedPrefixes {
+		if bytes.HasPrefix(tx, prefix) {
+			return true
+		}
+	}
+	value, ok := eventAttribute(res, mem.protectedAttribute)
+	return ok && value == "true"
+}
+
+// declaredSequence returns the sender and sequence declared by res, or an
+// empty sender if it doesn't declare both.
+func (mem *CListMempool) declaredSequence(res *abci.ResponseCheckTx) (string, uint64) {
+	sender, ok := eventAttribute(res, mem.senderAttribute)
+	if !ok || sender == "" {
+		return "", 0
+	}
+	value, ok := eventAttribute(res, mem.sequenceAttribute)
+	if !ok {
+		return "", 0
+	}
+	sequence, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return "", 0
+	}
+	return sender, sequence
+}
+
+// declaredPriority returns the priority of the tx checked with res, or 0 if it
+// doesn't declare one.
+func (mem *CListMempool) declaredPriority(res *abci.ResponseCheckTx) int64 {
+	value, ok := eventAttribute(res, mem.priorityAttribute)
+	if !ok {
+		return 0
+	}
+	priority, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return priority
+}
+
+// declaredFee returns the leading integer of the attr ("type.key") event
+// attribute of res, or 0 if there is none.
+func declaredFee(res *abci.ResponseCheckTx, attr string) int64 {
+	value, ok := eventAttribute(res, attr)
+	if !ok {
+		return 0
+	}
+	end := 0
+	for end < len(value) && value[end] >= '0' && value[end] <= '9' {
+		end++
+	}
+	fee, err := strconv.ParseInt(value[:end], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return fee
+}
+
+// eventAttribute returns the value of the first attr ("type.key") event
+// attribute of res, if any.
+func eventAttribute(res *abci.ResponseCheckTx, attr string) (string, bool) {
+	if attr == "" {
+		return "", false
+	}
+	eventType, key := attr, ""
+	if i := strings.Index(attr, "."); i >= 0 {
+		eventType, key = attr[:i], attr[i+1:]
+	}
+	for _, event := range res.Events {
+		if event.Type != eventType {
+			continue
+		}
+		for _, attribute := range event.Attributes {
+			if string(attribute.Key) == key {
+				return string(attribute.Value), true
+			}
+		}
+	}
+	return "", false
+}
+
 //--------------------------------------------------------------------------------
 
 type txCache interface {
@@ -728,11 +1806,14 @@ func (cache *mapTxCache) Reset() {
 // Push adds the given tx to the cache and returns true. It returns
 // false if tx is already in the cache.
 func (cache *mapTxCache) Push(tx types.Tx) bool {
+	// Use the tx hash in the cache
+	return cache.pushKey(TxKey(tx))
+}
+
+func (cache *mapTxCache) pushKey(txHash [TxKeySize]byte) bool {
 	cache.mtx.Lock()
 	defer cache.mtx.Unlock()
 
-	// Use the tx hash in the cache
-	txHash := TxKey(tx)
 	if moved, exists := cache.cacheMap[txHash]; exists {
 		cache.list.MoveToBack(moved)
 		return false
@@ -753,8 +1834,11 @@ func (cache *mapTxCache) Push(tx types.Tx) bool {
 
 // Remove removes the given tx from the cache.
 func (cache *mapTxCache) Remove(tx types.Tx) {
+	cache.removeKey(TxKey(tx))
+}
+
+func (cache *mapTxCache) removeKey(txHash [TxKeySize]byte) {
 	cache.mtx.Lock()
-	txHash := TxKey(tx)
 	popped := cache.cacheMap[txHash]
 	delete(cache.cacheMap, txHash)
 	if popped != nil {
@@ -764,6 +1848,51 @@ func (cache *mapTxCache) Remove(tx types.Tx) {
 	cache.mtx.Unlock()
 }
 
+// shardedTxCache splits a LRU cache of transactions into shards, each with
+// its own lock, so concurrent CheckTx calls rarely wait on each other. A tx
+// goes to the shard picked by the first bytes of its hash, and the least
+// recently used tx of its shard is evicted when the shard is full.
+type shardedTxCache struct {
+	shards []*mapTxCache
+}
+
+var _ txCache = (*shardedTxCache)(nil)
+
+// newShardedTxCache returns a new shardedTxCache holding about cacheSize txs
+// over numShards shards.
+func newShardedTxCache(cacheSize, numShards int) *shardedTxCache {
+	shardSize := (cacheSize + numShards - 1) / numShards
+	cache := &shardedTxCache{shards: make([]*mapTxCache, numShards)}
+	for i := range cache.shards {
+		cache.shards[i] = newMapTxCache(shardSize)
+	}
+	return cache
+}
+
+func (cache *shardedTxCache) shard(txHash [TxKeySize]byte) *mapTxCache {
+	return cache.shards[binary.BigEndian.Uint32(txHash[:4])%uint32(len(cache.shards))]
+}
+
+// Reset resets the cache to an empty state.
+func (cache *shardedTxCache) Reset() {
+	for _, shard := range cache.shards {
+		shard.Reset()
+	}
+}
+
+// Push adds the given tx to the cache and returns true. It returns
+// false if tx is already in the cache.
+func (cache *shardedTxCache) Push(tx types.Tx) bool {
+	txHash := TxKey(tx)
+	return cache.shard(txHash).pushKey(txHash)
+}
+
+// Remove removes the given tx from the cache.
+func (cache *shardedTxCache) Remove(tx types.Tx) {
+	txHash := TxKey(tx)
+	cache.shard(txHash).removeKey(txHash)
+}
+
 type nopTxCache struct{}
 
 var _ txCache = (*nopTxCache)(nil)
@@ -1,31 +1,48 @@
 package mempool
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	mrand "math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/gogo/protobuf/proto"
 	gogotypes "github.com/gogo/protobuf/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/tendermint/tendermint/abci/example/counter"
 	"github.com/tendermint/tendermint/abci/example/kvstore"
 	abciserver "github.com/tendermint/tendermint/abci/server"
 	abci "github.com/tendermint/tendermint/abci/types"
 	cfg "github.com/tendermint/tendermint/config"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 	"github.com/tendermint/tendermint/libs/log"
+	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
 	tmrand "github.com/tendermint/tendermint/libs/rand"
 	"github.com/tendermint/tendermint/libs/service"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/proxy"
 	"github.com/tendermint/tendermint/types"
 )
@@ -218,6 +235,25 @@ func TestReapMaxBytesMaxGasMempool(t *testing.T) {
 }
 
 // TODO: broken
+func TestReapTimeBudget(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0, WithReapTimeBudget(time.Nanosecond))
+	txs := checkTxs(t, mempool, 10, UnknownPeerID, nil, false)
+	sidecarTxs := []*MempoolTx{{tx: types.Tx("bundle0")}, {tx: types.Tx("bundle1")}}
+
+	// out of time, only the sidecar txs are reaped
+	assert.Equal(t, types.Txs{types.Tx("bundle0"), types.Tx("bundle1")},
+		mempool.ReapMaxBytesMaxGas(-1, -1, sidecarTxs))
+
+	mempool.reapTimeBudget = time.Minute
+	assert.Equal(t, txs, mempool.ReapMaxBytesMaxGas(-1, -1, nil))
+}
+
 func TestReapMaxBytesMaxGasWithTxsFromSidecar(t *testing.T) {
 	app := kvstore.NewApplication()
 	cc := proxy.NewLocalClientCreator(app)
@@ -730,6 +766,96 @@ func TestMempoolUpdate(t *testing.T) {
 	}
 }
 
+func TestMempoolTTL(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	config := cfg.ResetTestRoot("mempool_test")
+	config.Mempool.TTLNumBlocks = 2
+	config.Mempool.TTLDuration = time.Hour
+	mempool, _, cleanup := newMempoolWithAppAndConfig(cc, config)
+	defer cleanup()
+
+	// txs expire once more than ttl-num-blocks blocks were committed since
+	// they were added
+	require.NoError(t, mempool.CheckTx([]byte{0x01}, nil, TxInfo{}))
+	for height, size := range []int{1, 1, 0} {
+		mempool.Lock()
+		err := mempool.Update(int64(height+1), []types.Tx{}, abciResponses(0, abci.CodeTypeOK), nil, nil)
+		mempool.Unlock()
+		require.NoError(t, err)
+		require.Equal(t, size, mempool.Size())
+	}
+	// and can be resubmitted
+	require.NoError(t, mempool.CheckTx([]byte{0x01}, nil, TxInfo{}))
+
+	// or once they've been in for longer than ttl-duration
+	mempool.Lock()
+	mempool.purgeExpiredTxs(3, time.Now().Add(time.Minute))
+	require.Equal(t, 1, mempool.Size())
+	mempool.purgeExpiredTxs(3, time.Now().Add(2*time.Hour))
+	require.Zero(t, mempool.Size())
+	mempool.Unlock()
+}
+
+func TestPinnedTxs(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	config := cfg.TestMempoolConfig()
+	config.TTLNumBlocks = 1
+	mempool := NewCListMempool(config, appConnMem, 0)
+	sidecar := NewCListSidecar(0, WithTxPinner(mempool))
+
+	// the anchor of a bundle for height 3 is pinned, the other tx isn't
+	require.NoError(t, mempool.CheckTx(types.Tx("anchor"), nil, TxInfo{}))
+	require.NoError(t, mempool.CheckTx(types.Tx("other"), nil, TxInfo{}))
+	require.NoError(t, sidecar.AddTx(types.Tx("anchor"), TxInfo{DesiredHeight: 3, BundleSize: 2}))
+
+	update := func(height int64) {
+		mempool.Lock()
+		require.NoError(t, mempool.Update(height, nil, nil, nil, nil))
+		mempool.Unlock()
+	}
+	update(2)
+	require.Equal(t, 1, mempool.Size())
+	assert.EqualValues(t, "anchor", mempool.TxsFront().Value.(*MempoolTx).tx)
+
+	// the pin is released once the bundle's height passes
+	update(3)
+	assert.Zero(t, mempool.Size())
+	assert.Empty(t, mempool.pins)
+}
+
+func TestSidecarCollisions(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	metrics := NopMetrics()
+	metrics.SidecarCollidingTxs = generic.NewCounter("sidecar_colliding_txs")
+	metrics.ReapDedupedTxs = generic.NewCounter("reap_deduped_txs")
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0, WithMetrics(metrics))
+	sidecar := NewCListSidecar(0, WithTxPinner(mempool))
+
+	// a is public before it's bundled, b after, c is only public
+	require.NoError(t, mempool.CheckTx(types.Tx("a"), nil, TxInfo{}))
+	require.NoError(t, sidecar.AddTx(types.Tx("a"), TxInfo{DesiredHeight: 1, BundleOrder: 0, BundleSize: 2}))
+	require.NoError(t, sidecar.AddTx(types.Tx("b"), TxInfo{DesiredHeight: 1, BundleOrder: 1, BundleSize: 2}))
+	require.NoError(t, mempool.CheckTx(types.Tx("b"), nil, TxInfo{}))
+	require.NoError(t, mempool.CheckTx(types.Tx("c"), nil, TxInfo{}))
+	assert.EqualValues(t, 2, metrics.SidecarCollidingTxs.(*generic.Counter).Value())
+
+	// the bundle's copies are left out of the public txs
+	txs := mempool.ReapMaxBytesMaxGas(-1, -1, sidecar.ReapMaxTxs())
+	assert.Equal(t, types.Txs{types.Tx("a"), types.Tx("b"), types.Tx("c")}, txs)
+	assert.EqualValues(t, 2, metrics.ReapDedupedTxs.(*generic.Counter).Value())
+}
+
 func TestSidecarUpdate(t *testing.T) {
 	app := kvstore.NewApplication()
 	cc := proxy.NewLocalClientCreator(app)
@@ -765,6 +891,1023 @@ func TestSidecarUpdate(t *testing.T) {
 	}
 }
 
+func TestSidecarReset(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	_, sidecar, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	bInfo := testBundleInfo{
+		BundleSize:    2,
+		PeerId:        UnknownPeerID,
+		DesiredHeight: 1,
+		BundleId:      0,
+	}
+	addTxToSidecar(t, sidecar, bInfo, 0)
+	bInfo.BundleId = 1
+	addTxToSidecar(t, sidecar, bInfo, 0)
+	require.Equal(t, 2, sidecar.Size())
+	require.Equal(t, 2, sidecar.NumBundles())
+
+	// jump past the height the bundles were for without committing it
+	sidecar.Reset(10)
+	assert.Equal(t, 0, sidecar.Size())
+	assert.Equal(t, 0, sidecar.NumBundles())
+	assert.EqualValues(t, 0, sidecar.MaxBundleId())
+	assert.EqualValues(t, 11, sidecar.HeightForFiringAuction())
+}
+
+func TestSidecarUpdateCommittedBundles(t *testing.T) {
+	recorder := &removalRecorder{}
+	config := cfg.TestSidecarConfig()
+	config.BundleRetainHeights = 5
+	sidecar := NewCListSidecar(0, WithSidecarConfig(config), WithSidecarRemovalHook(recorder))
+
+	// a backrun of public tx "pub" for height 3, and two bundles for height 1
+	require.NoError(t, sidecar.AddTx(types.Tx("pub"), TxInfo{DesiredHeight: 3, BundleId: 0, BundleSize: 2}))
+	require.NoError(t, sidecar.AddTx(types.Tx("backrun"),
+		TxInfo{DesiredHeight: 3, BundleId: 0, BundleOrder: 1, BundleSize: 2}))
+	require.NoError(t, sidecar.AddTx(types.Tx("x"), TxInfo{DesiredHeight: 1, BundleId: 0, BundleSize: 1}))
+	require.NoError(t, sidecar.AddTx(types.Tx("y"), TxInfo{DesiredHeight: 1, BundleId: 1, BundleSize: 1}))
+
+	// "pub" made it into block 1 through the public mempool, along with
+	// bundle 0: the backrun can't be included anymore, and the included
+	// bundle isn't retained
+	block := types.Txs{types.Tx("pub"), types.Tx("x")}
+	require.NoError(t, sidecar.Update(1, block, abciResponses(2, abci.CodeTypeOK)))
+	assert.Equal(t, 1, sidecar.Size())
+	assert.Equal(t, 1, sidecar.NumBundles())
+	assert.ElementsMatch(t, []string{"3/0/conflicted", "1/0/committed"}, recorder.bundles)
+	assert.Contains(t, recorder.txs, "backrun/true/conflicted")
+}
+
+func TestSidecarCache(t *testing.T) {
+	config := cfg.TestSidecarConfig()
+	config.CacheSize = 2
+	sidecar := NewCListSidecar(0, WithSidecarConfig(config))
+	txInfo := TxInfo{DesiredHeight: 1, BundleSize: 3}
+
+	// the sidecar's cache drops duplicates, apart from the mempool's
+	require.NoError(t, sidecar.AddTx(types.Tx("tx0"), txInfo))
+	assert.Equal(t, ErrTxInCache, sidecar.AddTx(types.Tx("tx0"), txInfo))
+
+	// and only holds cache_size txs
+	txInfo.BundleOrder = 1
+	require.NoError(t, sidecar.AddTx(types.Tx("tx1"), txInfo))
+	txInfo.BundleOrder = 2
+	require.NoError(t, sidecar.AddTx(types.Tx("tx2"), txInfo))
+	assert.True(t, sidecar.cache.Push(types.Tx("tx0")), "tx0 should have been evicted")
+
+	// by default, the cache is cleared on commit
+	require.NoError(t, sidecar.Update(1, nil, nil))
+	assert.True(t, sidecar.cache.Push(types.Tx("tx2")))
+
+	// unless it's retained
+	config.CacheScope = cfg.SidecarCacheScopeRetain
+	require.NoError(t, sidecar.Update(2, nil, nil))
+	assert.False(t, sidecar.cache.Push(types.Tx("tx2")))
+}
+
+func TestSidecarTxByKey(t *testing.T) {
+	sidecar := NewCListSidecar(0)
+	bInfo := testBundleInfo{BundleSize: 2, PeerId: UnknownPeerID, DesiredHeight: 1, BundleId: 3}
+	txs := createSidecarBundleAndTxs(t, sidecar, bInfo)
+	require.Len(t, sidecar.ReapMaxTxs(), 2)
+
+	tx, txInfo, ok := sidecar.GetTxByKey(TxKey(txs[1]))
+	require.True(t, ok)
+	assert.Equal(t, txs[1], tx)
+	assert.Equal(t, TxInfo{DesiredHeight: 1, BundleId: 3, BundleOrder: 1, BundleSize: 2}, txInfo)
+
+	// a removed tx leaves its bundle incomplete until it's added again
+	sidecar.RemoveTxByKey(TxKey(txs[1]), true)
+	_, _, ok = sidecar.GetTxByKey(TxKey(txs[1]))
+	assert.False(t, ok)
+	assert.Equal(t, 1, sidecar.Size())
+	assert.Empty(t, sidecar.ReapMaxTxs())
+
+	require.NoError(t, sidecar.AddTx(txs[1], txInfo))
+	assert.Len(t, sidecar.ReapMaxTxs(), 2)
+}
+
+type bundleCheckerFunc func(height, bundleID int64, txs types.Txs) error
+
+func (f bundleCheckerFunc) CheckBundle(height, bundleID int64, txs types.Txs) error {
+	return f(height, bundleID, txs)
+}
+
+func TestSidecarBundleChecker(t *testing.T) {
+	checked := make(map[int64]types.Txs)
+	checker := bundleCheckerFunc(func(height, bundleID int64, txs types.Txs) error {
+		checked[bundleID] = txs
+		if bundleID == 1 {
+			return errors.New("front-runs an oracle tx")
+		}
+		return nil
+	})
+	sidecar := NewCListSidecar(0, WithBundleChecker(checker))
+
+	accepted := createSidecarBundleAndTxs(t, sidecar,
+		testBundleInfo{BundleSize: 2, PeerId: UnknownPeerID, DesiredHeight: 1, BundleId: 0})
+	vetoed := createSidecarBundleAndTxs(t, sidecar,
+		testBundleInfo{BundleSize: 2, PeerId: UnknownPeerID, DesiredHeight: 1, BundleId: 1})
+
+	// each bundle was checked whole, in order
+	assert.Equal(t, accepted, checked[0])
+	assert.Equal(t, vetoed, checked[1])
+
+	// the vetoed bundle's txs are gone, and it takes no more
+	assert.Equal(t, 2, sidecar.Size())
+	err := sidecar.AddTx(vetoed[0], TxInfo{SenderID: UnknownPeerID, BundleSize: 2, BundleId: 1, DesiredHeight: 1})
+	assert.Error(t, err)
+
+	memTxs, candidates := sidecar.ReapAuction()
+	require.Len(t, memTxs, 2)
+	assert.EqualValues(t, accepted[0], memTxs[0].tx)
+	assert.EqualValues(t, accepted[1], memTxs[1].tx)
+	require.Len(t, candidates, 2)
+	assert.Equal(t, types.AuctionBundleIncluded, candidates[0].Status)
+	assert.Equal(t, types.AuctionBundleVetoed, candidates[1].Status)
+}
+
+func TestSidecarAddTxContext(t *testing.T) {
+	release := make(chan struct{})
+	checker := bundleCheckerFunc(func(height, bundleID int64, txs types.Txs) error {
+		<-release
+		return nil
+	})
+	sidecar := NewCListSidecar(0, WithBundleChecker(checker))
+	defer close(release)
+
+	// the checker hangs, so the bundle is vetoed once the deadline passes
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	txInfo := TxInfo{SenderID: UnknownPeerID, BundleSize: 1, BundleId: 0, DesiredHeight: 1}
+	err := sidecar.AddTxContext(ctx, types.Tx("tx0"), txInfo)
+	require.IsType(t, ErrBundleVetoed{}, err)
+	assert.Equal(t, ErrCheckTxTimeout, err.(ErrBundleVetoed).reason)
+	assert.Equal(t, 0, sidecar.Size())
+	assert.Empty(t, sidecar.ReapMaxTxs())
+}
+
+func TestSidecarAuctionCutoff(t *testing.T) {
+	config := cfg.TestSidecarConfig()
+	config.AuctionCutoff = time.Second
+	// cutoff is larger than the proposal delay, so every new bundle for the
+	// upcoming auction is late
+	sidecar := NewCListSidecar(0, WithSidecarConfig(config), WithProposalDelay(0))
+
+	bInfo := testBundleInfo{BundleSize: 2, PeerId: UnknownPeerID, DesiredHeight: 1, BundleId: 0}
+	createSidecarBundleAndTxs(t, sidecar, bInfo)
+	require.Equal(t, 2, sidecar.Size(), "late bundles should still be queued for gossip")
+	require.Empty(t, sidecar.ReapMaxTxs(), "late bundles should not be reaped")
+
+	// bundles for later heights are not affected by this height's cutoff
+	bInfo = testBundleInfo{BundleSize: 1, PeerId: UnknownPeerID, DesiredHeight: 2, BundleId: 0}
+	createSidecarBundleAndTxs(t, sidecar, bInfo)
+
+	sidecar.Lock()
+	err := sidecar.Update(1, []types.Tx{}, abciResponses(0, abci.CodeTypeOK))
+	sidecar.Unlock()
+	require.NoError(t, err)
+	require.Len(t, sidecar.ReapMaxTxs(), 1)
+
+	// without a cutoff, bundles are always part of the auction
+	sidecar = NewCListSidecar(0, WithSidecarConfig(cfg.TestSidecarConfig()))
+	createSidecarBundleAndTxs(t, sidecar, testBundleInfo{BundleSize: 2, PeerId: UnknownPeerID, DesiredHeight: 1})
+	require.Len(t, sidecar.ReapMaxTxs(), 2)
+}
+
+func TestSidecarAuctionTiming(t *testing.T) {
+	// the window elapsed right away, so every new bundle for the upcoming
+	// auction is late, whatever the proposal delay
+	config := cfg.TestSidecarConfig()
+	config.AuctionWindow = time.Nanosecond
+	sidecar := NewCListSidecar(0, WithSidecarConfig(config), WithProposalDelay(time.Hour))
+	time.Sleep(time.Millisecond)
+	createSidecarBundleAndTxs(t, sidecar, testBundleInfo{BundleSize: 2, PeerId: UnknownPeerID, DesiredHeight: 1})
+	require.Equal(t, 2, sidecar.Size())
+	require.Empty(t, sidecar.ReapMaxTxs(), "bundles past the auction window should not be reaped")
+
+	// bundles are only taken up to max_bundle_heights_ahead past the auction
+	config = cfg.TestSidecarConfig()
+	config.MaxBundleHeightsAhead = 1
+	sidecar = NewCListSidecar(0, WithSidecarConfig(config))
+	txInfo := TxInfo{SenderID: UnknownPeerID, BundleId: 1, BundleSize: 1, DesiredHeight: 2}
+	require.NoError(t, sidecar.AddTx(types.Tx("ahead"), txInfo))
+	txInfo.DesiredHeight = 3
+	require.IsType(t, ErrWrongHeight{}, sidecar.AddTx(types.Tx("too far ahead"), txInfo))
+
+	// and auctioned at their height
+	require.Empty(t, sidecar.ReapMaxTxs())
+	sidecar.Lock()
+	err := sidecar.Update(1, []types.Tx{}, abciResponses(0, abci.CodeTypeOK))
+	sidecar.Unlock()
+	require.NoError(t, err)
+	reaped := sidecar.ReapMaxTxs()
+	require.Len(t, reaped, 1)
+	assert.Equal(t, types.Tx("ahead"), reaped[0].tx)
+
+	// unused bundles are kept for bundle_retain_heights past their height
+	config = cfg.TestSidecarConfig()
+	config.BundleRetainHeights = 1
+	sidecar = NewCListSidecar(0, WithSidecarConfig(config))
+	createSidecarBundleAndTxs(t, sidecar, testBundleInfo{BundleSize: 2, PeerId: UnknownPeerID, DesiredHeight: 1})
+	for height, size := range []int{2, 0} {
+		sidecar.Lock()
+		err := sidecar.Update(int64(height+1), []types.Tx{}, abciResponses(0, abci.CodeTypeOK))
+		sidecar.Unlock()
+		require.NoError(t, err)
+		require.Equal(t, size, sidecar.Size())
+		require.Equal(t, size/2, sidecar.NumBundles())
+	}
+}
+
+func TestSidecarReapsBundlesAhead(t *testing.T) {
+	config := cfg.TestSidecarConfig()
+	config.MaxBundleHeightsAhead = 1
+	sidecar := NewCListSidecar(0, WithSidecarConfig(config))
+
+	// a bundle for the next height, with an id above any of the upcoming
+	// auction's, isn't auctioned yet
+	require.NoError(t, sidecar.AddTx(types.Tx("now"), TxInfo{BundleSize: 1, DesiredHeight: 1}))
+	require.NoError(t, sidecar.AddTx(types.Tx("ahead"), TxInfo{BundleId: 5, BundleSize: 1, DesiredHeight: 2}))
+	reaped := sidecar.ReapMaxTxs()
+	require.Len(t, reaped, 1)
+	assert.Equal(t, types.Tx("now"), reaped[0].tx)
+
+	// but is once its height comes
+	sidecar.Lock()
+	err := sidecar.Update(1, []types.Tx{types.Tx("now")}, abciResponses(1, abci.CodeTypeOK))
+	sidecar.Unlock()
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, sidecar.MaxBundleId())
+	reaped = sidecar.ReapMaxTxs()
+	require.Len(t, reaped, 1)
+	assert.Equal(t, types.Tx("ahead"), reaped[0].tx)
+}
+
+func TestSidecarReapsOnlyHeldBundleIds(t *testing.T) {
+	sidecar := NewCListSidecar(0)
+
+	// a huge bundle id doesn't make the auction go through every id below it
+	require.NoError(t, sidecar.AddTx(types.Tx("huge"), TxInfo{BundleId: math.MaxInt64, BundleSize: 1,
+		DesiredHeight: 1}))
+	require.NoError(t, sidecar.AddTx(types.Tx("small"), TxInfo{BundleId: 3, BundleSize: 1, DesiredHeight: 1}))
+	assert.EqualValues(t, math.MaxInt64, sidecar.MaxBundleId())
+	reaped, candidates := sidecar.ReapAuction()
+	require.Len(t, reaped, 2)
+	assert.Equal(t, types.Tx("small"), reaped[0].tx)
+	assert.Equal(t, types.Tx("huge"), reaped[1].tx)
+	require.Len(t, candidates, 2)
+	assert.EqualValues(t, 3, candidates[0].BundleId)
+	assert.EqualValues(t, math.MaxInt64, candidates[1].BundleId)
+}
+
+func TestSidecarMaxBundleIdConcurrentAdds(t *testing.T) {
+	sidecar := NewCListSidecar(0)
+	var wg sync.WaitGroup
+	for id := int64(0); id < 20; id++ {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			tx := types.Tx(fmt.Sprintf("bundle%d", id))
+			assert.NoError(t, sidecar.AddTx(tx, TxInfo{BundleId: id, BundleSize: 1, DesiredHeight: 1}))
+			_ = sidecar.Dump()
+		}(id)
+	}
+	wg.Wait()
+	assert.EqualValues(t, 19, sidecar.MaxBundleId())
+}
+
+func TestSidecarBundleTTL(t *testing.T) {
+	config := cfg.TestSidecarConfig()
+	config.BundleTTLNumBlocks = 1
+	config.BundleTTLDuration = time.Hour
+	sidecar := NewCListSidecar(0, WithSidecarConfig(config))
+
+	// a bundle for a far height expires after waiting more than
+	// bundle_ttl_num_blocks blocks
+	createSidecarBundleAndTxs(t, sidecar, testBundleInfo{BundleSize: 2, PeerId: UnknownPeerID, DesiredHeight: 10})
+	for height, size := range []int{2, 0} {
+		sidecar.Lock()
+		err := sidecar.Update(int64(height+1), []types.Tx{}, abciResponses(0, abci.CodeTypeOK))
+		sidecar.Unlock()
+		require.NoError(t, err)
+		require.Equal(t, size, sidecar.Size())
+		require.Equal(t, size/2, sidecar.NumBundles())
+	}
+
+	// or after waiting longer than bundle_ttl_duration, which is also checked
+	// between commits, see bundle_purge_interval
+	createSidecarBundleAndTxs(t, sidecar, testBundleInfo{BundleSize: 2, PeerId: UnknownPeerID, DesiredHeight: 10})
+	sidecar.PurgeExpiredBundles(time.Now().Add(time.Minute))
+	require.Equal(t, 2, sidecar.Size())
+	sidecar.PurgeExpiredBundles(time.Now().Add(2 * time.Hour))
+	require.Zero(t, sidecar.Size())
+	require.Zero(t, sidecar.NumBundles())
+}
+
+func TestSidecarMinBid(t *testing.T) {
+	config := cfg.TestSidecarConfig()
+	config.MinBid = 10
+	sidecar := NewCListSidecar(0, WithSidecarConfig(config))
+
+	// bundles bidding under the floor are refused
+	txInfo := TxInfo{SenderID: UnknownPeerID, BundleSize: 1, DesiredHeight: 1, Bid: 9}
+	require.IsType(t, ErrBidTooLow{}, sidecar.AddTx(types.Tx("cheap"), txInfo))
+	txInfo.BundleId, txInfo.Bid = 1, 10
+	require.NoError(t, sidecar.AddTx(types.Tx("enough"), txInfo))
+
+	// the floor can be lowered at runtime, and the refused tx resent
+	sidecar.SetMinBid(0)
+	txInfo.BundleId, txInfo.Bid = 2, 0
+	require.NoError(t, sidecar.AddTx(types.Tx("cheap"), txInfo))
+	require.Equal(t, 2, sidecar.Size())
+}
+
+func TestSidecarBundleSize(t *testing.T) {
+	config := cfg.TestSidecarConfig()
+	config.MinBundleSize, config.MaxBundleSize = 2, 3
+	sidecar := NewCListSidecar(0, WithSidecarConfig(config))
+
+	// bundles declaring a size out of range are refused
+	txInfo := TxInfo{SenderID: UnknownPeerID, BundleSize: 1, DesiredHeight: 1}
+	require.IsType(t, ErrBundleSizeOutOfRange{}, sidecar.AddTx(types.Tx("tx"), txInfo))
+	txInfo.BundleId, txInfo.BundleSize = 1, 4
+	require.IsType(t, ErrBundleSizeOutOfRange{}, sidecar.AddTx(types.Tx("tx"), txInfo))
+
+	// and their txs may be resent in a bundle in range
+	txInfo.BundleId, txInfo.BundleSize = 2, 3
+	require.NoError(t, sidecar.AddTx(types.Tx("tx"), txInfo))
+	require.Equal(t, 1, sidecar.Size())
+}
+
+func TestSidecarMEVDisabled(t *testing.T) {
+	sidecar := NewCListSidecar(0, WithSidecarConfig(cfg.TestSidecarConfig()))
+	txInfo := TxInfo{SenderID: UnknownPeerID, BundleSize: 1, DesiredHeight: 1}
+	require.NoError(t, sidecar.AddTx(types.Tx("tx"), txInfo))
+	memTxs, _ := sidecar.ReapAuction()
+	require.Len(t, memTxs, 1)
+
+	// turning MEV off flushes the sidecar, refuses txs and auctions nothing
+	sidecar.SetMEVDisabled(true)
+	require.True(t, sidecar.MEVDisabled())
+	require.Zero(t, sidecar.Size())
+	txInfo.BundleId = 1
+	require.Equal(t, ErrMEVDisabled, sidecar.AddTx(types.Tx("tx"), txInfo))
+	memTxs, candidates := sidecar.ReapAuction()
+	assert.Empty(t, memTxs)
+	assert.Empty(t, candidates)
+
+	// until it's turned back on
+	sidecar.SetMEVDisabled(false)
+	require.NoError(t, sidecar.AddTx(types.Tx("tx"), txInfo))
+	memTxs, _ = sidecar.ReapAuction()
+	require.Len(t, memTxs, 1)
+}
+
+// feeApp declares the first byte of each tx as its fee, in a "tx.fee" event
+type feeApp struct {
+	abci.BaseApplication
+}
+
+func (feeApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	return abci.ResponseCheckTx{
+		Code:      abci.CodeTypeOK,
+		GasWanted: 1,
+		Events: []abci.Event{{
+			Type: "tx",
+			Attributes: []abci.EventAttribute{
+				{Key: []byte("fee"), Value: []byte(fmt.Sprintf("%duatom", req.Tx[0]))},
+			},
+		}},
+	}
+}
+
+func TestSelfBuilder(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(feeApp{})
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0, WithFeeAttribute("tx.fee"))
+
+	// fees 3, 0 (none declared), 7, 5 and 7 again
+	for i, fee := range []byte{3, 0, 7, 5, 7} {
+		require.NoError(t, mempool.CheckTx(types.Tx{fee, byte(i)}, nil, TxInfo{}))
+	}
+
+	memTxs, candidates := NewSelfBuilder(mempool, nil, 3).BuildBundle(-1, -1)
+	require.Len(t, memTxs, 3)
+	assert.Equal(t, types.Tx{7, 2}, memTxs[0].Tx())
+	assert.Equal(t, types.Tx{7, 4}, memTxs[1].Tx())
+	assert.Equal(t, types.Tx{5, 3}, memTxs[2].Tx())
+	require.Len(t, candidates, 1)
+	assert.Equal(t, types.AuctionBundle{
+		Size: 3, Bytes: 6, Bid: 19, Status: types.AuctionBundleIncluded, SelfBuilt: true,
+		Hash: types.Txs{{7, 2}, {7, 4}, {5, 3}}.Hash(),
+	}, candidates[0])
+
+	// txs that don't fit the gas budget are skipped, not truncated
+	memTxs, candidates = NewSelfBuilder(mempool, nil, 10).BuildBundle(-1, 2)
+	require.Len(t, memTxs, 2)
+	assert.EqualValues(t, 14, candidates[0].Bid)
+
+	// nothing is built while MEV is off
+	sidecar := NewCListSidecar(0, WithSidecarConfig(cfg.TestSidecarConfig()))
+	sidecar.SetMEVDisabled(true)
+	memTxs, candidates = NewSelfBuilder(mempool, sidecar, 10).BuildBundle(-1, -1)
+	assert.Empty(t, memTxs)
+	assert.Empty(t, candidates)
+
+	// without a fee attribute, nothing declares a fee
+	mempool = NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0)
+	require.NoError(t, mempool.CheckTx(types.Tx{9}, nil, TxInfo{}))
+	memTxs, candidates = NewSelfBuilder(mempool, nil, 10).BuildBundle(-1, -1)
+	assert.Empty(t, memTxs)
+	assert.Empty(t, candidates)
+}
+
+// protectApp marks txs starting with "ibc" as protected in a "tx.protected"
+// event
+type protectApp struct {
+	abci.BaseApplication
+}
+
+func (protectApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	protected := bytes.HasPrefix(req.Tx, []byte("ibc"))
+	return abci.ResponseCheckTx{
+		Code: abci.CodeTypeOK,
+		Events: []abci.Event{{
+			Type: "tx",
+			Attributes: []abci.EventAttribute{
+				{Key: []byte("protected"), Value: []byte(fmt.Sprintf("%t", protected))},
+			},
+		}},
+	}
+}
+
+func TestReapProtectedTxs(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(protectApp{})
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0,
+		WithProtectedTxs([][]byte{[]byte("oracle")}, "tx.protected"))
+
+	for _, tx := range []string{"public0", "oracle0", "public1", "ibc0"} {
+		require.NoError(t, mempool.CheckTx(types.Tx(tx), nil, TxInfo{}))
+	}
+	sidecarTxs := []*MempoolTx{{tx: types.Tx("bundle0"), bundleId: 1}, {tx: types.Tx("bundle1"), bundleId: 2}}
+
+	// protected txs go ahead of the bundles, the rest after them
+	got := mempool.ReapMaxBytesMaxGas(-1, -1, sidecarTxs)
+	assert.Equal(t, types.Txs{
+		types.Tx("oracle0"), types.Tx("ibc0"),
+		types.Tx("bundle0"), types.Tx("bundle1"),
+		types.Tx("public0"), types.Tx("public1"),
+	}, got)
+
+	// protected txs get the block space first
+	maxBytes := types.ComputeProtoSizeForTxs(got[:3])
+	got = mempool.ReapMaxBytesMaxGas(maxBytes, -1, sidecarTxs)
+	assert.Equal(t, types.Txs{types.Tx("oracle0"), types.Tx("ibc0"), types.Tx("bundle0")}, got)
+
+	// and bundles that don't fit are left out whole
+	sidecarTxs[1].bundleId = 1
+	got = mempool.ReapMaxBytesMaxGas(maxBytes, -1, sidecarTxs)
+	assert.Equal(t, types.Txs{types.Tx("oracle0"), types.Tx("ibc0")}, got)
+}
+
+// sequenceApp declares the sender and sequence of txs of the form
+// "sender/sequence" in a "tx" event
+type sequenceApp struct {
+	abci.BaseApplication
+}
+
+func (sequenceApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	res := abci.ResponseCheckTx{Code: abci.CodeTypeOK}
+	parts := strings.SplitN(string(req.Tx), "/", 2)
+	if len(parts) == 2 {
+		res.Events = []abci.Event{{
+			Type: "tx",
+			Attributes: []abci.EventAttribute{
+				{Key: []byte("sender"), Value: []byte(parts[0])},
+				{Key: []byte("sequence"), Value: []byte(parts[1])},
+			},
+		}}
+	}
+	return res
+}
+
+func TestReapSenderOrdering(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(sequenceApp{})
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	txs := []string{"alice/2", "bob/7", "anon", "alice/1", "bob/5", "alice/3"}
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0,
+		WithSenderOrdering("tx.sender", "tx.sequence"))
+	for _, tx := range txs {
+		require.NoError(t, mempool.CheckTx(types.Tx(tx), nil, TxInfo{}))
+	}
+
+	// each sender's txs are in sequence order, in the slots of its txs
+	expected := types.Txs{
+		types.Tx("alice/1"), types.Tx("bob/5"), types.Tx("anon"),
+		types.Tx("alice/2"), types.Tx("bob/7"), types.Tx("alice/3"),
+	}
+	assert.Equal(t, expected, mempool.ReapMaxBytesMaxGas(-1, -1, nil))
+	assert.Equal(t, expected, mempool.ReapMaxTxs(-1))
+
+	// without sender ordering, txs are reaped in arrival order
+	mempool = NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0)
+	for _, tx := range txs {
+		require.NoError(t, mempool.CheckTx(types.Tx(tx), nil, TxInfo{}))
+	}
+	assert.Equal(t, types.Tx("alice/2"), mempool.ReapMaxBytesMaxGas(-1, -1, nil)[0])
+}
+
+// priorityApp declares the priority of txs of the form "name:priority" in a
+// "tx" event, and the sender and sequence of txs of the form
+// "name:priority:sender/sequence"
+type priorityApp struct {
+	abci.BaseApplication
+}
+
+func (priorityApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	res := abci.ResponseCheckTx{Code: abci.CodeTypeOK}
+	parts := strings.Split(string(req.Tx), ":")
+	if len(parts) < 2 {
+		return res
+	}
+	attrs := []abci.EventAttribute{{Key: []byte("priority"), Value: []byte(parts[1])}}
+	if len(parts) == 3 {
+		senderSeq := strings.SplitN(parts[2], "/", 2)
+		attrs = append(attrs,
+			abci.EventAttribute{Key: []byte("sender"), Value: []byte(senderSeq[0])},
+			abci.EventAttribute{Key: []byte("sequence"), Value: []byte(senderSeq[1])})
+	}
+	res.Events = []abci.Event{{Type: "tx", Attributes: attrs}}
+	return res
+}
+
+func TestReapPriorityOrdering(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(priorityApp{})
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0, WithPriorityOrdering("tx.priority"))
+	for _, tx := range []string{"a:1", "b:5", "none", "c:5", "d:9", "e:-2"} {
+		require.NoError(t, mempool.CheckTx(types.Tx(tx), nil, TxInfo{}))
+	}
+
+	// highest priority first, ties and txs without priority in arrival order
+	expected := types.Txs{
+		types.Tx("d:9"), types.Tx("b:5"), types.Tx("c:5"),
+		types.Tx("a:1"), types.Tx("none"), types.Tx("e:-2"),
+	}
+	assert.Equal(t, expected, mempool.ReapMaxBytesMaxGas(-1, -1, nil))
+	assert.Equal(t, expected, mempool.ReapMaxTxs(-1))
+	assert.Equal(t, expected[:2], mempool.ReapMaxBytesMaxGas(types.ComputeProtoSizeForTxs(expected[:2]), -1, nil))
+
+	// a sender's txs keep their sequence order, whatever their priority
+	mempool = NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0,
+		WithPriorityOrdering("tx.priority"), WithSenderOrdering("tx.sender", "tx.sequence"))
+	for _, tx := range []string{"x:1:alice/1", "y:3", "z:7:alice/2"} {
+		require.NoError(t, mempool.CheckTx(types.Tx(tx), nil, TxInfo{}))
+	}
+	assert.Equal(t, types.Txs{types.Tx("x:1:alice/1"), types.Tx("y:3"), types.Tx("z:7:alice/2")},
+		mempool.ReapMaxTxs(-1))
+}
+
+func TestDeterministicReap(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(priorityApp{})
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	txs := []string{"a:1", "b:5", "c", "d:5", "e", "f:9:alice/2", "g:1:alice/1", "h"}
+	sidecarTxs := []*MempoolTx{{tx: types.Tx("bundle0")}}
+	reap := func(order []int) (types.Txs, []byte) {
+		mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0,
+			WithPriorityOrdering("tx.priority"), WithSenderOrdering("tx.sender", "tx.sequence"),
+			WithDeterministicReap())
+		for _, i := range order {
+			require.NoError(t, mempool.CheckTx(types.Tx(txs[i]), nil, TxInfo{}))
+		}
+		reaped := mempool.ReapMaxBytesMaxGas(-1, -1, sidecarTxs)
+		return reaped, mempool.LastReapHash()
+	}
+
+	// whatever the arrival order, the same txs are reaped in the same order
+	reaped, hash := reap([]int{0, 1, 2, 3, 4, 5, 6, 7})
+	for _, order := range [][]int{{7, 6, 5, 4, 3, 2, 1, 0}, {3, 0, 6, 2, 7, 1, 5, 4}} {
+		otherReaped, otherHash := reap(order)
+		assert.Equal(t, reaped, otherReaped)
+		assert.Equal(t, hash, otherHash)
+	}
+	assert.Equal(t, types.Txs{types.Tx("bundle0"), types.Tx("g:1:alice/1")}, reaped[:2],
+		"a sender's txs keep their sequence order")
+	assert.Equal(t, reaped.Hash(), hash)
+}
+
+// replaceApp declares the sender, sequence and fee of txs of the form
+// "sender/sequence/fee" in a "tx" event
+type replaceApp struct {
+	abci.BaseApplication
+}
+
+func (replaceApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	parts := strings.SplitN(string(req.Tx), "/", 3)
+	return abci.ResponseCheckTx{
+		Code: abci.CodeTypeOK,
+		Events: []abci.Event{{
+			Type: "tx",
+			Attributes: []abci.EventAttribute{
+				{Key: []byte("sender"), Value: []byte(parts[0])},
+				{Key: []byte("sequence"), Value: []byte(parts[1])},
+				{Key: []byte("fee"), Value: []byte(parts[2] + "uatom")},
+			},
+		}},
+	}
+}
+
+func TestReplaceByFee(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(replaceApp{})
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0,
+		WithSenderOrdering("tx.sender", "tx.sequence"), WithFeeAttribute("tx.fee"), WithReplaceByFee())
+	for _, tx := range []string{"alice/1/10", "bob/1/10", "alice/2/10"} {
+		require.NoError(t, mempool.CheckTx(types.Tx(tx), nil, TxInfo{}))
+	}
+
+	// a higher fee replaces the pending tx, in the sender's sequence slot
+	require.NoError(t, mempool.CheckTx(types.Tx("alice/1/20"), nil, TxInfo{}))
+	assert.Equal(t, types.Txs{types.Tx("bob/1/10"), types.Tx("alice/1/20"), types.Tx("alice/2/10")},
+		mempool.ReapMaxTxs(-1))
+
+	// no higher fee, no replacement, and the tx can be sent again later
+	require.NoError(t, mempool.CheckTx(types.Tx("bob/1/5"), nil, TxInfo{}))
+	require.NoError(t, mempool.CheckTx(types.Tx("alice/2/10/"), nil, TxInfo{})) // same fee
+	assert.Equal(t, 3, mempool.Size())
+	require.NoError(t, mempool.CheckTx(types.Tx("bob/1/5"), nil, TxInfo{}))
+
+	// the replaced tx isn't let back in
+	assert.Equal(t, ErrTxInCache, mempool.CheckTx(types.Tx("alice/1/10"), nil, TxInfo{}))
+
+	// replacements can be replaced
+	require.NoError(t, mempool.CheckTx(types.Tx("alice/1/30"), nil, TxInfo{}))
+	assert.Equal(t, types.Txs{types.Tx("bob/1/10"), types.Tx("alice/1/30"), types.Tx("alice/2/10")},
+		mempool.ReapMaxTxs(-1))
+}
+
+func TestMaxTxsPerSender(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(replaceApp{})
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0,
+		WithSenderOrdering("tx.sender", "tx.sequence"), WithFeeAttribute("tx.fee"), WithReplaceByFee(),
+		WithMaxTxsPerSender(2))
+	for _, tx := range []string{"alice/1/10", "alice/2/10", "alice/3/10", "bob/1/10"} {
+		require.NoError(t, mempool.CheckTx(types.Tx(tx), nil, TxInfo{}))
+	}
+	assert.Equal(t, types.Txs{types.Tx("alice/1/10"), types.Tx("alice/2/10"), types.Tx("bob/1/10")},
+		mempool.ReapMaxTxs(-1))
+
+	// replacing a pending tx doesn't count against the limit
+	require.NoError(t, mempool.CheckTx(types.Tx("alice/2/20"), nil, TxInfo{}))
+	assert.Equal(t, 3, mempool.Size())
+
+	// once the sender's txs are committed, the rejected tx can be sent again
+	mempool.Lock()
+	err := mempool.Update(1, types.Txs{types.Tx("alice/1/10")}, abciResponses(1, abci.CodeTypeOK), nil, nil)
+	mempool.Unlock()
+	require.NoError(t, err)
+	require.NoError(t, mempool.CheckTx(types.Tx("alice/3/10"), nil, TxInfo{}))
+	assert.Equal(t, types.Txs{types.Tx("bob/1/10"), types.Tx("alice/2/20"), types.Tx("alice/3/10")},
+		mempool.ReapMaxTxs(-1))
+
+	mempool.Flush()
+	for _, tx := range []string{"alice/4/10", "alice/5/10"} {
+		require.NoError(t, mempool.CheckTx(types.Tx(tx), nil, TxInfo{}))
+	}
+	assert.Equal(t, 2, mempool.Size())
+}
+
+func TestMaxPendingCheckTxs(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0, WithMaxPendingCheckTxs(1))
+	fromPeer := TxInfo{SenderID: 1}
+	require.NoError(t, mempool.CheckTx(types.Tx("tx0"), nil, fromPeer))
+	assert.EqualValues(t, 0, atomic.LoadInt64(&mempool.pendingChecks), "the response was handled")
+
+	// while the app is slow to answer, txs from peers are refused, but not
+	// those from the RPC
+	atomic.StoreInt64(&mempool.pendingChecks, 1)
+	err := mempool.CheckTx(types.Tx("tx1"), nil, fromPeer)
+	assert.IsType(t, ErrCheckTxOverloaded{}, err)
+	require.NoError(t, mempool.CheckTx(types.Tx("tx2"), nil, TxInfo{}))
+	assert.EqualValues(t, 1, atomic.LoadInt64(&mempool.pendingChecks))
+
+	// the refused tx isn't cached, so it can be sent again
+	atomic.StoreInt64(&mempool.pendingChecks, 0)
+	require.NoError(t, mempool.CheckTx(types.Tx("tx1"), nil, fromPeer))
+	assert.Equal(t, 3, mempool.Size())
+}
+
+// blockingApp holds each CheckTx call until it's released
+type blockingApp struct {
+	abci.BaseApplication
+	release chan struct{}
+}
+
+func (app blockingApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	<-app.release
+	return abci.ResponseCheckTx{Code: abci.CodeTypeOK}
+}
+
+func TestCheckTxContext(t *testing.T) {
+	app := blockingApp{release: make(chan struct{})}
+	cc := proxy.NewLocalClientCreator(app)
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0)
+	fromPeer := TxInfo{SenderID: 1}
+
+	// the app hangs, so the tx is refused once the deadline passes
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := mempool.CheckTxContext(ctx, types.Tx("tx0"), nil, fromPeer)
+	assert.Equal(t, ErrCheckTxTimeout, err)
+
+	// its late response is ignored
+	app.release <- struct{}{}
+	require.NoError(t, mempool.FlushAppConn())
+	assert.Equal(t, 0, mempool.Size())
+
+	// a call abandoned before the tx is sent doesn't reach the app
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	errs := mempool.CheckTxsContext(ctx, types.Txs{types.Tx("tx1")}, fromPeer)
+	assert.Equal(t, []error{ErrCheckTxTimeout}, errs)
+
+	// the refused txs aren't cached, so they can be sent again
+	close(app.release)
+	errs = mempool.CheckTxsContext(context.Background(), types.Txs{types.Tx("tx0"), types.Tx("tx1")}, fromPeer)
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Equal(t, 2, mempool.Size())
+}
+
+// gasApp counts the CheckTx calls it serves, and wants as much gas as a tx
+// has bytes
+type gasApp struct {
+	abci.BaseApplication
+	checks *int32
+}
+
+func (app gasApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	atomic.AddInt32(app.checks, 1)
+	return abci.ResponseCheckTx{Code: abci.CodeTypeOK, GasWanted: int64(len(req.Tx))}
+}
+
+func TestCheckTxResultCache(t *testing.T) {
+	var checks int32
+	cc := proxy.NewLocalClientCreator(gasApp{checks: &checks})
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	config := cfg.TestMempoolConfig()
+	config.Recheck = false
+	results := NewCheckTxResultCache(10)
+	mempool := NewCListMempool(config, appConnMem, 0, WithCheckTxResultCache(results))
+	sidecar := NewCListSidecar(0, WithSidecarCheckTxResultCache(results))
+
+	tx := types.Tx("tx")
+	require.NoError(t, mempool.CheckTx(tx, nil, TxInfo{}))
+	require.EqualValues(t, 1, atomic.LoadInt32(&checks))
+
+	// re-added at the same height, the tx isn't checked again
+	mempool.RemoveTxByKey(TxKey(tx), true)
+	require.NoError(t, mempool.CheckTx(tx, nil, TxInfo{}))
+	require.NoError(t, mempool.FlushAppConn())
+	assert.EqualValues(t, 1, atomic.LoadInt32(&checks))
+	require.Equal(t, 1, mempool.Size())
+	assert.Len(t, mempool.ReapMaxBytesMaxGas(-1, 1, nil), 0, "the cached gas wanted is kept")
+
+	// the sidecar gets the gas wanted by a bundle tx the mempool checked
+	require.NoError(t, sidecar.AddTx(tx, TxInfo{DesiredHeight: 1, BundleSize: 1}))
+	memTxs := sidecar.ReapMaxTxs()
+	require.Len(t, memTxs, 1)
+	assert.EqualValues(t, 2, memTxs[0].GasWanted())
+
+	// a result from a previous height, not refreshed by a recheck, doesn't
+	// count
+	mempool.Lock()
+	require.NoError(t, mempool.Update(1, nil, nil, nil, nil))
+	mempool.Unlock()
+	mempool.RemoveTxByKey(TxKey(tx), true)
+	require.NoError(t, mempool.CheckTx(tx, nil, TxInfo{}))
+	require.NoError(t, mempool.FlushAppConn())
+	assert.EqualValues(t, 2, atomic.LoadInt32(&checks))
+}
+
+// batchApp answers batches of txs on "/check_tx_batch", refusing txs
+// starting with "bad", and rechecked batches on "/check_tx_batch/recheck",
+// also refusing txs ending with "stale". It counts the CheckTx calls and
+// batches it serves.
+type batchApp struct {
+	abci.BaseApplication
+	checks, batches *int32
+}
+
+func (app batchApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	atomic.AddInt32(app.checks, 1)
+	if bytes.HasPrefix(req.Tx, []byte("bad")) {
+		return abci.ResponseCheckTx{Code: 1}
+	}
+	return abci.ResponseCheckTx{Code: abci.CodeTypeOK}
+}
+
+func (app batchApp) Query(req abci.RequestQuery) abci.ResponseQuery {
+	recheck := req.Path == "/check_tx_batch/recheck"
+	if req.Path != "/check_tx_batch" && !recheck {
+		return abci.ResponseQuery{Code: 1}
+	}
+	atomic.AddInt32(app.batches, 1)
+	var data tmproto.Data
+	if err := data.Unmarshal(req.Data); err != nil {
+		return abci.ResponseQuery{Code: 1, Log: err.Error()}
+	}
+	responses := make([]abci.ResponseCheckTx, len(data.Txs))
+	for i, tx := range data.Txs {
+		responses[i] = app.CheckTx(abci.RequestCheckTx{Tx: tx})
+		if recheck && bytes.HasSuffix(tx, []byte("stale")) {
+			responses[i].Code = 1
+		}
+	}
+	bz, err := EncodeTxBatchResponses(responses)
+	if err != nil {
+		return abci.ResponseQuery{Code: 1, Log: err.Error()}
+	}
+	return abci.ResponseQuery{Value: bz}
+}
+
+func TestCheckTxBatch(t *testing.T) {
+	var checks, batches int32
+	cc := proxy.NewLocalClientCreator(batchApp{checks: &checks, batches: &batches})
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+	appConnQuery, _ := cc.NewABCIClient()
+	require.NoError(t, appConnQuery.Start())
+	defer appConnQuery.Stop() //nolint:errcheck // ignore for tests
+
+	assert.False(t, NewQueryTxBatchChecker(appConnQuery, "/other").Supported())
+	checker := NewQueryTxBatchChecker(appConnQuery, "/check_tx_batch")
+	require.True(t, checker.Supported())
+	atomic.StoreInt32(&batches, 0)
+
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0,
+		WithTxBatchChecker(checker))
+	require.NoError(t, mempool.CheckTx(types.Tx("seen"), nil, TxInfo{}))
+	require.EqualValues(t, 1, atomic.LoadInt32(&checks))
+
+	// the txs not refused upfront are checked in a single batch
+	txs := types.Txs{types.Tx("good0"), types.Tx("seen"), types.Tx("bad0"), types.Tx("good1")}
+	errs := mempool.CheckTxs(txs, TxInfo{})
+	require.NoError(t, mempool.FlushAppConn())
+	assert.Equal(t, []error{nil, ErrTxInCache, nil, nil}, errs)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&batches))
+	assert.EqualValues(t, 4, atomic.LoadInt32(&checks))
+	assert.Equal(t, types.Txs{types.Tx("seen"), types.Tx("good0"), types.Tx("good1")},
+		mempool.ReapMaxTxs(-1))
+
+	// without a checker, the txs are checked one by one
+	mempool = NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0)
+	mempool.CheckTxs(types.Txs{types.Tx("good2"), types.Tx("good3")}, TxInfo{})
+	require.NoError(t, mempool.FlushAppConn())
+	assert.EqualValues(t, 1, atomic.LoadInt32(&batches))
+	assert.EqualValues(t, 6, atomic.LoadInt32(&checks))
+	assert.Equal(t, 2, mempool.Size())
+}
+
+// blockingTxBatchChecker holds batches until released.
+type blockingTxBatchChecker struct {
+	TxBatchChecker
+	started, release chan struct{}
+}
+
+func (bc blockingTxBatchChecker) CheckTxBatch(txs types.Txs) ([]abci.ResponseCheckTx, error) {
+	bc.started <- struct{}{}
+	<-bc.release
+	return bc.TxBatchChecker.CheckTxBatch(txs)
+}
+
+func TestCheckTxBatchDoesntBlockUpdate(t *testing.T) {
+	var checks, batches int32
+	cc := proxy.NewLocalClientCreator(batchApp{checks: &checks, batches: &batches})
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+	appConnQuery, _ := cc.NewABCIClient()
+	require.NoError(t, appConnQuery.Start())
+	defer appConnQuery.Stop() //nolint:errcheck // ignore for tests
+
+	checker := blockingTxBatchChecker{
+		TxBatchChecker: NewQueryTxBatchChecker(appConnQuery, "/check_tx_batch"),
+		started:        make(chan struct{}),
+		release:        make(chan struct{}),
+	}
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0, WithTxBatchChecker(checker))
+	done := make(chan []error)
+	go func() {
+		done <- mempool.CheckTxs(types.Txs{types.Tx("good0"), types.Tx("good1")}, TxInfo{})
+	}()
+	<-checker.started
+
+	// the mempool is updated while the batch is checked
+	updated := make(chan struct{})
+	go func() {
+		mempool.Lock()
+		defer mempool.Unlock()
+		assert.NoError(t, mempool.Update(1, nil, nil, nil, nil))
+		close(updated)
+	}()
+	select {
+	case <-updated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Update blocked by a batched CheckTx")
+	}
+
+	// so the batch's results are stale, and the txs are checked one by one
+	close(checker.release)
+	assert.Equal(t, []error{nil, nil}, <-done)
+	require.NoError(t, mempool.FlushAppConn())
+	assert.EqualValues(t, 1, atomic.LoadInt32(&batches))
+	assert.EqualValues(t, 4, atomic.LoadInt32(&checks))
+	assert.Equal(t, 2, mempool.Size())
+}
+
+func TestBatchedRecheck(t *testing.T) {
+	var checks, batches int32
+	cc := proxy.NewLocalClientCreator(batchApp{checks: &checks, batches: &batches})
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+	appConnQuery, _ := cc.NewABCIClient()
+	require.NoError(t, appConnQuery.Start())
+	defer appConnQuery.Stop() //nolint:errcheck // ignore for tests
+
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0,
+		WithTxBatchChecker(NewQueryTxBatchChecker(appConnQuery, "/check_tx_batch")), WithBatchedRecheck())
+	for i := 0; i < 10; i++ {
+		tx := types.Tx(fmt.Sprintf("tx%d", i))
+		if i%3 == 0 {
+			tx = types.Tx(fmt.Sprintf("tx%d-stale", i))
+		}
+		require.NoError(t, mempool.CheckTx(tx, nil, TxInfo{}))
+	}
+	require.EqualValues(t, 10, atomic.LoadInt32(&checks))
+
+	// the 10 txs are rechecked in a single batch, the stale ones being
+	// removed by the time Update returns
+	mempool.Lock()
+	require.NoError(t, mempool.Update(1, nil, nil, nil, nil))
+	mempool.Unlock()
+	assert.EqualValues(t, 1, atomic.LoadInt32(&batches))
+	assert.EqualValues(t, 20, atomic.LoadInt32(&checks))
+	assert.Equal(t, types.Txs{
+		types.Tx("tx1"), types.Tx("tx2"), types.Tx("tx4"), types.Tx("tx5"), types.Tx("tx7"), types.Tx("tx8"),
+	}, mempool.ReapMaxTxs(-1))
+
+	// if the batch fails, txs are rechecked one by one
+	mempool = NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0,
+		WithTxBatchChecker(NewQueryTxBatchChecker(appConnQuery, "/other")), WithBatchedRecheck())
+	require.NoError(t, mempool.CheckTx(types.Tx("tx0-stale"), nil, TxInfo{}))
+	mempool.Lock()
+	require.NoError(t, mempool.Update(1, nil, nil, nil, nil))
+	mempool.Unlock()
+	require.NoError(t, mempool.FlushAppConn())
+	assert.EqualValues(t, 22, atomic.LoadInt32(&checks))
+	assert.Equal(t, 1, mempool.Size())
+}
+
 func TestMempool_KeepInvalidTxsInCache(t *testing.T) {
 	app := counter.NewApplication(true)
 	cc := proxy.NewLocalClientCreator(app)
@@ -1049,7 +2192,7 @@ func TestMempoolCloseWAL(t *testing.T) {
 	sum1 := checksumFile(walFilepath, t)
 
 	// 6. Sanity check to ensure that the written TX matches the expectation.
-	require.Equal(t, sum1, checksumIt([]byte("foo\n")), "foo with a newline should be written")
+	require.Equal(t, sum1, checksumIt([]byte("\x03foo")), "foo prefixed by its length should be written")
 
 	// 7. Invoke CloseWAL() and ensure it discards the
 	// WAL thus any other write won't go through.
@@ -1065,6 +2208,126 @@ func TestMempoolCloseWAL(t *testing.T) {
 	require.Equal(t, 1, len(m3), "expecting the wal match in")
 }
 
+func TestMempoolWALReload(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "mempool-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	wcfg := cfg.DefaultConfig()
+	wcfg.Mempool.RootDir = rootDir
+	cc := proxy.NewLocalClientCreator(batchApp{checks: new(int32), batches: new(int32)})
+
+	// only accepted txs are persisted
+	mempool, _, _ := newMempoolWithAppAndConfig(cc, wcfg)
+	require.NoError(t, mempool.InitWAL())
+	for _, tx := range []string{"foo", "bad\nbar", "baz\n"} {
+		require.NoError(t, mempool.CheckTx(types.Tx(tx), nil, TxInfo{}))
+	}
+	walFilepath := mempool.wal.Path
+	mempool.CloseWAL()
+	txs, err := readWAL(walFilepath, wcfg.Mempool.MaxTxBytes)
+	require.NoError(t, err)
+	assert.Equal(t, types.Txs{types.Tx("foo"), types.Tx("baz\n")}, txs)
+
+	// a tx cut short by a crash is dropped
+	f, err := os.OpenFile(walFilepath, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("\x05ab"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// the WAL of an earlier release is set aside rather than misread
+	legacyFilepath := filepath.Join(wcfg.Mempool.WalDir(), legacyWALFileName)
+	require.NoError(t, ioutil.WriteFile(legacyFilepath, []byte("\xffqux\n"), 0600))
+
+	// after a restart, the accepted txs are pending again
+	mempool, _, _ = newMempoolWithAppAndConfig(cc, wcfg)
+	require.NoError(t, mempool.InitWAL())
+	defer mempool.CloseWAL()
+	assert.Equal(t, types.Txs{types.Tx("foo"), types.Tx("baz\n")}, mempool.ReapMaxTxs(-1))
+	assert.Equal(t, checksumIt([]byte("\x03foo\x04baz\n")), checksumFile(walFilepath, t),
+		"the WAL should be rewritten with the txs accepted again")
+	assert.NoFileExists(t, legacyFilepath)
+	assert.FileExists(t, legacyFilepath+".old")
+}
+
+func TestMempoolWALKeptUntilChecked(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "mempool-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	wcfg := cfg.DefaultConfig()
+	wcfg.Mempool.RootDir = rootDir
+	walFilepath := filepath.Join(wcfg.Mempool.WalDir(), walFileName)
+	require.NoError(t, os.MkdirAll(wcfg.Mempool.WalDir(), 0700))
+	require.NoError(t, ioutil.WriteFile(walFilepath, []byte("\x03foo\x03bar"), 0600))
+
+	app := blockingApp{release: make(chan struct{})}
+	mempool, _, cleanup := newMempoolWithAppAndConfig(proxy.NewLocalClientCreator(app), wcfg)
+	defer cleanup()
+	done := make(chan error)
+	go func() {
+		done <- mempool.InitWAL()
+	}()
+
+	// while the txs are checked again, the WAL still holds them all
+	app.release <- struct{}{}
+	txs, err := readWAL(walFilepath, wcfg.Mempool.MaxTxBytes)
+	require.NoError(t, err)
+	assert.Equal(t, types.Txs{types.Tx("foo"), types.Tx("bar")}, txs)
+
+	app.release <- struct{}{}
+	require.NoError(t, <-done)
+	defer mempool.CloseWAL()
+	txs, err = readWAL(walFilepath, wcfg.Mempool.MaxTxBytes)
+	require.NoError(t, err)
+	assert.Equal(t, types.Txs{types.Tx("foo"), types.Tx("bar")}, txs)
+	assert.Equal(t, walFilepath, mempool.wal.Path)
+}
+
+func TestMempoolWALCompaction(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "mempool-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	wcfg := cfg.DefaultConfig()
+	wcfg.Mempool.RootDir = rootDir
+	cc := proxy.NewLocalClientCreator(kvstore.NewApplication())
+	mempool, _, cleanup := newMempoolWithAppAndConfig(cc, wcfg)
+	defer cleanup()
+	require.NoError(t, mempool.InitWAL())
+	defer mempool.CloseWAL()
+
+	txs := make(types.Txs, 20)
+	for i := range txs {
+		txs[i] = bytes.Repeat([]byte{byte(i)}, 100_000)
+		require.NoError(t, mempool.CheckTx(txs[i], nil, TxInfo{}))
+	}
+	walFilepath := mempool.wal.Path
+
+	// committed txs stay in the WAL until it outgrows the mempool
+	mempool.Lock()
+	require.NoError(t, mempool.Update(1, txs[:1], abciResponses(1, abci.CodeTypeOK), nil, nil))
+	mempool.Unlock()
+	persisted, err := readWAL(walFilepath, wcfg.Mempool.MaxTxBytes)
+	require.NoError(t, err)
+	assert.Len(t, persisted, 20)
+
+	// and then it's rewritten with the pending txs only
+	mempool.Lock()
+	require.NoError(t, mempool.Update(2, txs[1:19], abciResponses(18, abci.CodeTypeOK), nil, nil))
+	mempool.Unlock()
+	persisted, err = readWAL(walFilepath, wcfg.Mempool.MaxTxBytes)
+	require.NoError(t, err)
+	assert.Equal(t, txs[19:], persisted)
+
+	// and written to as before
+	require.NoError(t, mempool.CheckTx(types.Tx("foo"), nil, TxInfo{}))
+	persisted, err = readWAL(walFilepath, wcfg.Mempool.MaxTxBytes)
+	require.NoError(t, err)
+	assert.Equal(t, append(txs[19:], types.Tx("foo")), persisted)
+}
+
 func TestMempool_CheckTxChecksTxSize(t *testing.T) {
 	app := kvstore.NewApplication()
 	cc := proxy.NewLocalClientCreator(app)
@@ -1267,3 +2530,748 @@ func abciResponses(n int, code uint32) []*abci.ResponseDeliverTx {
 	}
 	return responses
 }
+
+func TestSidecarLimits(t *testing.T) {
+	config := cfg.TestSidecarConfig()
+	config.MaxTxs, config.MaxBytes, config.MaxBundles = 3, 0, 2
+	sidecar := NewCListSidecar(0, WithSidecarConfig(config))
+	addTx := func(tx string, bundleID, bundleOrder int64) error {
+		return sidecar.AddTx(types.Tx(tx), TxInfo{DesiredHeight: 1, BundleId: bundleID, BundleOrder: bundleOrder, BundleSize: 2})
+	}
+
+	require.NoError(t, addTx("a0", 0, 0))
+	require.NoError(t, addTx("b0", 1, 0))
+	// no room for a third bundle, but for the txs of existing ones
+	assert.IsType(t, ErrSidecarIsFull{}, addTx("c0", 2, 0))
+	require.NoError(t, addTx("a1", 0, 1))
+	// no room for a fourth tx
+	assert.IsType(t, ErrSidecarIsFull{}, addTx("b1", 1, 1))
+
+	// refused txs can be sent again once there's room
+	sidecar.RemoveTxByKey(TxKey(types.Tx("a0")), false)
+	require.NoError(t, addTx("b1", 1, 1))
+	assert.Equal(t, 3, sidecar.Size())
+
+	config.MaxTxs, config.MaxBytes, config.MaxBundles = 0, 4, 0
+	sidecar = NewCListSidecar(0, WithSidecarConfig(config))
+	require.NoError(t, addTx("a0", 0, 0))
+	require.NoError(t, addTx("a1", 0, 1))
+	assert.IsType(t, ErrSidecarIsFull{}, addTx("b0", 1, 0))
+	// a tx larger than the sidecar never fits
+	assert.IsType(t, ErrTxTooLarge{}, addTx("large", 1, 0))
+	// nor does one at an order already taken
+	sidecar.RemoveTxByKey(TxKey(types.Tx("a1")), false)
+	assert.IsType(t, ErrBundleOrderTaken{}, addTx("b1", 0, 0))
+}
+
+func TestSidecarMaxMemory(t *testing.T) {
+	var (
+		txBytes     = txMemBytes(types.Tx("a0"))
+		bundleBytes = int64(bundleOverheadBytes)
+	)
+	config := cfg.TestSidecarConfig()
+	config.MaxMemory = config.CacheMemBytes() + 2*bundleBytes + 3*txBytes + 1
+	sidecar := NewCListSidecar(0, WithSidecarConfig(config))
+	addTx := func(tx string, height, bundleID, bundleOrder, bundleSize int64) error {
+		return sidecar.AddTx(types.Tx(tx), TxInfo{DesiredHeight: height, BundleId: bundleID, BundleOrder: bundleOrder, BundleSize: bundleSize})
+	}
+
+	require.NoError(t, addTx("a0", 1, 0, 0, 2))
+	require.NoError(t, addTx("a1", 1, 0, 1, 2))
+	require.NoError(t, addTx("b0", 2, 0, 0, 2))
+	assert.Equal(t, 2*bundleBytes+3*txBytes, sidecar.MemBytes())
+
+	// the incomplete bundle for a later height is evicted to make room
+	require.NoError(t, addTx("c0", 1, 1, 0, 1))
+	assert.Equal(t, 2*bundleBytes+3*txBytes, sidecar.MemBytes())
+	_, _, ok := sidecar.GetTxByKey(TxKey(types.Tx("b0")))
+	assert.False(t, ok)
+	assert.Len(t, sidecar.ReapMaxTxs(), 3)
+
+	// complete bundles, and those for the same height, are never evicted
+	assert.IsType(t, ErrSidecarIsFull{}, addTx("d0", 1, 2, 0, 1))
+	assert.IsType(t, ErrSidecarIsFull{}, addTx("b0", 2, 0, 0, 2))
+
+	// refused and evicted txs can be sent again once there's room, the
+	// bundles they left being held until purged
+	sidecar.RemoveTxByKey(TxKey(types.Tx("c0")), false)
+	sidecar.RemoveTxByKey(TxKey(types.Tx("a1")), false)
+	assert.Equal(t, 2*bundleBytes+txBytes, sidecar.MemBytes())
+	require.NoError(t, addTx("b0", 2, 0, 0, 2))
+
+	sidecar.Flush()
+	assert.Zero(t, sidecar.MemBytes())
+}
+
+func TestSidecarMetrics(t *testing.T) {
+	metrics := NopMetrics()
+	metrics.SidecarSize = generic.NewGauge("size")
+	metrics.SidecarSizeBytes = generic.NewGauge("size_bytes")
+	metrics.SidecarBundles = generic.NewGauge("bundles")
+	metrics.SidecarAddedTxs = generic.NewCounter("added_txs")
+	metrics.SidecarAuctionHeight = generic.NewGauge("auction_height")
+	metrics.SidecarBundlesReceived = generic.NewCounter("bundles_received")
+	metrics.SidecarBundlesIncluded = generic.NewCounter("bundles_included")
+	metrics.SidecarBundlesExpired = generic.NewCounter("bundles_expired")
+	metrics.BundleCompletionLatency = generic.NewSimpleHistogram()
+	metrics.BundleAuctionLatency = generic.NewSimpleHistogram()
+	metrics.BundleCompleteToAuctionLatency = generic.NewSimpleHistogram()
+	metrics.BundleSizeTxs = generic.NewSimpleHistogram()
+	metrics.BundleSizeBytes = generic.NewSimpleHistogram()
+	sidecar := NewCListSidecar(0, WithSidecarConfig(cfg.TestSidecarConfig()), WithSidecarMetrics(metrics))
+	addTx := func(tx string, height, bundleID, bundleOrder, bundleSize int64) error {
+		return sidecar.AddTx(types.Tx(tx), TxInfo{DesiredHeight: height, BundleId: bundleID, BundleOrder: bundleOrder, BundleSize: bundleSize})
+	}
+	assert.EqualValues(t, 1, metrics.SidecarAuctionHeight.(*generic.Gauge).Value())
+
+	// the latencies are measured from the first tx being received
+	require.NoError(t, addTx("a1", 1, 0, 1, 2))
+	require.NoError(t, sidecar.AddTx(types.Tx("a0"), TxInfo{DesiredHeight: 1, BundleSize: 2, ReceivedAt: time.Now().Add(-time.Second)}))
+	assert.GreaterOrEqual(t, metrics.BundleCompletionLatency.(*generic.SimpleHistogram).ApproximateMovingAverage(), 1.0)
+	assert.EqualValues(t, 2, metrics.BundleSizeTxs.(*generic.SimpleHistogram).ApproximateMovingAverage())
+	assert.EqualValues(t, 4, metrics.BundleSizeBytes.(*generic.SimpleHistogram).ApproximateMovingAverage())
+	time.Sleep(10 * time.Millisecond)
+	sidecar.ReapMaxTxs()
+	assert.GreaterOrEqual(t, metrics.BundleAuctionLatency.(*generic.SimpleHistogram).ApproximateMovingAverage(), 1.0)
+	// while the bundle was complete only since a0 arrived
+	completeToAuction := metrics.BundleCompleteToAuctionLatency.(*generic.SimpleHistogram).ApproximateMovingAverage()
+	assert.GreaterOrEqual(t, completeToAuction, 0.01)
+	assert.Less(t, completeToAuction, 1.0)
+	require.NoError(t, addTx("b00", 2, 0, 0, 1))
+	assert.Error(t, addTx("c0", 0, 0, 0, 1))
+	assert.EqualValues(t, 3, metrics.SidecarSize.(*generic.Gauge).Value())
+	assert.EqualValues(t, 7, metrics.SidecarSizeBytes.(*generic.Gauge).Value())
+	assert.EqualValues(t, 2, metrics.SidecarBundles.(*generic.Gauge).Value())
+	assert.EqualValues(t, 3, metrics.SidecarAddedTxs.(*generic.Counter).Value())
+
+	sidecar.Lock()
+	require.NoError(t, sidecar.Update(1, types.Txs{types.Tx("a0"), types.Tx("a1")}, abciResponses(2, abci.CodeTypeOK)))
+	sidecar.Unlock()
+	assert.EqualValues(t, 1, metrics.SidecarSize.(*generic.Gauge).Value())
+	assert.EqualValues(t, 3, metrics.SidecarSizeBytes.(*generic.Gauge).Value())
+	assert.EqualValues(t, 1, metrics.SidecarBundles.(*generic.Gauge).Value())
+	assert.EqualValues(t, 2, metrics.SidecarAuctionHeight.(*generic.Gauge).Value())
+
+	// the bundle for height 2 isn't, and expires
+	sidecar.Lock()
+	require.NoError(t, sidecar.Update(2, types.Txs{}, abciResponses(0, abci.CodeTypeOK)))
+	sidecar.Unlock()
+	assert.EqualValues(t, 2, metrics.SidecarBundlesReceived.(*generic.Counter).Value())
+	assert.EqualValues(t, 1, metrics.SidecarBundlesIncluded.(*generic.Counter).Value())
+	assert.EqualValues(t, 1, metrics.SidecarBundlesExpired.(*generic.Counter).Value())
+
+	assert.Equal(t, "height_passed", rejectionReason(ErrWrongHeight{0, 1}))
+	assert.Equal(t, "height_too_far", rejectionReason(ErrWrongHeight{5, 1}))
+	assert.Equal(t, "too_big", rejectionReason(ErrTxTooLarge{1, 2}))
+	assert.Equal(t, "duplicate", rejectionReason(ErrBundleOrderTaken{}))
+	assert.Equal(t, "in_cache", rejectionReason(ErrTxInCache))
+	assert.Equal(t, "other", rejectionReason(errors.New("other")))
+}
+
+func TestSidecarReassemblyMetrics(t *testing.T) {
+	metrics := NopMetrics()
+	metrics.SidecarTxsOutOfOrder = generic.NewCounter("txs_out_of_order")
+	metrics.SidecarTxsReordered = generic.NewCounter("txs_reordered")
+	incomplete := newCountersByLabels()
+	metrics.SidecarBundlesIncomplete = incomplete
+	sidecar := NewCListSidecar(0, WithSidecarConfig(cfg.TestSidecarConfig()), WithSidecarMetrics(metrics))
+	addTx := func(tx string, bundleID, bundleOrder, bundleSize int64) {
+		require.NoError(t, sidecar.AddTx(types.Tx(tx), TxInfo{
+			DesiredHeight: 1, BundleId: bundleID, BundleOrder: bundleOrder, BundleSize: bundleSize}))
+	}
+
+	// a2 arrives before a0 and a1, which are slotted before it
+	addTx("a2", 0, 2, 3)
+	addTx("a0", 0, 0, 3)
+	addTx("a1", 0, 1, 3)
+	assert.EqualValues(t, 1, metrics.SidecarTxsOutOfOrder.(*generic.Counter).Value())
+	assert.EqualValues(t, 2, metrics.SidecarTxsReordered.(*generic.Counter).Value())
+	// b0 never arrives
+	addTx("b1", 1, 1, 2)
+	assert.EqualValues(t, 2, metrics.SidecarTxsOutOfOrder.(*generic.Counter).Value())
+
+	sidecar.Lock()
+	require.NoError(t, sidecar.Update(1, types.Txs{}, abciResponses(0, abci.CodeTypeOK)))
+	sidecar.Unlock()
+	assert.EqualValues(t, 1, incomplete.value("unused"))
+	assert.Len(t, incomplete.counts, 1)
+}
+
+func TestSidecarBundleLogs(t *testing.T) {
+	var buf bytes.Buffer
+	sidecar := NewCListSidecar(0, WithSidecarConfig(cfg.TestSidecarConfig()))
+	sidecar.SetLogger(log.NewTMLogger(log.NewSyncWriter(&buf)))
+	txs := types.Txs{types.Tx("a0"), types.Tx("a1")}
+	for order, tx := range txs {
+		require.NoError(t, sidecar.AddTx(tx, TxInfo{DesiredHeight: 1, BundleId: 7, BundleOrder: int64(order), BundleSize: 2}))
+	}
+	sidecar.ReapMaxTxs()
+
+	// every line about the bundle carries its fields, and its hash once known
+	var lines []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.Contains(line, "bundle_id=7") {
+			assert.Contains(t, line, "bundle_height=1")
+			lines = append(lines, line)
+		}
+	}
+	hash := fmt.Sprintf("bundle_hash=%X", txs.Hash())
+	assert.Contains(t, strings.Join(lines, "\n"), "Added sidecar tx")
+	for _, msg := range []string{"Bundle complete", "Reaped bundle in auction"} {
+		found := false
+		for _, line := range lines {
+			if strings.Contains(line, msg) {
+				found = true
+				assert.Contains(t, line, hash, msg)
+			}
+		}
+		assert.True(t, found, msg)
+	}
+
+	// as do those of the proposal's assembly
+	buf.Reset()
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, _, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	mempool.SetLogger(log.NewTMLogger(log.NewSyncWriter(&buf)))
+	require.NoError(t, mempool.CheckTx(txs[1], nil, TxInfo{}))
+	mempool.ReapMaxBytesMaxGas(-1, -1, sidecar.ReapMaxTxs())
+	for _, msg := range []string{"Reaping sidecar tx", "Skipping mempool tx reaped with its bundle"} {
+		found := false
+		for _, line := range strings.Split(buf.String(), "\n") {
+			if strings.Contains(line, msg) && strings.Contains(line, "bundle_order=1") {
+				found = true
+				assert.Contains(t, line, "bundle_height=1", msg)
+				assert.Contains(t, line, "bundle_id=7", msg)
+				assert.Contains(t, line, fmt.Sprintf("tx=%X", txID(txs[1])), msg)
+			}
+		}
+		assert.True(t, found, msg)
+	}
+}
+
+func TestSidecarLogSampling(t *testing.T) {
+	var buf bytes.Buffer
+	config := cfg.TestSidecarConfig()
+	config.LogSampleInterval = time.Hour
+	config.LogSampleBurst = 1
+	sidecar := NewCListSidecar(0, WithSidecarConfig(config))
+	sidecar.SetLogger(log.NewTMLogger(log.NewSyncWriter(&buf)))
+	txs := types.Txs{types.Tx("a0"), types.Tx("a1"), types.Tx("a2")}
+	for order, tx := range txs {
+		require.NoError(t, sidecar.AddTx(tx, TxInfo{DesiredHeight: 1, BundleId: 7, BundleOrder: int64(order), BundleSize: 3}))
+	}
+
+	// the lines logged for every tx are sampled, the bundle's aren't
+	assert.Equal(t, 1, strings.Count(buf.String(), "Added sidecar tx"))
+	assert.Equal(t, 1, strings.Count(buf.String(), "Bundle complete"))
+}
+
+func TestSidecarBundleSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	sidecar := NewCListSidecar(0,
+		WithSidecarConfig(cfg.TestSidecarConfig()), WithSidecarTracer(provider.Tracer("test")))
+
+	receivedAt := time.Now().Add(-time.Second)
+	txs := types.Txs{types.Tx("a0"), types.Tx("a1")}
+	for order, tx := range txs {
+		require.NoError(t, sidecar.AddTx(tx, TxInfo{
+			DesiredHeight: 1, BundleId: 7, BundleOrder: int64(order), BundleSize: 2,
+			SenderP2PID: "peer", ReceivedAt: receivedAt,
+		}))
+	}
+	sidecar.ReapMaxTxs()
+	assert.Empty(t, recorder.Ended())
+
+	// the span ends once the bundle is committed
+	sidecar.Lock()
+	require.NoError(t, sidecar.Update(1, txs, abciResponses(2, abci.CodeTypeOK)))
+	sidecar.Unlock()
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "sidecar.Bundle", span.Name())
+	assert.Equal(t, receivedAt, span.StartTime())
+
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	assert.EqualValues(t, 1, attrs["bundle_height"].AsInt64())
+	assert.EqualValues(t, 7, attrs["bundle_id"].AsInt64())
+	assert.Equal(t, fmt.Sprintf("%X", txs.Hash()), attrs["bundle_hash"].AsString())
+	assert.Equal(t, RemovalCommitted.String(), attrs["removal_reason"].AsString())
+
+	var events []string
+	for _, event := range span.Events() {
+		events = append(events, event.Name)
+	}
+	assert.Equal(t, []string{"tx received", "tx received", "bundle complete", "bundle auctioned"}, events)
+}
+
+func TestSidecarDump(t *testing.T) {
+	sidecar := NewCListSidecar(0, WithSidecarConfig(cfg.TestSidecarConfig()))
+	// a complete bundle for height 1, and one missing its middle tx for height 2
+	complete := types.Txs{types.Tx("a0"), types.Tx("a1")}
+	for order, tx := range complete {
+		require.NoError(t, sidecar.AddTx(tx, TxInfo{DesiredHeight: 1, BundleId: 0, BundleOrder: int64(order), BundleSize: 2, Bid: 5}))
+	}
+	for _, order := range []int64{0, 2} {
+		require.NoError(t, sidecar.AddTx(types.Tx(fmt.Sprintf("b%d", order)),
+			TxInfo{DesiredHeight: 2, BundleId: 3, BundleOrder: order, BundleSize: 3}))
+	}
+
+	rec := httptest.NewRecorder()
+	SidecarDumpHandler(sidecar).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/sidecar", nil))
+	var dump SidecarDump
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &dump))
+
+	assert.EqualValues(t, 1, dump.HeightForFiringAuction)
+	assert.Equal(t, 4, dump.NumTxs)
+	require.Len(t, dump.Txs, 4)
+	assert.EqualValues(t, txID(complete[0]), dump.Txs[0].Hash)
+	assert.Equal(t, map[int64][]int64{1: {0}, 2: {3}}, dump.Heights)
+
+	require.Len(t, dump.Bundles, 2)
+	assert.EqualValues(t, 5, dump.Bundles[0].Bid)
+	assert.Equal(t, []int64{0, 1}, dump.Bundles[0].Received)
+	assert.Empty(t, dump.Bundles[0].Missing)
+	assert.EqualValues(t, complete.Hash(), dump.Bundles[0].Hash)
+	assert.Equal(t, []int64{0, 2}, dump.Bundles[1].Received)
+	assert.Equal(t, []int64{1}, dump.Bundles[1].Missing)
+	assert.Nil(t, dump.Bundles[1].Hash)
+}
+
+func TestSidecarLoad(t *testing.T) {
+	sidecar := NewCListSidecar(0, WithSidecarConfig(cfg.TestSidecarConfig()))
+	txs := types.Txs{types.Tx("a0"), types.Tx("a1")}
+	for order, tx := range txs {
+		require.NoError(t, sidecar.AddTx(tx,
+			TxInfo{DesiredHeight: 1, BundleId: 2, BundleOrder: int64(order), BundleSize: 3, Bid: 5, Origin: OriginRelay}))
+	}
+	path := filepath.Join(t.TempDir(), "sidecar.json")
+	require.NoError(t, WriteSidecarDumpFile(path, sidecar.Dump()))
+	dump, err := ReadSidecarDumpFile(path)
+	require.NoError(t, err)
+
+	// the sidecar must be empty
+	_, err = sidecar.Load(dump)
+	require.Error(t, err)
+
+	loaded := NewCListSidecar(5, WithSidecarConfig(cfg.TestSidecarConfig()))
+	added, err := loaded.Load(dump)
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+	assert.EqualValues(t, 1, loaded.HeightForFiringAuction())
+	assert.Equal(t, sidecar.Dump().Txs, loaded.Dump().Txs)
+	assert.Equal(t, 2, loaded.GetCurrBundleSize(2))
+	assert.Equal(t, 3, loaded.GetEnforcedBundleSize(2))
+
+	// dumps without the txs' bytes can't be loaded
+	dump.Txs[0].Tx = nil
+	_, err = NewCListSidecar(0, WithSidecarConfig(cfg.TestSidecarConfig())).Load(dump)
+	require.Error(t, err)
+}
+
+func TestTxFilters(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	denylist := NewTxDenylist()
+	filters := []TxFilter{MaxTxBytesFilter(8), denylist}
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0, WithTxFilters(filters...))
+	sidecar := NewCListSidecar(0, WithSidecarTxFilters(filters...))
+	fromPeer := TxInfo{SenderID: 1, SenderP2PID: "peer"}
+
+	err := mempool.CheckTx(types.Tx("too large"), nil, TxInfo{})
+	assert.IsType(t, ErrTxFiltered{}, err)
+	err = sidecar.AddTx(types.Tx("too large"), TxInfo{DesiredHeight: 1, BundleSize: 1})
+	assert.IsType(t, ErrTxFiltered{}, err)
+
+	denylist.DenyTx(TxKey(types.Tx("denied")), true)
+	denylist.DenyPeer("peer", true)
+	assert.IsType(t, ErrTxFiltered{}, mempool.CheckTx(types.Tx("denied"), nil, TxInfo{}))
+	assert.IsType(t, ErrTxFiltered{}, mempool.CheckTx(types.Tx("tx"), nil, fromPeer))
+	assert.Zero(t, mempool.Size())
+
+	// refused txs aren't cached, so they can be sent again once allowed
+	denylist.DenyTx(TxKey(types.Tx("denied")), false)
+	denylist.DenyPeer("peer", false)
+	require.NoError(t, mempool.CheckTx(types.Tx("denied"), nil, TxInfo{}))
+	require.NoError(t, mempool.CheckTx(types.Tx("tx"), nil, fromPeer))
+	assert.Equal(t, 2, mempool.Size())
+}
+
+func TestTxOrigin(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	feed := NewTxFeed()
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0, WithTxFeed(feed))
+	sidecar := NewCListSidecar(0, WithSidecarTxFeed(feed))
+	sub := feed.Subscribe(10)
+
+	require.NoError(t, mempool.CheckTx(types.Tx("a"), nil, TxInfo{Origin: OriginRPC}))
+	require.NoError(t, sidecar.AddTx(types.Tx("b"), TxInfo{DesiredHeight: 1, BundleSize: 1, Origin: OriginRelay}))
+	assert.Equal(t, OriginRPC, (<-sub.Out()).Origin)
+	assert.Equal(t, OriginRelay, (<-sub.Out()).Origin)
+
+	// the origin is kept through reap and lookups
+	memTx := mempool.TxsFront().Value.(*MempoolTx)
+	assert.Equal(t, OriginRPC, memTx.Origin())
+	memTxs := sidecar.ReapMaxTxs()
+	require.Len(t, memTxs, 1)
+	assert.Equal(t, OriginRelay, memTxs[0].Origin())
+	_, txInfo, ok := sidecar.GetTxByKey(TxKey(types.Tx("b")))
+	require.True(t, ok)
+	assert.Equal(t, OriginRelay, txInfo.Origin)
+
+	// and reported on inclusion
+	mempool.Lock()
+	require.NoError(t, mempool.Update(1, types.Txs{types.Tx("a")}, abciResponses(1, abci.CodeTypeOK), nil, nil))
+	mempool.Unlock()
+	event := <-sub.Out()
+	assert.Equal(t, RemovalCommitted, event.Reason)
+	assert.Equal(t, OriginRPC, event.Origin)
+
+	for _, s := range []string{"rpc", "p2p", "sidecar", "relay"} {
+		origin, err := ParseTxOrigin(s)
+		require.NoError(t, err)
+		assert.Equal(t, s, origin.String())
+	}
+	_, err := ParseTxOrigin("unknown")
+	assert.Error(t, err)
+}
+
+func TestTxFeed(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	feed := NewTxFeed()
+	mempool := NewCListMempool(cfg.TestMempoolConfig(), appConnMem, 0, WithTxFeed(feed))
+	sidecar := NewCListSidecar(0, WithSidecarTxFeed(feed))
+	sub := feed.Subscribe(10)
+	slowSub := feed.Subscribe(1)
+	assert.Equal(t, 2, feed.NumSubscribers())
+
+	require.NoError(t, mempool.CheckTx(types.Tx("a"), nil, TxInfo{}))
+	require.NoError(t, sidecar.AddTx(types.Tx("b"), TxInfo{DesiredHeight: 1, BundleId: 0, BundleOrder: 0, BundleSize: 1}))
+	mempool.Lock()
+	require.NoError(t, mempool.Update(1, types.Txs{types.Tx("a")}, abciResponses(1, abci.CodeTypeOK), nil, nil))
+	mempool.Unlock()
+	sidecar.RemoveTxByKey(TxKey(types.Tx("b")), false)
+
+	expected := []TxEvent{
+		{Type: TxAdded, Tx: types.Tx("a")},
+		{Type: TxAdded, Tx: types.Tx("b"), Sidecar: true, DesiredHeight: 1, BundleSize: 1},
+		{Type: TxRemoved, Tx: types.Tx("a"), Reason: RemovalCommitted},
+		{Type: TxRemoved, Tx: types.Tx("b"), Reason: RemovalRequested, Sidecar: true, DesiredHeight: 1, BundleSize: 1},
+	}
+	for _, event := range expected {
+		assert.Equal(t, event, <-sub.Out())
+	}
+	assert.Zero(t, sub.Dropped())
+
+	// a subscriber that's behind misses events, but doesn't block the mempool
+	assert.Equal(t, expected[0], <-slowSub.Out())
+	assert.EqualValues(t, 3, slowSub.Dropped())
+
+	feed.Unsubscribe(slowSub)
+	_, ok := <-slowSub.Out()
+	assert.False(t, ok)
+	assert.Equal(t, 1, feed.NumSubscribers())
+}
+
+// removalRecorder records the removals it's notified of
+type removalRecorder struct {
+	txs, bundles []string
+}
+
+func (r *removalRecorder) TxRemoved(tx types.Tx, sidecar bool, reason RemovalReason) {
+	r.txs = append(r.txs, fmt.Sprintf("%s/%t/%s", string(tx), sidecar, reason))
+}
+
+func (r *removalRecorder) BundleRemoved(height, bundleID int64, reason RemovalReason) {
+	r.bundles = append(r.bundles, fmt.Sprintf("%d/%d/%s", height, bundleID, reason))
+}
+
+func TestRemovalHooks(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	recorder := &removalRecorder{}
+	config := cfg.TestMempoolConfig()
+	config.TTLNumBlocks = 1
+	mempool := NewCListMempool(config, appConnMem, 0, WithRemovalHook(recorder))
+	sidecar := NewCListSidecar(0, WithSidecarRemovalHook(recorder))
+
+	require.NoError(t, mempool.CheckTx(types.Tx("a"), nil, TxInfo{}))
+	require.NoError(t, mempool.CheckTx(types.Tx("b"), nil, TxInfo{}))
+	require.NoError(t, sidecar.AddTx(types.Tx("x"), TxInfo{DesiredHeight: 1, BundleId: 0, BundleSize: 1}))
+	require.NoError(t, sidecar.AddTx(types.Tx("y"), TxInfo{DesiredHeight: 1, BundleId: 1, BundleSize: 1}))
+
+	// bundle 0 made it into block 1, bundle 1 didn't
+	block := types.Txs{types.Tx("x"), types.Tx("a")}
+	mempool.Lock()
+	require.NoError(t, mempool.Update(1, block, abciResponses(2, abci.CodeTypeOK), nil, nil))
+	mempool.Unlock()
+	require.NoError(t, sidecar.Update(1, block, abciResponses(2, abci.CodeTypeOK)))
+	// b outlives its TTL
+	mempool.Lock()
+	require.NoError(t, mempool.Update(3, nil, nil, nil, nil))
+	mempool.Unlock()
+
+	assert.Equal(t, []string{"a/false/committed", "x/true/committed", "y/true/unused", "b/false/expired"},
+		recorder.txs)
+	assert.ElementsMatch(t, []string{"1/0/committed", "1/1/unused"}, recorder.bundles)
+}
+
+func TestSidecarEventBus(t *testing.T) {
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	defer eventBus.Stop() //nolint:errcheck // ignore for tests
+
+	ctx := context.Background()
+	added, err := eventBus.Subscribe(ctx, "test", types.EventQuerySidecarTxAdded, 10)
+	require.NoError(t, err)
+	query := tmquery.MustParse(fmt.Sprintf("%s='%s' AND %s='unused'",
+		types.EventTypeKey, types.EventSidecarTxRemoved, types.SidecarReasonKey))
+	unused, err := eventBus.Subscribe(ctx, "test", query, 10)
+	require.NoError(t, err)
+
+	sidecar := NewCListSidecar(0, WithSidecarEventBus(eventBus))
+	require.NoError(t, sidecar.AddTx(types.Tx("x"), TxInfo{DesiredHeight: 1, BundleId: 0, BundleSize: 1}))
+	require.NoError(t, sidecar.AddTx(types.Tx("y"), TxInfo{
+		DesiredHeight: 1, BundleId: 1, BundleSize: 1, Origin: OriginRelay}))
+	// bundle 0 made it into block 1, bundle 1 didn't
+	block := types.Txs{types.Tx("x")}
+	require.NoError(t, sidecar.Update(1, block, abciResponses(1, abci.CodeTypeOK)))
+
+	for _, expected := range []types.EventDataSidecarTx{
+		{Tx: types.Tx("x"), Height: 1, BundleSize: 1, Origin: "unknown"},
+		{Tx: types.Tx("y"), Height: 1, BundleID: 1, BundleSize: 1, Origin: "relay"},
+	} {
+		msg := <-added.Out()
+		assert.Equal(t, expected, msg.Data())
+		assert.Equal(t, []string{fmt.Sprint(expected.BundleID)}, msg.Events()[types.SidecarBundleIDKey])
+	}
+	msg := <-unused.Out()
+	assert.Equal(t, types.EventDataSidecarTx{
+		Tx: types.Tx("y"), Height: 1, BundleID: 1, BundleSize: 1, Origin: "relay", Reason: "unused",
+	}, msg.Data())
+	select {
+	case msg := <-unused.Out():
+		t.Fatalf("unexpected event %v", msg.Data())
+	default:
+	}
+}
+
+func TestBundleAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "bundles.jsonl")
+	auditLog, err := NewBundleAuditLog(path, 1024*1024, 0)
+	require.NoError(t, err)
+	require.NoError(t, auditLog.Start())
+
+	checker := bundleCheckerFunc(func(height, bundleID int64, txs types.Txs) error {
+		if bundleID == 1 {
+			return errors.New("front-runs an oracle tx")
+		}
+		return nil
+	})
+	sidecar := NewCListSidecar(0, WithBundleChecker(checker), WithBundleAuditLog(auditLog))
+	require.NoError(t, sidecar.AddTx(types.Tx("x"), TxInfo{
+		DesiredHeight: 1, BundleId: 0, BundleSize: 1, Bid: 5, SenderP2PID: "relay", Origin: OriginRelay}))
+	assert.Error(t, sidecar.AddTx(types.Tx("y"), TxInfo{DesiredHeight: 1, BundleId: 1, BundleSize: 1}))
+	sidecar.ReapAuction()
+	// bundle 0 made it into block 1, bundle 1 didn't
+	require.NoError(t, sidecar.Update(1, types.Txs{types.Tx("x")}, abciResponses(1, abci.CodeTypeOK)))
+
+	// the records are flushed once stopped
+	require.NoError(t, auditLog.Stop())
+	bz, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	var records []BundleAuditRecord
+	for _, line := range strings.Split(strings.TrimSpace(string(bz)), "\n") {
+		var record BundleAuditRecord
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		assert.False(t, record.Time.IsZero())
+		record.Time = time.Time{}
+		records = append(records, record)
+	}
+	hashX := tmbytes.HexBytes(types.Txs{types.Tx("x")}.Hash())
+	hashY := tmbytes.HexBytes(types.Txs{types.Tx("y")}.Hash())
+	assert.Equal(t, []BundleAuditRecord{
+		{Event: BundleAuditReceived, Height: 1, BundleSize: 1, Bid: 5, Sender: "relay", Origin: "relay"},
+		{Event: BundleAuditAccepted, Height: 1, BundleSize: 1, Bid: 5, Hash: hashX},
+		{Event: BundleAuditReceived, Height: 1, BundleID: 1, BundleSize: 1, Origin: "unknown"},
+		{Event: BundleAuditVetoed, Height: 1, BundleID: 1, BundleSize: 1, Hash: hashY, Reason: "front-runs an oracle tx"},
+		{Event: BundleAuditAuctioned, Height: 1, BundleSize: 1, Bid: 5, Hash: hashX},
+		{Event: BundleAuditIncluded, Height: 1, BundleSize: 1, Bid: 5, Hash: hashX},
+		{Event: BundleAuditDropped, Height: 1, BundleID: 1, BundleSize: 1, Hash: hashY, Reason: "vetoed"},
+	}, records)
+}
+
+func TestBundleMirror(t *testing.T) {
+	txs := types.Txs{types.Tx("x"), types.Tx("y")}
+	want := BundleMirrorRecord{
+		Height:     1,
+		BundleSize: 2,
+		Bid:        5,
+		Hash:       txs.Hash(),
+		TxHashes:   []tmbytes.HexBytes{txs[0].Hash(), txs[1].Hash()},
+		Txs:        []tmbytes.HexBytes{tmbytes.HexBytes("x"), tmbytes.HexBytes("y")},
+	}
+	mirrorBundle := func(mirror *BundleMirror) {
+		require.NoError(t, mirror.Start())
+		t.Cleanup(func() { _ = mirror.Stop() })
+		sidecar := NewCListSidecar(0, WithBundleMirror(mirror))
+		for i, tx := range txs {
+			require.NoError(t, sidecar.AddTx(tx, TxInfo{DesiredHeight: 1, BundleOrder: int64(i), BundleSize: 2, Bid: 5}))
+		}
+	}
+
+	// complete bundles are POSTed to an HTTP sink
+	records := make(chan BundleMirrorRecord, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var record BundleMirrorRecord
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&record))
+		records <- record
+	}))
+	defer server.Close()
+	mirror, err := NewBundleMirror(server.URL, false)
+	require.NoError(t, err)
+	mirrorBundle(mirror)
+	select {
+	case record := <-records:
+		assert.False(t, record.Time.IsZero())
+		record.Time = time.Time{}
+		assert.Equal(t, want, record)
+	case <-time.After(5 * time.Second):
+		t.Fatal("bundle wasn't mirrored")
+	}
+
+	// or written as a line to a socket, here without their txs
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	mirror, err = NewBundleMirror("tcp://"+listener.Addr().String(), true)
+	require.NoError(t, err)
+	mirrorBundle(mirror)
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	var record BundleMirrorRecord
+	require.NoError(t, json.NewDecoder(conn).Decode(&record))
+	record.Time = time.Time{}
+	want.Txs = nil
+	assert.Equal(t, want, record)
+
+	_, err = NewBundleMirror("udp://127.0.0.1:9000", false)
+	assert.Error(t, err)
+}
+
+func TestMEVStats(t *testing.T) {
+	stats := NewMEVStats(10)
+	sidecar := NewCListSidecar(0, WithMEVStats(stats))
+	now := time.Now()
+	for _, txInfo := range []TxInfo{
+		// complete
+		{DesiredHeight: 1, BundleId: 0, BundleOrder: 0, BundleSize: 2, Bid: 5, ReceivedAt: now.Add(-2 * time.Second)},
+		{DesiredHeight: 1, BundleId: 0, BundleOrder: 1, BundleSize: 2, Bid: 5},
+		{DesiredHeight: 1, BundleId: 1, BundleOrder: 0, BundleSize: 1, Bid: 3, ReceivedAt: now.Add(-time.Second)},
+		// incomplete
+		{DesiredHeight: 1, BundleId: 2, BundleOrder: 0, BundleSize: 2, Bid: 1},
+	} {
+		tx := types.Tx(fmt.Sprintf("%d/%d", txInfo.BundleId, txInfo.BundleOrder))
+		require.NoError(t, sidecar.AddTx(tx, txInfo))
+	}
+	// bundles aren't accounted for while in the sidecar
+	assert.Zero(t, stats.Summary(1, 100).BundlesReceived)
+
+	// bundle 0 made it into block 1, the others didn't
+	block := types.Txs{types.Tx("0/0"), types.Tx("0/1")}
+	require.NoError(t, sidecar.Update(1, block, abciResponses(2, abci.CodeTypeOK)))
+	summary := stats.Summary(1, 100)
+	assert.EqualValues(t, 1, summary.FromHeight)
+	assert.EqualValues(t, 1, summary.ToHeight)
+	assert.EqualValues(t, 3, summary.BundlesReceived)
+	assert.EqualValues(t, 1, summary.BundlesIncluded)
+	assert.EqualValues(t, 5, summary.TotalBids)
+	assert.InDelta(t, 1.0/3, summary.WinRate, 0.001)
+	// averaged over the complete bundles
+	assert.InDelta(t, 1.5, summary.AvgLatency.Seconds(), 0.2)
+
+	// only the last heights are kept
+	for height := int64(2); height <= 11; height++ {
+		require.NoError(t, sidecar.Update(height, nil, nil))
+	}
+	assert.Equal(t, MEVStatsSummary{FromHeight: 2, ToHeight: 11}, stats.Summary(11, 100))
+}
+
+// laneApp declares the lane of txs of the form "lane/n" in a "tx" event
+type laneApp struct {
+	abci.BaseApplication
+}
+
+func (laneApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	lane := strings.SplitN(string(req.Tx), "/", 2)[0]
+	return abci.ResponseCheckTx{
+		Code: abci.CodeTypeOK,
+		Events: []abci.Event{{
+			Type:       "tx",
+			Attributes: []abci.EventAttribute{{Key: []byte("lane"), Value: []byte(lane)}},
+		}},
+	}
+}
+
+func TestMempoolLanes(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(laneApp{})
+	appConnMem, _ := cc.NewABCIClient()
+	require.NoError(t, appConnMem.Start())
+	defer appConnMem.Stop() //nolint:errcheck // ignore for tests
+
+	config := cfg.TestMempoolConfig()
+	config.Lanes = "oracle:5,bulk:-1:2"
+	lanes, err := config.LaneList()
+	require.NoError(t, err)
+	mempool := NewCListMempool(config, appConnMem, 0, WithLanes(lanes, "tx.lane"))
+	assert.ElementsMatch(t, []byte{LaneChannelBase, LaneChannelBase + 1}, mempool.laneChannels())
+
+	for _, tx := range []string{"bulk/0", "default/0", "oracle/0", "unknown/0", "bulk/1", "mev/0"} {
+		require.NoError(t, mempool.CheckTx(types.Tx(tx), nil, TxInfo{}))
+	}
+	// the bulk lane is full
+	require.NoError(t, mempool.CheckTx(types.Tx("bulk/2"), nil, TxInfo{}))
+	require.Equal(t, 6, mempool.Size())
+
+	// by lane priority, txs of unknown lanes going to the default one, and
+	// the oracle lane ranking above the mev lane's bundles
+	sidecarTxs := []*MempoolTx{{tx: types.Tx("bundle0")}}
+	assert.Equal(t, types.Txs{
+		types.Tx("oracle/0"), types.Tx("bundle0"),
+		types.Tx("default/0"), types.Tx("unknown/0"), types.Tx("mev/0"),
+		types.Tx("bulk/0"), types.Tx("bulk/1"),
+	}, mempool.ReapMaxBytesMaxGas(-1, -1, sidecarTxs))
+
+	// once a bulk tx is committed, the lane has room again
+	mempool.Lock()
+	err = mempool.Update(1, types.Txs{types.Tx("bulk/0")}, abciResponses(1, abci.CodeTypeOK), nil, nil)
+	mempool.Unlock()
+	require.NoError(t, err)
+	require.NoError(t, mempool.CheckTx(types.Tx("bulk/2"), nil, TxInfo{}))
+	assert.Equal(t, 6, mempool.Size())
+}
@@ -1,12 +1,20 @@
 package mempool
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	cfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/libs/clist"
+	"github.com/tendermint/tendermint/libs/log"
 	tmsync "github.com/tendermint/tendermint/libs/sync"
 	"github.com/tendermint/tendermint/types"
 )
@@ -21,6 +29,9 @@ type CListPriorityTxSidecar struct {
 	height                 int64 // the last block Update()'d to
 	heightForFiringAuction int64 // the height of the block to fire the auction for
 	txsBytes               int64 // total size of sidecar, in bytes
+	memBytes               int64 // memory taken by the txs and bundles, see MemBytes
+	minBid                 int64 // lowest bid accepted, see SetMinBid
+	mevDisabled            int32 // 1 if MEV is turned off, see SetMEVDisabled
 
 	// notify listeners (ie. consensus) when txs are available
 	notifiedTxsAvailable bool
@@ -37,17 +48,67 @@ type CListPriorityTxSidecar struct {
 	// // sync.Map bundleOrder -> *SidecarTx
 	// }
 	bundles     sync.Map
-	maxBundleId int64
+	maxBundleId int64 // atomic, raised concurrently by AddTx
+
+	// guards the memory accounted to each bundle, so AddTx can evict bundles
+	// to make room, see SidecarConfig.MaxMemory
+	memMtx tmsync.Mutex
 
 	updateMtx tmsync.RWMutex
 
 	// Keep a cache of already-seen txs.
 	// This reduces the pressure on the proxyApp.
 	cache txCache
+
+	config *cfg.SidecarConfig
+	// chain the bundles are for, see WithSidecarChainID
+	chainID string
+
+	// expected delay between committing a block and proposing the next one,
+	// used together with config.AuctionCutoff to compute auctionDeadline
+	proposalDelay time.Duration
+	// bundles for heightForFiringAuction first seen after this are excluded
+	// from the auction (zero if no cutoff is configured)
+	auctionDeadline time.Time
+
+	// if set, lets the app veto each bundle once all its txs are in
+	bundleChecker BundleChecker
+	// vet each tx before it's added to its bundle, see WithSidecarTxFilters
+	txFilters []TxFilter
+	// if set, pins each tx until its bundle's height, see WithTxPinner
+	pinner TxPinner
+
+	// if set, gives the gas wanted by txs the mempool checked too
+	checkTxResults *CheckTxResultCache
+
+	// streams added and removed txs to subscribers, if set
+	feed *TxFeed
+	// notified of every removed tx and bundle
+	removalHooks []RemovalHook
+	// publishes added and removed txs as events, if set
+	eventBus types.SidecarEventPublisher
+	// records every bundle and the decisions taken on it, if set
+	auditLog *BundleAuditLog
+	// sends a copy of every bundle completed to an analytics sink, if set
+	mirror *BundleMirror
+	// rolls up what became of the bundles, if set
+	stats *MEVStats
+
+	logger  log.Logger
+	txLog   log.Logger // samples the lines logged for every tx, see txLogger
+	metrics *Metrics
+	tracer  trace.Tracer
+	// guards reportedHeightsAhead, the heights_ahead labels reported by
+	// SidecarHeightBundles
+	metricsMtx           tmsync.Mutex
+	reportedHeightsAhead map[int64]struct{}
 }
 
 var _ PriorityTxSidecar = &CListPriorityTxSidecar{}
 
+// CListSidecarOption sets an optional parameter on the sidecar.
+type CListSidecarOption func(*CListPriorityTxSidecar)
+
 type Key struct {
 	height, bundleId int64
 }
@@ -55,21 +116,143 @@ type Key struct {
 // NewCListSidecar returns a new sidecar with the given configuration
 func NewCListSidecar(
 	height int64,
+	options ...CListSidecarOption,
 ) *CListPriorityTxSidecar {
 	sidecar := &CListPriorityTxSidecar{
 		txs:                    clist.New(),
 		height:                 height,
 		heightForFiringAuction: height + 1,
+		config:                 cfg.DefaultSidecarConfig(),
+		logger:                 log.NewNopLogger(),
+		txLog:                  log.NewNopLogger(),
+		metrics:                NopMetrics(),
+		tracer:                 trace.NewNoopTracerProvider().Tracer(""),
+		reportedHeightsAhead:   make(map[int64]struct{}),
+	}
+	for _, option := range options {
+		option(sidecar)
 	}
-	// TODO: update
-	sidecar.cache = newMapTxCache(10000)
+	// the sidecar has its own cache, so bundle txs are never seen by the
+	// mempool's, nor evicted by public gossip
+	if sidecar.config.CacheSize > 0 {
+		sidecar.cache = newMapTxCache(sidecar.config.CacheSize)
+	} else {
+		sidecar.cache = nopTxCache{}
+	}
+	sidecar.minBid = sidecar.config.MinBid
+	if sidecar.config.MEVDisabled {
+		sidecar.mevDisabled = 1
+	}
+	sidecar.auctionDeadline = sidecar.nextAuctionDeadline(time.Now())
+	sidecar.metrics.SidecarAuctionHeight.Set(float64(sidecar.heightForFiringAuction))
 	return sidecar
 }
 
+// SetLogger sets the Logger.
+func (sc *CListPriorityTxSidecar) SetLogger(l log.Logger) {
+	sc.logger = l
+	sc.txLog = sampledLogger(l, sc.config)
+}
+
+// WithSidecarConfig sets the sidecar configuration.
+func WithSidecarConfig(config *cfg.SidecarConfig) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.config = config }
+}
+
+// WithSidecarChainID sets the chain the sidecar holds bundles for: the txs
+// it gossips are tagged with it, and bundles tagged for any other are
+// refused, see forOtherChain. Bundles of untagged messages are taken.
+func WithSidecarChainID(chainID string) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.chainID = chainID }
+}
+
+// forOtherChain returns true if a bundle tagged with chainID is for a chain
+// other than the sidecar's. Untagged bundles, or any if the sidecar's chain
+// isn't set, are for any chain.
+func (sc *CListPriorityTxSidecar) forOtherChain(chainID string) bool {
+	return chainID != "" && sc.chainID != "" && chainID != sc.chainID
+}
+
+// SetMinBid sets the lowest bid a bundle may make, 0 for none, over
+// SidecarConfig.MinBid. Bundle txs already held aren't affected.
+//
+// Safe for concurrent use by multiple goroutines.
+func (sc *CListPriorityTxSidecar) SetMinBid(minBid int64) {
+	atomic.StoreInt64(&sc.minBid, minBid)
+}
+
+// SetMEVDisabled turns MEV off, or back on, over SidecarConfig.MEVDisabled.
+// While off, every tx is refused with ErrMEVDisabled and no bundle is
+// auctioned, so the node proposes like one without a sidecar. Turning it off
+// flushes the sidecar.
+//
+// Safe for concurrent use by multiple goroutines.
+func (sc *CListPriorityTxSidecar) SetMEVDisabled(disabled bool) {
+	var v int32
+	if disabled {
+		v = 1
+	}
+	if atomic.SwapInt32(&sc.mevDisabled, v) == v || !disabled {
+		return
+	}
+	sc.Lock()
+	defer sc.Unlock()
+	sc.Flush()
+}
+
+// MEVDisabled returns true if MEV is turned off, see SetMEVDisabled.
+//
+// Safe for concurrent use by multiple goroutines.
+func (sc *CListPriorityTxSidecar) MEVDisabled() bool {
+	return atomic.LoadInt32(&sc.mevDisabled) == 1
+}
+
+// WithBundleChecker sets a BundleChecker asked to accept every bundle once
+// its last tx arrives. Txs of vetoed bundles are dropped from the sidecar.
+func WithBundleChecker(checker BundleChecker) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.bundleChecker = checker }
+}
+
+// WithSidecarCheckTxResultCache sets the cache of the mempool's CheckTx
+// results, from which sidecar txs also checked by the mempool get the gas they
+// want. Other sidecar txs want no gas.
+func WithSidecarCheckTxResultCache(cache *CheckTxResultCache) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.checkTxResults = cache }
+}
+
+// WithSidecarTxFeed sets a feed the txs added to and removed from the
+// sidecar are published to.
+func WithSidecarTxFeed(feed *TxFeed) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.feed = feed }
+}
+
+// WithSidecarEventBus sets an event bus the txs added to and removed from the
+// sidecar are published to, as EventSidecarTxAdded and EventSidecarTxRemoved.
+func WithSidecarEventBus(eventBus types.SidecarEventPublisher) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.eventBus = eventBus }
+}
+
+// WithSidecarRemovalHook adds a hook notified of every tx and bundle removed
+// from the sidecar, with the reason why.
+func WithSidecarRemovalHook(hook RemovalHook) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.removalHooks = append(sc.removalHooks, hook) }
+}
+
+// WithSidecarMetrics sets the metrics.
+func WithSidecarMetrics(metrics *Metrics) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.metrics = metrics }
+}
+
+// WithProposalDelay sets the expected delay between committing a block and
+// proposing the next one (ie. consensus timeout_commit). The auction cutoff is
+// measured back from the end of this delay.
+func WithProposalDelay(delay time.Duration) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.proposalDelay = delay }
+}
+
 func (sc *CListPriorityTxSidecar) PrettyPrintBundles() {
 	fmt.Println(fmt.Sprintf("-------------"))
-	for bundleIdIter := 0; bundleIdIter <= int(sc.maxBundleId); bundleIdIter++ {
-		bundleIdIter := int64(bundleIdIter)
+	for _, bundleIdIter := range sc.bundleIdsAt(sc.heightForFiringAuction) {
 		if bundle, ok := sc.bundles.Load(Key{sc.heightForFiringAuction, bundleIdIter}); ok {
 			bundle := bundle.(*Bundle)
 			fmt.Println(fmt.Sprintf("BUNDLE ID: %d", bundleIdIter))
@@ -119,17 +302,40 @@ func (sc *CListPriorityTxSidecar) notifyTxsAvailable() {
 
 // TODO: Update to AddTx(tx types.Tx, txInfo TxInfo, order int64) error
 func (sc *CListPriorityTxSidecar) AddTx(tx types.Tx, txInfo TxInfo) error {
+	return sc.AddTxContext(context.Background(), tx, txInfo)
+}
 
+// AddTxContext is AddTx, but gives up on the app's BundleChecker once ctx is
+// done, so a hung app can't block the caller: the bundle the tx completes is
+// then vetoed, with ErrCheckTxTimeout as the reason.
+func (sc *CListPriorityTxSidecar) AddTxContext(ctx context.Context, tx types.Tx, txInfo TxInfo) error {
+	err := sc.addTx(ctx, tx, txInfo)
+	if err != nil {
+		sc.metrics.SidecarRejectedTxs.With("reason", rejectionReason(err)).Add(1)
+	}
+	return err
+}
+
+func (sc *CListPriorityTxSidecar) addTx(ctx context.Context, tx types.Tx, txInfo TxInfo) error {
 	sc.updateMtx.RLock()
 	// use defer to unlock mutex because application (*local client*) might panic
 	defer sc.updateMtx.RUnlock()
 
-	fmt.Println(fmt.Sprintf("[mev-tendermint]: STARTING TO ADD TRANSACTION %.20q TO SIDECAR! with bundleId %d, bundleOrder %d, desiredHeight %d, bundleSize %d", tx, txInfo.BundleId, txInfo.BundleOrder, txInfo.DesiredHeight, txInfo.BundleSize))
+	logger := sc.txLogger(tx, txInfo)
+	logger.Debug("Adding sidecar tx", "bundle_size", txInfo.BundleSize, "src", txInfo.SenderP2PID)
+
+	if sc.MEVDisabled() {
+		return ErrMEVDisabled
+	}
+
+	if err := filterTx(sc.txFilters, tx, txInfo); err != nil {
+		logger.Debug("Skipping sidecar tx", "err", err)
+		return err
+	}
 
 	// don't add any txs already in cache
 	if !sc.cache.Push(tx) {
-		fmt.Println("[mev-tendermint]: trying to add tx to sidecar AddTx - but already in cache!")
-		fmt.Println(tx)
+		logger.Debug("Skipping sidecar tx already in cache")
 		// Record a new sender for a tx we've already seen.
 		// Note it's possible a tx is still in the cache but no longer in the mempool
 		// (eg. after committing a block, txs are removed from mempool but not cache),
@@ -150,23 +356,56 @@ func (sc *CListPriorityTxSidecar) AddTx(tx types.Tx, txInfo TxInfo) error {
 		bundleId:      txInfo.BundleId,
 		bundleOrder:   txInfo.BundleOrder,
 		bundleSize:    txInfo.BundleSize,
-		// TODO: gas
+		bid:           txInfo.Bid,
+		origin:        txInfo.Origin,
+		envelope:      txInfo.Envelope,
+	}
+	if sc.checkTxResults != nil {
+		if res, _, ok := sc.checkTxResults.Get(tx); ok {
+			scTx.gasWanted = res.GasWanted
+		}
 	}
 
 	// -------- BASIC CHECKS ON TX INFO ---------
 
 	// Can't add transactions asking to be included in a height for auction we're not on
 	if txInfo.DesiredHeight < sc.heightForFiringAuction {
-		fmt.Println(fmt.Sprintf("[mev-tendermint]: AddTx() skip tx... trying to add a tx for height %d whereas height for curr auction is %d", txInfo.DesiredHeight, sc.heightForFiringAuction))
+		logger.Debug("Skipping sidecar tx for a height past the auction's", "auction_height", sc.heightForFiringAuction)
+		return ErrWrongHeight{
+			int(txInfo.DesiredHeight),
+			int(sc.heightForFiringAuction),
+		}
+	}
+
+	// Nor for heights too far ahead, if limited
+	if maxAhead := sc.config.MaxBundleHeightsAhead; maxAhead > 0 && txInfo.DesiredHeight > sc.heightForFiringAuction+maxAhead {
+		logger.Debug("Skipping sidecar tx for a height too far past the auction's",
+			"auction_height", sc.heightForFiringAuction, "max_heights_ahead", maxAhead)
 		return ErrWrongHeight{
 			int(txInfo.DesiredHeight),
 			int(sc.heightForFiringAuction),
 		}
 	}
 
+	// Nor bidding under the floor
+	if minBid := atomic.LoadInt64(&sc.minBid); txInfo.Bid < minBid {
+		logger.Debug("Skipping sidecar tx bidding under the floor", "bid", txInfo.Bid, "min_bid", minBid)
+		// remove from cache (the bundle may be resent with a higher bid)
+		sc.cache.Remove(tx)
+		return ErrBidTooLow{txInfo.Bid, minBid}
+	}
+
+	// Nor declaring a bundle size out of bounds
+	if err := sc.checkBundleSize(txInfo.BundleSize); err != nil {
+		logger.Info("Skipping sidecar tx with a bundle size out of range", "bundle_size", txInfo.BundleSize)
+		// remove from cache (the tx may be resent in a bundle of another size)
+		sc.cache.Remove(tx)
+		return err
+	}
+
 	// revert if tx asking to be included has an order greater/equal to size
 	if txInfo.BundleOrder >= txInfo.BundleSize {
-		fmt.Println("[mev-tendermint]: AddTx() skip tx... trying to insert a tx for bundle at an order greater than the size of the bundle... THIS IS PROBABLY A FATAL ERROR")
+		logger.Info("Skipping malformed sidecar tx, ordered past its bundle's size", "bundle_size", txInfo.BundleSize)
 		return ErrTxMalformedForBundle{
 			txInfo.BundleId,
 			txInfo.BundleSize,
@@ -175,26 +414,87 @@ func (sc *CListPriorityTxSidecar) AddTx(tx types.Tx, txInfo TxInfo) error {
 		}
 	}
 
+	scTx.msg, scTx.tx = encodeSidecarTxMsg(scTx, sc.chainID)
+
+	// -------- CAPACITY CHECKS ---------
+
+	// a tx bigger than the whole sidecar would never fit
+	if sc.config.MaxBytes > 0 && int64(len(tx)) > sc.config.MaxBytes {
+		logger.Info("Skipping sidecar tx larger than the sidecar", "max_bytes", sc.config.MaxBytes)
+		return ErrTxTooLarge{int(sc.config.MaxBytes), len(tx)}
+	}
+
+	key := Key{txInfo.DesiredHeight, txInfo.BundleId}
+	if err := sc.isFull(len(tx), key); err != nil {
+		logger.Debug("Skipping sidecar tx", "err", err)
+		// remove from cache (the sidecar might have room later)
+		sc.cache.Remove(tx)
+		return err
+	}
+
+	receivedAt := txInfo.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+
+	// the memory is reserved, and the tx stored in its bundle, atomically
+	// with respect to evictions
+	sc.memMtx.Lock()
+	needed := txMemBytes(tx)
+	if _, ok := sc.bundles.Load(key); !ok {
+		needed += bundleOverheadBytes
+	}
+	if err := sc.reserveMemory(key, needed); err != nil {
+		sc.memMtx.Unlock()
+		logger.Debug("Skipping sidecar tx", "err", err)
+		// remove from cache (the sidecar might have room later)
+		sc.cache.Remove(tx)
+		return err
+	}
+
 	// -------- BUNDLE EXISTENCE CHECKS ---------
 
 	var bundle *Bundle
 	// load existing bundle, or MAKE NEW if not
-	existingBundle, _ := sc.bundles.LoadOrStore(Key{txInfo.DesiredHeight, txInfo.BundleId}, &Bundle{
+	existingBundle, loaded := sc.bundles.LoadOrStore(key, &Bundle{
 		desiredHeight: txInfo.DesiredHeight,
 		bundleId:      txInfo.BundleId,
 		currSize:      int64(0),
 		enforcedSize:  txInfo.BundleSize,
+		bid:           txInfo.Bid,
 		// TODO: add from gossip info?
 		gasWanted:     int64(0),
 		orderedTxsMap: &sync.Map{},
+		late:          sc.isPastAuctionCutoff(txInfo.DesiredHeight, time.Now()),
+
+		firstSeenHeight: sc.height,
+		firstSeen:       time.Now(),
+
+		memBytes:      bundleOverheadBytes,
+		firstReceived: receivedAt,
+		extensions:    envelopeExtensions(txInfo.Envelope),
 	})
 	bundle = existingBundle.(*Bundle)
+	if !loaded {
+		sc.startBundleSpan(bundle)
+		record := bundleAuditRecord(bundle, BundleAuditReceived, receivedAt)
+		record.Sender, record.Origin = string(txInfo.SenderP2PID), txInfo.Origin.String()
+		sc.auditLog.Write(record)
+		sc.addMemBytes(bundleOverheadBytes)
+		sc.metrics.SidecarBundlesReceived.Add(1)
+		defer sc.reportBundles()
+	}
+	if !loaded && bundle.late {
+		logger.Info("Bundle arrived after the auction cutoff, will gossip but not reap it")
+	}
 
 	// -------- BUNDLE SIZE CHECKS ---------
 
 	// check if bundle is asking for a different size than one already stored
 	if txInfo.BundleSize != bundle.enforcedSize {
-		fmt.Println("[mev-tendermint]: AddTx() skip tx... Trying to insert a tx with a size different than what's said by other txs for this bundle?? ... THIS IS PROBABLY A FATAL ERROR")
+		sc.memMtx.Unlock()
+		logger.Info("Skipping malformed sidecar tx, its bundle's size differs from the other txs'",
+			"bundle_size", txInfo.BundleSize, "enforced_size", bundle.enforcedSize)
 		return ErrTxMalformedForBundle{
 			txInfo.BundleId,
 			txInfo.BundleSize,
@@ -206,7 +506,8 @@ func (sc *CListPriorityTxSidecar) AddTx(tx types.Tx, txInfo TxInfo) error {
 	// Can't add transactions if the bundle is already full
 	// check if the current size of this bundle is greater than the expected size for the bundle, if so skip
 	if bundle.currSize >= bundle.enforcedSize {
-		fmt.Println("[mev-tendermint]: AddTx() skip tx... already full for this BundleId... THIS IS PROBABLY A FATAL ERROR")
+		sc.memMtx.Unlock()
+		logger.Info("Skipping sidecar tx, its bundle is full already")
 		return ErrBundleFull{
 			txInfo.BundleId,
 			txInfo.BundleSize,
@@ -220,41 +521,149 @@ func (sc *CListPriorityTxSidecar) AddTx(tx types.Tx, txInfo TxInfo) error {
 
 	// TODO: could add check to not add if bundleSize already over limit!
 	// if we already have a tx at this bundleId, bundleOrder, and height, then skip this one!
+	var currSize int64
 	if _, loaded := orderedTxsMap.LoadOrStore(txInfo.BundleOrder, scTx); loaded {
-		// if we had the tx already, then skip
-		// TODO: return error
-		fmt.Println(fmt.Sprintf("[mev-tendermint]: AddTx() skip tx... already have a tx for bundleId %d, height %d, bundleOrder %d", txInfo.BundleId, scTx.desiredHeight, txInfo.BundleOrder))
-		return nil
+		sc.memMtx.Unlock()
+		logger.Debug("Skipping sidecar tx, its bundle has a tx at its order already")
+		return ErrBundleOrderTaken{
+			txInfo.BundleId,
+			txInfo.DesiredHeight,
+			txInfo.BundleOrder,
+		}
 	} else {
 		// if we added, then increment bundle size for bundleId
-		atomic.AddInt64(&bundle.currSize, int64(1))
+		if receivedAt.Before(bundle.firstReceived) {
+			bundle.firstReceived = receivedAt
+		}
+		traceTxReceived(bundle, tx, txInfo, receivedAt)
+		sc.countReassembly(bundle, txInfo.BundleOrder)
+		currSize = atomic.AddInt64(&bundle.currSize, int64(1))
+		bundle.memBytes += txMemBytes(tx)
+		sc.addMemBytes(txMemBytes(tx))
+		if currSize == bundle.enforcedSize {
+			txs := bundleTxs(bundle)
+			bundle.hash = txs.Hash()
+			bundle.completed = time.Now()
+			latency := bundle.completed.Sub(bundle.firstReceived)
+			sc.metrics.BundleCompletionLatency.Observe(latency.Seconds())
+			sc.metrics.BundleSizeTxs.Observe(float64(len(txs)))
+			var txsBytes int
+			for _, tx := range txs {
+				txsBytes += len(tx)
+			}
+			sc.metrics.BundleSizeBytes.Observe(float64(txsBytes))
+			sc.bundleLogger(bundle).Info("Bundle complete", "latency", latency)
+			traceBundleComplete(bundle)
+			sc.mirror.Mirror(bundleMirrorRecord(bundle, txs, bundle.completed))
+		}
+		sc.memMtx.Unlock()
+	}
+
+	// -------- APP VETO ---------
+
+	// the tx completing the bundle has the app check it as a whole
+	if sc.bundleChecker != nil && currSize == bundle.enforcedSize {
+		if err := sc.checkBundle(ctx, bundle); err != nil {
+			return err
+		}
+	}
+	if atomic.LoadInt32(&bundle.vetoed) == 1 {
+		return ErrBundleVetoed{
+			bundle.bundleId,
+			bundle.desiredHeight,
+			errors.New("bundle already vetoed"),
+		}
+	}
+	if currSize == bundle.enforcedSize {
+		sc.memMtx.Lock()
+		sc.auditBundle(bundle, BundleAuditAccepted, "")
+		sc.memMtx.Unlock()
 	}
 
 	// -------- UPDATE MAX BUNDLE ---------
 
-	if txInfo.BundleId >= sc.maxBundleId {
-		fmt.Println("[mev-tendermint]: AddTx(): updating maxBundleId to", txInfo.BundleId)
-		sc.maxBundleId = txInfo.BundleId
+	for maxID := atomic.LoadInt64(&sc.maxBundleId); txInfo.BundleId > maxID; maxID = atomic.LoadInt64(&sc.maxBundleId) {
+		if atomic.CompareAndSwapInt64(&sc.maxBundleId, maxID, txInfo.BundleId) {
+			break
+		}
 	}
 
 	// -------- TX INSERTION INTO MAIN TXS LIST ---------
 	// -------- TODO: In the future probably want to refactor to not have txs clist ---------
 
+	sc.memMtx.Lock()
+	defer sc.memMtx.Unlock()
+	if bundle.evicted {
+		logger.Debug("Skipping sidecar tx, its bundle was evicted to make room")
+		sc.cache.Remove(tx)
+		return sc.fullError()
+	}
 	e := sc.txs.PushBack(scTx)
 	sc.txsMap.Store(TxKey(scTx.tx), e)
 	atomic.AddInt64(&sc.txsBytes, int64(len(scTx.tx)))
-	fmt.Println("[mev-tendermint]: AddTx(): actually added the tx to the sc.txs CList, sidecar size is now", sc.Size())
+	sc.metrics.AddedTxs.With("origin", scTx.origin.String()).Add(1)
+	sc.metrics.SidecarAddedTxs.Add(1)
+	sc.reportSize()
+	sc.feed.publishSidecarTx(TxAdded, scTx, 0)
+	if sc.eventBus != nil {
+		if err := sc.eventBus.PublishEventSidecarTxAdded(sidecarTxEventData(scTx, "")); err != nil {
+			logger.Error("Failed publishing sidecar tx added event", "err", err)
+		}
+	}
+	if sc.pinner != nil {
+		sc.pinner.PinTx(TxKey(tx), scTx.desiredHeight)
+	}
+	logger.Debug("Added sidecar tx", "sidecar_size", sc.Size())
 
 	// TODO: in the future, refactor to only notifyTxsAvailable when we have at least one full bundle
 	if sc.Size() > 0 {
 		sc.notifyTxsAvailable()
 	}
 
-	fmt.Println("[mev-tendermint]: ADDING SIDECAR TX FUNCTION COMPLETION")
-
 	return nil
 }
 
+// checkBundle asks the BundleChecker to accept the complete bundle, giving up
+// once ctx is done. If it's vetoed, the bundle is marked so and its txs
+// already in the sidecar are dropped; the bundle itself is kept, full, so its
+// txs aren't taken again.
+func (sc *CListPriorityTxSidecar) checkBundle(ctx context.Context, bundle *Bundle) error {
+	txs := bundleTxs(bundle)
+
+	var err error
+	if ctx.Done() == nil {
+		err = sc.bundleChecker.CheckBundle(bundle.desiredHeight, bundle.bundleId, txs)
+	} else {
+		errCh := make(chan error, 1)
+		go func() { errCh <- sc.bundleChecker.CheckBundle(bundle.desiredHeight, bundle.bundleId, txs) }()
+		select {
+		case err = <-errCh:
+		case <-ctx.Done():
+			err = ErrCheckTxTimeout
+		}
+	}
+	if err == nil {
+		return nil
+	}
+
+	sc.bundleLogger(bundle).Info("Bundle vetoed by the app", "err", err)
+	traceBundleVetoed(bundle, err)
+	sc.memMtx.Lock()
+	sc.auditBundle(bundle, BundleAuditVetoed, err.Error())
+	sc.memMtx.Unlock()
+	atomic.StoreInt32(&bundle.vetoed, 1)
+	for _, tx := range txs {
+		if e, ok := sc.txsMap.Load(TxKey(tx)); ok {
+			sc.removeTx(tx, e.(*clist.CElement), false, RemovalVetoed)
+		}
+	}
+	return ErrBundleVetoed{
+		bundle.bundleId,
+		bundle.desiredHeight,
+		err,
+	}
+}
+
 // TxsWaitChan returns a channel to wait on transactions. It will be closed
 // once the sidecar is not empty (ie. the internal `mem.txs` has at least one
 // element)
@@ -284,61 +693,181 @@ func (sc *CListPriorityTxSidecar) Update(
 	sc.height = height
 	sc.notifiedTxsAvailable = false
 	sc.heightForFiringAuction = height + 1
+	sc.auctionDeadline = sc.nextAuctionDeadline(time.Now())
 
+	committedBundles := make(map[Key]struct{})
 	for i, tx := range txs {
 		if e, ok := sc.txsMap.Load(TxKey(tx)); ok {
-			fmt.Println(fmt.Sprintf("[mev-tendermint]: on sidecar Update(), found COMMITTED tx %.20q in sidecar, removing!", tx))
-			if deliverTxResponses[i].Code == abci.CodeTypeOK {
-				fmt.Println("... and was valid!")
-			} else {
-				fmt.Println("... and was invalid!")
-			}
-			sc.removeTx(tx, e.(*clist.CElement), false)
+			scTx := e.(*clist.CElement).Value.(*SidecarTx)
+			committedBundles[Key{scTx.desiredHeight, scTx.bundleId}] = struct{}{}
+			sc.logger.Debug("Removing committed sidecar tx", "tx", txID(tx),
+				"bundle_height", scTx.desiredHeight, "bundle_id", scTx.bundleId, "bundle_order", scTx.bundleOrder,
+				"valid", deliverTxResponses[i].Code == abci.CodeTypeOK)
+			sc.removeTx(tx, e.(*clist.CElement), false, RemovalCommitted)
 		}
 	}
+	sc.removeCommittedBundles(committedBundles)
 
-	// TODO: cache reset correct?
-	sc.cache.Reset()
-	sc.maxBundleId = 0
-
-	// remove from txs list and txmap
-	for e := sc.txs.Front(); e != nil; e = e.Next() {
-		scTx := e.Value.(*SidecarTx)
-		if scTx.desiredHeight <= height {
-			fmt.Println(fmt.Sprintf("[mev-tendermint]: on sidecar Update(), found UNCOMMITTED tx %.20q in sidecar, removing! height for tx is %d, and updating to height %d", scTx.tx, scTx.desiredHeight, height))
-			tx := scTx.tx
-			sc.removeTx(tx, e, false)
-		}
+	if sc.config.CacheScope != cfg.SidecarCacheScopeRetain {
+		sc.cache.Reset()
 	}
 
-	// remove the bundles
+	// bundles are purged once bundle_retain_heights have passed since their height
+	purgeHeight := height - sc.config.BundleRetainHeights
+
+	// remove the bundles, and their txs from the txs list and txmap
 	sc.bundles.Range(func(key, _ interface{}) bool {
 		if bundle, ok := sc.bundles.Load(key); ok {
 			bundle := bundle.(*Bundle)
-			if bundle.desiredHeight <= height {
-				fmt.Println(fmt.Sprintf("[mev-tendermint]: on sidecar Update(), removing bundle with id %d in sidecar! height for bundle is %d, and updating to height %d", bundle.bundleId, bundle.desiredHeight, height))
-				sc.bundles.Delete(key)
+			if bundle.desiredHeight <= purgeHeight {
+				sc.bundleLogger(bundle).Debug("Removing bundle past its height", "height", height)
+				// the bundle was included if none of its txs is left
+				reason := RemovalCommitted
+				if atomic.LoadInt32(&bundle.vetoed) == 1 {
+					reason = RemovalVetoed
+				}
+				bundle.orderedTxsMap.Range(func(_, scTx interface{}) bool {
+					tx := scTx.(*SidecarTx).tx
+					if e, ok := sc.txsMap.Load(TxKey(tx)); ok {
+						sc.bundleLogger(bundle).Debug("Removing uncommitted sidecar tx", "tx", txID(tx))
+						sc.removeTx(tx, e.(*clist.CElement), false, RemovalUnused)
+						if reason == RemovalCommitted {
+							reason = RemovalUnused
+						}
+					}
+					return true
+				})
+				sc.deleteBundle(key.(Key), bundle, reason)
 			}
 		}
 		return true
 	})
 
+	sc.purgeExpiredBundles(height, time.Now())
+	sc.stats.prune(height)
+
+	// bundles accepted ahead of their height are auctioned once it comes
+	atomic.StoreInt64(&sc.maxBundleId, sc.maxBundleIdAt(sc.heightForFiringAuction))
+
+	sc.metrics.SidecarAuctionHeight.Set(float64(sc.heightForFiringAuction))
+	sc.reportBundles()
+
 	return nil
 }
 
+// maxBundleIdAt returns the highest id of the bundles held for height, 0 if
+// none.
+//
+// Lock() must be held by the caller during execution.
+func (sc *CListPriorityTxSidecar) maxBundleIdAt(height int64) int64 {
+	var maxID int64
+	sc.bundles.Range(func(key, _ interface{}) bool {
+		if k := key.(Key); k.height == height && k.bundleId > maxID {
+			maxID = k.bundleId
+		}
+		return true
+	})
+	return maxID
+}
+
+// bundleIdsAt returns the ids of the bundles held for height, in ascending
+// order. Ids come from the wire, so the bundles are iterated by these rather
+// than by every id up to the highest.
+func (sc *CListPriorityTxSidecar) bundleIdsAt(height int64) []int64 {
+	ids := make([]int64, 0)
+	sc.bundles.Range(func(key, _ interface{}) bool {
+		if k := key.(Key); k.height == height {
+			ids = append(ids, k.bundleId)
+		}
+		return true
+	})
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// removeCommittedBundles removes the bundles with keys in committed, some of
+// whose txs were just committed, whatever height they target. Those whose txs
+// were all committed are satisfied. The others can't be included whole
+// anymore, so their remaining txs are dropped, keeping the sidecar consistent
+// with the blocks the mempool was updated with.
+//
+// Lock() must be held by the caller during execution.
+func (sc *CListPriorityTxSidecar) removeCommittedBundles(committed map[Key]struct{}) {
+	for key := range committed {
+		value, ok := sc.bundles.Load(key)
+		if !ok {
+			continue
+		}
+		bundle := value.(*Bundle)
+		reason := RemovalCommitted
+		bundle.orderedTxsMap.Range(func(_, scTx interface{}) bool {
+			tx := scTx.(*SidecarTx).tx
+			if e, ok := sc.txsMap.Load(TxKey(tx)); ok {
+				sc.removeTx(tx, e.(*clist.CElement), false, RemovalConflicted)
+				reason = RemovalConflicted
+			}
+			return true
+		})
+		sc.deleteBundle(key, bundle, reason)
+	}
+}
+
+// purgeExpiredBundles removes the bundles, and their txs, whose first tx
+// arrived more than BundleTTLNumBlocks blocks or BundleTTLDuration before
+// height was committed at now.
+//
+// Lock() must be held by the caller during execution.
+func (sc *CListPriorityTxSidecar) purgeExpiredBundles(height int64, now time.Time) {
+	ttlNumBlocks, ttlDuration := sc.config.BundleTTLNumBlocks, sc.config.BundleTTLDuration
+	if ttlNumBlocks == 0 && ttlDuration == 0 {
+		return
+	}
+
+	sc.bundles.Range(func(key, value interface{}) bool {
+		bundle := value.(*Bundle)
+		if (ttlNumBlocks == 0 || height-bundle.firstSeenHeight <= ttlNumBlocks) &&
+			(ttlDuration == 0 || now.Sub(bundle.firstSeen) <= ttlDuration) {
+			return true
+		}
+		sc.bundleLogger(bundle).Info("Removing expired bundle")
+		bundle.orderedTxsMap.Range(func(_, scTx interface{}) bool {
+			tx := scTx.(*SidecarTx).tx
+			if e, ok := sc.txsMap.Load(TxKey(tx)); ok {
+				sc.removeTx(tx, e.(*clist.CElement), false, RemovalExpired)
+			}
+			return true
+		})
+		sc.deleteBundle(key.(Key), bundle, RemovalExpired)
+		return true
+	})
+}
+
+// PurgeExpiredBundles removes the bundles, and their txs, that have been
+// waiting longer than BundleTTLDuration, as of now, without waiting for the
+// next commit. See SidecarConfig.BundlePurgeInterval.
+//
+// Safe for concurrent use by multiple goroutines.
+func (sc *CListPriorityTxSidecar) PurgeExpiredBundles(now time.Time) {
+	sc.Lock()
+	defer sc.Unlock()
+	sc.purgeExpiredBundles(sc.height, now)
+	sc.reportBundles()
+}
+
 // Lock() must be help by the caller during execution.
 // Lock() must be help by the caller during execution.
 func (sc *CListPriorityTxSidecar) Flush() {
 	sc.cache.Reset()
 
 	sc.notifiedTxsAvailable = false
-	sc.maxBundleId = 0
+	atomic.StoreInt64(&sc.maxBundleId, 0)
 
 	_ = atomic.SwapInt64(&sc.txsBytes, 0)
 
 	for e := sc.txs.Front(); e != nil; e = e.Next() {
 		sc.txs.Remove(e)
 		e.DetachPrev()
+		sc.notifyTxRemoved(e.Value.(*SidecarTx), RemovalFlushed)
 	}
 
 	sc.txsMap.Range(func(key, _ interface{}) bool {
@@ -347,10 +876,23 @@ func (sc *CListPriorityTxSidecar) Flush() {
 	})
 
 	// TODO: does the below not have garbage collection?
-	sc.bundles.Range(func(key, _ interface{}) bool {
-		sc.bundles.Delete(key)
+	sc.bundles.Range(func(key, bundle interface{}) bool {
+		sc.deleteBundle(key.(Key), bundle.(*Bundle), RemovalFlushed)
 		return true
 	})
+	sc.reportSize()
+	sc.reportBundles()
+}
+
+// Lock() must be held by the caller during execution.
+func (sc *CListPriorityTxSidecar) Reset(height int64) {
+	sc.Flush()
+
+	sc.height = height
+	sc.heightForFiringAuction = height + 1
+	sc.auctionDeadline = sc.nextAuctionDeadline(time.Now())
+	sc.metrics.SidecarAuctionHeight.Set(float64(sc.heightForFiringAuction))
+	sc.reportBundles()
 }
 
 // Safe for concurrent use by multiple goroutines.
@@ -358,6 +900,38 @@ func (sc *CListPriorityTxSidecar) Size() int {
 	return sc.txs.Len()
 }
 
+// isFull returns an error if a tx of txSize bytes, for the bundle with key,
+// would take the sidecar past its limits. Only new bundles count against
+// MaxBundles.
+func (sc *CListPriorityTxSidecar) isFull(txSize int, key Key) error {
+	var (
+		numTxs   = sc.Size()
+		txsBytes = sc.TxsBytes()
+		full     = (sc.config.MaxTxs > 0 && numTxs >= sc.config.MaxTxs) ||
+			(sc.config.MaxBytes > 0 && int64(txSize)+txsBytes > sc.config.MaxBytes)
+	)
+	if !full && sc.config.MaxBundles > 0 {
+		if _, ok := sc.bundles.Load(key); !ok {
+			full = sc.NumBundles() >= sc.config.MaxBundles
+		}
+	}
+
+	if full {
+		return sc.fullError()
+	}
+	return nil
+}
+
+// fullError returns the ErrSidecarIsFull telling how full the sidecar is.
+func (sc *CListPriorityTxSidecar) fullError() error {
+	return ErrSidecarIsFull{
+		sc.Size(), sc.config.MaxTxs,
+		sc.TxsBytes(), sc.config.MaxBytes,
+		sc.NumBundles(), sc.config.MaxBundles,
+		sc.MemBytes() + sc.config.CacheMemBytes(), sc.config.MaxMemory,
+	}
+}
+
 // Safe for concurrent use by multiple goroutines.
 func (sc *CListPriorityTxSidecar) NumBundles() int {
 	i := 0
@@ -370,7 +944,7 @@ func (sc *CListPriorityTxSidecar) NumBundles() int {
 
 // Safe for concurrent use by multiple goroutines.
 func (sc *CListPriorityTxSidecar) MaxBundleId() int64 {
-	return sc.maxBundleId
+	return atomic.LoadInt64(&sc.maxBundleId)
 }
 
 func (sc *CListPriorityTxSidecar) HeightForFiringAuction() int64 {
@@ -383,7 +957,7 @@ func (sc *CListPriorityTxSidecar) GetEnforcedBundleSize(bundleId int64) int {
 		bundle := bundle.(*Bundle)
 		return int(bundle.enforcedSize)
 	} else {
-		fmt.Println("Error GetEnforcedBundleSize(): Don't have a bundle for bundleId", bundleId)
+		sc.logger.Debug("No bundle to get the enforced size of", "bundle_height", sc.heightForFiringAuction, "bundle_id", bundleId)
 		return 0
 	}
 }
@@ -394,7 +968,7 @@ func (sc *CListPriorityTxSidecar) GetCurrBundleSize(bundleId int64) int {
 		bundle := bundle.(*Bundle)
 		return int(bundle.currSize)
 	} else {
-		fmt.Println("Error GetBundleSize(): Don't have a bundle for bundleId", bundleId)
+		sc.logger.Debug("No bundle to get the size of", "bundle_height", sc.heightForFiringAuction, "bundle_id", bundleId)
 		return 0
 	}
 }
@@ -404,54 +978,278 @@ func (sc *CListPriorityTxSidecar) TxsBytes() int64 {
 	return atomic.LoadInt64(&sc.txsBytes)
 }
 
+// nextAuctionDeadline returns the time after which new bundles for the next
+// auction are considered late, given that the previous block was committed at
+// committedAt. Returns the zero time if no cutoff is configured.
+func (sc *CListPriorityTxSidecar) nextAuctionDeadline(committedAt time.Time) time.Time {
+	if sc.config.AuctionWindow > 0 {
+		return committedAt.Add(sc.config.AuctionWindow)
+	}
+	if sc.config.AuctionCutoff <= 0 {
+		return time.Time{}
+	}
+	return committedAt.Add(sc.proposalDelay - sc.config.AuctionCutoff)
+}
+
+// isPastAuctionCutoff returns true if a bundle for desiredHeight first seen at
+// now should be excluded from the upcoming auction.
+func (sc *CListPriorityTxSidecar) isPastAuctionCutoff(desiredHeight int64, now time.Time) bool {
+	if sc.auctionDeadline.IsZero() || desiredHeight != sc.heightForFiringAuction {
+		return false
+	}
+	return now.After(sc.auctionDeadline)
+}
+
 // Called from:
 //  - FlushSidecar (lock held) if tx was committed
-func (sc *CListPriorityTxSidecar) removeTx(tx types.Tx, elem *clist.CElement, removeFromCache bool) {
+func (sc *CListPriorityTxSidecar) removeTx(tx types.Tx, elem *clist.CElement, removeFromCache bool, reason RemovalReason) {
 	sc.txs.Remove(elem)
 	elem.DetachPrev()
 	sc.txsMap.Delete(TxKey(tx))
 	atomic.AddInt64(&sc.txsBytes, int64(-len(tx)))
+	sc.reportSize()
+	sc.notifyTxRemoved(elem.Value.(*SidecarTx), reason)
 
 	if removeFromCache {
 		sc.cache.Remove(tx)
 	}
 }
 
+func (sc *CListPriorityTxSidecar) notifyTxRemoved(scTx *SidecarTx, reason RemovalReason) {
+	if reason == RemovalCommitted {
+		sc.metrics.IncludedTxs.With("origin", scTx.origin.String()).Add(1)
+	}
+	sc.feed.publishSidecarTx(TxRemoved, scTx, reason)
+	if sc.eventBus != nil {
+		data := sidecarTxEventData(scTx, reason.String())
+		if err := sc.eventBus.PublishEventSidecarTxRemoved(data); err != nil {
+			sc.logger.Error("Failed publishing sidecar tx removed event", "err", err)
+		}
+	}
+	for _, hook := range sc.removalHooks {
+		hook.TxRemoved(scTx.tx, true, reason)
+	}
+}
+
+// sidecarTxEventData describes scTx for the event bus, with the reason it was
+// removed if any.
+func sidecarTxEventData(scTx *SidecarTx, reason string) types.EventDataSidecarTx {
+	return types.EventDataSidecarTx{
+		Tx:          scTx.tx,
+		Height:      scTx.desiredHeight,
+		BundleID:    scTx.bundleId,
+		BundleOrder: scTx.bundleOrder,
+		BundleSize:  scTx.bundleSize,
+		Origin:      scTx.origin.String(),
+		Reason:      reason,
+	}
+}
+
+func (sc *CListPriorityTxSidecar) notifyBundleRemoved(bundle *Bundle, reason RemovalReason) {
+	switch reason {
+	case RemovalCommitted:
+		sc.metrics.SidecarBundlesIncluded.Add(1)
+	case RemovalExpired, RemovalUnused:
+		sc.metrics.SidecarBundlesExpired.Add(1)
+	}
+	if reason != RemovalCommitted && atomic.LoadInt64(&bundle.currSize) < bundle.enforcedSize {
+		sc.metrics.SidecarBundlesIncomplete.With("reason", reason.String()).Add(1)
+	}
+	for _, hook := range sc.removalHooks {
+		hook.BundleRemoved(bundle.desiredHeight, bundle.bundleId, reason)
+	}
+	if reason == RemovalCommitted {
+		sc.auditBundle(bundle, BundleAuditIncluded, "")
+	} else {
+		sc.auditBundle(bundle, BundleAuditDropped, reason.String())
+	}
+	sc.stats.bundleRemoved(bundle, reason)
+	endBundleSpan(bundle, reason)
+}
+
+// GetTxByKey returns the sidecar tx with the given TxKey, and the bundle it
+// belongs to as TxInfo.
+func (sc *CListPriorityTxSidecar) GetTxByKey(txKey [TxKeySize]byte) (types.Tx, TxInfo, bool) {
+	e, ok := sc.txsMap.Load(txKey)
+	if !ok {
+		return nil, TxInfo{}, false
+	}
+	scTx := e.(*clist.CElement).Value.(*SidecarTx)
+	return scTx.tx, TxInfo{
+		DesiredHeight: scTx.desiredHeight,
+		BundleId:      scTx.bundleId,
+		BundleOrder:   scTx.bundleOrder,
+		BundleSize:    scTx.bundleSize,
+		Bid:           scTx.bid,
+		Origin:        scTx.origin,
+	}, true
+}
+
+// RemoveTxByKey removes a tx from the sidecar by its TxKey index. Its bundle
+// is left incomplete, so it isn't reaped unless the tx is added again.
+//
+// Safe for concurrent use by multiple goroutines.
+func (sc *CListPriorityTxSidecar) RemoveTxByKey(txKey [TxKeySize]byte, removeFromCache bool) {
+	sc.updateMtx.Lock()
+	defer sc.updateMtx.Unlock()
+	e, ok := sc.txsMap.Load(txKey)
+	if !ok {
+		return
+	}
+	scTx := e.(*clist.CElement).Value.(*SidecarTx)
+	if bundle, ok := sc.bundles.Load(Key{scTx.desiredHeight, scTx.bundleId}); ok {
+		sc.removeBundleTx(bundle.(*Bundle), scTx)
+	}
+	sc.removeTx(scTx.tx, e.(*clist.CElement), removeFromCache, RemovalRequested)
+}
+
+// RemoveBundleTxs removes txs from the bundle for height with bundleId, if
+// they're part of it, and the bundle itself if it's left empty. The other
+// txs of the bundle, eg. added by another caller colliding on its id, are
+// kept.
+//
+// Safe for concurrent use by multiple goroutines.
+func (sc *CListPriorityTxSidecar) RemoveBundleTxs(height, bundleId int64, txs types.Txs, removeFromCache bool) {
+	sc.updateMtx.Lock()
+	defer sc.updateMtx.Unlock()
+	key := Key{height, bundleId}
+	value, ok := sc.bundles.Load(key)
+	if !ok {
+		return
+	}
+	bundle := value.(*Bundle)
+	for _, tx := range txs {
+		e, ok := sc.txsMap.Load(TxKey(tx))
+		if !ok {
+			continue
+		}
+		scTx := e.(*clist.CElement).Value.(*SidecarTx)
+		if scTx.desiredHeight != height || scTx.bundleId != bundleId {
+			continue
+		}
+		sc.removeBundleTx(bundle, scTx)
+		sc.removeTx(scTx.tx, e.(*clist.CElement), removeFromCache, RemovalRequested)
+	}
+	if atomic.LoadInt64(&bundle.currSize) == 0 {
+		sc.deleteBundle(key, bundle, RemovalRequested)
+	}
+}
+
+// removeBundleTx removes scTx from bundle, if it's held at its order, and
+// the memory it took.
+//
+// Lock() must be held by the caller during execution.
+func (sc *CListPriorityTxSidecar) removeBundleTx(bundle *Bundle, scTx *SidecarTx) {
+	sc.memMtx.Lock()
+	defer sc.memMtx.Unlock()
+	if stored, ok := bundle.orderedTxsMap.Load(scTx.bundleOrder); ok && stored.(*SidecarTx) == scTx {
+		bundle.orderedTxsMap.Delete(scTx.bundleOrder)
+		atomic.AddInt64(&bundle.currSize, -1)
+		bundle.memBytes -= txMemBytes(scTx.tx)
+		sc.addMemBytes(-txMemBytes(scTx.tx))
+	}
+}
+
+// NextBundleId returns the id after the highest of the bundles held for
+// height, and false if a bundle is held with it already.
+//
+// Safe for concurrent use by multiple goroutines.
+func (sc *CListPriorityTxSidecar) NextBundleId(height int64) (int64, bool) {
+	sc.updateMtx.Lock()
+	defer sc.updateMtx.Unlock()
+	bundleId := sc.maxBundleIdAt(height) + 1
+	_, taken := sc.bundles.Load(Key{height, bundleId})
+	return bundleId, !taken
+}
+
+// checkBundleSize returns ErrBundleSizeOutOfRange if bundleSize is out of
+// SidecarConfig.MinBundleSize and MaxBundleSize.
+//
+// Safe for concurrent use by multiple goroutines.
+func (sc *CListPriorityTxSidecar) checkBundleSize(bundleSize int64) error {
+	minSize, maxSize := sc.config.MinBundleSize, sc.config.MaxBundleSize
+	if bundleSize < minSize || (maxSize > 0 && bundleSize > maxSize) {
+		return ErrBundleSizeOutOfRange{bundleSize, minSize, maxSize}
+	}
+	return nil
+}
+
+// Safe for concurrent use by multiple goroutines.
+func (sc *CListPriorityTxSidecar) ReapMaxTxs() []*MempoolTx {
+	memTxs, _ := sc.ReapAuction()
+	return memTxs
+}
+
 // Safe for concurrent use by multiple goroutines.
 // TODO: add gas and byte limits (but requires tracking gas)
 
-// this reap function iterates over all the bundleIds up to maxBundleId
+// this reap function iterates over the bundleIds held for the auction's height, in ascending order
 // ... then goes over each bundle via the bundleOrders (up to enforcedSize for bundle)
 // ... and reaps them in this order
-func (sc *CListPriorityTxSidecar) ReapMaxTxs() []*MempoolTx {
+func (sc *CListPriorityTxSidecar) ReapAuction() ([]*MempoolTx, []types.AuctionBundle) {
 	sc.updateMtx.RLock()
 	defer sc.updateMtx.RUnlock()
 
-	fmt.Println(fmt.Sprintf("REAPING SIDECAR via ReapMaxTxs(): sidecar size at this time is %d", sc.Size()))
+	start := time.Now()
+	defer func() { sc.metrics.SidecarReapDuration.Observe(time.Since(start).Seconds()) }()
+
+	sc.logger.Debug("Reaping the sidecar's auction", "height", sc.heightForFiringAuction, "sidecar_size", sc.Size())
 
 	memTxs := make([]*MempoolTx, 0, sc.txs.Len())
+	candidates := make([]types.AuctionBundle, 0)
 
 	if (sc.txs.Len() == 0) || (sc.NumBundles() == 0) {
-		return memTxs
+		return memTxs, candidates
+	}
+	// sentries and relays only pass bundles on, and nothing is auctioned
+	// while MEV is off
+	if !sc.config.IncludesBundles() || sc.MEVDisabled() {
+		return memTxs, candidates
 	}
 
-	// iterate over all bundleIds up to the max we've seen
+	// iterate over the bundleIds we hold, not every one up to the max we've seen
 	// CONTRACT: this assumes that bundles don't care about previous bundles, so still want to execute if any missing between
-	for bundleIdIter := 0; bundleIdIter <= int(sc.maxBundleId); bundleIdIter++ {
-		bundleIdIter := int64(bundleIdIter)
-
+	for _, bundleIdIter := range sc.bundleIdsAt(sc.heightForFiringAuction) {
 		if bundle, ok := sc.bundles.Load(Key{sc.heightForFiringAuction, bundleIdIter}); ok {
 			bundle := bundle.(*Bundle)
 			bundleOrderedTxsMap := bundle.orderedTxsMap
+			logger := sc.logger.With("bundle_height", sc.heightForFiringAuction, "bundle_id", bundleIdIter)
+
+			candidate := types.AuctionBundle{
+				BundleId:   bundleIdIter,
+				Size:       bundle.enforcedSize,
+				Bid:        bundle.bid,
+				Status:     types.AuctionBundleIncomplete,
+				Extensions: bundle.extensions,
+			}
+
+			// bundles that showed up after the auction cutoff are only gossiped
+			if bundle.late {
+				logger.Debug("Skipping bundle in auction, it arrived after the auction cutoff")
+				candidate.Status = types.AuctionBundleLate
+				candidates = append(candidates, candidate)
+				continue
+			}
+
+			if atomic.LoadInt32(&bundle.vetoed) == 1 {
+				logger.Debug("Skipping bundle in auction, it was vetoed by the app")
+				candidate.Status = types.AuctionBundleVetoed
+				candidates = append(candidates, candidate)
+				continue
+			}
 
 			// check to see if bundle is full, if not, just skip now
 			if bundle.currSize != bundle.enforcedSize {
-				fmt.Println(fmt.Sprintf("ReapMaxTxs() SKIPPING BUNDLE...: size mismatch for bundleId %d at height %d: currSize %d, enforcedSize %d: SKIPPING...", bundleIdIter, sc.heightForFiringAuction, bundle.currSize, bundle.enforcedSize))
+				logger.Debug("Skipping bundle in auction, it's incomplete",
+					"size", atomic.LoadInt64(&bundle.currSize), "enforced_size", bundle.enforcedSize)
+				candidates = append(candidates, candidate)
 				continue
 			}
 
 			// if full, iterate over bundle in order and add txs to temporary store, then add all if we have enough (i.e. matches enforcedBundleSize)
 			innerTxs := make([]*MempoolTx, 0, bundle.enforcedSize)
+			bundleTxs := make(types.Txs, 0, bundle.enforcedSize)
+			var innerBytes int64
 			for bundleOrderIter := 0; bundleOrderIter < int(bundle.enforcedSize); bundleOrderIter++ {
 				bundleOrderIter := int64(bundleOrderIter)
 
@@ -460,15 +1258,23 @@ func (sc *CListPriorityTxSidecar) ReapMaxTxs() []*MempoolTx {
 					scTx := scTx.(*SidecarTx)
 					memTx := &MempoolTx{
 						// CONTRACT: since the only height this could have been added into is desiredHeight = mem.height + 1, then this tx must have been validated against mem.height
-						height:    scTx.desiredHeight - 1,
-						gasWanted: scTx.gasWanted,
-						tx:        scTx.tx,
-						senders:   scTx.senders,
+						height:      scTx.desiredHeight - 1,
+						gasWanted:   scTx.gasWanted,
+						tx:          scTx.tx,
+						origin:      scTx.origin,
+						bundleId:    scTx.bundleId,
+						bundleOrder: scTx.bundleOrder,
 					}
+					scTx.senders.Range(func(key, value interface{}) bool {
+						memTx.senders.Store(key, value)
+						return true
+					})
 					innerTxs = append(innerTxs, memTx)
+					bundleTxs = append(bundleTxs, scTx.tx)
+					innerBytes += int64(len(scTx.tx))
 				} else {
 					// can't find tx at this bundleOrder for this bundleId
-					fmt.Println(fmt.Sprintf("ReapMaxTxs() skip: don't have memTx for bundleOrder %d bundleId %d at height %d", bundleOrderIter, bundleIdIter, sc.heightForFiringAuction))
+					logger.Debug("Missing bundle tx in auction", "bundle_order", bundleOrderIter)
 				}
 			}
 
@@ -476,16 +1282,24 @@ func (sc *CListPriorityTxSidecar) ReapMaxTxs() []*MempoolTx {
 			if bundle.enforcedSize == int64(len(innerTxs)) {
 				// check to see if we've reaped the right number of txs expected for the bundle
 				memTxs = append(memTxs, innerTxs...)
+				candidate.Bytes = innerBytes
+				candidate.Hash = bundleTxs.Hash()
+				candidate.Status = types.AuctionBundleIncluded
+				sc.observeAuctionLatency(bundle)
+				logger.Debug("Reaped bundle in auction", "bundle_hash", candidate.Hash)
 			} else {
-				fmt.Println(fmt.Sprintf("ReapMaxTxs() SKIPPING BUNDLE...: size mismatch for bundleId %d at height %d: reaped %d, bundleSize %d, enforcedBundleSize %d: SKIPPING...", bundleIdIter, sc.heightForFiringAuction, len(innerTxs), bundle.currSize, bundle.enforcedSize))
+				logger.Debug("Skipping bundle in auction, some of its txs are missing",
+					"reaped", len(innerTxs), "enforced_size", bundle.enforcedSize)
 			}
+			candidates = append(candidates, candidate)
 		} else {
-			// can't find a bundle for this bundleId, panic! (incomplete gossipping)
-			fmt.Println(fmt.Sprintf("ReapMaxTxs() SKIPPING BUNDLE...: don't have bundle entry for bundleId %d at height %d", bundleIdIter, sc.heightForFiringAuction))
+			// the bundle was removed since its id was listed
+			sc.logger.Debug("Skipping bundle in auction, it was removed",
+				"bundle_height", sc.heightForFiringAuction, "bundle_id", bundleIdIter)
 		}
 	}
 
-	return memTxs
+	return memTxs, candidates
 }
 
 // Safe for concurrent use by multiple goroutines.
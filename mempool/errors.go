@@ -3,11 +3,25 @@ package mempool
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
 	// ErrTxInCache is returned to the client if we saw tx earlier
 	ErrTxInCache = errors.New("tx already exists in cache")
+
+	// ErrCheckTxTimeout is returned to the client if the app didn't check tx
+	// before the deadline
+	ErrCheckTxTimeout = errors.New("app didn't check tx in time")
+
+	// ErrMEVDisabled is returned to the client if MEV is turned off on this
+	// node, see CListPriorityTxSidecar.SetMEVDisabled
+	ErrMEVDisabled = errors.New("MEV is disabled on this node")
+
+	// ErrRelayAssignsBundleIDs is returned to the client submitting a bundle
+	// to a node with a relay, which assigns the bundle ids, see
+	// Reactor.SubmitBundle
+	ErrRelayAssignsBundleIDs = errors.New("bundle ids are assigned by the relay configured on this node")
 )
 
 // ErrWrongHeight means the tx is asking to be in a height that doesn't match the current auction
@@ -30,6 +44,18 @@ func (e ErrBundleFull) Error() string {
 	return fmt.Sprintf("Tx submitted but bundle is full, for bundleId %d with bundle size %d", e.bundleId, e.bundleHeight)
 }
 
+// ErrBundleOrderTaken means the bundle already holds a different tx at the
+// tx's order
+type ErrBundleOrderTaken struct {
+	bundleId     int64
+	bundleHeight int64
+	bundleOrder  int64
+}
+
+func (e ErrBundleOrderTaken) Error() string {
+	return fmt.Sprintf("Tx submitted but its bundle has a tx at its order already, for bundleId %d, at height %d, and bundleOrder %d", e.bundleId, e.bundleHeight, e.bundleOrder)
+}
+
 // ErrTxMalformedForBundle is a general malformed error for specific cases
 type ErrTxMalformedForBundle struct {
 	bundleId     int64
@@ -42,6 +68,43 @@ func (e ErrTxMalformedForBundle) Error() string {
 	return fmt.Sprintf("Tx submitted but malformed with respect to bundling, for bundleId %d, at height %d, with bundleSize %d, and bundleOrder %d", e.bundleId, e.bundleHeight, e.bundleSize, e.bundleOrder)
 }
 
+// ErrBundleVetoed means the app refused the bundle the tx completed
+type ErrBundleVetoed struct {
+	bundleId     int64
+	bundleHeight int64
+	reason       error
+}
+
+func (e ErrBundleVetoed) Error() string {
+	return fmt.Sprintf("Bundle vetoed by the app, for bundleId %d at height %d: %v", e.bundleId, e.bundleHeight, e.reason)
+}
+
+// ErrBidTooLow means the tx's bundle bids less than the sidecar's floor
+type ErrBidTooLow struct {
+	bid    int64
+	minBid int64
+}
+
+func (e ErrBidTooLow) Error() string {
+	return fmt.Sprintf("Bundle bid too low. Min bid is %d, but got %d", e.minBid, e.bid)
+}
+
+// ErrBundleSizeOutOfRange means the tx's bundle declares a size out of the
+// sidecar's bounds
+type ErrBundleSizeOutOfRange struct {
+	bundleSize    int64
+	minBundleSize int64
+	maxBundleSize int64 // 0 if unlimited
+}
+
+func (e ErrBundleSizeOutOfRange) Error() string {
+	if e.maxBundleSize == 0 {
+		return fmt.Sprintf("Bundle size out of range. Min size is %d, but got %d", e.minBundleSize, e.bundleSize)
+	}
+	return fmt.Sprintf("Bundle size out of range. Size must be between %d and %d, but got %d",
+		e.minBundleSize, e.maxBundleSize, e.bundleSize)
+}
+
 // ErrTxTooLarge means the tx is too big to be sent in a message to other peers
 type ErrTxTooLarge struct {
 	max    int
@@ -68,6 +131,74 @@ func (e ErrMempoolIsFull) Error() string {
 		e.txsBytes, e.maxTxsBytes)
 }
 
+// ErrSenderIsFull means the sender of a tx already has as many pending txs
+// in the mempool as it may
+type ErrSenderIsFull struct {
+	sender string
+	numTxs int
+	maxTxs int
+}
+
+func (e ErrSenderIsFull) Error() string {
+	return fmt.Sprintf("sender %s has too many pending txs: %d (max: %d)", e.sender, e.numTxs, e.maxTxs)
+}
+
+// ErrCheckTxOverloaded means too many CheckTx calls are awaiting the app's
+// response to check a tx received from a peer
+type ErrCheckTxOverloaded struct {
+	pending    int64
+	maxPending int64
+}
+
+func (e ErrCheckTxOverloaded) Error() string {
+	return fmt.Sprintf("too many pending CheckTx calls: %d (max: %d)", e.pending, e.maxPending)
+}
+
+// ErrLaneIsFull means the lane of a tx already holds as many txs as it may
+type ErrLaneIsFull struct {
+	lane   string
+	numTxs int
+	maxTxs int
+}
+
+func (e ErrLaneIsFull) Error() string {
+	return fmt.Sprintf("lane %s is full: number of txs %d (max: %d)", e.lane, e.numTxs, e.maxTxs)
+}
+
+// ErrSidecarIsFull means the sidecar holds as many txs, bytes or bundles as
+// it may
+type ErrSidecarIsFull struct {
+	numTxs int
+	maxTxs int
+
+	txsBytes int64
+	maxBytes int64
+
+	numBundles int
+	maxBundles int
+
+	memBytes  int64
+	maxMemory int64
+}
+
+func (e ErrSidecarIsFull) Error() string {
+	return fmt.Sprintf(
+		"sidecar is full: number of txs %d (max: %d), total txs bytes %d (max: %d), number of bundles %d (max: %d), memory %d (max: %d)",
+		e.numTxs, e.maxTxs,
+		e.txsBytes, e.maxBytes,
+		e.numBundles, e.maxBundles,
+		e.memBytes, e.maxMemory)
+}
+
+// ErrTxFiltered means a TxFilter refused the tx
+type ErrTxFiltered struct {
+	Reason error
+}
+
+func (e ErrTxFiltered) Error() string {
+	return fmt.Sprintf("tx refused by filter: %v", e.Reason)
+}
+
 // ErrPreCheck is returned when tx is too big
 type ErrPreCheck struct {
 	Reason error
@@ -82,3 +213,13 @@ func IsPreCheckError(err error) bool {
 	_, ok := err.(ErrPreCheck)
 	return ok
 }
+
+// ErrSidecarStalled means the MEV pipeline stalled, for each of the reasons
+// given, see SidecarConfig.StallBlocks and StallTimeout.
+type ErrSidecarStalled struct {
+	Reasons []string
+}
+
+func (e ErrSidecarStalled) Error() string {
+	return fmt.Sprintf("MEV pipeline stalled: %s", strings.Join(e.Reasons, ", "))
+}
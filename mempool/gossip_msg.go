@@ -0,0 +1,158 @@
+package mempool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+
+	protomem "github.com/tendermint/tendermint/proto/tendermint/mempool"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Txs are gossiped in messages encoded once, when they're added, and shared
+// by the broadcast routines of all the peers instead of being encoded again
+// for each of them. Their bytes are kept once: the tx is the tail of its
+// encoded message (the txs are written last, see MarshalToSizedBuffer), and
+// aliases it.
+//
+// Received messages are copied once, as the connection reuses their buffer,
+// and their txs alias the copy instead of being copied each on their own.
+// Both are immutable once built.
+
+// encodeTxMsg returns the message gossiping the mempool tx, and tx aliasing
+// its tail.
+func encodeTxMsg(tx types.Tx) ([]byte, types.Tx) {
+	msg := protomem.Message{
+		Sum: &protomem.Message_Txs{
+			Txs: &protomem.Txs{Txs: [][]byte{tx}},
+		},
+	}
+	return marshalTxMsg(nil, &msg, tx)
+}
+
+// encodeSidecarTxMsg returns the message gossiping scTx, tagged for chainID,
+// and its tx aliasing its tail. The fields carrying localSidecarProtocol and
+// the chain ID are written first, as they're numbered past the txs'.
+func encodeSidecarTxMsg(scTx *SidecarTx, chainID string) ([]byte, types.Tx) {
+	msg := protomem.MEVMessage{
+		Sum: &protomem.MEVMessage_Txs{
+			Txs: &protomem.Txs{Txs: [][]byte{scTx.tx}},
+		},
+		DesiredHeight: scTx.desiredHeight,
+		BundleId:      scTx.bundleId,
+		BundleOrder:   scTx.bundleOrder,
+		BundleSize:    scTx.bundleSize,
+		Bid:           scTx.bid,
+	}
+	return marshalTxMsg(sidecarMsgFields(chainID), &msg, scTx.tx)
+}
+
+// marshalTxMsg returns msg encoded after the encoded fields prefix, and tx
+// aliasing its tail if written last.
+func marshalTxMsg(prefix []byte, msg proto.Marshaler, tx types.Tx) ([]byte, types.Tx) {
+	body, err := msg.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	bz := body
+	if len(prefix) > 0 {
+		bz = append(append(make([]byte, 0, len(prefix)+len(body)), prefix...), body...)
+	}
+	tail := bz[len(bz)-len(tx) : len(bz) : len(bz)]
+	if !bytes.Equal(tail, tx) {
+		// not written last after all, keep the tx as it is
+		return bz, tx
+	}
+	return bz, tail
+}
+
+// gossipMsg returns the message gossiping the tx to peers.
+func (memTx *MempoolTx) gossipMsg() []byte {
+	if memTx.msg != nil {
+		return memTx.msg
+	}
+	bz, _ := encodeTxMsg(memTx.tx)
+	return bz
+}
+
+// gossipMsg returns the message gossiping the tx to sidecar peers.
+func (scTx *SidecarTx) gossipMsg() []byte {
+	if scTx.msg != nil {
+		return scTx.msg
+	}
+	bz, _ := encodeSidecarTxMsg(scTx, "")
+	return bz
+}
+
+//-----------------------------------------------------------------------------
+// Decoding
+
+var errWireType = errors.New("proto: wrong wireType")
+
+// rangeFields calls f with each field of the encoded message bz: its number,
+// its wire type, and its value, the varint for proto.WireVarint and the bytes,
+// aliasing bz, for proto.WireBytes. Fields of other wire types are skipped.
+func rangeFields(bz []byte, f func(num int32, wireType int, v uint64, b []byte) error) error {
+	for len(bz) > 0 {
+		tag, n := proto.DecodeVarint(bz)
+		if n == 0 {
+			return errors.New("proto: truncated tag")
+		}
+		bz = bz[n:]
+		num, wireType := int32(tag>>3), int(tag&0x7)
+		if num <= 0 {
+			return fmt.Errorf("proto: illegal tag %d", num)
+		}
+
+		var (
+			v uint64
+			b []byte
+		)
+		switch wireType {
+		case proto.WireVarint:
+			if v, n = proto.DecodeVarint(bz); n == 0 {
+				return errors.New("proto: truncated varint")
+			}
+		case proto.WireFixed64:
+			n = 8
+		case proto.WireBytes:
+			l, m := proto.DecodeVarint(bz)
+			if m == 0 || l > uint64(len(bz)-m) {
+				return errors.New("proto: unexpected EOF")
+			}
+			n = m + int(l)
+			b = bz[m:n:n]
+		case proto.WireFixed32:
+			n = 4
+		default:
+			return fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if n > len(bz) {
+			return errors.New("proto: unexpected EOF")
+		}
+		bz = bz[n:]
+
+		if err := f(num, wireType, v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeTxs returns the txs of the encoded protomem.Txs bz, aliasing bz.
+func decodeTxs(bz []byte) ([]types.Tx, error) {
+	var txs []types.Tx
+	err := rangeFields(bz, func(num int32, wireType int, _ uint64, b []byte) error {
+		if num != 1 {
+			return nil
+		}
+		if wireType != proto.WireBytes {
+			return errWireType
+		}
+		txs = append(txs, b)
+		return nil
+	})
+	return txs, err
+}
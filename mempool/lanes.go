@@ -0,0 +1,149 @@
+package mempool
+
+import (
+	"bytes"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/p2p"
+)
+
+// LaneChannelBase is the channel the txs of the first custom lane are
+// gossiped on, those of the next ones following in their configured order.
+// Default lane txs are gossiped on MempoolChannel, and the mev lane's bundles
+// on SidecarChannel.
+const LaneChannelBase = byte(0x31)
+
+// lane is a class of mempool txs, reaped ahead of the lanes of lower priority.
+// See WithLanes.
+type lane struct {
+	cfg.LaneConfig
+	channel byte // the channel its txs are gossiped on
+	numTxs  int  // number of pending txs, guarded by CListMempool.lanesMtx
+}
+
+// LaneChannels returns the channels the custom lanes among lanes are gossiped
+// on, to be advertised in the node info.
+func LaneChannels(lanes []cfg.LaneConfig) []byte {
+	channels := make([]byte, 0)
+	for _, l := range lanes {
+		if l.Name != cfg.DefaultLane && l.Name != cfg.MEVLane {
+			channels = append(channels, LaneChannelBase+byte(len(channels)))
+		}
+	}
+	return channels
+}
+
+// WithLanes splits the mempool into lanes (see config.MempoolConfig.Lanes),
+// given the CheckTx event attribute ("type.key") naming the lane of a tx.
+// Txs are reaped by descending lane priority, those of lanes ranking above
+// the mev lane going ahead of the sidecar bundles. Txs declaring no known
+// lane go to the default lane, and those over the limit of their lane are
+// rejected. With priority or sender ordering too, txs are ordered within
+// their lane, except a sender's txs always keep their sequence order.
+func WithLanes(lanes []cfg.LaneConfig, attr string) CListMempoolOption {
+	return func(mem *CListMempool) {
+		mem.laneAttribute = attr
+		mem.lanes = make(map[string]*lane, len(lanes))
+		channels := LaneChannels(lanes)
+		for _, laneCfg := range lanes {
+			l := &lane{LaneConfig: laneCfg, channel: MempoolChannel}
+			switch laneCfg.Name {
+			case cfg.MEVLane:
+				// held by the sidecar
+				mem.mevPriority = laneCfg.Priority
+				continue
+			case cfg.DefaultLane:
+				mem.defaultLane = l
+			default:
+				l.channel, channels = channels[0], channels[1:]
+			}
+			mem.lanes[laneCfg.Name] = l
+		}
+	}
+}
+
+// declaredLane returns the lane of the tx checked with res, or nil if the
+// mempool isn't split into lanes.
+func (mem *CListMempool) declaredLane(res *abci.ResponseCheckTx) *lane {
+	if mem.lanes == nil {
+		return nil
+	}
+	if name, ok := eventAttribute(res, mem.laneAttribute); ok {
+		if l, ok := mem.lanes[name]; ok {
+			return l
+		}
+	}
+	return mem.defaultLane
+}
+
+// isLaneFull returns an error if the lane of memTx holds as many txs as it
+// may.
+func (mem *CListMempool) isLaneFull(memTx *MempoolTx) error {
+	if memTx.lane == nil || memTx.lane.MaxTxs == 0 {
+		return nil
+	}
+	mem.lanesMtx.Lock()
+	defer mem.lanesMtx.Unlock()
+	if memTx.lane.numTxs >= memTx.lane.MaxTxs {
+		return ErrLaneIsFull{memTx.lane.Name, memTx.lane.numTxs, memTx.lane.MaxTxs}
+	}
+	return nil
+}
+
+// countLaneTx adds delta to the number of txs in the lane of memTx.
+func (mem *CListMempool) countLaneTx(memTx *MempoolTx, delta int) {
+	if memTx.lane == nil {
+		return
+	}
+	mem.lanesMtx.Lock()
+	memTx.lane.numTxs += delta
+	mem.lanesMtx.Unlock()
+}
+
+// leadsBundles returns true if memTx is reaped ahead of the sidecar bundles.
+func (mem *CListMempool) leadsBundles(memTx *MempoolTx) bool {
+	return memTx.protected || (memTx.lane != nil && memTx.lane.Priority > mem.mevPriority)
+}
+
+// isLaneChannel returns true if chID is the channel of one of the lanes.
+func (mem *CListMempool) isLaneChannel(chID byte) bool {
+	for _, l := range mem.lanes {
+		if l.channel == chID {
+			return true
+		}
+	}
+	return false
+}
+
+// laneChannels returns the channels of the custom lanes.
+func (mem *CListMempool) laneChannels() []byte {
+	channels := make([]byte, 0)
+	for _, l := range mem.lanes {
+		if l.channel != MempoolChannel {
+			channels = append(channels, l.channel)
+		}
+	}
+	return channels
+}
+
+// Lane returns the name of the lane the tx is in.
+func (memTx *MempoolTx) Lane() string {
+	if memTx.lane == nil {
+		return cfg.DefaultLane
+	}
+	return memTx.lane.Name
+}
+
+func (memTx *MempoolTx) lanePriority() int {
+	if memTx.lane == nil {
+		return 0
+	}
+	return memTx.lane.Priority
+}
+
+// peerHasChannel returns true if peer advertised chID in its node info.
+func peerHasChannel(peer p2p.Peer, chID byte) bool {
+	nodeInfo, ok := peer.NodeInfo().(p2p.DefaultNodeInfo)
+	return ok && bytes.IndexByte(nodeInfo.Channels, chID) >= 0
+}
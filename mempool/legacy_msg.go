@@ -0,0 +1,70 @@
+package mempool
+
+import (
+	"errors"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// Sidecar messages predating the protocol version, see SidecarProtocol, are
+// MEVTxs messages in their original wire format: the txs, with fields 2 to 5,
+// the desired height and the bundle's id, order and size, and, from later
+// releases, field 6, the bid. Skip Protocol's format is the original one.
+// They're decoded apart from current messages, by decodeLegacyTxsMsg, so the
+// current format may change without breaking them, and upgraded to the
+// current representation: taken for any chain, at a bid of 0 if they carry
+// none. Nodes of adjacent releases thus keep exchanging bundles across a
+// network upgrade.
+//
+// Likewise, messages of types this node doesn't know, from peers speaking a
+// newer protocol version, are ignored rather than taken for malformed, so
+// those peers aren't disconnected, see errUnknownMEVMessage.
+
+// errUnknownMEVMessage is returned when decoding a message of a type this
+// node doesn't know, from a peer speaking a newer protocol version.
+var errUnknownMEVMessage = errors.New("unknown MEVMessage type from a newer protocol version")
+
+// legacyTxsFields is the number of the last field of the legacy MEVTxs wire
+// format, the bid.
+const legacyTxsFields = 6
+
+// decodeLegacyTxsMsg returns the txs encoded in bz in the legacy MEVTxs wire
+// format, upgraded to a MEVTxsMessage. The txs alias a copy of bz, see
+// gossip_msg.go. Fields past the legacy format's are ignored.
+func decodeLegacyTxsMsg(bz []byte) (MEVTxsMessage, error) {
+	return parseLegacyTxsMsg(append(make([]byte, 0, len(bz)), bz...))
+}
+
+// parseLegacyTxsMsg is decodeLegacyTxsMsg, with the txs aliasing bz itself.
+func parseLegacyTxsMsg(bz []byte) (MEVTxsMessage, error) {
+	var (
+		msg     MEVTxsMessage
+		varints = [...]*int64{&msg.DesiredHeight, &msg.BundleId, &msg.BundleOrder, &msg.BundleSize, &msg.Bid}
+	)
+	err := rangeFields(bz, func(num int32, wireType int, v uint64, b []byte) (err error) {
+		switch {
+		case num == 1:
+			if wireType != proto.WireBytes {
+				return errWireType
+			}
+			if msg.Txs, err = decodeTxs(b); msg.Txs == nil {
+				msg.Txs = []types.Tx{}
+			}
+		case num >= 2 && num <= legacyTxsFields:
+			if wireType != proto.WireVarint {
+				return errWireType
+			}
+			*varints[num-2] = int64(v)
+		}
+		return err
+	})
+	if err != nil {
+		return MEVTxsMessage{}, err
+	}
+	if len(msg.Txs) == 0 {
+		return MEVTxsMessage{}, errors.New("empty TxsMessage")
+	}
+	return msg, nil
+}
@@ -3,6 +3,9 @@ package mempool
 import (
 	"fmt"
 	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/p2p"
@@ -22,6 +25,11 @@ type PriorityTxSidecar interface {
 	// transactions (~ all available transactions).
 	ReapMaxTxs() []*MempoolTx
 
+	// ReapAuction reaps the txs of every bundle selected by the auction for
+	// the current auction height, in order, along with a record of every
+	// bundle that was considered.
+	ReapAuction() ([]*MempoolTx, []types.AuctionBundle)
+
 	// Lock locks the mempool. The consensus must be able to hold lock to safely update.
 	Lock()
 
@@ -40,6 +48,12 @@ type PriorityTxSidecar interface {
 		deliverTxResponses []*abci.ResponseDeliverTx,
 	) error
 
+	// Reset flushes the sidecar and moves it to blockHeight, as if that block
+	// had just been committed. Used when the node catches up by other means
+	// than committing blocks one by one, so no stale bundle gets reaped.
+	// NOTE: Lock/Unlock must be managed by caller
+	Reset(blockHeight int64)
+
 	// TxsAvailable returns a channel which fires once for every height,
 	// and only when transactions are available in the mempool.
 	// NOTE: the returned channel may be nil if EnableTxsAvailable was not called.
@@ -47,6 +61,10 @@ type PriorityTxSidecar interface {
 
 	HeightForFiringAuction() int64
 
+	// GetTxByKey returns the sidecar tx with the given TxKey, if any, and the
+	// bundle it belongs to as TxInfo.
+	GetTxByKey(txKey [TxKeySize]byte) (types.Tx, TxInfo, bool)
+
 	// EnableTxsAvailable initializes the TxsAvailable channel, ensuring it will
 	// trigger once every height when transactions are available.
 
@@ -118,8 +136,9 @@ type Mempool interface {
 	// TxsBytes returns the total size of all txs in the mempool.
 	TxsBytes() int64
 
-	// InitWAL creates a directory for the WAL file and opens a file itself. If
-	// there is an error, it will be of type *PathError.
+	// InitWAL creates a directory for the WAL file and opens a file itself,
+	// checking again the txs accepted before a restart. If there is an error,
+	// it will be of type *PathError.
 	InitWAL() error
 
 	// CloseWAL closes and discards the underlying WAL file.
@@ -156,13 +175,33 @@ type TxInfo struct {
 	BundleOrder int64
 	// total size of bundle
 	BundleSize int64
+	// value the bundle pays the proposer, as reported by the relay
+	Bid int64
+	// where the tx was received from
+	Origin TxOrigin
+	// time the tx was received from a peer, zero otherwise
+	ReceivedAt time.Time
+	// envelope the tx was received in, if any
+	Envelope *BundleEnvelope
 }
 
 // MempoolTx is a transaction that successfully ran
 type MempoolTx struct {
-	height    int64    // height of state that this tx had been validated against
-	gasWanted int64    // amount of gas this tx states it will require
-	tx        types.Tx //
+	height    int64     // height of state that this tx had been validated against
+	gasWanted int64     // amount of gas this tx states it will require
+	tx        types.Tx  //
+	fee       int64     // fee this tx declared in its CheckTx events, see WithFeeAttribute
+	protected bool      // always reaped ahead of sidecar bundles, see WithProtectedTxs
+	timestamp time.Time // time this tx was added to the mempool, for its TTL
+	sender    string    // sender declared in its CheckTx events, see WithSenderOrdering
+	sequence  uint64    // sequence of the tx among the sender's txs
+	priority  int64     // priority declared in its CheckTx events, see WithPriorityOrdering
+	lane      *lane     // lane declared in its CheckTx events, see WithLanes
+	origin    TxOrigin  // where the tx was received from
+	msg       []byte    // message gossiping the tx, tx aliases its tail
+
+	// bundle of the sidecar txs reaped for the auction, see ReapAuction
+	bundleId, bundleOrder int64
 
 	// ids of peers who've sent us this tx (as a map for quick lookups).
 	// senders: PeerID -> bool
@@ -175,9 +214,16 @@ type SidecarTx struct {
 	bundleId      int64 // ordered id of bundle
 	bundleOrder   int64 // order of tx within bundle
 	bundleSize    int64 // total size of bundle
+	bid           int64 // value the bundle pays the proposer
 
 	gasWanted int64    // amount of gas this tx states it will require
 	tx        types.Tx // tx bytes
+	origin    TxOrigin // where the tx was received from
+	msg       []byte   // message gossiping the tx, tx aliases its tail
+
+	// envelope the tx was received in, if any, passed on in place of the
+	// txs of its bundle to peers taking envelopes
+	envelope *BundleEnvelope
 
 	// ids of peers who've sent us this tx (as a map for quick lookups).
 	// senders: PeerID -> bool
@@ -190,9 +236,38 @@ type Bundle struct {
 	bundleId      int64 // ordered id of bundle
 	currSize      int64 // total size of bundle
 	enforcedSize  int64 // total size of bundle
+	bid           int64 // value the bundle pays the proposer
 
 	gasWanted     int64     // amount of gas this tx states it will require
 	orderedTxsMap *sync.Map // map from bundleOrder to *mempoolTx
+
+	late   bool  // first seen after the auction cutoff, so gossiped but never reaped
+	vetoed int32 // set atomically once the app refused the bundle through a BundleChecker
+
+	firstSeenHeight int64     // sidecar height when the first tx arrived, for the bundle's TTL
+	firstSeen       time.Time // time the first tx arrived, for the bundle's TTL
+
+	// memory taken by the bundle and its txs, and whether it was evicted to
+	// make room, both guarded by CListPriorityTxSidecar.memMtx
+	memBytes int64
+	evicted  bool
+	// time the first of its txs was received, for the propagation latency
+	// metrics, guarded by CListPriorityTxSidecar.memMtx too
+	firstReceived time.Time
+	// time its last tx was received, zero until then, guarded by
+	// CListPriorityTxSidecar.memMtx too
+	completed time.Time
+	// set atomically once the bundle was reaped for its auction
+	auctioned int32
+	// merkle root of its txs, as in types.Txs.Hash, set once complete and
+	// guarded by CListPriorityTxSidecar.memMtx
+	hash []byte
+	// traces the bundle from its first tx being received to its removal, set
+	// once created and guarded by CListPriorityTxSidecar.memMtx too
+	span trace.Span
+	// extensions of the envelope the bundle was received in, if received in
+	// one before any of its txs were received alone
+	extensions []types.BundleExtension
 }
 
 //--------------------------------------------------------------------------------
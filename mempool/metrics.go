@@ -24,13 +24,116 @@ type Metrics struct {
 	FailedTxs metrics.Counter
 	// Number of times transactions are rechecked in the mempool.
 	RecheckTimes metrics.Counter
+	// Number of transactions removed for outliving their TTL.
+	ExpiredTxs metrics.Counter
+	// Number of transactions replaced by a transaction paying a higher fee.
+	ReplacedTxs metrics.Counter
+	// Time taken to reap the txs of a block, in seconds.
+	ReapDuration metrics.Histogram
+	// Time taken to reap the sidecar's auction, in seconds.
+	SidecarReapDuration metrics.Histogram
+	// Number of reaps cut short for running out of time.
+	TruncatedReaps metrics.Counter
+	// Number of txs found both in the mempool and in a sidecar bundle.
+	SidecarCollidingTxs metrics.Counter
+	// Number of mempool txs left out of proposals for being part of a bundle
+	// reaped already.
+	ReapDedupedTxs metrics.Counter
+	// Number of CheckTx calls awaiting the app's response.
+	PendingCheckTxs metrics.Gauge
+	// Number of txs received from peers dropped to shed load, by reason.
+	ShedTxs metrics.Counter
+	// Memory taken by the sidecar's txs and bundles, in bytes.
+	SidecarMemoryBytes metrics.Gauge
+	// Number of txs added to the mempool or the sidecar, by origin.
+	AddedTxs metrics.Counter
+	// Number of txs removed from the mempool or the sidecar for being
+	// committed, by origin.
+	IncludedTxs metrics.Counter
+
+	// Number of txs in the sidecar.
+	SidecarSize metrics.Gauge
+	// Total size of the sidecar's txs, in bytes.
+	SidecarSizeBytes metrics.Gauge
+	// Number of bundles in the sidecar.
+	SidecarBundles metrics.Gauge
+	// Number of bundles in the sidecar for each height, by how many heights
+	// past the auction's it is.
+	SidecarHeightBundles metrics.Gauge
+	// Number of txs added to the sidecar.
+	SidecarAddedTxs metrics.Counter
+	// Number of txs the sidecar refused, by reason.
+	SidecarRejectedTxs metrics.Counter
+	// Height of the sidecar's next auction.
+	SidecarAuctionHeight metrics.Gauge
+
+	// Number of bundles received by the sidecar.
+	SidecarBundlesReceived metrics.Counter
+	// Number of the sidecar's bundles committed.
+	SidecarBundlesIncluded metrics.Counter
+	// Number of the sidecar's bundles dropped for outliving their TTL, or
+	// their height passing without them being committed.
+	SidecarBundlesExpired metrics.Counter
+	// Number of the sidecar's bundles removed before all their txs arrived,
+	// by why they were removed.
+	SidecarBundlesIncomplete metrics.Counter
+	// Number of bundle txs that arrived before a tx preceding them in their
+	// bundle.
+	SidecarTxsOutOfOrder metrics.Counter
+	// Number of bundle txs slotted before txs of their bundle received
+	// earlier.
+	SidecarTxsReordered metrics.Counter
+
+	// Time from the first of a bundle's txs being received to the bundle
+	// being complete, in seconds.
+	BundleCompletionLatency metrics.Histogram
+	// Time from the first of a bundle's txs being received to the bundle
+	// being reaped for its auction, in seconds.
+	BundleAuctionLatency metrics.Histogram
+	// Time from a bundle being complete to it being reaped for its auction,
+	// in seconds.
+	BundleCompleteToAuctionLatency metrics.Histogram
+	// Histogram of the number of txs of the bundles completed in the sidecar.
+	BundleSizeTxs metrics.Histogram
+	// Histogram of the total size of the txs of the bundles completed in the
+	// sidecar, in bytes.
+	BundleSizeBytes metrics.Histogram
+
+	// Whether the configured relay is connected (1) or not (0).
+	RelayConnected metrics.Gauge
+	// Seconds since the last sidecar message from the configured relay.
+	RelayLastMessageAge metrics.Gauge
+	// Number of times the configured relay connected again after
+	// disconnecting.
+	RelayReconnects metrics.Counter
+
+	// Number of sidecar messages received from each peer.
+	SidecarPeerMessages metrics.Counter
+	// Number of bytes of the sidecar messages received from each peer.
+	SidecarPeerBytes metrics.Counter
+	// Number of invalid sidecar messages received from each peer, by why
+	// they're invalid.
+	SidecarPeerInvalidMessages metrics.Counter
+	// Number of sidecar messages that couldn't be sent to each peer, by why:
+	// its send queue stayed full, or it was too far behind to drain it.
+	SidecarPeerDroppedMessages metrics.Counter
+
+	// Rate of the bytes sent on the sidecar channel, in bytes per second.
+	SidecarSendRate metrics.Gauge
+	// Rate of the bytes received on the sidecar channel, in bytes per second.
+	SidecarReceiveRate metrics.Gauge
+
+	// Whether the MEV pipeline is stalled (1) or not (0), by why it is.
+	SidecarStalled metrics.Gauge
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
 // Optionally, labels can be provided along with their values ("foo",
 // "fooValue").
 func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
-	labels := []string{}
+	// exactly sized, so the labels appended to it for each metric don't
+	// share their backing array
+	labels := make([]string, 0, len(labelsAndValues)/2)
 	for i := 0; i < len(labelsAndValues); i += 2 {
 		labels = append(labels, labelsAndValues[i])
 	}
@@ -60,6 +163,253 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "recheck_times",
 			Help:      "Number of times transactions are rechecked in the mempool.",
 		}, labels).With(labelsAndValues...),
+		ExpiredTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "expired_txs",
+			Help:      "Number of transactions removed from the mempool for outliving their TTL.",
+		}, labels).With(labelsAndValues...),
+		ReplacedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "replaced_txs",
+			Help:      "Number of transactions replaced by a transaction paying a higher fee.",
+		}, labels).With(labelsAndValues...),
+		ReapDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "reap_duration_seconds",
+			Help:      "Time taken to reap the txs of a block, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0001, 2, 16),
+		}, labels).With(labelsAndValues...),
+		SidecarReapDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_reap_duration_seconds",
+			Help:      "Time taken to reap the sidecar's auction, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0001, 2, 16),
+		}, labels).With(labelsAndValues...),
+		TruncatedReaps: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "truncated_reaps",
+			Help:      "Number of reaps cut short for running out of time.",
+		}, labels).With(labelsAndValues...),
+		SidecarCollidingTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_colliding_txs",
+			Help:      "Number of txs found both in the mempool and in a sidecar bundle.",
+		}, labels).With(labelsAndValues...),
+		ReapDedupedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "reap_deduped_txs",
+			Help:      "Number of mempool txs left out of proposals for being part of a bundle reaped already.",
+		}, labels).With(labelsAndValues...),
+		PendingCheckTxs: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "pending_check_txs",
+			Help:      "Number of CheckTx calls awaiting the app's response.",
+		}, labels).With(labelsAndValues...),
+		ShedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "shed_txs",
+			Help:      "Number of txs received from peers dropped to shed load, by reason.",
+		}, append(labels, "reason")).With(labelsAndValues...),
+		SidecarMemoryBytes: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_memory_bytes",
+			Help:      "Memory taken by the sidecar's txs and bundles, in bytes.",
+		}, labels).With(labelsAndValues...),
+		AddedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "added_txs",
+			Help:      "Number of txs added to the mempool or the sidecar, by origin.",
+		}, append(labels, "origin")).With(labelsAndValues...),
+		IncludedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "included_txs",
+			Help:      "Number of txs removed from the mempool or the sidecar for being committed, by origin.",
+		}, append(labels, "origin")).With(labelsAndValues...),
+		SidecarSize: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_size",
+			Help:      "Number of txs in the sidecar.",
+		}, labels).With(labelsAndValues...),
+		SidecarSizeBytes: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_size_bytes",
+			Help:      "Total size of the sidecar's txs, in bytes.",
+		}, labels).With(labelsAndValues...),
+		SidecarBundles: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_bundles",
+			Help:      "Number of bundles in the sidecar.",
+		}, labels).With(labelsAndValues...),
+		SidecarHeightBundles: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_height_bundles",
+			Help:      "Number of bundles in the sidecar for each height, by how many heights past the auction's it is.",
+		}, append(labels, "heights_ahead")).With(labelsAndValues...),
+		SidecarAddedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_added_txs",
+			Help:      "Number of txs added to the sidecar.",
+		}, labels).With(labelsAndValues...),
+		SidecarRejectedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_rejected_txs",
+			Help:      "Number of txs the sidecar refused, by reason.",
+		}, append(labels, "reason")).With(labelsAndValues...),
+		SidecarAuctionHeight: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_auction_height",
+			Help:      "Height of the sidecar's next auction.",
+		}, labels).With(labelsAndValues...),
+		SidecarBundlesReceived: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_bundles_received",
+			Help:      "Number of bundles received by the sidecar.",
+		}, labels).With(labelsAndValues...),
+		SidecarBundlesIncluded: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_bundles_included",
+			Help:      "Number of the sidecar's bundles committed.",
+		}, labels).With(labelsAndValues...),
+		SidecarBundlesExpired: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_bundles_expired",
+			Help:      "Number of the sidecar's bundles dropped for outliving their TTL, or their height passing without them being committed.",
+		}, labels).With(labelsAndValues...),
+		SidecarBundlesIncomplete: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_bundles_incomplete",
+			Help:      "Number of the sidecar's bundles removed before all their txs arrived, by why they were removed.",
+		}, append(labels, "reason")).With(labelsAndValues...),
+		SidecarTxsOutOfOrder: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_txs_out_of_order",
+			Help:      "Number of bundle txs that arrived before a tx preceding them in their bundle.",
+		}, labels).With(labelsAndValues...),
+		SidecarTxsReordered: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_txs_reordered",
+			Help:      "Number of bundle txs slotted before txs of their bundle received earlier.",
+		}, labels).With(labelsAndValues...),
+		BundleCompletionLatency: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bundle_completion_latency_seconds",
+			Help:      "Time from the first of a bundle's txs being received to the bundle being complete, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.001, 2, 16),
+		}, labels).With(labelsAndValues...),
+		BundleAuctionLatency: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bundle_auction_latency_seconds",
+			Help:      "Time from the first of a bundle's txs being received to the bundle being reaped for its auction, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.001, 2, 16),
+		}, labels).With(labelsAndValues...),
+		BundleCompleteToAuctionLatency: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bundle_complete_to_auction_latency_seconds",
+			Help:      "Time from a bundle being complete in the sidecar to it being reaped for its auction, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.001, 2, 16),
+		}, labels).With(labelsAndValues...),
+		BundleSizeTxs: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bundle_size_txs",
+			Help:      "Number of txs of the bundles completed in the sidecar.",
+			Buckets:   stdprometheus.ExponentialBuckets(1, 2, 10),
+		}, labels).With(labelsAndValues...),
+		BundleSizeBytes: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bundle_size_bytes",
+			Help:      "Total size of the txs of the bundles completed in the sidecar, in bytes.",
+			Buckets:   stdprometheus.ExponentialBuckets(1, 3, 17),
+		}, labels).With(labelsAndValues...),
+		RelayConnected: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "relay_connected",
+			Help:      "Whether the configured relay is connected (1) or not (0).",
+		}, append(labels, "relay_id")).With(labelsAndValues...),
+		RelayLastMessageAge: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "relay_last_message_age_seconds",
+			Help:      "Seconds since the last sidecar message from the configured relay.",
+		}, append(labels, "relay_id")).With(labelsAndValues...),
+		RelayReconnects: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "relay_reconnects",
+			Help:      "Number of times the configured relay connected again after disconnecting.",
+		}, append(labels, "relay_id")).With(labelsAndValues...),
+		SidecarPeerMessages: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_peer_messages",
+			Help:      "Number of sidecar messages received from each peer.",
+		}, append(labels, "peer_id")).With(labelsAndValues...),
+		SidecarPeerBytes: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_peer_bytes",
+			Help:      "Number of bytes of the sidecar messages received from each peer.",
+		}, append(labels, "peer_id")).With(labelsAndValues...),
+		SidecarPeerInvalidMessages: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_peer_invalid_messages",
+			Help:      "Number of invalid sidecar messages received from each peer.",
+		}, append(labels, "peer_id", "reason")).With(labelsAndValues...),
+		SidecarPeerDroppedMessages: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_peer_dropped_messages",
+			Help:      "Number of sidecar messages that couldn't be sent to each peer, by why.",
+		}, append(labels, "peer_id", "reason")).With(labelsAndValues...),
+		SidecarSendRate: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_send_rate",
+			Help:      "Rate of the bytes sent on the sidecar channel, in bytes per second.",
+		}, labels).With(labelsAndValues...),
+		SidecarReceiveRate: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_receive_rate",
+			Help:      "Rate of the bytes received on the sidecar channel, in bytes per second.",
+		}, labels).With(labelsAndValues...),
+		SidecarStalled: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_stalled",
+			Help:      "Whether the MEV pipeline is stalled (1) or not (0), by why it is.",
+		}, append(labels, "reason")).With(labelsAndValues...),
 	}
 }
 
@@ -70,5 +420,56 @@ func NopMetrics() *Metrics {
 		TxSizeBytes:  discard.NewHistogram(),
 		FailedTxs:    discard.NewCounter(),
 		RecheckTimes: discard.NewCounter(),
+		ExpiredTxs:   discard.NewCounter(),
+		ReplacedTxs:  discard.NewCounter(),
+
+		ReapDuration:        discard.NewHistogram(),
+		SidecarReapDuration: discard.NewHistogram(),
+		TruncatedReaps:      discard.NewCounter(),
+		SidecarCollidingTxs: discard.NewCounter(),
+		ReapDedupedTxs:      discard.NewCounter(),
+
+		PendingCheckTxs: discard.NewGauge(),
+		ShedTxs:         discard.NewCounter(),
+
+		SidecarMemoryBytes: discard.NewGauge(),
+		AddedTxs:           discard.NewCounter(),
+		IncludedTxs:        discard.NewCounter(),
+
+		SidecarSize:          discard.NewGauge(),
+		SidecarSizeBytes:     discard.NewGauge(),
+		SidecarBundles:       discard.NewGauge(),
+		SidecarHeightBundles: discard.NewGauge(),
+		SidecarAddedTxs:      discard.NewCounter(),
+		SidecarRejectedTxs:   discard.NewCounter(),
+		SidecarAuctionHeight: discard.NewGauge(),
+
+		SidecarBundlesReceived: discard.NewCounter(),
+		SidecarBundlesIncluded: discard.NewCounter(),
+		SidecarBundlesExpired:  discard.NewCounter(),
+
+		SidecarBundlesIncomplete: discard.NewCounter(),
+		SidecarTxsOutOfOrder:     discard.NewCounter(),
+		SidecarTxsReordered:      discard.NewCounter(),
+
+		BundleCompletionLatency:        discard.NewHistogram(),
+		BundleAuctionLatency:           discard.NewHistogram(),
+		BundleCompleteToAuctionLatency: discard.NewHistogram(),
+		BundleSizeTxs:                  discard.NewHistogram(),
+		BundleSizeBytes:                discard.NewHistogram(),
+
+		RelayConnected:      discard.NewGauge(),
+		RelayLastMessageAge: discard.NewGauge(),
+		RelayReconnects:     discard.NewCounter(),
+
+		SidecarPeerMessages:        discard.NewCounter(),
+		SidecarPeerBytes:           discard.NewCounter(),
+		SidecarPeerInvalidMessages: discard.NewCounter(),
+		SidecarPeerDroppedMessages: discard.NewCounter(),
+
+		SidecarSendRate:    discard.NewGauge(),
+		SidecarReceiveRate: discard.NewGauge(),
+
+		SidecarStalled: discard.NewGauge(),
 	}
 }
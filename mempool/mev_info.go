@@ -0,0 +1,28 @@
+package mempool
+
+import "sync/atomic"
+
+// MEVInfo describes how a node takes part in MEV, see Reactor.MEVInfo.
+type MEVInfo struct {
+	Mode           string // role in the MEV pipeline, see SidecarConfig.Mode
+	MEVDisabled    bool
+	DryRun         bool
+	RelayConnected bool  // whether the configured relay is connected
+	AuctionHeight  int64 // height of the block the next auction is for
+}
+
+// MEVInfo returns how the node takes part in MEV, as reported by /status.
+//
+// Safe for concurrent use by multiple goroutines.
+func (memR *Reactor) MEVInfo() MEVInfo {
+	info := MEVInfo{
+		Mode:          memR.sidecar.config.Mode,
+		MEVDisabled:   memR.sidecar.MEVDisabled(),
+		DryRun:        memR.sidecar.config.DryRun,
+		AuctionHeight: atomic.LoadInt64(&memR.sidecar.heightForFiringAuction),
+	}
+	if memR.Switch != nil {
+		info.RelayConnected = memR.relayerPeer() != nil
+	}
+	return info
+}
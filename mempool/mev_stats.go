@@ -0,0 +1,147 @@
+package mempool
+
+import (
+	"time"
+
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+)
+
+// DefaultMEVStatsHeights is how many heights MEVStats keeps by default.
+const DefaultMEVStatsHeights = 1000
+
+// MEVStats rolls up what became of the bundles the sidecar received, by the
+// height they targeted, for the last heights, for lightweight dashboards (see
+// the mev_stats RPC endpoint). Bundles are accounted for once they leave the
+// sidecar, so those still held, eg. for SidecarConfig.BundleRetainHeights,
+// aren't yet.
+//
+// Safe for concurrent use by multiple goroutines. Recording to a nil MEVStats
+// is a no-op.
+type MEVStats struct {
+	mtx tmsync.Mutex
+
+	retainHeights int64
+	heights       map[int64]*heightMEVStats
+}
+
+// heightMEVStats sums up the bundles that targeted a height.
+type heightMEVStats struct {
+	received int64
+	complete int64
+	included int64
+	// bids of the included bundles
+	bids int64
+	// time from the first to the last tx being received, over the complete
+	// bundles
+	latency time.Duration
+}
+
+// MEVStatsSummary sums up the bundles that targeted heights FromHeight to
+// ToHeight.
+type MEVStatsSummary struct {
+	FromHeight      int64
+	ToHeight        int64
+	BundlesReceived int64
+	BundlesIncluded int64
+	// sum of the bids of the included bundles
+	TotalBids int64
+	// fraction of the bundles received that were included, 0 if none was
+	// received
+	WinRate float64
+	// average time from the first to the last tx of a bundle being received,
+	// over the complete bundles
+	AvgLatency time.Duration
+}
+
+// NewMEVStats returns an MEVStats keeping the last retainHeights heights.
+func NewMEVStats(retainHeights int64) *MEVStats {
+	return &MEVStats{
+		retainHeights: retainHeights,
+		heights:       make(map[int64]*heightMEVStats),
+	}
+}
+
+// RetainHeights returns how many heights are kept.
+func (s *MEVStats) RetainHeights() int64 {
+	return s.retainHeights
+}
+
+// Summary sums up the bundles that targeted the last blocks heights up to
+// lastHeight, or those kept if fewer.
+func (s *MEVStats) Summary(lastHeight, blocks int64) MEVStatsSummary {
+	if blocks > s.retainHeights {
+		blocks = s.retainHeights
+	}
+	summary := MEVStatsSummary{FromHeight: lastHeight - blocks + 1, ToHeight: lastHeight}
+	if summary.FromHeight < 1 {
+		summary.FromHeight = 1
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var complete int64
+	var latency time.Duration
+	for height, stats := range s.heights {
+		if height < summary.FromHeight || height > summary.ToHeight {
+			continue
+		}
+		summary.BundlesReceived += stats.received
+		summary.BundlesIncluded += stats.included
+		summary.TotalBids += stats.bids
+		complete += stats.complete
+		latency += stats.latency
+	}
+	if summary.BundlesReceived > 0 {
+		summary.WinRate = float64(summary.BundlesIncluded) / float64(summary.BundlesReceived)
+	}
+	if complete > 0 {
+		summary.AvgLatency = latency / time.Duration(complete)
+	}
+	return summary
+}
+
+// bundleRemoved accounts for bundle, removed from the sidecar for reason.
+//
+// CListPriorityTxSidecar.memMtx must be held by the caller, or the sidecar
+// locked.
+func (s *MEVStats) bundleRemoved(bundle *Bundle, reason RemovalReason) {
+	if s == nil {
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	stats, ok := s.heights[bundle.desiredHeight]
+	if !ok {
+		stats = &heightMEVStats{}
+		s.heights[bundle.desiredHeight] = stats
+	}
+	stats.received++
+	if !bundle.completed.IsZero() {
+		stats.complete++
+		stats.latency += bundle.completed.Sub(bundle.firstReceived)
+	}
+	if reason == RemovalCommitted {
+		stats.included++
+		stats.bids += bundle.bid
+	}
+}
+
+// prune drops the heights no longer kept once height is committed.
+func (s *MEVStats) prune(height int64) {
+	if s == nil {
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for h := range s.heights {
+		if h <= height-s.retainHeights {
+			delete(s.heights, h)
+		}
+	}
+}
+
+// WithMEVStats sets the MEVStats the bundles leaving the sidecar are
+// accounted for in.
+func WithMEVStats(stats *MEVStats) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.stats = stats }
+}
@@ -65,12 +65,19 @@ var _ mempl.PriorityTxSidecar = PriorityTxSidecar{}
 
 func (PriorityTxSidecar) AddTx(_ types.Tx, _ mempl.TxInfo) error { return nil }
 func (PriorityTxSidecar) ReapMaxTxs() []*mempl.MempoolTx         { return []*mempl.MempoolTx{} }
+func (PriorityTxSidecar) ReapAuction() ([]*mempl.MempoolTx, []types.AuctionBundle) {
+	return []*mempl.MempoolTx{}, []types.AuctionBundle{}
+}
 
 func (PriorityTxSidecar) Lock()   {}
 func (PriorityTxSidecar) Unlock() {}
 
 func (PriorityTxSidecar) HeightForFiringAuction() int64 { return 0 }
 
+func (PriorityTxSidecar) GetTxByKey(_ [mempl.TxKeySize]byte) (types.Tx, mempl.TxInfo, bool) {
+	return nil, mempl.TxInfo{}, false
+}
+
 func (PriorityTxSidecar) Flush() {}
 func (PriorityTxSidecar) Update(
 	blockHeight int64,
@@ -79,6 +86,7 @@ func (PriorityTxSidecar) Update(
 ) error {
 	return nil
 }
+func (PriorityTxSidecar) Reset(_ int64) {}
 
 func (PriorityTxSidecar) TxsAvailable() <-chan struct{} { return make(chan struct{}) }
 func (PriorityTxSidecar) EnableTxsAvailable()           {}
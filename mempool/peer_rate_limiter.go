@@ -0,0 +1,84 @@
+package mempool
+
+import (
+	"time"
+
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/p2p"
+)
+
+// peerRateLimiter limits the txs each peer submits through the mempool
+// channel, so one peer can't saturate the app's mempool connection. Each peer
+// has a bucket of burst txs, refilled at rate txs per second. A peer sending
+// more than its bucket holds is muted for muteDuration, all its txs being
+// dropped meanwhile.
+type peerRateLimiter struct {
+	mtx tmsync.Mutex
+
+	rate         float64
+	burst        float64
+	muteDuration time.Duration
+	peers        map[p2p.ID]*peerBucket
+}
+
+type peerBucket struct {
+	tokens     float64
+	refilledAt time.Time
+	mutedUntil time.Time
+}
+
+func newPeerRateLimiter(rate float64, burst int, muteDuration time.Duration) *peerRateLimiter {
+	return &peerRateLimiter{
+		rate:         rate,
+		burst:        float64(burst),
+		muteDuration: muteDuration,
+		peers:        make(map[p2p.ID]*peerBucket),
+	}
+}
+
+// allow returns true if peer may submit numTxs more txs at now, taking them
+// from its bucket. Otherwise, the peer is muted if it wasn't already.
+func (rl *peerRateLimiter) allow(peer p2p.ID, numTxs int, now time.Time) bool {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	bucket, ok := rl.peers[peer]
+	if !ok {
+		bucket = &peerBucket{tokens: rl.burst, refilledAt: now}
+		rl.peers[peer] = bucket
+	}
+	if now.Before(bucket.mutedUntil) {
+		return false
+	}
+
+	bucket.tokens += now.Sub(bucket.refilledAt).Seconds() * rl.rate
+	if bucket.tokens > rl.burst {
+		bucket.tokens = rl.burst
+	}
+	bucket.refilledAt = now
+
+	if bucket.tokens < float64(numTxs) {
+		bucket.tokens = 0
+		bucket.mutedUntil = now.Add(rl.muteDuration)
+		return false
+	}
+	bucket.tokens -= float64(numTxs)
+	return true
+}
+
+// removePeer forgets about peer, unless it's still muted at now so it can't
+// get around its mute by reconnecting. Peers whose mute ended are forgotten
+// too.
+func (rl *peerRateLimiter) removePeer(peer p2p.ID, now time.Time) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	if bucket, ok := rl.peers[peer]; ok && !now.Before(bucket.mutedUntil) {
+		delete(rl.peers, peer)
+	}
+	for id, bucket := range rl.peers {
+		if !bucket.mutedUntil.IsZero() && !now.Before(bucket.mutedUntil) {
+			delete(rl.peers, id)
+		}
+	}
+}
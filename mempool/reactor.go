@@ -1,23 +1,38 @@
 package mempool
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
+	"sync/atomic"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
+
 	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/crypto"
+	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
+	"github.com/tendermint/tendermint/crypto/tmhash"
 	"github.com/tendermint/tendermint/libs/clist"
 	"github.com/tendermint/tendermint/libs/log"
 	tmsync "github.com/tendermint/tendermint/libs/sync"
 	"github.com/tendermint/tendermint/p2p"
 	protomem "github.com/tendermint/tendermint/proto/tendermint/mempool"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/types"
 )
 
 const (
 	MempoolChannel = byte(0x30)
 
+	// SidecarChannel carries protomem.MEVMessage, the only format the
+	// sidecar has spoken, so there's no legacy channel to keep serving while
+	// nodes upgrade. Fields added to its messages are ignored by older
+	// nodes. New message types aren't: older nodes drop the peer sending
+	// them, so they're only sent to peers expected to understand them, as
+	// told by the protocol version and capabilities every message carries,
+	// see SidecarProtocol.
 	SidecarChannel = byte(0x80)
 
 	peerCatchupSleepIntervalMS = 100 // If peer is behind, sleep this amount
@@ -38,6 +53,37 @@ type Reactor struct {
 	mempool *CListMempool
 	sidecar *CListPriorityTxSidecar
 	ids     *mempoolIDs
+
+	// admits received txs off the receive routines, if configured
+	checkTxPool *checkTxPool
+
+	// limits the txs each peer submits, if configured
+	rateLimiter *peerRateLimiter
+
+	// origins whose txs aren't gossiped
+	privateOrigins map[TxOrigin]bool
+
+	// reports the liveness of the configured relay, if any, and holds its ID
+	// and the API key registered with it, see SetRelay, the key signing the
+	// registration, see SetRelaySigner, the relays allowed, see
+	// SetRelayAllowlist, and the peers speaking Skip Protocol's format, see
+	// SetSkipFormatPeers
+	relayMtx        tmsync.RWMutex
+	relay           *relayMonitor
+	relayAPIKey     string
+	relaySigner     RelaySigner
+	relayAllowlist  map[p2p.ID]struct{}
+	skipFormatPeers map[p2p.ID]struct{}
+	// flags the MEV pipeline as stalled, if configured
+	watchdog *stallWatchdog
+	// measures the traffic on the sidecar channel
+	bandwidth *sidecarBandwidth
+	// samples the lines logged for every sidecar tx
+	sidecarTxLogger log.Logger
+	// pings sent to sidecar peers by the self-test, awaiting an answer
+	pings *sidecarPings
+	// serializes the bundles submitted through the RPC, see SubmitBundle
+	submitMtx tmsync.Mutex
 }
 
 type mempoolIDs struct {
@@ -110,8 +156,31 @@ func NewReactor(config *cfg.MempoolConfig, mempool *CListMempool, sidecar *CList
 		mempool: mempool,
 		sidecar: sidecar,
 		ids:     newMempoolIDs(),
+
+		privateOrigins: privateOrigins(config),
+		bandwidth:      newSidecarBandwidth(mempool.metrics),
+		pings:          newSidecarPings(),
+	}
+	if config.CheckTxWorkers > 0 {
+		memR.checkTxPool = newCheckTxPool(config.CheckTxWorkers, config.CheckTxQueueSize)
+	}
+	if config.PeerCheckTxRate > 0 {
+		memR.rateLimiter = newPeerRateLimiter(config.PeerCheckTxRate, config.PeerCheckTxBurst,
+			config.PeerMuteDuration)
+	}
+	if relayerID := sidecar.config.RelayPeerID(); relayerID != "" {
+		memR.relay = newRelayMonitor(p2p.ID(relayerID), mempool.metrics, time.Now())
+	}
+	memR.relayAPIKey = sidecar.config.RelayAPIKey
+	if allowlist, err := sidecar.config.RelayAllowlist(); err == nil {
+		memR.SetRelayAllowlist(relayAllowlistIDs(allowlist))
+	}
+	memR.SetSkipFormatPeers(skipFormatPeerIDs(sidecar.config.SkipFormatPeerIDList()))
+	if sidecar.config.StallBlocks > 0 || sidecar.config.StallTimeout > 0 {
+		memR.watchdog = newStallWatchdog(sidecar.config, mempool.metrics, time.Now())
 	}
 	memR.BaseReactor = *p2p.NewBaseReactor("Mempool", memR)
+	memR.sidecarTxLogger = memR.Logger
 	return memR
 }
 
@@ -121,10 +190,13 @@ func (memR *Reactor) InitPeer(peer p2p.Peer) p2p.Peer {
 	return peer
 }
 
-// SetLogger sets the Logger on the reactor and the underlying mempool.
+// SetLogger sets the Logger on the reactor and the underlying mempool and
+// sidecar.
 func (memR *Reactor) SetLogger(l log.Logger) {
 	memR.Logger = l
+	memR.sidecarTxLogger = sampledLogger(l, memR.sidecar.config)
 	memR.mempool.SetLogger(l)
+	memR.sidecar.SetLogger(l)
 }
 
 // OnStart implements p2p.BaseReactor.
@@ -132,9 +204,43 @@ func (memR *Reactor) OnStart() error {
 	if !memR.config.Broadcast {
 		memR.Logger.Info("Tx broadcasting is disabled")
 	}
+	if memR.checkTxPool != nil {
+		memR.checkTxPool.start()
+	}
+	go memR.relayMonitorRoutine()
+	if memR.watchdog != nil {
+		go memR.stallWatchdogRoutine()
+	}
+	go memR.sidecarBandwidthRoutine()
+	go memR.sidecarSelfTestRoutine()
+	if memR.sidecar.config.BundlePurgeInterval > 0 {
+		go memR.bundlePurgeRoutine(memR.sidecar.config.BundlePurgeInterval)
+	}
 	return nil
 }
 
+// OnStop implements p2p.BaseReactor.
+func (memR *Reactor) OnStop() {
+	if memR.checkTxPool != nil {
+		memR.checkTxPool.stop()
+	}
+}
+
+// bundlePurgeRoutine purges the sidecar's expired bundles every interval
+// until the reactor stops.
+func (memR *Reactor) bundlePurgeRoutine(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			memR.sidecar.PurgeExpiredBundles(now)
+		case <-memR.Quit():
+			return
+		}
+	}
+}
+
 // GetChannels implements Reactor by returning the list of channels for this
 // reactor.
 func (memR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
@@ -145,7 +251,7 @@ func (memR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
 		},
 	}
 
-	return []*p2p.ChannelDescriptor{
+	channels := []*p2p.ChannelDescriptor{
 		{
 			ID:                  MempoolChannel,
 			Priority:            5,
@@ -157,17 +263,38 @@ func (memR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
 			RecvMessageCapacity: batchMsg.Size(),
 		},
 	}
+	for _, chID := range memR.mempool.laneChannels() {
+		channels = append(channels, &p2p.ChannelDescriptor{
+			ID:                  chID,
+			Priority:            5,
+			RecvMessageCapacity: batchMsg.Size(),
+		})
+	}
+	return channels
 }
 
 // AddPeer implements Reactor.
 // It starts a broadcast routine ensuring all txs are forwarded to the given peer.
 func (memR *Reactor) AddPeer(peer p2p.Peer) {
+	if relay := memR.relayMonitor(); relay != nil {
+		relay.peerAdded(peer)
+	}
+	if relayerID, apiKey := memR.relayRegistration(); peer.ID() == relayerID {
+		memR.registerWithRelay(peer, apiKey)
+	}
+	if memR.watchdog != nil && peer.IsSidecarPeer() {
+		memR.watchdog.peerAdded()
+	}
 	if memR.config.Broadcast {
 		go memR.broadcastMempoolTxRoutine(peer)
-		fmt.Println("[mev-tendermint] Starting mempool tx broadcast routine for ", peer.ID())
+		memR.Logger.Debug("Starting mempool tx broadcast routine", "peer", peer.ID())
 		// go memR.broadcastSidecarTxRoutine(peer)
 		if peer.IsSidecarPeer() {
-			fmt.Println("[mev-tendermint] Starting sidecar tx broadcast routine for ", peer.ID())
+			if peerMEVDisabled(peer) {
+				memR.Logger.Info("Peer opted out of MEV, not gossiping sidecar txs to it", "peer", peer.ID())
+				return
+			}
+			memR.Logger.Debug("Starting sidecar tx broadcast routine", "peer", peer.ID())
 			go memR.broadcastSidecarTxRoutine(peer)
 		}
 	}
@@ -176,6 +303,15 @@ func (memR *Reactor) AddPeer(peer p2p.Peer) {
 // RemovePeer implements Reactor.
 func (memR *Reactor) RemovePeer(peer p2p.Peer, reason interface{}) {
 	memR.ids.Reclaim(peer)
+	if relay := memR.relayMonitor(); relay != nil {
+		relay.peerRemoved(peer)
+	}
+	if memR.watchdog != nil && peer.IsSidecarPeer() {
+		memR.watchdog.peerRemoved()
+	}
+	if memR.rateLimiter != nil {
+		memR.rateLimiter.removePeer(peer.ID(), time.Now())
+	}
 	// broadcast routine checks if peer is gone and returns
 }
 
@@ -183,7 +319,7 @@ func (memR *Reactor) RemovePeer(peer p2p.Peer, reason interface{}) {
 // It adds any received transactions to the mempool.
 func (memR *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
 	isSidecarPeer := src.IsSidecarPeer()
-	if chID == MempoolChannel {
+	if chID == MempoolChannel || memR.mempool.isLaneChannel(chID) {
 		msg, err := memR.decodeMsg(msgBytes)
 		if err != nil {
 			memR.Logger.Error("Error decoding message", "src", src, "chId", chID, "err", err)
@@ -191,51 +327,328 @@ func (memR *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
 			return
 		}
 		memR.Logger.Info("Received Mempool Tx", "src ", src.ID, "chId", chID, "msg", msg)
-		txInfo := TxInfo{SenderID: memR.ids.GetForPeer(src)}
+		if memR.rateLimiter != nil && !memR.rateLimiter.allow(src.ID(), len(msg.Txs), time.Now()) {
+			memR.Logger.Debug("Dropping txs from rate limited peer", "src", src, "txs", len(msg.Txs))
+			return
+		}
+		txInfo := TxInfo{SenderID: memR.ids.GetForPeer(src), Origin: OriginPeer}
 		if src != nil {
 			txInfo.SenderP2PID = src.ID()
 		}
-		for _, tx := range msg.Txs {
-
-			err = memR.mempool.CheckTx(tx, nil, txInfo)
-			if err == ErrTxInCache {
-				memR.Logger.Debug("Tx already exists in cache", "tx", txID(tx))
-			} else if err != nil {
-				memR.Logger.Info("Could not check tx", "tx", txID(tx), "err", err)
+		txs := types.Txs(msg.Txs)
+		job := func() {
+			ctx, cancel := memR.checkTxContext()
+			defer cancel()
+			for i, err := range memR.mempool.CheckTxsContext(ctx, txs, txInfo) {
+				if err == ErrTxInCache {
+					memR.Logger.Debug("Tx already exists in cache", "tx", txID(txs[i]))
+				} else if err != nil {
+					memR.Logger.Info("Could not check tx", "tx", txID(txs[i]), "err", err)
+				}
+			}
+		}
+		if memR.config.ShedGossipTxs && memR.checkTxPool != nil {
+			if !memR.checkTxPool.trySubmit(txInfo.SenderID, job) {
+				memR.Logger.Debug("Dropping txs, CheckTx queue is full", "src", src, "txs", len(txs))
+				memR.mempool.metrics.ShedTxs.With("reason", "queue_full").Add(float64(len(txs)))
 			}
+			return
+		}
+		memR.admit(txInfo.SenderID, job)
+	} else if chID == SidecarChannel {
+		memR.countSidecarMsg(src, msgBytes)
+		// relays take submissions from any peer
+		if !isSidecarPeer && memR.sidecar.config.Mode != cfg.NodeModeRelay {
+			memR.countInvalidSidecarMsg(src, "not_sidecar_peer")
+			return
+		}
+		if !memR.relayAllowed(src.ID()) {
+			memR.countInvalidSidecarMsg(src, "relay_not_allowed")
+			memR.Logger.Debug("Dropping sidecar message from a relay not in relay_pub_keys", "src", src)
+			return
+		}
+		if relay := memR.relayMonitor(); relay != nil {
+			relay.messageReceived(src, time.Now())
+		}
+		if memR.watchdog != nil {
+			memR.watchdog.sidecarMessageReceived()
+		}
+		if memR.sidecar.MEVDisabled() {
+			memR.Logger.Debug("MEV is disabled, dropping sidecar message", "src", src)
+			return
+		}
+		var (
+			mevMsg     interface{}
+			protocol   SidecarProtocol
+			err        error
+			skipFormat = memR.speaksSkipFormat(src.ID())
+		)
+		if skipFormat {
+			mevMsg, err = decodeSkipMsg(msgBytes)
+		} else {
+			mevMsg, protocol, err = memR.decodeBundleMsg(msgBytes)
+		}
+		if errors.Is(err, errUnknownMEVMessage) {
+			memR.recordSidecarProtocol(src, protocol)
+			memR.countInvalidSidecarMsg(src, "unknown_type")
+			memR.Logger.Debug("Ignoring sidecar message of an unknown type", "src", src,
+				"version", protocol.Version)
+			return
 		}
-	} else if chID == SidecarChannel && isSidecarPeer {
-		msg, err := memR.decodeBundleMsg(msgBytes)
 		if err != nil {
+			memR.countInvalidSidecarMsg(src, "undecodable")
 			memR.Logger.Error("Error decoding message", "src", src, "chId", chID, "err", err)
 			memR.Switch.StopPeerForError(src, err)
 			return
 		}
-		fmt.Println("[mev-tendermint] Reactor (receive) RECEIVED TX FROM ", src.ID())
-		// memR.Logger.Debug("Receive Sidecar Tx", "src", src, "chId", chID, "msg", msg)
-		txInfo := TxInfo{SenderID: memR.ids.GetForPeer(src), DesiredHeight: msg.DesiredHeight, BundleId: msg.BundleId, BundleOrder: msg.BundleOrder, BundleSize: msg.BundleSize}
-		if src != nil {
-			txInfo.SenderP2PID = src.ID()
+		if !skipFormat {
+			memR.recordSidecarProtocol(src, protocol)
+		}
+		if pingMsg, ok := mevMsg.(MEVPingMessage); ok {
+			memR.receivePing(src, pingMsg)
+			return
+		}
+		if receiptsMsg, ok := mevMsg.(MEVReceiptsMessage); ok {
+			memR.forwardReceipts(src, receiptsMsg.Receipts)
+			return
+		}
+		if regMsg, ok := mevMsg.(MEVRegistrationMessage); ok {
+			if memR.sidecar.config.Mode != cfg.NodeModeRelay {
+				memR.Logger.Debug("Not a relay, ignoring relay registration", "src", src)
+				return
+			}
+			if err := regMsg.Verify(src.ID()); err != nil {
+				memR.countInvalidSidecarMsg(src, "bad_registration")
+				memR.Logger.Info("Refusing relay registration", "peer", src.ID(), "err", err)
+				return
+			}
+			// the key itself isn't logged, only its hash for the operator to
+			// match against the keys they issued
+			logger := memR.Logger.With("peer", src.ID(),
+				"api_key_hash", fmt.Sprintf("%X", tmhash.Sum([]byte(regMsg.APIKey))[:8]))
+			if regMsg.PubKey != nil {
+				logger = logger.With("relay_key", regMsg.PubKey.Address())
+			}
+			logger.Info("Node registered with this relay", "max_bundle_heights_ahead", regMsg.MaxBundleHeightsAhead)
+			return
+		}
+		if envMsg, ok := mevMsg.(MEVEnvelopeMessage); ok {
+			memR.receiveEnvelope(src, envMsg.Envelope)
+			return
+		}
+		msg := mevMsg.(MEVTxsMessage)
+		if memR.sidecar.forOtherChain(msg.ChainID) {
+			memR.countInvalidSidecarMsg(src, "wrong_chain")
+			memR.sidecarTxLogger.Info("Dropping sidecar txs for another chain", "src", src,
+				"chain_id", msg.ChainID, "bundle_height", msg.DesiredHeight, "bundle_id", msg.BundleId)
+			return
+		}
+		if err := memR.sidecar.checkBundleSize(msg.BundleSize); err != nil {
+			memR.countInvalidSidecarMsg(src, "bundle_size")
+			memR.sidecarTxLogger.Info("Dropping sidecar txs", "src", src, "bundle_height", msg.DesiredHeight,
+				"bundle_id", msg.BundleId, "err", err)
+			return
 		}
+		txInfo := memR.sidecarTxInfo(src)
+		txInfo.DesiredHeight, txInfo.BundleId, txInfo.BundleOrder, txInfo.BundleSize, txInfo.Bid =
+			msg.DesiredHeight, msg.BundleId, msg.BundleOrder, msg.BundleSize, msg.Bid
 		for _, tx := range msg.Txs {
-			fmt.Println(fmt.Sprintf("[mev-tendermint] Reactor (receive): received sidecar tx %.20q! desiredHeight %d, bundleId %d, bundleOrder %d, bundleSize %d", tx, msg.DesiredHeight, msg.BundleId, msg.BundleOrder, msg.BundleSize))
+			memR.sidecarTxLogger.Debug("Received sidecar tx", "src", src, "tx", txID(tx),
+				"bundle_height", msg.DesiredHeight, "bundle_id", msg.BundleId,
+				"bundle_order", msg.BundleOrder, "bundle_size", msg.BundleSize)
 
-			err = memR.sidecar.AddTx(tx, txInfo)
-			if err == ErrTxInCache {
-				memR.Logger.Debug("SidecarTx already exists in cache", "tx", txID(tx))
-			} else if err != nil {
-				memR.Logger.Info("Could not add SidecarTx", "tx", txID(tx), "err", err)
-			}
+			tx := tx
+			memR.admit(txInfo.SenderID, func() {
+				ctx, cancel := memR.checkTxContext()
+				defer cancel()
+				err := memR.sidecar.AddTxContext(ctx, tx, txInfo)
+				if _, ok := err.(ErrTxMalformedForBundle); ok {
+					memR.countInvalidSidecarMsg(src, "malformed")
+				}
+				if err == ErrTxInCache {
+					memR.sidecarTxLogger.Debug("SidecarTx already exists in cache", "tx", txID(tx),
+						"bundle_height", txInfo.DesiredHeight, "bundle_id", txInfo.BundleId, "bundle_order", txInfo.BundleOrder)
+				} else if err != nil {
+					memR.sidecarTxLogger.Info("Could not add SidecarTx", "tx", txID(tx),
+						"bundle_height", txInfo.DesiredHeight, "bundle_id", txInfo.BundleId, "bundle_order", txInfo.BundleOrder,
+						"err", err)
+				}
+			})
 		}
 	}
 	// broadcasting happens from go routines per peer
 }
 
+// sidecarTxInfo returns the TxInfo of the sidecar txs received from src, the
+// bundle's aside.
+func (memR *Reactor) sidecarTxInfo(src p2p.Peer) TxInfo {
+	txInfo := TxInfo{SenderID: memR.ids.GetForPeer(src)}
+	if src != nil {
+		txInfo.SenderP2PID = src.ID()
+	}
+	txInfo.Origin = OriginSidecar
+	txInfo.ReceivedAt = time.Now()
+	if relayerID := memR.relayerID(); relayerID != "" && txInfo.SenderP2PID == relayerID {
+		txInfo.Origin = OriginRelay
+	}
+	return txInfo
+}
+
+// countSidecarMsg counts the sidecar message bz received from src.
+func (memR *Reactor) countSidecarMsg(src p2p.Peer, bz []byte) {
+	peerID := string(src.ID())
+	memR.mempool.metrics.SidecarPeerMessages.With("peer_id", peerID).Add(1)
+	memR.mempool.metrics.SidecarPeerBytes.With("peer_id", peerID).Add(float64(len(bz)))
+	memR.bandwidth.received.Update(len(bz))
+}
+
+// countInvalidSidecarMsg counts a sidecar message received from src that's
+// invalid for reason.
+func (memR *Reactor) countInvalidSidecarMsg(src p2p.Peer, reason string) {
+	memR.mempool.metrics.SidecarPeerInvalidMessages.With("peer_id", string(src.ID()), "reason", reason).Add(1)
+}
+
+// checkTxContext returns the context the app checks a received tx within.
+func (memR *Reactor) checkTxContext() (context.Context, context.CancelFunc) {
+	if memR.config.CheckTxTimeout > 0 {
+		return context.WithTimeout(context.Background(), memR.config.CheckTxTimeout)
+	}
+	return context.Background(), func() {}
+}
+
+// admit runs job, admitting a tx received from sender, on the CheckTx worker
+// pool if there's one, or else right away.
+func (memR *Reactor) admit(sender uint16, job func()) {
+	if memR.checkTxPool == nil {
+		job()
+		return
+	}
+	memR.checkTxPool.submit(sender, job)
+}
+
+// BroadcastReceipts sends the receipts for the bundles of a block we proposed
+// towards the relay: straight to it if we're connected, or else to our sidecar
+// peers (ie. sentries), which forward them.
+func (memR *Reactor) BroadcastReceipts(receipts []types.BundleReceipt) {
+	if relayer := memR.relayerPeer(); relayer != nil {
+		memR.sendReceipts(relayer, receipts)
+		return
+	}
+	for _, peer := range memR.Switch.Peers().List() {
+		if peer.IsSidecarPeer() && !peerMEVDisabled(peer) {
+			memR.sendReceipts(peer, receipts)
+		}
+	}
+}
+
+// forwardReceipts passes receipts received from src on to the relay. Receipts
+// only travel towards the relay, so they're never sent to other sidecar peers.
+func (memR *Reactor) forwardReceipts(src p2p.Peer, receipts []types.BundleReceipt) {
+	relayer := memR.relayerPeer()
+	if relayer == nil || relayer.ID() == src.ID() {
+		memR.Logger.Debug("Not connected to the relay, dropping bundle receipts", "src", src)
+		return
+	}
+	if receipts = memR.validReceipts(src, receipts); len(receipts) == 0 {
+		return
+	}
+	memR.sendReceipts(relayer, receipts)
+}
+
+// validReceipts returns those of the receipts received from src that are
+// signed for this chain and for a height no later than the auction's, so
+// peers can't use us to push forged receipts at the relay. The others are
+// dropped and counted against src.
+func (memR *Reactor) validReceipts(src p2p.Peer, receipts []types.BundleReceipt) []types.BundleReceipt {
+	auctionHeight := atomic.LoadInt64(&memR.sidecar.heightForFiringAuction)
+	valid := make([]types.BundleReceipt, 0, len(receipts))
+	for i := range receipts {
+		receipt := &receipts[i]
+		if receipt.Height > auctionHeight {
+			memR.countInvalidSidecarMsg(src, "bad_receipt")
+			memR.Logger.Debug("Dropping bundle receipt for a height past the auction's",
+				"src", src, "height", receipt.Height, "auction_height", auctionHeight)
+			continue
+		}
+		if err := receipt.Verify(memR.sidecar.chainID); err != nil {
+			memR.countInvalidSidecarMsg(src, "bad_receipt")
+			memR.Logger.Debug("Dropping bundle receipt", "src", src, "height", receipt.Height, "err", err)
+			continue
+		}
+		valid = append(valid, *receipt)
+	}
+	return valid
+}
+
+// relayerPeer returns the relay peer, or nil if we're not connected to it.
+func (memR *Reactor) relayerPeer() p2p.Peer {
+	relayerID := memR.relayerID()
+	if relayerID == "" {
+		return nil
+	}
+	return memR.Switch.Peers().Get(relayerID)
+}
+
+func (memR *Reactor) sendReceipts(peer p2p.Peer, receipts []types.BundleReceipt) {
+	if !memR.peerSidecarProtocol(peer).Has(CapabilityReceipts) {
+		memR.Logger.Debug("Peer doesn't take bundle receipts, not sending them", "peer", peer.ID())
+		return
+	}
+	pbReceipts := make([]*tmproto.BundleReceipt, len(receipts))
+	for i := range receipts {
+		pb, err := receipts[i].ToProto()
+		if err != nil {
+			memR.Logger.Error("Error converting bundle receipt", "err", err)
+			return
+		}
+		pbReceipts[i] = pb
+	}
+	msg := protomem.MEVMessage{
+		Sum: &protomem.MEVMessage_Receipts{
+			Receipts: &protomem.BundleReceipts{Receipts: pbReceipts},
+		},
+	}
+	bz := marshalMEVMessage(&msg)
+	if !memR.sendSidecar(peer, bz) {
+		memR.Logger.Info("Failed sending bundle receipts", "peer", peer.ID())
+		return
+	}
+	for _, receipt := range receipts {
+		memR.Logger.Debug("Sent bundle receipt", "peer", peer.ID(),
+			"bundle_height", receipt.Height, "bundle_id", receipt.BundleID, "bundle_hash", receipt.BundleHash)
+	}
+}
+
+// peerMEVDisabled returns true if the peer advertised that it doesn't take
+// part in MEV auctions.
+func peerMEVDisabled(peer p2p.Peer) bool {
+	nodeInfo, ok := peer.NodeInfo().(p2p.DefaultNodeInfo)
+	return ok && nodeInfo.Other.MEVDisabled
+}
+
 // PeerState describes the state of a peer.
 type PeerState interface {
 	GetHeight() int64
 }
 
+// sidecarGossipMsg returns the message gossiping scTx to peer, in Skip
+// Protocol's format if peer speaks it. The txs received in an envelope are
+// passed on in it to peers taking envelopes, sent with the first tx of the
+// bundle, nil being returned for the others.
+func (memR *Reactor) sidecarGossipMsg(peer p2p.Peer, scTx *SidecarTx) []byte {
+	switch {
+	case memR.speaksSkipFormat(peer.ID()):
+		return encodeSkipTxMsg(scTx)
+	case scTx.envelope != nil && memR.peerSidecarProtocol(peer).Has(CapabilityEnvelopes):
+		if scTx.bundleOrder != 0 {
+			return nil
+		}
+		return scTx.envelope.gossipMsg()
+	default:
+		return scTx.gossipMsg()
+	}
+}
+
 // Send new mempool txs to peer.
 func (memR *Reactor) broadcastSidecarTxRoutine(peer p2p.Peer) {
 	peerID := memR.ids.GetForPeer(peer)
@@ -254,9 +667,7 @@ func (memR *Reactor) broadcastSidecarTxRoutine(peer p2p.Peer) {
 			select {
 			case <-memR.sidecar.TxsWaitChan(): // Wait until a tx is available in sidecar
 				// if a tx is available on sidecar, if fire is set too, then fire
-				fmt.Println("[mev-tendermint]: BroadcastSidecarTx() sidecar tx wait chan entered!")
 				if next = memR.sidecar.TxsFront(); next == nil {
-					fmt.Println("[mev-tendermint]: BroadcastSidecarTx() next is nil after sidecar txs front()")
 					continue
 				}
 			case <-peer.Quit():
@@ -267,28 +678,17 @@ func (memR *Reactor) broadcastSidecarTxRoutine(peer p2p.Peer) {
 		}
 
 		if scTx, okConv := next.Value.(*SidecarTx); okConv && isSidecarPeer {
-			fmt.Println("[mev-tendermint]: BroadcastSidecarTx() as sidecarTx to peer", peerID)
-			if _, ok := scTx.senders.Load(peerID); !ok {
-				msg := protomem.MEVMessage{
-					Sum: &protomem.MEVMessage_Txs{
-						Txs: &protomem.Txs{Txs: [][]byte{scTx.tx}},
-					},
-					DesiredHeight: scTx.desiredHeight,
-					BundleId:      scTx.bundleId,
-					BundleOrder:   scTx.bundleOrder,
-					BundleSize:    scTx.bundleSize,
-				}
-				bz, err := msg.Marshal()
-				if err != nil {
-					panic(err)
-				}
-				success := peer.Send(SidecarChannel, bz)
-				if !success {
-					time.Sleep(peerCatchupSleepIntervalMS * time.Millisecond)
-					continue
+			// txs of private origins are kept to this node
+			if _, ok := scTx.senders.Load(peerID); !ok && !memR.privateOrigins[scTx.origin] {
+				if msg := memR.sidecarGossipMsg(peer, scTx); msg != nil {
+					success := memR.sendSidecar(peer, msg)
+					if !success {
+						time.Sleep(peerCatchupSleepIntervalMS * time.Millisecond)
+						continue
+					}
+					memR.sidecarTxLogger.Debug("Sent sidecar tx", "peer", peer.ID(),
+						"bundle_height", scTx.desiredHeight, "bundle_id", scTx.bundleId, "bundle_order", scTx.bundleOrder)
 				}
-			} else {
-				fmt.Println(fmt.Sprintf("[mev-tendermint]: BroadcastSidecarTx() failed: isSideCarPeer is %t, conversion was %t", isSidecarPeer, okConv))
 			}
 		}
 
@@ -348,24 +748,22 @@ func (memR *Reactor) broadcastMempoolTxRoutine(peer p2p.Peer) {
 				continue
 			}
 
-			if _, ok := memTx.senders.Load(peerID); !ok {
-				msg := protomem.Message{
-					Sum: &protomem.Message_Txs{
-						Txs: &protomem.Txs{Txs: [][]byte{memTx.tx}},
-					},
-				}
-				bz, err := msg.Marshal()
-				if err != nil {
-					panic(err)
+			// txs of private origins are kept to this node
+			if _, ok := memTx.senders.Load(peerID); !ok && !memR.privateOrigins[memTx.origin] {
+				// txs of custom lanes go on their own channel, unless the
+				// peer doesn't know about it
+				chID := MempoolChannel
+				if memTx.lane != nil && memTx.lane.channel != MempoolChannel && peerHasChannel(peer, memTx.lane.channel) {
+					chID = memTx.lane.channel
 				}
-				success := peer.Send(MempoolChannel, bz)
+				success := peer.Send(chID, memTx.gossipMsg())
 				if !success {
 					time.Sleep(peerCatchupSleepIntervalMS * time.Millisecond)
 					continue
 				}
 			}
 		} else {
-			fmt.Println("[mev-tendermint]: BroadcastMempoolTx() couldn't cast mempoolTx!")
+			memR.Logger.Error("Mempool element isn't a MempoolTx", "type", fmt.Sprintf("%T", next.Value))
 		}
 
 		select {
@@ -383,66 +781,205 @@ func (memR *Reactor) broadcastMempoolTxRoutine(peer p2p.Peer) {
 //-----------------------------------------------------------------------------
 // Messages
 
-func (memR *Reactor) decodeBundleMsg(bz []byte) (MEVTxsMessage, error) {
-	msg := protomem.MEVMessage{}
-	err := msg.Unmarshal(bz)
+// Numbers of the MEVMessage fields in proto/tendermint/mempool/types.proto,
+// for decodeBundleMsg to tell messages apart without unmarshaling them.
+const (
+	mevMsgTxsField           = 1
+	mevMsgDesiredHeightField = 2 // the tx metadata, numbered consecutively
+	mevMsgBundleIDField      = 3
+	mevMsgBundleOrderField   = 4
+	mevMsgBundleSizeField    = 5
+	mevMsgBidField           = 6
+	mevMsgReceiptsField      = 7
+	mevMsgRegistrationField  = 8
+	mevMsgPingField          = 9
+	mevMsgPongField          = 10
+	mevMsgVersionField       = 11
+	mevMsgCapabilitiesField  = 12
+	mevMsgEnvelopeField      = 13
+	mevMsgChainIDField       = 14
+)
+
+// decodeBundleMsg returns either a MEVTxsMessage, a MEVEnvelopeMessage, a
+// MEVReceiptsMessage, a MEVRegistrationMessage or a MEVPingMessage, and the
+// protocol of its sender. The txs alias a copy of bz, see gossip_msg.go.
+func (memR *Reactor) decodeBundleMsg(bz []byte) (interface{}, SidecarProtocol, error) {
+	var (
+		p              SidecarProtocol
+		txs            []types.Tx
+		isReceipts     bool
+		isRegistration bool
+		isPing         bool
+		isEnvelope     bool
+		msg            protomem.MEVMessage
+	)
+	varints := [...]*int64{&msg.DesiredHeight, &msg.BundleId, &msg.BundleOrder, &msg.BundleSize, &msg.Bid}
+	bz = append(make([]byte, 0, len(bz)), bz...)
+	err := rangeFields(bz, func(num int32, wireType int, v uint64, b []byte) (err error) {
+		switch num {
+		case mevMsgTxsField, mevMsgReceiptsField, mevMsgRegistrationField, mevMsgPingField, mevMsgPongField,
+			mevMsgEnvelopeField:
+			if wireType != proto.WireBytes {
+				return errWireType
+			}
+			// the last of the oneof's fields wins
+			isReceipts, isRegistration = num == mevMsgReceiptsField, num == mevMsgRegistrationField
+			isPing, isEnvelope = num == mevMsgPingField || num == mevMsgPongField, num == mevMsgEnvelopeField
+			if num == mevMsgTxsField {
+				txs, err = decodeTxs(b)
+			}
+			if txs == nil {
+				txs = []types.Tx{}
+			}
+		case mevMsgDesiredHeightField, mevMsgBundleIDField, mevMsgBundleOrderField, mevMsgBundleSizeField,
+			mevMsgBidField:
+			if wireType != proto.WireVarint {
+				return errWireType
+			}
+			*varints[num-mevMsgDesiredHeightField] = int64(v)
+		case mevMsgVersionField, mevMsgCapabilitiesField:
+			if wireType != proto.WireVarint {
+				return errWireType
+			}
+			if num == mevMsgVersionField {
+				p.Version = v
+			} else {
+				p.Capabilities = SidecarCapabilities(v)
+			}
+		case mevMsgChainIDField:
+			if wireType != proto.WireBytes {
+				return errWireType
+			}
+			msg.ChainId = string(b)
+		}
+		return err
+	})
 	if err != nil {
-		return MEVTxsMessage{}, err
+		return MEVTxsMessage{}, p, err
 	}
 
-	var message MEVTxsMessage
-
-	if i, ok := msg.Sum.(*protomem.MEVMessage_Txs); ok {
-		txs := i.Txs.GetTxs()
+	if isEnvelope {
+		if err := msg.Unmarshal(bz); err != nil {
+			return MEVEnvelopeMessage{}, p, err
+		}
+		env, err := BundleEnvelopeFromProto(msg.GetEnvelope())
+		if err != nil {
+			return MEVEnvelopeMessage{}, p, err
+		}
+		return MEVEnvelopeMessage{Envelope: env}, p, nil
+	}
+	if isPing {
+		if err := msg.Unmarshal(bz); err != nil {
+			return MEVPingMessage{}, p, err
+		}
+		if pong := msg.GetPong(); pong != nil {
+			return MEVPingMessage{Nonce: pong.Nonce, Pong: true}, p, nil
+		}
+		return MEVPingMessage{Nonce: msg.GetPing().GetNonce()}, p, nil
+	}
+	if isRegistration {
+		if err := msg.Unmarshal(bz); err != nil {
+			return MEVRegistrationMessage{}, p, err
+		}
+		reg := msg.GetRegistration()
+		regMsg := MEVRegistrationMessage{
+			APIKey:                reg.GetApiKey(),
+			NodeID:                p2p.ID(reg.GetNodeID()),
+			Signature:             reg.GetSignature(),
+			MaxBundleHeightsAhead: reg.GetMaxBundleHeightsAhead(),
+		}
+		if reg.GetPubKey() != nil {
+			if regMsg.PubKey, err = cryptoenc.PubKeyFromProto(*reg.GetPubKey()); err != nil {
+				return MEVRegistrationMessage{}, p, err
+			}
+		}
+		return regMsg, p, nil
+	}
+	if isReceipts {
+		// rare enough not to bother
+		if err := msg.Unmarshal(bz); err != nil {
+			return MEVReceiptsMessage{}, p, err
+		}
+		pbReceipts := msg.GetReceipts().GetReceipts()
+		if len(pbReceipts) == 0 {
+			return MEVReceiptsMessage{}, p, errors.New("empty ReceiptsMessage")
+		}
 
-		if len(txs) == 0 {
-			return message, errors.New("empty TxsMessage")
+		receipts := make([]types.BundleReceipt, len(pbReceipts))
+		for j, pb := range pbReceipts {
+			receipt, err := types.BundleReceiptFromProto(pb)
+			if err != nil {
+				return MEVReceiptsMessage{}, p, err
+			}
+			receipts[j] = *receipt
 		}
+		return MEVReceiptsMessage{Receipts: receipts}, p, nil
+	}
+
+	var message MEVTxsMessage
 
-		decoded := make([]types.Tx, len(txs))
-		for j, tx := range txs {
-			decoded[j] = types.Tx(tx)
+	if txs != nil && p.Version == 0 {
+		// from a release predating the protocol version, see legacy_msg.go
+		message, err = parseLegacyTxsMsg(bz)
+		return message, p, err
+	}
+	if txs != nil {
+		if len(txs) == 0 {
+			return message, p, errors.New("empty TxsMessage")
 		}
 
 		message = MEVTxsMessage{
-			Txs:           decoded,
+			Txs:           txs,
 			DesiredHeight: msg.GetDesiredHeight(),
 			BundleId:      msg.GetBundleId(),
 			BundleOrder:   msg.GetBundleOrder(),
 			BundleSize:    msg.GetBundleSize(),
+			Bid:           msg.GetBid(),
+			ChainID:       msg.GetChainId(),
 		}
-		return message, nil
+		return message, p, nil
+	}
+	if p.Version > localSidecarProtocol.Version {
+		return message, p, errUnknownMEVMessage
 	}
-	return message, fmt.Errorf("msg type: %T is not supported", msg)
+	return message, p, fmt.Errorf("msg type: %T is not supported", msg)
 }
 
+// decodeMsg returns the TxsMessage encoded in bz. The txs alias a copy of bz,
+// see gossip_msg.go.
 func (memR *Reactor) decodeMsg(bz []byte) (TxsMessage, error) {
-	msg := protomem.Message{}
-	err := msg.Unmarshal(bz)
+	var txs []types.Tx
+	bz = append(make([]byte, 0, len(bz)), bz...)
+	err := rangeFields(bz, func(num int32, wireType int, _ uint64, b []byte) (err error) {
+		if num != 1 {
+			return nil
+		}
+		if wireType != proto.WireBytes {
+			return errWireType
+		}
+		// the last of the oneof's fields wins
+		if txs, err = decodeTxs(b); txs == nil {
+			txs = []types.Tx{}
+		}
+		return err
+	})
 	if err != nil {
 		return TxsMessage{}, err
 	}
 
 	var message TxsMessage
 
-	if i, ok := msg.Sum.(*protomem.Message_Txs); ok {
-		txs := i.Txs.GetTxs()
-
+	if txs != nil {
 		if len(txs) == 0 {
 			return message, errors.New("empty TxsMessage")
 		}
 
-		decoded := make([]types.Tx, len(txs))
-		for j, tx := range txs {
-			decoded[j] = types.Tx(tx)
-		}
-
 		message = TxsMessage{
-			Txs: decoded,
+			Txs: txs,
 		}
 		return message, nil
 	}
-	return message, fmt.Errorf("msg type: %T is not supported", msg)
+	return message, fmt.Errorf("msg type: %T is not supported", protomem.Message{})
 }
 
 //-------------------------------------
@@ -459,6 +996,52 @@ type MEVTxsMessage struct {
 	BundleId      int64
 	BundleOrder   int64
 	BundleSize    int64
+	Bid           int64
+	ChainID       string
+}
+
+// MEVEnvelopeMessage is a Message containing a whole bundle, signed.
+type MEVEnvelopeMessage struct {
+	Envelope *BundleEnvelope
+}
+
+// MEVReceiptsMessage is a Message containing bundle receipts.
+type MEVReceiptsMessage struct {
+	Receipts []types.BundleReceipt
+}
+
+// MEVRegistrationMessage is a Message registering a validator's node with the
+// relay.
+type MEVRegistrationMessage struct {
+	APIKey string
+	// if signed with the node's relay key, the node it was signed for, the
+	// key, and the signature
+	NodeID    p2p.ID
+	PubKey    crypto.PubKey
+	Signature []byte
+	// how many heights past its upcoming auction the node accepts bundles
+	// for, 0 for no limit
+	MaxBundleHeightsAhead int64
+}
+
+// MEVPingMessage is a Message pinging a sidecar peer, or answering its ping
+// if Pong.
+type MEVPingMessage struct {
+	Nonce uint64
+	Pong  bool
+}
+
+// Verify returns an error if the registration is signed, but not by its
+// PubKey, or not for the node nodeID presenting it.
+func (m MEVRegistrationMessage) Verify(nodeID p2p.ID) error {
+	if m.PubKey == nil {
+		return nil
+	}
+	if m.NodeID != nodeID ||
+		!m.PubKey.VerifySignature(RelayRegistrationSignBytes(m.APIKey, m.NodeID), m.Signature) {
+		return ErrRelayRegistrationInvalidSignature
+	}
+	return nil
 }
 
 // String returns a string representation of the TxsMessage.
@@ -1,28 +1,42 @@
 package mempool
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"net"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/fortytw2/leaktest"
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/go-kit/log/term"
+	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/tendermint/tendermint/abci/example/kvstore"
 	abci "github.com/tendermint/tendermint/abci/types"
 	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
+	"github.com/tendermint/tendermint/crypto/tmhash"
 	"github.com/tendermint/tendermint/libs/log"
 	tmrand "github.com/tendermint/tendermint/libs/rand"
 	"github.com/tendermint/tendermint/p2p"
 	"github.com/tendermint/tendermint/p2p/mock"
 	memproto "github.com/tendermint/tendermint/proto/tendermint/mempool"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/proxy"
 	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
 )
 
 const (
@@ -65,6 +79,29 @@ func TestReactorBroadcastTxsMessage(t *testing.T) {
 	waitForTxsOnReactors(t, txs, reactors, false)
 }
 
+func TestReactorBroadcastTxsMessageCheckTxWorkers(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.CheckTxWorkers = 2
+	const N = 2
+	reactors := makeAndConnectReactors(config, N)
+	defer func() {
+		for _, r := range reactors {
+			if err := r.Stop(); err != nil {
+				assert.NoError(t, err)
+			}
+		}
+	}()
+	for _, r := range reactors {
+		for _, peer := range r.Switch.Peers().List() {
+			peer.Set(types.PeerStateKey, peerState{1})
+		}
+	}
+
+	// txs received from a peer are admitted in order
+	txs := checkTxs(t, reactors[0].mempool, numTxs, UnknownPeerID, reactors[0].sidecar, false)
+	waitForTxsOnReactors(t, txs, reactors, false)
+}
+
 func TestReactorBroadcastSidecarOnly(t *testing.T) {
 	config := cfg.TestConfig()
 	const N = 8
@@ -225,6 +262,30 @@ func TestReactorNoBroadcastToSender(t *testing.T) {
 	ensureNoTxs(t, reactors[peerID], 100*time.Millisecond)
 }
 
+func TestReactorNoBroadcastOfPrivateOrigins(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.PrivateOrigins = "rpc"
+	const N = 2
+	reactors := makeAndConnectReactors(config, N)
+	defer func() {
+		for _, r := range reactors {
+			if err := r.Stop(); err != nil {
+				assert.NoError(t, err)
+			}
+		}
+	}()
+	for _, r := range reactors {
+		for _, peer := range r.Switch.Peers().List() {
+			peer.Set(types.PeerStateKey, peerState{1})
+		}
+	}
+
+	tx := types.Tx("private")
+	require.NoError(t, reactors[0].mempool.CheckTx(tx, nil, TxInfo{Origin: OriginRPC}))
+	require.Equal(t, 1, reactors[0].mempool.Size())
+	ensureNoTxs(t, reactors[1], 100*time.Millisecond)
+}
+
 func TestReactor_MaxTxBytes(t *testing.T) {
 	config := cfg.TestConfig()
 
@@ -306,6 +367,894 @@ func TestBroadcastTxForPeerStopsWhenReactorStops(t *testing.T) {
 	leaktest.CheckTimeout(t, 10*time.Second)()
 }
 
+func TestPeerRateLimiter(t *testing.T) {
+	rl := newPeerRateLimiter(10, 20, time.Minute)
+	now := time.Now()
+
+	// peers have their own bucket
+	assert.True(t, rl.allow("a", 20, now))
+	assert.True(t, rl.allow("b", 5, now))
+
+	// buckets refill at the rate, up to the burst
+	assert.True(t, rl.allow("a", 10, now.Add(time.Second)))
+	assert.True(t, rl.allow("b", 15, now.Add(time.Hour)))
+
+	// going over mutes the peer, even once its bucket refilled
+	assert.False(t, rl.allow("a", 1, now.Add(time.Second)))
+	assert.False(t, rl.allow("a", 1, now.Add(30*time.Second)))
+	assert.True(t, rl.allow("a", 1, now.Add(61*time.Second)))
+
+	// reconnecting doesn't end a mute
+	assert.False(t, rl.allow("c", 21, now))
+	rl.removePeer("c", now.Add(time.Second))
+	assert.False(t, rl.allow("c", 1, now.Add(2*time.Second)))
+	rl.removePeer("c", now.Add(2*time.Minute))
+	assert.True(t, rl.allow("c", 20, now.Add(2*time.Minute)))
+}
+
+func TestReactorPeerRateLimit(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.PeerCheckTxRate = 1
+	config.Mempool.PeerCheckTxBurst = 1
+	const N = 2
+	reactors := makeAndConnectReactors(config, N)
+	defer func() {
+		for _, r := range reactors {
+			if err := r.Stop(); err != nil {
+				assert.NoError(t, err)
+			}
+		}
+	}()
+	reactor := reactors[0]
+	peer := mock.NewPeer(nil)
+	reactor.InitPeer(peer)
+
+	// the txs of a peer going over its limit are dropped
+	txs := types.Txs{types.Tx("tx0"), types.Tx("tx1")}
+	for _, tx := range txs {
+		msg := memproto.Message{
+			Sum: &memproto.Message_Txs{
+				Txs: &memproto.Txs{Txs: [][]byte{tx}},
+			},
+		}
+		bz, err := msg.Marshal()
+		require.NoError(t, err)
+		reactor.Receive(MempoolChannel, peer, bz)
+	}
+	assert.Equal(t, txs[:1], reactor.mempool.ReapMaxTxs(-1))
+}
+
+func TestCheckTxPoolTrySubmit(t *testing.T) {
+	pool := newCheckTxPool(2, 1)
+
+	// each worker queues one job, and refuses more until it's run
+	ran := make(chan struct{}, 3)
+	job := func() { ran <- struct{}{} }
+	assert.True(t, pool.trySubmit(0, job))
+	assert.False(t, pool.trySubmit(2, job))
+	assert.True(t, pool.trySubmit(1, job))
+
+	pool.start()
+	defer pool.stop()
+	<-ran
+	<-ran
+	assert.Eventually(t, func() bool { return pool.trySubmit(2, job) }, time.Second, 10*time.Millisecond)
+}
+
+func TestRelayMonitor(t *testing.T) {
+	relay, other := mock.NewPeer(nil), mock.NewPeer(nil)
+	metrics := NopMetrics()
+	metrics.RelayConnected = generic.NewGauge("relay_connected")
+	metrics.RelayLastMessageAge = generic.NewGauge("relay_last_message_age_seconds")
+	metrics.RelayReconnects = generic.NewCounter("relay_reconnects")
+	start := time.Now()
+	rm := newRelayMonitor(relay.ID(), metrics, start)
+	connected := func() float64 { return rm.connectedGauge.(*generic.Gauge).Value() }
+
+	// other peers are ignored
+	rm.peerAdded(other)
+	assert.Zero(t, connected())
+	rm.peerAdded(relay)
+	assert.EqualValues(t, 1, connected())
+	rm.peerRemoved(other)
+	assert.EqualValues(t, 1, connected())
+
+	// the age is measured from the start until the relay sends a message
+	rm.report(start.Add(3 * time.Second))
+	assert.EqualValues(t, 3, rm.ageGauge.(*generic.Gauge).Value())
+	rm.messageReceived(relay, start.Add(5*time.Second))
+	rm.messageReceived(other, start.Add(6*time.Second))
+	rm.report(start.Add(7 * time.Second))
+	assert.EqualValues(t, 2, rm.ageGauge.(*generic.Gauge).Value())
+
+	rm.peerRemoved(relay)
+	assert.Zero(t, connected())
+	assert.Zero(t, rm.reconnects.(*generic.Counter).Value())
+	rm.peerAdded(relay)
+	assert.EqualValues(t, 1, connected())
+	assert.EqualValues(t, 1, rm.reconnects.(*generic.Counter).Value())
+}
+
+func TestStallWatchdog(t *testing.T) {
+	config := cfg.TestSidecarConfig()
+	config.StallBlocks = 3
+	config.StallTimeout = time.Minute
+	m := NopMetrics()
+	m.SidecarStalled = generic.NewGauge("sidecar_stalled")
+	start := time.Now()
+	w := newStallWatchdog(config, m, start)
+	stalled := func(gauge metrics.Gauge) float64 { return gauge.(*generic.Gauge).Value() }
+
+	// without sidecar peers, no message is expected
+	for height := int64(1); height <= 5; height++ {
+		w.check(height, height+1, start)
+	}
+	assert.NoError(t, w.err())
+
+	// with one, messages keep the pipeline healthy
+	w.peerAdded()
+	w.check(6, 7, start)
+	w.sidecarMessageReceived()
+	w.check(8, 9, start)
+	w.check(10, 11, start)
+	assert.NoError(t, w.err())
+	assert.Zero(t, stalled(w.noMessagesGauge))
+	w.check(11, 12, start)
+	assert.Equal(t, ErrSidecarStalled{Reasons: []string{stallNoSidecarMessages}}, w.err())
+	assert.EqualValues(t, 1, stalled(w.noMessagesGauge))
+	w.sidecarMessageReceived()
+	w.check(11, 12, start)
+	assert.NoError(t, w.err())
+
+	// the auction height must advance within the timeout
+	w.sidecarMessageReceived()
+	w.check(11, 12, start.Add(time.Minute))
+	assert.Equal(t, ErrSidecarStalled{Reasons: []string{stallAuctionHeight}}, w.err())
+	assert.EqualValues(t, 1, stalled(w.auctionHeightGauge))
+	w.peerRemoved()
+	w.check(12, 13, start.Add(time.Minute))
+	assert.NoError(t, w.err())
+	assert.Zero(t, stalled(w.auctionHeightGauge))
+}
+
+func TestSidecarBandwidth(t *testing.T) {
+	m := NopMetrics()
+	m.SidecarSendRate = generic.NewGauge("sidecar_send_rate")
+	m.SidecarReceiveRate = generic.NewGauge("sidecar_receive_rate")
+	b := newSidecarBandwidth(m)
+
+	b.sent.Update(10000)
+	// the rates are sampled every 100ms
+	time.Sleep(200 * time.Millisecond)
+	b.report()
+	assert.Positive(t, m.SidecarSendRate.(*generic.Gauge).Value())
+	assert.Zero(t, m.SidecarReceiveRate.(*generic.Gauge).Value())
+}
+
+// fullPeer is a sidecar peer whose send queue is always full.
+type fullPeer struct {
+	*mock.Peer
+}
+
+func (p fullPeer) Send(chID byte, msgBytes []byte) bool { return false }
+
+func (p fullPeer) NodeInfo() p2p.NodeInfo {
+	nodeInfo := p.Peer.NodeInfo().(p2p.DefaultNodeInfo)
+	nodeInfo.Channels = []byte{SidecarChannel}
+	return nodeInfo
+}
+
+func TestSidecarDroppedMessages(t *testing.T) {
+	config := cfg.TestConfig()
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, _, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	dropped := newCountersByLabels()
+	mempool.metrics.SidecarPeerDroppedMessages = dropped
+	// the auction is for height 6
+	sidecar := NewCListSidecar(5)
+	reactor := NewReactor(config.Mempool, mempool, sidecar)
+
+	peer := fullPeer{mock.NewPeer(nil)}
+	peerID := string(peer.ID())
+	peer.Set(types.PeerStateKey, peerState{5})
+	assert.False(t, reactor.sendSidecar(peer, []byte{0x1}))
+	assert.EqualValues(t, 1, dropped.value(peerID, "queue_full"))
+
+	peer.Set(types.PeerStateKey, peerState{4})
+	assert.False(t, reactor.sendSidecar(peer, []byte{0x1}))
+	assert.EqualValues(t, 1, dropped.value(peerID, "peer_behind"))
+
+	// sends to stopped peers fail without dropping anything from their queue
+	require.NoError(t, peer.Stop())
+	assert.False(t, reactor.sendSidecar(peer, []byte{0x1}))
+	assert.EqualValues(t, 1, dropped.value(peerID, "queue_full"))
+	assert.EqualValues(t, 1, dropped.value(peerID, "peer_behind"))
+}
+
+// recordingPeer is a sidecar peer recording the messages sent to it.
+type recordingPeer struct {
+	*mock.Peer
+	sent *[][]byte
+}
+
+func (p recordingPeer) Send(chID byte, msgBytes []byte) bool {
+	*p.sent = append(*p.sent, msgBytes)
+	return true
+}
+
+func TestRelayRegistration(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.Broadcast = false
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, _, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	sidecarConfig := cfg.TestSidecarConfig()
+	sidecarConfig.MaxBundleHeightsAhead = 3
+	reactor := NewReactor(config.Mempool, mempool, NewCListSidecar(5, WithSidecarConfig(sidecarConfig)))
+	reactor.SetLogger(log.TestingLogger())
+
+	var relaySent, otherSent [][]byte
+	relay := recordingPeer{mock.NewPeer(nil), &relaySent}
+	other := recordingPeer{mock.NewPeer(nil), &otherSent}
+	reactor.SetRelay(relay.ID(), "secret")
+
+	// the API key is only sent to the relay, once connected, along with the
+	// heights bundles are accepted for
+	reactor.AddPeer(other)
+	assert.Empty(t, otherSent)
+	reactor.AddPeer(relay)
+	require.Len(t, relaySent, 1)
+	msg, _, err := reactor.decodeBundleMsg(relaySent[0])
+	require.NoError(t, err)
+	assert.Equal(t, MEVRegistrationMessage{APIKey: "secret", MaxBundleHeightsAhead: 3}, msg)
+
+	// and not at all without one
+	reactor.SetRelay(relay.ID(), "")
+	reactor.AddPeer(relay)
+	assert.Len(t, relaySent, 1)
+
+	// nor while MEV is off
+	reactor.SetRelay(relay.ID(), "secret")
+	reactor.SetMEVDisabled(true)
+	reactor.AddPeer(relay)
+	assert.Len(t, relaySent, 1)
+}
+
+func TestRelayAllowlist(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.Broadcast = false
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, sidecar, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	invalid := newCountersByLabels()
+	mempool.metrics.SidecarPeerInvalidMessages = invalid
+	reactor := NewReactor(config.Mempool, mempool, sidecar)
+	reactor.SetLogger(log.TestingLogger())
+	p2p.MakeConnectedSwitches(config.P2P, 1, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("MEMPOOL", reactor)
+		return s
+	}, p2p.Connect2Switches)
+	defer func() {
+		assert.NoError(t, reactor.Stop())
+	}()
+
+	var relaySent [][]byte
+	relay := recordingPeer{mock.NewPeer(nil), &relaySent}
+	personal := mock.NewPeer(nil)
+	reactor.SetRelay(relay.ID(), "secret")
+	reactor.SetRelayAllowlist([]p2p.ID{"0123456789abcdef0123456789abcdef01234567"})
+
+	// a relay not allowed isn't registered with, and its bundles are refused
+	reactor.AddPeer(relay)
+	assert.Empty(t, relaySent)
+	fromRelay, _ := encodeSidecarTxMsg(&SidecarTx{desiredHeight: 1, bundleSize: 1, tx: types.Tx("relay")}, "")
+	reactor.Receive(SidecarChannel, relay, fromRelay)
+	assert.Zero(t, sidecar.Size())
+	assert.EqualValues(t, 1, invalid.value(string(relay.ID()), "relay_not_allowed"))
+
+	// other sidecar peers aren't relays
+	fromPersonal, _ := encodeSidecarTxMsg(&SidecarTx{desiredHeight: 1, bundleId: 1, bundleSize: 1,
+		tx: types.Tx("personal")}, "")
+	reactor.Receive(SidecarChannel, personal, fromPersonal)
+	assert.EqualValues(t, 1, sidecar.Size())
+
+	// once allowed, it is
+	reactor.SetRelayAllowlist([]p2p.ID{"0123456789abcdef0123456789abcdef01234567", relay.ID()})
+	reactor.AddPeer(relay)
+	assert.Len(t, relaySent, 1)
+	reactor.Receive(SidecarChannel, relay, fromRelay)
+	assert.EqualValues(t, 2, sidecar.Size())
+}
+
+func TestReactorMEVInfo(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.Broadcast = false
+	reactors := make([]*Reactor, 2)
+	for i := range reactors {
+		app := kvstore.NewApplication()
+		cc := proxy.NewLocalClientCreator(app)
+		mempool, sidecar, cleanup := newMempoolWithApp(cc)
+		defer cleanup()
+		reactors[i] = NewReactor(config.Mempool, mempool, sidecar)
+		reactors[i].SetLogger(log.TestingLogger())
+	}
+	p2p.MakeConnectedSwitches(config.P2P, len(reactors), func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("MEMPOOL", reactors[i])
+		return s
+	}, p2p.Connect2Switches)
+	defer func() {
+		for _, r := range reactors {
+			assert.NoError(t, r.Stop())
+		}
+	}()
+
+	info := reactors[0].MEVInfo()
+	assert.Equal(t, cfg.NodeModeValidator, info.Mode)
+	assert.False(t, info.MEVDisabled)
+	assert.False(t, info.RelayConnected)
+	assert.EqualValues(t, 1, info.AuctionHeight)
+
+	reactors[0].SetRelay(reactors[1].Switch.NodeInfo().ID(), "")
+	assert.True(t, reactors[0].MEVInfo().RelayConnected)
+	reactors[0].SetMEVDisabled(true)
+	assert.True(t, reactors[0].MEVInfo().MEVDisabled)
+}
+
+func TestSidecarProtocol(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.Broadcast = false
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, sidecar, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	reactor := NewReactor(config.Mempool, mempool, sidecar)
+	reactor.SetLogger(log.TestingLogger())
+
+	var sent [][]byte
+	peer := recordingPeer{mock.NewPeer(nil), &sent}
+	receipt := types.BundleReceipt{
+		Height:     1,
+		BlockHash:  tmhash.Sum([]byte("block")),
+		BundleID:   2,
+		BundleHash: tmhash.Sum([]byte("bundle")),
+		Size:       1,
+	}
+	require.NoError(t, receipt.Sign("test_chain_id", ed25519.GenPrivKey()))
+	receipts := []types.BundleReceipt{receipt}
+
+	// peers are taken to speak the legacy protocol until they say otherwise,
+	// and sent no optional messages
+	assert.Equal(t, SidecarProtocol{}, reactor.peerSidecarProtocol(peer))
+	reactor.sendReceipts(peer, receipts)
+	assert.Empty(t, sent)
+
+	// a peer advertising its protocol is adapted to
+	pong := memproto.MEVMessage{
+		Sum:          &memproto.MEVMessage_Pong{Pong: &memproto.SidecarPong{Nonce: 1}},
+		Version:      2,
+		Capabilities: uint64(CapabilityCancellation | CapabilityCompression),
+	}
+	bz, err := pong.Marshal()
+	require.NoError(t, err)
+	reactor.Receive(SidecarChannel, peer, bz)
+	protocol := reactor.peerSidecarProtocol(peer)
+	assert.EqualValues(t, 2, protocol.Version)
+	assert.True(t, protocol.Has(CapabilityCancellation|CapabilityCompression))
+	assert.False(t, protocol.Has(CapabilityReceipts))
+	reactor.sendReceipts(peer, receipts)
+	assert.Empty(t, sent)
+	pong.Capabilities = uint64(CapabilityReceipts)
+	bz, err = pong.Marshal()
+	require.NoError(t, err)
+	reactor.Receive(SidecarChannel, peer, bz)
+	reactor.sendReceipts(peer, receipts)
+	assert.Len(t, sent, 1)
+
+	// and every message sent carries this node's
+	reactor.receivePing(peer, MEVPingMessage{Nonce: 2})
+	require.Len(t, sent, 2)
+	_, protocol, err = reactor.decodeBundleMsg(sent[1])
+	require.NoError(t, err)
+	assert.Equal(t, localSidecarProtocol, protocol)
+}
+
+func TestSkipFormatPeers(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.Broadcast = false
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, sidecar, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	reactor := NewReactor(config.Mempool, mempool, sidecar)
+	reactor.SetLogger(log.TestingLogger())
+	p2p.MakeConnectedSwitches(config.P2P, 1, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("MEMPOOL", reactor)
+		return s
+	}, p2p.Connect2Switches)
+	defer func() {
+		assert.NoError(t, reactor.Stop())
+	}()
+
+	var sent [][]byte
+	peer := recordingPeer{mock.NewPeer(nil), &sent}
+	reactor.SetSkipFormatPeers([]p2p.ID{peer.ID()})
+	reactor.SetRelay(peer.ID(), "secret")
+
+	// txs sent in Skip Protocol's format lose their bid, and nothing else
+	scTx := &SidecarTx{desiredHeight: 1, bundleId: 2, bundleSize: 1, bid: 10, tx: types.Tx("tx")}
+	msg, err := decodeSkipMsg(encodeSkipTxMsg(scTx))
+	require.NoError(t, err)
+	assert.Equal(t, MEVTxsMessage{Txs: []types.Tx{scTx.tx}, DesiredHeight: 1, BundleId: 2, BundleSize: 1}, msg)
+	_, err = decodeSkipMsg(nil)
+	assert.Error(t, err)
+
+	// those received are added with a bid of 0
+	reactor.Receive(SidecarChannel, peer, encodeSkipTxMsg(scTx))
+	require.EqualValues(t, 1, sidecar.Size())
+	dump := sidecar.Dump()
+	assert.Zero(t, dump.Txs[0].Bid)
+	assert.EqualValues(t, 2, dump.Txs[0].BundleID)
+
+	// and the peer is sent no registration, receipt nor ping
+	reactor.AddPeer(peer)
+	receipt := types.BundleReceipt{
+		Height:     1,
+		BlockHash:  tmhash.Sum([]byte("block")),
+		BundleID:   2,
+		BundleHash: tmhash.Sum([]byte("bundle")),
+		Size:       1,
+	}
+	require.NoError(t, receipt.Sign("test_chain_id", ed25519.GenPrivKey()))
+	reactor.sendReceipts(peer, []types.BundleReceipt{receipt})
+	assert.Equal(t, SidecarProtocol{}, reactor.peerSidecarProtocol(peer))
+	assert.Empty(t, sent)
+}
+
+func TestLegacySidecarMsgs(t *testing.T) {
+	config := cfg.TestConfig()
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, _, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	invalid := newCountersByLabels()
+	mempool.metrics.SidecarPeerInvalidMessages = invalid
+	sidecar := NewCListSidecar(0, WithSidecarChainID("chain-a"))
+	reactor := NewReactor(config.Mempool, mempool, sidecar)
+	reactor.SetLogger(log.TestingLogger())
+	peer := mock.NewPeer(nil)
+
+	// messages predating the protocol version are upgraded, taken for any
+	// chain, with their bid if they carry one
+	legacy := memproto.MEVMessage{
+		Sum:           &memproto.MEVMessage_Txs{Txs: &memproto.Txs{Txs: [][]byte{[]byte("legacy")}}},
+		DesiredHeight: 1,
+		BundleId:      2,
+		BundleSize:    1,
+		ChainId:       "chain-b",
+	}
+	bz, err := legacy.Marshal()
+	require.NoError(t, err)
+	msg, protocol, err := reactor.decodeBundleMsg(bz)
+	require.NoError(t, err)
+	assert.Equal(t, SidecarProtocol{}, protocol)
+	assert.Equal(t, MEVTxsMessage{Txs: []types.Tx{types.Tx("legacy")}, DesiredHeight: 1, BundleId: 2, BundleSize: 1},
+		msg)
+	legacy.Bid = 10
+	bz, err = legacy.Marshal()
+	require.NoError(t, err)
+	msg, _, err = reactor.decodeBundleMsg(bz)
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, msg.(MEVTxsMessage).Bid)
+	reactor.Receive(SidecarChannel, peer, bz)
+	assert.EqualValues(t, 1, sidecar.Size())
+	assert.Equal(t, SidecarProtocol{}, reactor.peerSidecarProtocol(peer))
+
+	// messages of unknown types are ignored from peers speaking a newer
+	// version, their protocol recorded, and malformed from any other
+	unknown := proto.EncodeVarint(11<<3 | proto.WireVarint)
+	unknown = append(unknown, proto.EncodeVarint(localSidecarProtocol.Version+1)...)
+	unknown = append(unknown, proto.EncodeVarint(15<<3|proto.WireBytes)...)
+	unknown = append(unknown, 1, 'x')
+	reactor.Receive(SidecarChannel, peer, unknown)
+	assert.EqualValues(t, 1, invalid.value(string(peer.ID()), "unknown_type"))
+	assert.Equal(t, localSidecarProtocol.Version+1, reactor.peerSidecarProtocol(peer).Version)
+	unknown[1] = byte(localSidecarProtocol.Version)
+	_, _, err = reactor.decodeBundleMsg(unknown)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, errUnknownMEVMessage))
+}
+
+func TestSubmitBundle(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.Broadcast = false
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, sidecar, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	reactor := NewReactor(config.Mempool, mempool, sidecar)
+	reactor.SetLogger(log.TestingLogger())
+
+	_, err := reactor.SubmitBundle(nil, 1)
+	assert.Error(t, err)
+
+	// bundles are given the IDs after the highest the sidecar holds
+	bundleID, err := reactor.SubmitBundle(types.Txs{types.Tx("a"), types.Tx("b")}, 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, bundleID)
+	bundleID, err = reactor.SubmitBundle(types.Txs{types.Tx("c")}, 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, bundleID)
+	assert.Equal(t, 3, sidecar.Size())
+	assert.Equal(t, 2, sidecar.NumBundles())
+	dump := sidecar.Dump()
+	assert.Equal(t, OriginRPC.String(), dump.Txs[0].Origin)
+	assert.EqualValues(t, 1, dump.Txs[1].BundleOrder)
+
+	// and refused as any other
+	_, err = reactor.SubmitBundle(types.Txs{types.Tx("d")}, 0)
+	assert.IsType(t, ErrWrongHeight{}, errors.Unwrap(err))
+
+	// with the txs added before a refused one removed
+	_, err = reactor.SubmitBundle(types.Txs{types.Tx("e"), types.Tx("a")}, 1)
+	assert.ErrorIs(t, err, ErrTxInCache)
+	assert.Equal(t, 3, sidecar.Size())
+	assert.Equal(t, 2, sidecar.NumBundles())
+	_, err = reactor.SubmitBundle(types.Txs{types.Tx("e")}, 1)
+	assert.NoError(t, err)
+
+	// ids are taken after those held for the height, even if accepted ahead
+	require.NoError(t, sidecar.AddTx(types.Tx("g"), TxInfo{DesiredHeight: 2, BundleId: 1, BundleSize: 2}))
+	bundleID, err = reactor.SubmitBundle(types.Txs{types.Tx("h")}, 2)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, bundleID)
+
+	// and only the txs added by a failed submission are removed
+	numBundles := sidecar.NumBundles()
+	sidecar.RemoveBundleTxs(2, 1, types.Txs{types.Tx("h")}, true)
+	assert.Equal(t, numBundles, sidecar.NumBundles())
+	sidecar.RemoveBundleTxs(2, 2, types.Txs{types.Tx("h")}, true)
+	assert.Equal(t, numBundles-1, sidecar.NumBundles())
+
+	// nodes with a relay leave the ids to it
+	reactor.SetRelay("relay", "")
+	_, err = reactor.SubmitBundle(types.Txs{types.Tx("f")}, 1)
+	assert.ErrorIs(t, err, ErrRelayAssignsBundleIDs)
+}
+
+func TestBundleEnvelope(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.Broadcast = false
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, sidecar, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	invalid := newCountersByLabels()
+	mempool.metrics.SidecarPeerInvalidMessages = invalid
+	reactor := NewReactor(config.Mempool, mempool, sidecar)
+	reactor.SetLogger(log.TestingLogger())
+	p2p.MakeConnectedSwitches(config.P2P, 1, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("MEMPOOL", reactor)
+		return s
+	}, p2p.Connect2Switches)
+	defer func() {
+		assert.NoError(t, reactor.Stop())
+	}()
+
+	relayKey := GenFileRelayKey()
+	env := &BundleEnvelope{
+		Txs:       types.Txs{types.Tx("a"), types.Tx("b")},
+		MinHeight: 1,
+		MaxHeight: 3,
+		Bid:       10,
+		BundleID:  4,
+		Extensions: []types.BundleExtension{
+			{Key: "strategy", Value: []byte("arb")},
+			{Key: "refund", Value: []byte("addr")},
+		},
+	}
+	require.NoError(t, env.Sign(relayKey))
+	require.NoError(t, env.Verify())
+
+	// envelopes round-trip on the wire, signed
+	peer := mock.NewPeer(nil)
+	msg, _, err := reactor.decodeBundleMsg(env.gossipMsg())
+	require.NoError(t, err)
+	received := msg.(MEVEnvelopeMessage).Envelope
+	assert.Equal(t, env.Txs, received.Txs)
+	assert.Equal(t, env.Extensions, received.Extensions)
+	assert.NoError(t, received.Verify())
+
+	// and can't be tampered with
+	forged := &BundleEnvelope{Txs: env.Txs, MinHeight: 1, MaxHeight: 3, Bid: 10, BundleID: 4,
+		PubKey: env.PubKey, Signature: env.Signature,
+		Extensions: []types.BundleExtension{{Key: "refund", Value: []byte("thief")}}}
+	assert.Equal(t, ErrBundleEnvelopeInvalidSignature, forged.Verify())
+	forged.Extensions = append(forged.Extensions, types.BundleExtension{Key: "refund"})
+	assert.Error(t, forged.ValidateBasic())
+	forged.Extensions = nil
+	reactor.Receive(SidecarChannel, peer, forged.gossipMsg())
+	assert.Zero(t, sidecar.Size())
+	assert.EqualValues(t, 1, invalid.value(string(peer.ID()), "bad_envelope"))
+
+	// nor signed by a relay not allowed
+	reactor.SetRelayAllowlist([]p2p.ID{"0123456789abcdef0123456789abcdef01234567"})
+	reactor.Receive(SidecarChannel, peer, env.gossipMsg())
+	assert.Zero(t, sidecar.Size())
+	reactor.SetRelayAllowlist([]p2p.ID{p2p.PubKeyToID(relayKey.PubKey())})
+
+	// the txs of those received are added for the upcoming auction, in
+	// order, with the envelope's bid
+	reactor.Receive(SidecarChannel, peer, env.gossipMsg())
+	require.EqualValues(t, 2, sidecar.Size())
+	dump := sidecar.Dump()
+	assert.EqualValues(t, 1, dump.Txs[0].Height)
+	assert.EqualValues(t, 4, dump.Txs[1].BundleID)
+	assert.EqualValues(t, 1, dump.Txs[1].BundleOrder)
+	assert.EqualValues(t, 10, dump.Txs[1].Bid)
+
+	// and the envelope is passed on with the first tx to peers taking
+	// envelopes, as peer advertised it does, the txs one by one to the others
+	elem := sidecar.TxsFront()
+	first, second := elem.Value.(*SidecarTx), elem.Next().Value.(*SidecarTx)
+	legacy := mock.NewPeer(nil)
+	assert.Equal(t, first.gossipMsg(), reactor.sidecarGossipMsg(legacy, first))
+	assert.Equal(t, second.gossipMsg(), reactor.sidecarGossipMsg(legacy, second))
+	assert.Equal(t, env.gossipMsg(), reactor.sidecarGossipMsg(peer, first))
+	assert.Nil(t, reactor.sidecarGossipMsg(peer, second))
+
+	// and its extensions are reported with the bundle by the auction
+	_, candidates := sidecar.ReapAuction()
+	require.Len(t, candidates, 1)
+	assert.Equal(t, types.AuctionBundleIncluded, candidates[0].Status)
+	assert.Equal(t, env.Extensions, candidates[0].Extensions)
+
+	// bundles are added for the first height of their range if still ahead,
+	// and refused past it
+	height, err := env.height(0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, height)
+	_, err = env.height(4)
+	assert.IsType(t, ErrWrongHeight{}, err)
+
+	// if a tx is refused, those added before it are removed, leaving those
+	// of the bundle added otherwise
+	require.NoError(t, sidecar.AddTx(types.Tx("x"), TxInfo{DesiredHeight: 1, BundleId: 5, BundleOrder: 1, BundleSize: 2}))
+	clash := &BundleEnvelope{Txs: types.Txs{types.Tx("e"), types.Tx("f")}, MinHeight: 1, MaxHeight: 1, BundleID: 5}
+	require.NoError(t, clash.Sign(relayKey))
+	_, err = reactor.SubmitEnvelope(clash)
+	assert.Error(t, err)
+	assert.Equal(t, 3, sidecar.Size())
+	assert.Equal(t, 1, sidecar.GetCurrBundleSize(5))
+
+	// placements other than the mev lane's aren't supported yet
+	top := &BundleEnvelope{Txs: types.Txs{types.Tx("c")}, MinHeight: 1, MaxHeight: 1,
+		Placement: memproto.BundlePlacement_BUNDLE_PLACEMENT_TOP_OF_BLOCK}
+	require.NoError(t, top.Sign(relayKey))
+	_, err = reactor.SubmitEnvelope(top)
+	assert.True(t, errors.Is(err, ErrBundlePlacementUnsupported))
+}
+
+func TestBundleSubmissionServer(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.Broadcast = false
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, sidecar, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	reactor := NewReactor(config.Mempool, mempool, sidecar)
+	reactor.SetLogger(log.TestingLogger())
+	relayKey := GenFileRelayKey()
+	reactor.SetRelayAllowlist([]p2p.ID{p2p.PubKeyToID(relayKey.PubKey())})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		_ = StartBundleSubmissionServer(ln, reactor)
+	}()
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := memproto.NewBundleSubmissionClient(conn)
+	submit := func(env *BundleEnvelope) (*memproto.ResponseSubmitBundle, codes.Code) {
+		pb, err := env.ToProto()
+		require.NoError(t, err)
+		res, err := client.SubmitBundle(context.Background(), &memproto.RequestSubmitBundle{Envelope: pb})
+		return res, status.Code(err)
+	}
+
+	// bundles submitted by the relay are added, and acknowledged
+	env := &BundleEnvelope{Txs: types.Txs{types.Tx("a"), types.Tx("b")}, MinHeight: 1, MaxHeight: 3, BundleID: 1}
+	require.NoError(t, env.Sign(relayKey))
+	res, code := submit(env)
+	require.Equal(t, codes.OK, code)
+	assert.EqualValues(t, 1, res.Height)
+	assert.Equal(t, env.Txs.Hash(), res.Hash)
+	assert.EqualValues(t, 2, sidecar.Size())
+
+	// once only
+	_, code = submit(env)
+	assert.Equal(t, codes.AlreadyExists, code)
+
+	// those not signed by a relay allowed, or not at all, are refused
+	other := &BundleEnvelope{Txs: types.Txs{types.Tx("c")}, MinHeight: 1, MaxHeight: 1}
+	require.NoError(t, other.Sign(GenFileRelayKey()))
+	_, code = submit(other)
+	assert.Equal(t, codes.PermissionDenied, code)
+	other.Signature = nil
+	_, code = submit(other)
+	assert.Equal(t, codes.InvalidArgument, code)
+
+	// as are those the sidecar refuses
+	past := &BundleEnvelope{Txs: types.Txs{types.Tx("d")}, MinHeight: 1, MaxHeight: 1}
+	require.NoError(t, past.Sign(relayKey))
+	require.NoError(t, sidecar.Update(1, nil, nil))
+	_, code = submit(past)
+	assert.Equal(t, codes.FailedPrecondition, code)
+}
+
+func TestSidecarChainID(t *testing.T) {
+	config := cfg.TestConfig()
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, _, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	invalid := newCountersByLabels()
+	mempool.metrics.SidecarPeerInvalidMessages = invalid
+	sidecar := NewCListSidecar(0, WithSidecarChainID("chain-a"))
+	reactor := NewReactor(config.Mempool, mempool, sidecar)
+	reactor.SetLogger(log.TestingLogger())
+	peer := mock.NewPeer(nil)
+
+	// txs are gossiped tagged with the sidecar's chain
+	require.NoError(t, sidecar.AddTx(types.Tx("ours"), TxInfo{DesiredHeight: 1, BundleSize: 1}))
+	scTx := sidecar.TxsFront().Value.(*SidecarTx)
+	msg, _, err := reactor.decodeBundleMsg(scTx.gossipMsg())
+	require.NoError(t, err)
+	assert.Equal(t, "chain-a", msg.(MEVTxsMessage).ChainID)
+
+	// and those tagged for another chain are refused, untagged ones taken
+	other, _ := encodeSidecarTxMsg(&SidecarTx{desiredHeight: 1, bundleId: 1, bundleSize: 1, tx: types.Tx("b")},
+		"chain-b")
+	reactor.Receive(SidecarChannel, peer, other)
+	assert.EqualValues(t, 1, sidecar.Size())
+	assert.EqualValues(t, 1, invalid.value(string(peer.ID()), "wrong_chain"))
+	untagged, _ := encodeSidecarTxMsg(&SidecarTx{desiredHeight: 1, bundleId: 2, bundleSize: 1, tx: types.Tx("u")}, "")
+	reactor.Receive(SidecarChannel, peer, untagged)
+	assert.EqualValues(t, 2, sidecar.Size())
+
+	// as are envelopes, whose chain is signed over
+	relayKey := GenFileRelayKey()
+	env := &BundleEnvelope{Txs: types.Txs{types.Tx("e")}, MinHeight: 1, MaxHeight: 1, BundleID: 3, ChainID: "chain-b"}
+	require.NoError(t, env.Sign(relayKey))
+	reactor.Receive(SidecarChannel, peer, env.gossipMsg())
+	assert.EqualValues(t, 2, invalid.value(string(peer.ID()), "wrong_chain"))
+	_, err = reactor.SubmitEnvelope(env)
+	assert.True(t, errors.Is(err, ErrBundleForOtherChain))
+	forged := &BundleEnvelope{Txs: env.Txs, MinHeight: 1, MaxHeight: 1, BundleID: 3, ChainID: "chain-a",
+		PubKey: env.PubKey, Signature: env.Signature}
+	assert.Equal(t, ErrBundleEnvelopeInvalidSignature, forged.Verify())
+	env = &BundleEnvelope{Txs: env.Txs, MinHeight: 1, MaxHeight: 1, BundleID: 3, ChainID: "chain-a"}
+	require.NoError(t, env.Sign(relayKey))
+	_, err = reactor.SubmitEnvelope(env)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, sidecar.Size())
+}
+
+func TestReceiptsVerifiedBeforeForwarding(t *testing.T) {
+	config := cfg.TestConfig()
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, _, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	invalid := newCountersByLabels()
+	mempool.metrics.SidecarPeerInvalidMessages = invalid
+	reactor := NewReactor(config.Mempool, mempool, NewCListSidecar(0, WithSidecarChainID("chain-a")))
+	reactor.SetLogger(log.TestingLogger())
+	peer := mock.NewPeer(nil)
+
+	key := ed25519.GenPrivKey()
+	signed := func(height int64, chainID string) types.BundleReceipt {
+		receipt := types.BundleReceipt{
+			Height:     height,
+			BlockHash:  tmhash.Sum([]byte("block")),
+			BundleID:   2,
+			BundleHash: tmhash.Sum([]byte("bundle")),
+			Size:       1,
+		}
+		require.NoError(t, receipt.Sign(chainID, key))
+		return receipt
+	}
+	good := signed(1, "chain-a")
+	tampered := signed(1, "chain-a")
+	tampered.Position = 3
+
+	// only receipts signed for this chain, for a height it produced, are kept
+	valid := reactor.validReceipts(peer, []types.BundleReceipt{
+		signed(1, "chain-b"), good, signed(2, "chain-a"), tampered,
+	})
+	assert.Equal(t, []types.BundleReceipt{good}, valid)
+	assert.EqualValues(t, 3, invalid.value(string(peer.ID()), "bad_receipt"))
+}
+
+func TestRelayRegistrationSigned(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.Broadcast = false
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, _, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	reactor := NewReactor(config.Mempool, mempool, NewCListSidecar(5))
+	reactor.SetLogger(log.TestingLogger())
+
+	var relaySent [][]byte
+	relay := recordingPeer{mock.NewPeer(nil), &relaySent}
+	key := GenFileRelayKey()
+	reactor.SetRelay(relay.ID(), "")
+	reactor.SetRelaySigner(key)
+
+	// the relay key is enough to register, and signs the registration
+	reactor.AddPeer(relay)
+	require.Len(t, relaySent, 1)
+	msg, _, err := reactor.decodeBundleMsg(relaySent[0])
+	require.NoError(t, err)
+	reg := msg.(MEVRegistrationMessage)
+	assert.True(t, key.PubKey().Equals(reg.PubKey))
+	assert.NoError(t, reg.Verify(reg.NodeID))
+
+	// which can't be presented by another node, nor tampered with
+	assert.Equal(t, ErrRelayRegistrationInvalidSignature, reg.Verify("other"))
+	reg.APIKey = "stolen"
+	assert.Equal(t, ErrRelayRegistrationInvalidSignature, reg.Verify(reg.NodeID))
+
+	// unsigned registrations are left to the API key
+	assert.NoError(t, MEVRegistrationMessage{APIKey: "secret"}.Verify("other"))
+}
+
+func TestSidecarSelfTest(t *testing.T) {
+	config := cfg.TestConfig()
+	reactors := makeAndConnectReactors(config, 2)
+	defer func() {
+		for _, r := range reactors {
+			assert.NoError(t, r.Stop())
+		}
+	}()
+	sw := reactors[0].Switch
+	peerID := sw.Peers().List()[0].ID()
+	const missingID = p2p.ID("0123456789abcdef0123456789abcdef01234567")
+	sw.SetSidecarPeers(p2p.SidecarPeers{peerID: {}, missingID: {}})
+	reactors[0].SetRelay(peerID, "")
+
+	// the connected peer answers the ping, the other fails the test
+	st := reactors[0].SidecarSelfTest(context.Background())
+	require.Len(t, st.Peers, 2)
+	missing, peer := st.Peers[0], st.Peers[1]
+	if missing.ID != missingID {
+		missing, peer = peer, missing
+	}
+	assert.Equal(t, SidecarPeerCheck{ID: missingID, Err: errSidecarPeerNotConnected}, missing)
+	assert.NoError(t, peer.Err)
+	assert.True(t, peer.Relay)
+	assert.True(t, peer.Connected)
+	assert.True(t, peer.Channel)
+	assert.Positive(t, peer.RTT)
+	assert.False(t, st.Passed())
+
+	sw.SetSidecarPeers(p2p.SidecarPeers{peerID: {}})
+	assert.True(t, reactors[0].SidecarSelfTest(context.Background()).Passed())
+
+	// nothing is checked while MEV is off
+	reactors[0].SetMEVDisabled(true)
+	st = reactors[0].SidecarSelfTest(context.Background())
+	assert.True(t, st.MEVDisabled)
+	assert.Empty(t, st.Peers)
+	assert.False(t, st.Passed())
+}
+
 func TestMempoolIDsBasic(t *testing.T) {
 	ids := newMempoolIDs()
 
@@ -397,6 +1346,48 @@ func makeAndConnectReactorsEvensSidecar(config *cfg.Config, n int) []*Reactor {
 }
 
 // connect N mempool reactors through N switches
+func TestReactorNoSidecarBroadcastToMEVDisabledPeer(t *testing.T) {
+	config := cfg.TestConfig()
+	const N = 2
+	reactors := make([]*Reactor, N)
+	for i := 0; i < N; i++ {
+		app := kvstore.NewApplication()
+		cc := proxy.NewLocalClientCreator(app)
+		mempool, sidecar, cleanup := newMempoolWithApp(cc)
+		defer cleanup()
+
+		reactors[i] = NewReactor(config.Mempool, mempool, sidecar)
+		reactors[i].SetLogger(mempoolLogger().With("validator", i))
+	}
+	// the second node opts out of MEV
+	reactors[1].SetMEVDisabled(true)
+
+	p2p.MakeConnectedSwitches(config.P2P, N, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("MEMPOOL", reactors[i])
+		return s
+	}, func(switches []*p2p.Switch, i, j int) {
+		ni := switches[1].NodeInfo().(p2p.DefaultNodeInfo)
+		ni.Other.MEVDisabled = true
+		switches[1].SetNodeInfo(ni)
+		p2p.Connect2Switches(switches, i, j)
+	})
+	defer func() {
+		for _, r := range reactors {
+			if err := r.Stop(); err != nil {
+				assert.NoError(t, err)
+			}
+		}
+	}()
+
+	peer := reactors[0].Switch.Peers().List()[0]
+	require.True(t, peerMEVDisabled(peer))
+
+	addNumBundlesToSidecar(t, reactors[0].sidecar, 2, 5, UnknownPeerID)
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, 10, reactors[0].sidecar.Size())
+	assert.Zero(t, reactors[1].sidecar.Size())
+}
+
 // can add additional logic to set which ones should be treated as Sidecar
 // peers in p2p.Connect2Switches, including based on index
 func makeAndConnectReactors(config *cfg.Config, n int) []*Reactor {
@@ -508,3 +1499,323 @@ func TestMempoolVectors(t *testing.T) {
 		require.Equal(t, tc.expBytes, hex.EncodeToString(bz), tc.testName)
 	}
 }
+
+func TestGossipMsgs(t *testing.T) {
+	tx := types.Tx("proto encoding in mempool")
+
+	// a mempool tx's message is the one gossiped before, and the tx aliases it
+	bz, aliased := encodeTxMsg(tx)
+	assert.Equal(t, "0a1b0a1970726f746f20656e636f64696e6720696e206d656d706f6f6c", hex.EncodeToString(bz))
+	assert.Equal(t, tx, aliased)
+	assert.True(t, &bz[len(bz)-len(tx)] == &aliased[0], "tx doesn't alias its message")
+
+	// and so is a sidecar tx's
+	scTx := &SidecarTx{desiredHeight: 10, bundleId: 2, bundleOrder: 1, bundleSize: 3, bid: 500, tx: tx}
+	bz, aliased = encodeSidecarTxMsg(scTx, "")
+	expMsg := memproto.MEVMessage{
+		Sum:           &memproto.MEVMessage_Txs{Txs: &memproto.Txs{Txs: [][]byte{tx}}},
+		DesiredHeight: 10,
+		BundleId:      2,
+		BundleOrder:   1,
+		BundleSize:    3,
+		Bid:           500,
+		Version:       version.SidecarProtocol,
+		Capabilities:  uint64(LocalSidecarCapabilities),
+	}
+	var gossiped memproto.MEVMessage
+	require.NoError(t, gossiped.Unmarshal(bz))
+	assert.Equal(t, expMsg, gossiped)
+	assert.True(t, &bz[len(bz)-len(tx)] == &aliased[0], "tx doesn't alias its message")
+
+	// tagged with the chain it's for, if set
+	bz, aliased = encodeSidecarTxMsg(scTx, "test-chain")
+	expMsg.ChainId = "test-chain"
+	gossiped = memproto.MEVMessage{}
+	require.NoError(t, gossiped.Unmarshal(bz))
+	assert.Equal(t, expMsg, gossiped)
+	assert.True(t, &bz[len(bz)-len(tx)] == &aliased[0], "tx doesn't alias its message")
+
+	// received messages are decoded as the generated code would, their txs
+	// aliasing a copy of them
+	memR := &Reactor{}
+	txs := [][]byte{tx, {}, []byte("second")}
+	bz, err := (&memproto.Message{Sum: &memproto.Message_Txs{Txs: &memproto.Txs{Txs: txs}}}).Marshal()
+	require.NoError(t, err)
+	msg, err := memR.decodeMsg(bz)
+	require.NoError(t, err)
+	assert.Equal(t, types.Txs{tx, types.Tx{}, types.Tx("second")}, types.Txs(msg.Txs))
+	bz[len(bz)-1] = 'x'
+	assert.Equal(t, types.Tx("second"), msg.Txs[2], "received tx aliases the connection's buffer")
+
+	expMsg.Sum = &memproto.MEVMessage_Txs{Txs: &memproto.Txs{Txs: txs}}
+	bz, err = expMsg.Marshal()
+	require.NoError(t, err)
+	mevMsg, protocol, err := memR.decodeBundleMsg(bz)
+	require.NoError(t, err)
+	assert.Equal(t, localSidecarProtocol, protocol)
+	assert.Equal(t, MEVTxsMessage{
+		Txs:           []types.Tx{tx, types.Tx{}, types.Tx("second")},
+		DesiredHeight: 10,
+		BundleId:      2,
+		BundleOrder:   1,
+		BundleSize:    3,
+		Bid:           500,
+		ChainID:       "test-chain",
+	}, mevMsg)
+
+	receipt := types.BundleReceipt{
+		Height:     10,
+		BlockHash:  tmhash.Sum([]byte("block")),
+		BundleID:   2,
+		BundleHash: tmhash.Sum([]byte("bundle")),
+		Size:       3,
+	}
+	require.NoError(t, receipt.Sign("test_chain_id", ed25519.GenPrivKey()))
+	pbReceipt, err := receipt.ToProto()
+	require.NoError(t, err)
+	bz, err = (&memproto.MEVMessage{Sum: &memproto.MEVMessage_Receipts{
+		Receipts: &memproto.BundleReceipts{Receipts: []*tmproto.BundleReceipt{pbReceipt}},
+	}}).Marshal()
+	require.NoError(t, err)
+	mevMsg, _, err = memR.decodeBundleMsg(bz)
+	require.NoError(t, err)
+	assert.Equal(t, MEVReceiptsMessage{Receipts: []types.BundleReceipt{receipt}}, mevMsg)
+
+	// and malformed ones are refused
+	for _, bz := range [][]byte{
+		{},                 // no txs
+		{0x0a, 0x00},       // empty txs
+		{0x0a, 0x05, 0x0a}, // truncated
+		{0x08, 0x01},       // wrong wire type
+	} {
+		_, err = memR.decodeMsg(bz)
+		assert.Error(t, err, "%X", bz)
+		_, _, err = memR.decodeBundleMsg(bz)
+		assert.Error(t, err, "%X", bz)
+	}
+}
+
+func TestMEVMessageFields(t *testing.T) {
+	// the field numbers decodeBundleMsg goes by are the generated code's
+	fields := map[string]int32{
+		"txs":            mevMsgTxsField,
+		"desired_height": mevMsgDesiredHeightField,
+		"bundle_id":      mevMsgBundleIDField,
+		"bundle_order":   mevMsgBundleOrderField,
+		"bundle_size":    mevMsgBundleSizeField,
+		"bid":            mevMsgBidField,
+		"receipts":       mevMsgReceiptsField,
+		"registration":   mevMsgRegistrationField,
+		"ping":           mevMsgPingField,
+		"pong":           mevMsgPongField,
+		"version":        mevMsgVersionField,
+		"capabilities":   mevMsgCapabilitiesField,
+		"envelope":       mevMsgEnvelopeField,
+		"chain_id":       mevMsgChainIDField,
+	}
+	generated := make(map[string]int32)
+	props := proto.GetProperties(reflect.TypeOf(memproto.MEVMessage{}))
+	for _, prop := range props.Prop {
+		if prop.Tag > 0 {
+			generated[prop.OrigName] = int32(prop.Tag)
+		}
+	}
+	for _, oneof := range props.OneofTypes {
+		generated[oneof.Prop.OrigName] = int32(oneof.Prop.Tag)
+	}
+	assert.Equal(t, fields, generated)
+
+	// and every message type round-trips through them
+	receipt := types.BundleReceipt{
+		Height:     10,
+		BlockHash:  tmhash.Sum([]byte("block")),
+		BundleID:   2,
+		BundleHash: tmhash.Sum([]byte("bundle")),
+		Size:       3,
+	}
+	require.NoError(t, receipt.Sign("test_chain_id", ed25519.GenPrivKey()))
+	pbReceipt, err := receipt.ToProto()
+	require.NoError(t, err)
+	regKey := ed25519.GenPrivKey()
+	pbRegKey, err := cryptoenc.PubKeyToProto(regKey.PubKey())
+	require.NoError(t, err)
+	env := &BundleEnvelope{Txs: types.Txs{types.Tx("e")}, MinHeight: 1, MaxHeight: 2, Bid: 5, BundleID: 3,
+		ChainID: "test-chain"}
+	require.NoError(t, env.Sign(GenFileRelayKey()))
+	pbEnv, err := env.ToProto()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		msg    memproto.MEVMessage
+		expMsg interface{}
+	}{
+		{
+			memproto.MEVMessage{
+				Sum:           &memproto.MEVMessage_Txs{Txs: &memproto.Txs{Txs: [][]byte{[]byte("tx")}}},
+				DesiredHeight: 10,
+				BundleId:      2,
+				BundleOrder:   1,
+				BundleSize:    3,
+				Bid:           500,
+				ChainId:       "test-chain",
+			},
+			MEVTxsMessage{Txs: []types.Tx{types.Tx("tx")}, DesiredHeight: 10, BundleId: 2, BundleOrder: 1,
+				BundleSize: 3, Bid: 500, ChainID: "test-chain"},
+		},
+		{
+			memproto.MEVMessage{Sum: &memproto.MEVMessage_Receipts{
+				Receipts: &memproto.BundleReceipts{Receipts: []*tmproto.BundleReceipt{pbReceipt}},
+			}},
+			MEVReceiptsMessage{Receipts: []types.BundleReceipt{receipt}},
+		},
+		{
+			memproto.MEVMessage{Sum: &memproto.MEVMessage_Registration{Registration: &memproto.RelayRegistration{
+				ApiKey: "secret", NodeID: "node", PubKey: &pbRegKey, Signature: []byte("sig"),
+				MaxBundleHeightsAhead: 4,
+			}}},
+			MEVRegistrationMessage{APIKey: "secret", NodeID: "node", PubKey: regKey.PubKey(),
+				Signature: []byte("sig"), MaxBundleHeightsAhead: 4},
+		},
+		{
+			memproto.MEVMessage{Sum: &memproto.MEVMessage_Ping{Ping: &memproto.SidecarPing{Nonce: 7}}},
+			MEVPingMessage{Nonce: 7},
+		},
+		{
+			memproto.MEVMessage{Sum: &memproto.MEVMessage_Pong{Pong: &memproto.SidecarPong{Nonce: 7}}},
+			MEVPingMessage{Nonce: 7, Pong: true},
+		},
+		{
+			memproto.MEVMessage{Sum: &memproto.MEVMessage_Envelope{Envelope: pbEnv}},
+			MEVEnvelopeMessage{Envelope: env},
+		},
+	}
+	memR := &Reactor{}
+	for _, tc := range testCases {
+		tc := tc
+		mevMsg, protocol, err := memR.decodeBundleMsg(marshalMEVMessage(&tc.msg))
+		require.NoError(t, err, "%T", tc.msg.Sum)
+		assert.Equal(t, localSidecarProtocol, protocol, "%T", tc.msg.Sum)
+		assert.Equal(t, tc.expMsg, mevMsg, "%T", tc.msg.Sum)
+	}
+}
+
+// countersByLabels is a metrics.Counter keeping a count for each of the sets
+// of label values it's counted with.
+//
+// Counts are looked up by the values alone, eg. value("a") for the count
+// With("label", "a").
+type countersByLabels struct {
+	mtx         *sync.Mutex
+	counts      map[string]float64
+	labelValues []string
+}
+
+func newCountersByLabels() *countersByLabels {
+	return &countersByLabels{mtx: &sync.Mutex{}, counts: make(map[string]float64)}
+}
+
+func (c *countersByLabels) With(labelValues ...string) metrics.Counter {
+	return &countersByLabels{
+		mtx:         c.mtx,
+		counts:      c.counts,
+		labelValues: append(append([]string{}, c.labelValues...), labelValues...),
+	}
+}
+
+func (c *countersByLabels) Add(delta float64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	values := make([]string, 0, len(c.labelValues)/2)
+	for i := 1; i < len(c.labelValues); i += 2 {
+		values = append(values, c.labelValues[i])
+	}
+	c.counts[strings.Join(values, ",")] += delta
+}
+
+func (c *countersByLabels) value(values ...string) float64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.counts[strings.Join(values, ",")]
+}
+
+func TestReactorCountsSidecarPeerMessages(t *testing.T) {
+	config := cfg.TestConfig()
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, sidecar, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	messages, bytes, invalid := newCountersByLabels(), newCountersByLabels(), newCountersByLabels()
+	mempool.metrics.SidecarPeerMessages = messages
+	mempool.metrics.SidecarPeerBytes = bytes
+	mempool.metrics.SidecarPeerInvalidMessages = invalid
+
+	reactor := NewReactor(config.Mempool, mempool, sidecar)
+	reactor.SetLogger(log.TestingLogger())
+	p2p.MakeConnectedSwitches(config.P2P, 1, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("MEMPOOL", reactor)
+		return s
+	}, p2p.Connect2Switches)
+	defer func() {
+		assert.NoError(t, reactor.Stop())
+	}()
+
+	peer, other := mock.NewPeer(nil), mock.NewPeer(nil)
+	valid, _ := encodeSidecarTxMsg(&SidecarTx{desiredHeight: 1, bundleSize: 1, tx: types.Tx("valid")}, "")
+	malformed, _ := encodeSidecarTxMsg(&SidecarTx{desiredHeight: 1, bundleOrder: 1, bundleSize: 1,
+		tx: types.Tx("bad")}, "")
+	reactor.Receive(SidecarChannel, peer, valid)
+	reactor.Receive(SidecarChannel, peer, malformed)
+	reactor.Receive(SidecarChannel, peer, []byte{0x1, 0x2, 0x3})
+	empty, _ := encodeSidecarTxMsg(&SidecarTx{desiredHeight: 1, tx: types.Tx("empty")}, "")
+	reactor.Receive(SidecarChannel, peer, empty)
+	other.SidecarPeer = false
+	reactor.Receive(SidecarChannel, other, valid)
+
+	assert.EqualValues(t, 1, sidecar.Size())
+	assert.EqualValues(t, 4, messages.value(string(peer.ID())))
+	assert.EqualValues(t, len(valid)+len(malformed)+3+len(empty), bytes.value(string(peer.ID())))
+	assert.EqualValues(t, 1, invalid.value(string(peer.ID()), "malformed"))
+	assert.EqualValues(t, 1, invalid.value(string(peer.ID()), "undecodable"))
+	assert.EqualValues(t, 1, invalid.value(string(peer.ID()), "bundle_size"))
+	assert.EqualValues(t, 1, messages.value(string(other.ID())))
+	assert.EqualValues(t, 1, invalid.value(string(other.ID()), "not_sidecar_peer"))
+}
+
+func TestReactorModes(t *testing.T) {
+	config := cfg.TestConfig()
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, _, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	newReactor := func(mode string) *Reactor {
+		sidecarConfig := cfg.TestSidecarConfig()
+		sidecarConfig.Mode = mode
+		reactor := NewReactor(config.Mempool, mempool, NewCListSidecar(0, WithSidecarConfig(sidecarConfig)))
+		reactor.SetLogger(log.TestingLogger())
+		return reactor
+	}
+	searcher := mock.NewPeer(nil)
+	searcher.SidecarPeer = false
+	msg, _ := encodeSidecarTxMsg(&SidecarTx{desiredHeight: 1, bundleSize: 1, tx: types.Tx("bundle")}, "")
+
+	// relays take bundles from any peer, but never include them
+	relay := newReactor(cfg.NodeModeRelay)
+	relay.Receive(SidecarChannel, searcher, msg)
+	assert.EqualValues(t, 1, relay.sidecar.Size())
+	assert.Empty(t, relay.sidecar.ReapMaxTxs())
+
+	// sentries only take them from their sidecar peers
+	sentry := newReactor(cfg.NodeModeSentry)
+	sentry.Receive(SidecarChannel, searcher, msg)
+	assert.Zero(t, sentry.sidecar.Size())
+	sentry.Receive(SidecarChannel, mock.NewPeer(nil), msg)
+	assert.EqualValues(t, 1, sentry.sidecar.Size())
+	assert.Empty(t, sentry.sidecar.ReapMaxTxs())
+
+	// which validators include
+	validator := newReactor(cfg.NodeModeValidator)
+	validator.Receive(SidecarChannel, searcher, msg)
+	assert.Zero(t, validator.sidecar.Size())
+	validator.Receive(SidecarChannel, mock.NewPeer(nil), msg)
+	assert.Len(t, validator.sidecar.ReapMaxTxs(), 1)
+}
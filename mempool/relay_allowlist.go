@@ -0,0 +1,68 @@
+package mempool
+
+import (
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/p2p"
+)
+
+// SetRelayAllowlist sets the node IDs of the relays this node may take as its
+// relay, see SidecarConfig.RelayPubKeys, none allowing any. Sidecar messages
+// from a relay not on the list are refused, and it isn't registered with.
+//
+// Safe for concurrent use by multiple goroutines.
+func (memR *Reactor) SetRelayAllowlist(ids []p2p.ID) {
+	var allowlist map[p2p.ID]struct{}
+	if len(ids) > 0 {
+		allowlist = make(map[p2p.ID]struct{}, len(ids))
+		for _, id := range ids {
+			allowlist[id] = struct{}{}
+		}
+	}
+	memR.relayMtx.Lock()
+	memR.relayAllowlist = allowlist
+	memR.relayMtx.Unlock()
+}
+
+// relayAllowed returns false if peerID is the relay, but isn't allowed to be.
+func (memR *Reactor) relayAllowed(peerID p2p.ID) bool {
+	memR.relayMtx.RLock()
+	defer memR.relayMtx.RUnlock()
+	if memR.relayAllowlist == nil || memR.relay == nil || memR.relay.relayID != peerID {
+		return true
+	}
+	_, ok := memR.relayAllowlist[peerID]
+	return ok
+}
+
+// envelopeSignerAllowed returns true if the bundle envelopes signed with
+// pubKey are taken: if it's the node key of a relay allowed, see
+// SetRelayAllowlist, or if no allowlist is set.
+//
+// Safe for concurrent use by multiple goroutines.
+func (memR *Reactor) envelopeSignerAllowed(pubKey crypto.PubKey) bool {
+	memR.relayMtx.RLock()
+	noAllowlist := memR.relayAllowlist == nil
+	memR.relayMtx.RUnlock()
+	return noAllowlist || memR.relayListed(p2p.PubKeyToID(pubKey))
+}
+
+// relayListed returns true if the relay relayID is on the allowlist, see
+// SetRelayAllowlist, false if none is set.
+//
+// Safe for concurrent use by multiple goroutines.
+func (memR *Reactor) relayListed(relayID p2p.ID) bool {
+	memR.relayMtx.RLock()
+	defer memR.relayMtx.RUnlock()
+	_, ok := memR.relayAllowlist[relayID]
+	return ok
+}
+
+// relayAllowlistIDs returns the node IDs of the relays allowed by the config,
+// which has been validated, see SidecarConfig.ValidateBasic.
+func relayAllowlistIDs(allowlist []string) []p2p.ID {
+	ids := make([]p2p.ID, len(allowlist))
+	for i, id := range allowlist {
+		ids[i] = p2p.ID(id)
+	}
+	return ids
+}
@@ -0,0 +1,105 @@
+package mempool
+
+import (
+	"errors"
+	"io/ioutil"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/libs/protoio"
+	"github.com/tendermint/tendermint/p2p"
+	protomem "github.com/tendermint/tendermint/proto/tendermint/mempool"
+)
+
+// ErrRelayRegistrationInvalidSignature means a registration wasn't signed by
+// the relay key it carries, or not for the node presenting it.
+var ErrRelayRegistrationInvalidSignature = errors.New("invalid relay registration signature")
+
+// RelaySigner signs this node's registrations with the relay, with a key used
+// for nothing else, so relay credentials can be rotated independently of the
+// node and validator keys. Implementations may keep the key out of the node,
+// eg. in an external signer.
+type RelaySigner interface {
+	PubKey() crypto.PubKey
+	Sign(msg []byte) ([]byte, error)
+}
+
+// FileRelayKey is a RelaySigner whose key is stored in a file, see
+// SidecarConfig.RelayKeyFile.
+type FileRelayKey struct {
+	PrivKey crypto.PrivKey `json:"priv_key"`
+}
+
+var _ RelaySigner = (*FileRelayKey)(nil)
+
+// GenFileRelayKey returns a new relay key.
+func GenFileRelayKey() *FileRelayKey {
+	return &FileRelayKey{PrivKey: ed25519.GenPrivKey()}
+}
+
+// LoadFileRelayKey loads the relay key stored in filePath.
+func LoadFileRelayKey(filePath string) (*FileRelayKey, error) {
+	jsonBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	key := new(FileRelayKey)
+	if err := tmjson.Unmarshal(jsonBytes, key); err != nil {
+		return nil, err
+	}
+	if key.PrivKey == nil {
+		return nil, errors.New("missing priv_key")
+	}
+	return key, nil
+}
+
+// SaveAs persists the relay key to filePath.
+func (key *FileRelayKey) SaveAs(filePath string) error {
+	jsonBytes, err := tmjson.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, jsonBytes, 0600)
+}
+
+// PubKey implements RelaySigner.
+func (key *FileRelayKey) PubKey() crypto.PubKey {
+	return key.PrivKey.PubKey()
+}
+
+// Sign implements RelaySigner.
+func (key *FileRelayKey) Sign(msg []byte) ([]byte, error) {
+	return key.PrivKey.Sign(msg)
+}
+
+// RelayRegistrationSignBytes returns the proto-encoding of the canonicalized
+// registration, for signing. Panics if the marshaling fails.
+func RelayRegistrationSignBytes(apiKey string, nodeID p2p.ID) []byte {
+	pb := protomem.CanonicalRelayRegistration{
+		ApiKey: apiKey,
+		NodeID: string(nodeID),
+	}
+	bz, err := protoio.MarshalDelimited(&pb)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// signRelayRegistration signs reg, for the node nodeID, with signer.
+func signRelayRegistration(reg *protomem.RelayRegistration, nodeID p2p.ID, signer RelaySigner) error {
+	sig, err := signer.Sign(RelayRegistrationSignBytes(reg.ApiKey, nodeID))
+	if err != nil {
+		return err
+	}
+	pk, err := cryptoenc.PubKeyToProto(signer.PubKey())
+	if err != nil {
+		return err
+	}
+	reg.NodeID = string(nodeID)
+	reg.PubKey = &pk
+	reg.Signature = sig
+	return nil
+}
@@ -0,0 +1,156 @@
+package mempool
+
+import (
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/p2p"
+)
+
+// relayMonitorInterval is how often the age of the relay's last message is
+// reported.
+const relayMonitorInterval = time.Second
+
+// relayMonitor reports the liveness of the relay configured with
+// SidecarConfig.RelayerID, so the bundle feed drying up, eg. because the
+// relay is connected but silent, can be alerted on.
+type relayMonitor struct {
+	mtx tmsync.Mutex
+
+	relayID p2p.ID
+	// the relay's metrics, labeled with its ID
+	connectedGauge metrics.Gauge
+	ageGauge       metrics.Gauge
+	reconnects     metrics.Counter
+
+	// whether the relay connected since the monitor was created
+	everConnected bool
+	// last sidecar message received from the relay, or when the monitor was
+	// created if none was
+	lastMessage time.Time
+}
+
+func newRelayMonitor(relayID p2p.ID, m *Metrics, now time.Time) *relayMonitor {
+	rm := &relayMonitor{
+		relayID:        relayID,
+		connectedGauge: m.RelayConnected.With("relay_id", string(relayID)),
+		ageGauge:       m.RelayLastMessageAge.With("relay_id", string(relayID)),
+		reconnects:     m.RelayReconnects.With("relay_id", string(relayID)),
+		lastMessage:    now,
+	}
+	rm.connectedGauge.Set(0)
+	return rm
+}
+
+// peerAdded records peer connecting, if it's the relay.
+func (rm *relayMonitor) peerAdded(peer p2p.Peer) {
+	if peer.ID() != rm.relayID {
+		return
+	}
+	rm.mtx.Lock()
+	defer rm.mtx.Unlock()
+	if rm.everConnected {
+		rm.reconnects.Add(1)
+	}
+	rm.everConnected = true
+	rm.connectedGauge.Set(1)
+}
+
+// peerRemoved records peer disconnecting, if it's the relay.
+func (rm *relayMonitor) peerRemoved(peer p2p.Peer) {
+	if peer.ID() != rm.relayID {
+		return
+	}
+	rm.connectedGauge.Set(0)
+}
+
+// messageReceived records a sidecar message received from src at now, if it's
+// the relay.
+func (rm *relayMonitor) messageReceived(src p2p.Peer, now time.Time) {
+	if src.ID() != rm.relayID {
+		return
+	}
+	rm.mtx.Lock()
+	defer rm.mtx.Unlock()
+	rm.lastMessage = now
+}
+
+// report sets the age of the relay's last message as of now.
+func (rm *relayMonitor) report(now time.Time) {
+	rm.mtx.Lock()
+	defer rm.mtx.Unlock()
+	rm.ageGauge.Set(now.Sub(rm.lastMessage).Seconds())
+}
+
+// relayMonitorRoutine reports the age of the relay's last message, if one is
+// configured, until the reactor stops.
+func (memR *Reactor) relayMonitorRoutine() {
+	ticker := time.NewTicker(relayMonitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			if relay := memR.relayMonitor(); relay != nil {
+				relay.report(now)
+			}
+		case <-memR.Quit():
+			return
+		}
+	}
+}
+
+// relayMonitor returns the monitor of the configured relay, or nil if none is.
+func (memR *Reactor) relayMonitor() *relayMonitor {
+	memR.relayMtx.RLock()
+	defer memR.relayMtx.RUnlock()
+	return memR.relay
+}
+
+// relayerID returns the ID of the configured relay, or "" if none is.
+func (memR *Reactor) relayerID() p2p.ID {
+	if relay := memR.relayMonitor(); relay != nil {
+		return relay.relayID
+	}
+	return ""
+}
+
+// SetRelay changes the relay, set by SidecarConfig.RelayerID or RelayAddr,
+// that the bundles are expected from and the receipts sent back to, "" for
+// none, and the API key this node registers with it, set by
+// SidecarConfig.RelayAPIKey. Its liveness is reported from scratch. If the
+// relay is connected, the node registers with it again under the new key.
+//
+// Safe for concurrent use by multiple goroutines.
+func (memR *Reactor) SetRelay(relayerID p2p.ID, apiKey string) {
+	memR.relayMtx.Lock()
+	old, oldAPIKey := memR.relay, memR.relayAPIKey
+	memR.relayAPIKey = apiKey
+	if old == nil || old.relayID != relayerID {
+		if old != nil {
+			old.connectedGauge.Set(0)
+		}
+		memR.relay = nil
+		if relayerID != "" {
+			memR.relay = newRelayMonitor(relayerID, memR.mempool.metrics, time.Now())
+		}
+	} else if apiKey == oldAPIKey {
+		memR.relayMtx.Unlock()
+		return
+	}
+	relay := memR.relay
+	memR.relayMtx.Unlock()
+
+	if relay == nil || memR.Switch == nil {
+		return
+	}
+	peer := memR.Switch.Peers().Get(relayerID)
+	if peer == nil {
+		return
+	}
+	if relay != old {
+		relay.peerAdded(peer)
+	}
+	memR.registerWithRelay(peer, apiKey)
+}
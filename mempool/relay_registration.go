@@ -0,0 +1,105 @@
+package mempool
+
+import (
+	"github.com/tendermint/tendermint/p2p"
+	protomem "github.com/tendermint/tendermint/proto/tendermint/mempool"
+)
+
+// relayRegistration returns the ID of the configured relay, and the API key
+// this node registers with it, "" for none.
+func (memR *Reactor) relayRegistration() (p2p.ID, string) {
+	memR.relayMtx.RLock()
+	defer memR.relayMtx.RUnlock()
+	if memR.relay == nil {
+		return "", memR.relayAPIKey
+	}
+	return memR.relay.relayID, memR.relayAPIKey
+}
+
+// registerWithRelay registers this node with peer, the relay, sending it the
+// API key it issued to this validator, and signing the registration with the
+// relay key, if there are. The key is only ever sent to the relay: unlike the
+// node info, it doesn't reach other peers. The registration also tells the
+// relay how many heights ahead bundles are accepted for, see
+// SidecarConfig.MaxBundleHeightsAhead. Nothing is sent while MEV is turned
+// off, nor to a relay not allowed, see SetRelayAllowlist, nor to one speaking
+// Skip Protocol's format, which has no registrations, see SetSkipFormatPeers.
+func (memR *Reactor) registerWithRelay(peer p2p.Peer, apiKey string) {
+	memR.relayMtx.RLock()
+	signer := memR.relaySigner
+	memR.relayMtx.RUnlock()
+	if (apiKey == "" && signer == nil) || memR.sidecar.MEVDisabled() {
+		return
+	}
+	if !memR.relayAllowed(peer.ID()) {
+		memR.Logger.Error("Not registering with a relay not in relay_pub_keys", "peer", peer.ID())
+		return
+	}
+	if memR.speaksSkipFormat(peer.ID()) {
+		return
+	}
+	reg := &protomem.RelayRegistration{
+		ApiKey:                apiKey,
+		MaxBundleHeightsAhead: memR.sidecar.config.MaxBundleHeightsAhead,
+	}
+	if signer != nil {
+		if err := signRelayRegistration(reg, memR.nodeID(), signer); err != nil {
+			memR.Logger.Error("Failed signing relay registration", "peer", peer.ID(), "err", err)
+			return
+		}
+	}
+	msg := protomem.MEVMessage{
+		Sum: &protomem.MEVMessage_Registration{Registration: reg},
+	}
+	bz := marshalMEVMessage(&msg)
+	if !memR.sendSidecar(peer, bz) {
+		memR.Logger.Error("Failed registering with the relay", "peer", peer.ID())
+		return
+	}
+	memR.Logger.Info("Registered with the relay", "peer", peer.ID(), "signed", signer != nil)
+}
+
+// nodeID returns the ID of this node, "" if not known yet.
+func (memR *Reactor) nodeID() p2p.ID {
+	if memR.Switch == nil || memR.Switch.NodeInfo() == nil {
+		return ""
+	}
+	return memR.Switch.NodeInfo().ID()
+}
+
+// SetRelaySigner sets the key signing this node's registrations with the
+// relay, nil for none. The relay, if connected, is registered with again if
+// the key changed, so that a rotated key takes effect without reconnecting.
+//
+// Safe for concurrent use by multiple goroutines.
+func (memR *Reactor) SetRelaySigner(signer RelaySigner) {
+	memR.relayMtx.Lock()
+	old := memR.relaySigner
+	memR.relaySigner = signer
+	memR.relayMtx.Unlock()
+	if signer == nil || (old != nil && old.PubKey().Equals(signer.PubKey())) || memR.Switch == nil {
+		return
+	}
+	relayerID, apiKey := memR.relayRegistration()
+	if peer := memR.Switch.Peers().Get(relayerID); relayerID != "" && peer != nil {
+		memR.registerWithRelay(peer, apiKey)
+	}
+}
+
+// SetMEVDisabled turns MEV off, or back on, see
+// CListPriorityTxSidecar.SetMEVDisabled. While off, sidecar messages are
+// dropped, and this node doesn't register with the relay. Turning it back on
+// registers with the relay if connected.
+//
+// Safe for concurrent use by multiple goroutines.
+func (memR *Reactor) SetMEVDisabled(disabled bool) {
+	wasDisabled := memR.sidecar.MEVDisabled()
+	memR.sidecar.SetMEVDisabled(disabled)
+	if disabled || !wasDisabled || memR.Switch == nil {
+		return
+	}
+	relayerID, apiKey := memR.relayRegistration()
+	if peer := memR.Switch.Peers().Get(relayerID); relayerID != "" && peer != nil {
+		memR.registerWithRelay(peer, apiKey)
+	}
+}
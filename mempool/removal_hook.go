@@ -0,0 +1,61 @@
+package mempool
+
+import (
+	"github.com/tendermint/tendermint/types"
+)
+
+// RemovalReason tells why a tx or bundle left the mempool or the sidecar.
+type RemovalReason uint8
+
+const (
+	RemovalCommitted  RemovalReason = iota + 1 // included in a committed block
+	RemovalInvalid                             // refused when rechecked after a commit
+	RemovalExpired                             // outlived its TTL
+	RemovalReplaced                            // replaced by a tx paying a higher fee
+	RemovalVetoed                              // its bundle was refused by the app
+	RemovalUnused                              // its bundle's height passed without it being included
+	RemovalRequested                           // removed through RemoveTxByKey
+	RemovalFlushed                             // the mempool or sidecar was flushed
+	RemovalConflicted                          // another tx of its bundle was committed without it
+	RemovalEvicted                             // its bundle was evicted to make room, see SidecarConfig.MaxMemory
+)
+
+func (reason RemovalReason) String() string {
+	switch reason {
+	case RemovalCommitted:
+		return "committed"
+	case RemovalInvalid:
+		return "invalid"
+	case RemovalExpired:
+		return "expired"
+	case RemovalReplaced:
+		return "replaced"
+	case RemovalVetoed:
+		return "vetoed"
+	case RemovalUnused:
+		return "unused"
+	case RemovalRequested:
+		return "requested"
+	case RemovalFlushed:
+		return "flushed"
+	case RemovalConflicted:
+		return "conflicted"
+	case RemovalEvicted:
+		return "evicted"
+	default:
+		return "unknown"
+	}
+}
+
+// RemovalHook is notified of the txs and bundles removed from the mempool and
+// the sidecar, eg. to track tx status or keep an audit log. Its methods are
+// called synchronously, with the mempool or sidecar locked, so they must
+// return quickly and must not call back into either.
+type RemovalHook interface {
+	// TxRemoved is called with each tx removed from the mempool, or from the
+	// sidecar if sidecar is true.
+	TxRemoved(tx types.Tx, sidecar bool, reason RemovalReason)
+	// BundleRemoved is called with each bundle removed from the sidecar,
+	// after its txs.
+	BundleRemoved(height, bundleID int64, reason RemovalReason)
+}
@@ -0,0 +1,90 @@
+package mempool
+
+import (
+	"sort"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// SelfBuilder assembles a top-of-block bundle out of the public mempool,
+// ordered by declared fee, for proposers that received no usable bundles from
+// a relay. The bundle is reaped and auctioned exactly like a relayed one.
+//
+// Fees are only known for txs checked with WithFeeAttribute set.
+type SelfBuilder struct {
+	mempool *CListMempool
+	sidecar *CListPriorityTxSidecar
+	maxTxs  int
+}
+
+// NewSelfBuilder returns a SelfBuilder putting at most maxTxs of the highest
+// fee txs of mempool at the top of the block. Nothing is built while MEV is
+// turned off on sidecar, if set.
+func NewSelfBuilder(mempool *CListMempool, sidecar *CListPriorityTxSidecar, maxTxs int) *SelfBuilder {
+	return &SelfBuilder{
+		mempool: mempool,
+		sidecar: sidecar,
+		maxTxs:  maxTxs,
+	}
+}
+
+// BuildBundle returns the txs of the self-built bundle, highest fee first, and
+// the bundle as an auction candidate. The bundle fits within maxBytes and
+// maxGas, so it's never truncated by the reap. Txs declaring no fee are left
+// in their usual mempool order.
+//
+// Safe for concurrent use by multiple goroutines.
+func (sb *SelfBuilder) BuildBundle(maxBytes, maxGas int64) ([]*MempoolTx, []types.AuctionBundle) {
+	if sb.sidecar != nil && sb.sidecar.MEVDisabled() {
+		return nil, nil
+	}
+	mem := sb.mempool
+	mem.updateMtx.RLock()
+	defer mem.updateMtx.RUnlock()
+
+	feeTxs := make([]*MempoolTx, 0)
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		if memTx := e.Value.(*MempoolTx); memTx.fee > 0 {
+			feeTxs = append(feeTxs, memTx)
+		}
+	}
+	// stable, so equal fees keep their arrival order
+	sort.SliceStable(feeTxs, func(i, j int) bool {
+		return feeTxs[i].fee > feeTxs[j].fee
+	})
+
+	var (
+		memTxs   = make([]*MempoolTx, 0, sb.maxTxs)
+		txs      = make(types.Txs, 0, sb.maxTxs)
+		bundle   = types.AuctionBundle{Status: types.AuctionBundleIncluded, SelfBuilt: true}
+		totalGas int64
+	)
+	for _, memTx := range feeTxs {
+		if len(memTxs) == sb.maxTxs {
+			break
+		}
+		if maxBytes > -1 && types.ComputeProtoSizeForTxs(append(txs, memTx.tx)) > maxBytes {
+			continue
+		}
+		if maxGas > -1 && totalGas+memTx.gasWanted > maxGas {
+			continue
+		}
+		totalGas += memTx.gasWanted
+		txs = append(txs, memTx.tx)
+		memTxs = append(memTxs, &MempoolTx{
+			height:    memTx.height,
+			gasWanted: memTx.gasWanted,
+			tx:        memTx.tx,
+			fee:       memTx.fee,
+		})
+		bundle.Size++
+		bundle.Bytes += int64(len(memTx.tx))
+		bundle.Bid += memTx.fee
+	}
+
+	if len(memTxs) == 0 {
+		return memTxs, []types.AuctionBundle{}
+	}
+	bundle.Hash = txs.Hash()
+	return memTxs, []types.AuctionBundle{bundle}
+}
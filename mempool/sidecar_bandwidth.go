@@ -0,0 +1,82 @@
+package mempool
+
+import (
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+
+	flow "github.com/tendermint/tendermint/libs/flowrate"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+// sidecarBandwidthInterval is how often the bandwidth of the sidecar channel
+// is reported.
+const sidecarBandwidthInterval = time.Second
+
+// sidecarBandwidth measures the rate of the bytes sent and received on the
+// sidecar channel, apart from the node's other p2p traffic, to tell the
+// overhead MEV gossip adds to it.
+type sidecarBandwidth struct {
+	sent, received *flow.Monitor
+	// the SidecarSendRate and SidecarReceiveRate gauges
+	sendRate, receiveRate metrics.Gauge
+}
+
+func newSidecarBandwidth(m *Metrics) *sidecarBandwidth {
+	return &sidecarBandwidth{
+		sent:        flow.New(0, 0),
+		received:    flow.New(0, 0),
+		sendRate:    m.SidecarSendRate,
+		receiveRate: m.SidecarReceiveRate,
+	}
+}
+
+// report sets the gauges to the current rates.
+func (b *sidecarBandwidth) report() {
+	b.sendRate.Set(float64(b.sent.Status().CurRate))
+	b.receiveRate.Set(float64(b.received.Status().CurRate))
+}
+
+// sidecarBandwidthRoutine reports the bandwidth of the sidecar channel until
+// the reactor stops.
+func (memR *Reactor) sidecarBandwidthRoutine() {
+	ticker := time.NewTicker(sidecarBandwidthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			memR.bandwidth.report()
+		case <-memR.Quit():
+			return
+		}
+	}
+}
+
+// sendSidecar sends bz to peer on the sidecar channel, accounting for it in
+// the channel's bandwidth if it was queued, or counting it as dropped if not.
+func (memR *Reactor) sendSidecar(peer p2p.Peer, bz []byte) bool {
+	if !peer.Send(SidecarChannel, bz) {
+		memR.countSidecarDropped(peer)
+		return false
+	}
+	memR.bandwidth.sent.Update(len(bz))
+	return true
+}
+
+// countSidecarDropped counts a sidecar message peer's send queue had no room
+// for, as it stayed full until the send timed out: because the peer is more
+// than a block behind the sidecar's auction, and busy catching up, or else
+// because it doesn't drain it fast enough. Messages to stopped peers, or
+// peers without the sidecar channel, aren't counted.
+func (memR *Reactor) countSidecarDropped(peer p2p.Peer) {
+	if !peer.IsRunning() || !peerHasChannel(peer, SidecarChannel) {
+		return
+	}
+	reason := "queue_full"
+	if ps, ok := peer.Get(types.PeerStateKey).(PeerState); ok &&
+		ps.GetHeight() < memR.sidecar.HeightForFiringAuction()-1 {
+		reason = "peer_behind"
+	}
+	memR.mempool.metrics.SidecarPeerDroppedMessages.With("peer_id", string(peer.ID()), "reason", reason).Add(1)
+}
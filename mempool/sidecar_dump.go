@@ -0,0 +1,247 @@
+package mempool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/libs/tempfile"
+	"github.com/tendermint/tendermint/types"
+)
+
+// SidecarDump is a snapshot of the sidecar's internals, for offline analysis
+// of ordering bugs. See CListPriorityTxSidecar.Dump, and Load to reproduce
+// them against the same bundle set.
+type SidecarDump struct {
+	Height                 int64     `json:"height"`
+	HeightForFiringAuction int64     `json:"height_for_firing_auction"`
+	AuctionDeadline        time.Time `json:"auction_deadline"`
+	MaxBundleID            int64     `json:"max_bundle_id"`
+	NumTxs                 int       `json:"num_txs"`
+	TxsBytes               int64     `json:"txs_bytes"`
+	MemBytes               int64     `json:"mem_bytes"`
+
+	// txs in the order they're gossiped, ie. the order they were added in
+	Txs []SidecarTxDump `json:"txs"`
+	// bundles ordered by height, then id
+	Bundles []BundleDump `json:"bundles"`
+	// ids of the bundles for each height, in order
+	Heights map[int64][]int64 `json:"heights"`
+}
+
+// SidecarTxDump describes a tx of the sidecar.
+type SidecarTxDump struct {
+	Hash        tmbytes.HexBytes `json:"hash"`
+	Bytes       int              `json:"bytes"`
+	Height      int64            `json:"height"`
+	BundleID    int64            `json:"bundle_id"`
+	BundleOrder int64            `json:"bundle_order"`
+	BundleSize  int64            `json:"bundle_size"`
+	Bid         int64            `json:"bid"`
+	Origin      string           `json:"origin"`
+	Tx          tmbytes.HexBytes `json:"tx"`
+}
+
+// BundleDump describes a bundle of the sidecar, and how far it was
+// reassembled.
+type BundleDump struct {
+	Height       int64 `json:"height"`
+	BundleID     int64 `json:"bundle_id"`
+	EnforcedSize int64 `json:"enforced_size"`
+	CurrSize     int64 `json:"curr_size"`
+	Bid          int64 `json:"bid"`
+	GasWanted    int64 `json:"gas_wanted"`
+	// orders of the txs received so far, and of those still missing
+	Received []int64 `json:"received"`
+	Missing  []int64 `json:"missing"`
+
+	Late      bool             `json:"late"`
+	Vetoed    bool             `json:"vetoed"`
+	Auctioned bool             `json:"auctioned"`
+	Evicted   bool             `json:"evicted"`
+	Hash      tmbytes.HexBytes `json:"hash,omitempty"`
+
+	FirstSeenHeight int64     `json:"first_seen_height"`
+	FirstSeen       time.Time `json:"first_seen"`
+	FirstReceived   time.Time `json:"first_received"`
+	Completed       time.Time `json:"completed"`
+	MemBytes        int64     `json:"mem_bytes"`
+}
+
+// Dump returns a snapshot of the sidecar's internals. Txs and bundles being
+// added concurrently may or may not be part of it, but it's consistent with
+// respect to Update.
+//
+// Safe for concurrent use by multiple goroutines.
+func (sc *CListPriorityTxSidecar) Dump() SidecarDump {
+	sc.updateMtx.RLock()
+	defer sc.updateMtx.RUnlock()
+
+	dump := SidecarDump{
+		Height:                 atomic.LoadInt64(&sc.height),
+		HeightForFiringAuction: atomic.LoadInt64(&sc.heightForFiringAuction),
+		AuctionDeadline:        sc.auctionDeadline,
+		MaxBundleID:            atomic.LoadInt64(&sc.maxBundleId),
+		NumTxs:                 sc.txs.Len(),
+		TxsBytes:               sc.TxsBytes(),
+		MemBytes:               sc.MemBytes(),
+		Txs:                    make([]SidecarTxDump, 0, sc.txs.Len()),
+		Bundles:                []BundleDump{},
+		Heights:                make(map[int64][]int64),
+	}
+
+	for e := sc.txs.Front(); e != nil; e = e.Next() {
+		scTx := e.Value.(*SidecarTx)
+		dump.Txs = append(dump.Txs, SidecarTxDump{
+			Hash:        txID(scTx.tx),
+			Bytes:       len(scTx.tx),
+			Height:      scTx.desiredHeight,
+			BundleID:    scTx.bundleId,
+			BundleOrder: scTx.bundleOrder,
+			BundleSize:  scTx.bundleSize,
+			Bid:         scTx.bid,
+			Origin:      scTx.origin.String(),
+			Tx:          tmbytes.HexBytes(scTx.tx),
+		})
+	}
+
+	sc.bundles.Range(func(_, value interface{}) bool {
+		dump.Bundles = append(dump.Bundles, sc.dumpBundle(value.(*Bundle)))
+		return true
+	})
+	sort.Slice(dump.Bundles, func(i, j int) bool {
+		if dump.Bundles[i].Height != dump.Bundles[j].Height {
+			return dump.Bundles[i].Height < dump.Bundles[j].Height
+		}
+		return dump.Bundles[i].BundleID < dump.Bundles[j].BundleID
+	})
+	for _, bundle := range dump.Bundles {
+		dump.Heights[bundle.Height] = append(dump.Heights[bundle.Height], bundle.BundleID)
+	}
+	return dump
+}
+
+// dumpBundle describes bundle, whose txs are listed up to its enforced size.
+func (sc *CListPriorityTxSidecar) dumpBundle(bundle *Bundle) BundleDump {
+	dump := BundleDump{
+		Height:          bundle.desiredHeight,
+		BundleID:        bundle.bundleId,
+		EnforcedSize:    bundle.enforcedSize,
+		CurrSize:        atomic.LoadInt64(&bundle.currSize),
+		Bid:             bundle.bid,
+		GasWanted:       bundle.gasWanted,
+		Received:        []int64{},
+		Missing:         []int64{},
+		Late:            bundle.late,
+		Vetoed:          atomic.LoadInt32(&bundle.vetoed) == 1,
+		Auctioned:       atomic.LoadInt32(&bundle.auctioned) == 1,
+		FirstSeenHeight: bundle.firstSeenHeight,
+		FirstSeen:       bundle.firstSeen,
+	}
+	for order := int64(0); order < bundle.enforcedSize; order++ {
+		if _, ok := bundle.orderedTxsMap.Load(order); ok {
+			dump.Received = append(dump.Received, order)
+		} else {
+			dump.Missing = append(dump.Missing, order)
+		}
+	}
+
+	sc.memMtx.Lock()
+	defer sc.memMtx.Unlock()
+	dump.Evicted = bundle.evicted
+	dump.Hash = bundle.hash
+	dump.FirstReceived = bundle.firstReceived
+	dump.Completed = bundle.completed
+	dump.MemBytes = bundle.memBytes
+	return dump
+}
+
+// Load adds the txs of dump to the sidecar, moved to the dump's height first,
+// in the order they were dumped, as if received again with the same bundle
+// ids, orders, sizes and bids, so ordering bugs seen in production can be
+// reproduced offline, eg. on a test node. The sidecar must be empty. Txs it
+// refuses, eg. bidding under its floor, are skipped, and the number of txs
+// added returned.
+//
+// Safe for concurrent use by multiple goroutines, but meant for a sidecar
+// nothing else adds txs to.
+func (sc *CListPriorityTxSidecar) Load(dump SidecarDump) (int, error) {
+	for _, tx := range dump.Txs {
+		if len(tx.Tx) == 0 {
+			return 0, fmt.Errorf("tx %X of the dump has no bytes: dumped by an older version?", tx.Hash)
+		}
+	}
+
+	sc.Lock()
+	if sc.Size() > 0 || sc.NumBundles() > 0 {
+		sc.Unlock()
+		return 0, errors.New("the sidecar isn't empty")
+	}
+	sc.Reset(dump.Height)
+	sc.Unlock()
+
+	added := 0
+	for _, tx := range dump.Txs {
+		origin, err := ParseTxOrigin(tx.Origin)
+		if err != nil {
+			origin = OriginUnknown
+		}
+		err = sc.AddTx(types.Tx(tx.Tx), TxInfo{
+			SenderID:      UnknownPeerID,
+			DesiredHeight: tx.Height,
+			BundleId:      tx.BundleID,
+			BundleOrder:   tx.BundleOrder,
+			BundleSize:    tx.BundleSize,
+			Bid:           tx.Bid,
+			Origin:        origin,
+		})
+		if err != nil {
+			sc.logger.Info("Skipping sidecar tx of the dump", "tx", tx.Hash, "bundle_id", tx.BundleID,
+				"bundle_order", tx.BundleOrder, "err", err)
+			continue
+		}
+		added++
+	}
+	return added, nil
+}
+
+// WriteSidecarDumpFile writes dump to the file at path, as JSON.
+func WriteSidecarDumpFile(path string, dump SidecarDump) error {
+	bz, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return err
+	}
+	return tempfile.WriteFileAtomic(path, bz, 0600)
+}
+
+// ReadSidecarDumpFile reads the dump written to the file at path.
+func ReadSidecarDumpFile(path string) (SidecarDump, error) {
+	var dump SidecarDump
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return dump, err
+	}
+	if err := json.Unmarshal(bz, &dump); err != nil {
+		return dump, fmt.Errorf("failed to read sidecar dump %s: %w", path, err)
+	}
+	return dump, nil
+}
+
+// SidecarDumpHandler returns an HTTP handler serving the Dump of sidecar as
+// JSON, meant for the node's debug server.
+func SidecarDumpHandler(sidecar *CListPriorityTxSidecar) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(sidecar.Dump()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package mempool
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/tendermint/tendermint/libs/clist"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Rough estimates of the memory the sidecar takes to keep a tx besides its
+// bytes (the SidecarTx, its list element, and its entries in the txs map and
+// the bundle), and to keep a bundle besides its txs. The cache of seen txs is
+// accounted apart, see SidecarConfig.CacheMemBytes.
+const (
+	sidecarTxOverheadBytes = 512
+	bundleOverheadBytes    = 256
+)
+
+// txMemBytes returns the memory the sidecar takes to keep tx.
+func txMemBytes(tx types.Tx) int64 {
+	return int64(len(tx)) + sidecarTxOverheadBytes
+}
+
+// MemBytes returns the memory taken by the sidecar's txs and bundles, in
+// bytes, as counted against SidecarConfig.MaxMemory once the room of the
+// cache is set aside.
+//
+// Safe for concurrent use by multiple goroutines.
+func (sc *CListPriorityTxSidecar) MemBytes() int64 {
+	return atomic.LoadInt64(&sc.memBytes)
+}
+
+func (sc *CListPriorityTxSidecar) addMemBytes(delta int64) {
+	sc.metrics.SidecarMemoryBytes.Set(float64(atomic.AddInt64(&sc.memBytes, delta)))
+}
+
+// reserveMemory makes sure needed more bytes fit in the sidecar's memory
+// limit, for a tx of the bundle with key, evicting bundles if they don't.
+//
+// sc.memMtx must be held by the caller.
+func (sc *CListPriorityTxSidecar) reserveMemory(key Key, needed int64) error {
+	if sc.config.MaxMemory == 0 {
+		return nil
+	}
+	// the cache takes its room whatever the txs held
+	maxMemory := sc.config.MaxMemory - sc.config.CacheMemBytes()
+	if sc.MemBytes()+needed <= maxMemory {
+		return nil
+	}
+	if !sc.evict(key, sc.MemBytes()+needed-maxMemory) {
+		return sc.fullError()
+	}
+	sc.reportBundles()
+	return nil
+}
+
+// evict removes incomplete bundles for later heights than the bundle with
+// key, and their txs, until they freed at least excess bytes: those for the
+// furthest heights first, then the last seen. Complete bundles are never
+// evicted, as they may be in the middle of being checked or reaped. Nothing
+// is evicted, and false is returned, if they can't free enough.
+//
+// sc.memMtx must be held by the caller.
+func (sc *CListPriorityTxSidecar) evict(key Key, excess int64) bool {
+	var (
+		keys      = make(map[*Bundle]Key)
+		bundles   = make([]*Bundle, 0)
+		evictable int64
+	)
+	sc.bundles.Range(func(k, value interface{}) bool {
+		bundle := value.(*Bundle)
+		if bundle.desiredHeight > key.height &&
+			atomic.LoadInt64(&bundle.currSize) < bundle.enforcedSize &&
+			atomic.LoadInt32(&bundle.vetoed) == 0 {
+			keys[bundle] = k.(Key)
+			bundles = append(bundles, bundle)
+			evictable += bundle.memBytes
+		}
+		return true
+	})
+	if evictable < excess {
+		return false
+	}
+
+	sort.Slice(bundles, func(i, j int) bool {
+		if bundles[i].desiredHeight != bundles[j].desiredHeight {
+			return bundles[i].desiredHeight > bundles[j].desiredHeight
+		}
+		return bundles[i].firstSeen.After(bundles[j].firstSeen)
+	})
+	for _, bundle := range bundles {
+		if excess <= 0 {
+			break
+		}
+		sc.bundleLogger(bundle).Info("Evicting bundle to make room", "mem_bytes", bundle.memBytes)
+		excess -= bundle.memBytes
+		bundle.evicted = true
+		bundle.orderedTxsMap.Range(func(_, scTx interface{}) bool {
+			tx := scTx.(*SidecarTx).tx
+			if e, ok := sc.txsMap.Load(TxKey(tx)); ok {
+				// the sidecar might have room for it later
+				sc.removeTx(tx, e.(*clist.CElement), true, RemovalEvicted)
+			}
+			return true
+		})
+		sc.deleteBundle(keys[bundle], bundle, RemovalEvicted)
+	}
+	return true
+}
+
+// deleteBundle removes the bundle with key, releasing the memory it takes.
+// Its txs must have been removed already.
+func (sc *CListPriorityTxSidecar) deleteBundle(key Key, bundle *Bundle, reason RemovalReason) {
+	sc.bundles.Delete(key)
+	sc.addMemBytes(-bundle.memBytes)
+	bundle.memBytes = 0
+	sc.notifyBundleRemoved(bundle, reason)
+}
@@ -0,0 +1,122 @@
+package mempool
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// rejectionReason returns the label SidecarRejectedTxs counts a tx refused
+// with err under.
+func rejectionReason(err error) string {
+	switch err := err.(type) {
+	case ErrTxFiltered:
+		return "filtered"
+	case ErrWrongHeight:
+		if err.desiredHeight < err.currentAuctionHeight {
+			return "height_passed"
+		}
+		return "height_too_far"
+	case ErrTxMalformedForBundle:
+		return "malformed"
+	case ErrBidTooLow:
+		return "bid_too_low"
+	case ErrBundleSizeOutOfRange:
+		return "bundle_size"
+	case ErrTxTooLarge:
+		return "too_big"
+	case ErrBundleOrderTaken:
+		return "duplicate"
+	case ErrSidecarIsFull:
+		return "full"
+	case ErrBundleFull:
+		return "bundle_full"
+	case ErrBundleVetoed:
+		return "vetoed"
+	}
+	switch err {
+	case ErrTxInCache:
+		return "in_cache"
+	case ErrMEVDisabled:
+		return "mev_disabled"
+	}
+	return "other"
+}
+
+// reportSize sets the metrics of the sidecar's size.
+func (sc *CListPriorityTxSidecar) reportSize() {
+	sc.metrics.SidecarSize.Set(float64(sc.Size()))
+	sc.metrics.SidecarSizeBytes.Set(float64(sc.TxsBytes()))
+}
+
+// reportBundles sets the metrics of the sidecar's bundles, counting those for
+// each height by how far past the auction's height it is. The heights no
+// bundle is left for anymore are reported with none.
+//
+// Safe for concurrent use by multiple goroutines.
+func (sc *CListPriorityTxSidecar) reportBundles() {
+	sc.metricsMtx.Lock()
+	defer sc.metricsMtx.Unlock()
+
+	var (
+		auctionHeight = sc.heightForFiringAuction
+		numBundles    int
+		heightsAhead  = make(map[int64]int)
+	)
+	sc.bundles.Range(func(_, value interface{}) bool {
+		numBundles++
+		heightsAhead[value.(*Bundle).desiredHeight-auctionHeight]++
+		return true
+	})
+
+	sc.metrics.SidecarBundles.Set(float64(numBundles))
+	for ahead := range sc.reportedHeightsAhead {
+		if _, ok := heightsAhead[ahead]; !ok {
+			sc.metrics.SidecarHeightBundles.With("heights_ahead", strconv.FormatInt(ahead, 10)).Set(0)
+			delete(sc.reportedHeightsAhead, ahead)
+		}
+	}
+	for ahead, n := range heightsAhead {
+		sc.metrics.SidecarHeightBundles.With("heights_ahead", strconv.FormatInt(ahead, 10)).Set(float64(n))
+		sc.reportedHeightsAhead[ahead] = struct{}{}
+	}
+}
+
+// observeAuctionLatency records the time from the first of the bundle's txs
+// being received, and from the bundle being complete, to the bundle being
+// reaped for its auction, the first time it is, and marks it in the bundle's
+// span and the audit log.
+func (sc *CListPriorityTxSidecar) observeAuctionLatency(bundle *Bundle) {
+	if !atomic.CompareAndSwapInt32(&bundle.auctioned, 0, 1) {
+		return
+	}
+	sc.memMtx.Lock()
+	firstReceived, completed := bundle.firstReceived, bundle.completed
+	traceBundleAuctioned(bundle)
+	sc.auditBundle(bundle, BundleAuditAuctioned, "")
+	sc.memMtx.Unlock()
+	now := time.Now()
+	sc.metrics.BundleAuctionLatency.Observe(now.Sub(firstReceived).Seconds())
+	// only complete bundles are reaped
+	sc.metrics.BundleCompleteToAuctionLatency.Observe(now.Sub(completed).Seconds())
+}
+
+// countReassembly counts the tx at order, just stored in bundle, as out of
+// order if a tx preceding it in the bundle is still missing, and as reordered
+// if a tx following it was received already.
+func (sc *CListPriorityTxSidecar) countReassembly(bundle *Bundle, order int64) {
+	var outOfOrder, reordered bool
+	for o := int64(0); o < order && !outOfOrder; o++ {
+		_, ok := bundle.orderedTxsMap.Load(o)
+		outOfOrder = !ok
+	}
+	for o := order + 1; o < bundle.enforcedSize && !reordered; o++ {
+		_, reordered = bundle.orderedTxsMap.Load(o)
+	}
+	if outOfOrder {
+		sc.metrics.SidecarTxsOutOfOrder.Add(1)
+	}
+	if reordered {
+		sc.metrics.SidecarTxsReordered.Add(1)
+	}
+}
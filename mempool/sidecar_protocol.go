@@ -0,0 +1,103 @@
+package mempool
+
+import (
+	"github.com/tendermint/tendermint/p2p"
+	protomem "github.com/tendermint/tendermint/proto/tendermint/mempool"
+	"github.com/tendermint/tendermint/version"
+)
+
+// SidecarCapabilities is a bitmask of the optional features of the sidecar
+// protocol, advertised with every MEVMessage.
+type SidecarCapabilities uint64
+
+const (
+	// CapabilityCancellation is set by nodes taking bundle cancellations.
+	CapabilityCancellation SidecarCapabilities = 1 << iota
+	// CapabilityCompression is set by nodes taking compressed txs.
+	CapabilityCompression
+	// CapabilityEncryption is set by nodes taking encrypted bundles.
+	CapabilityEncryption
+	// CapabilityReceipts is set by nodes sending and forwarding bundle
+	// receipts.
+	CapabilityReceipts
+	// CapabilityEnvelopes is set by nodes taking signed bundle envelopes.
+	CapabilityEnvelopes
+)
+
+// LocalSidecarCapabilities are the capabilities this node advertises.
+const LocalSidecarCapabilities = CapabilityReceipts | CapabilityEnvelopes
+
+// legacySidecarCapabilities are those assumed of peers predating versioned
+// messages: none, as the oldest releases disconnect peers sending them
+// anything but txs.
+const legacySidecarCapabilities SidecarCapabilities = 0
+
+// sidecarProtocolKey is the key of a peer's SidecarProtocol, see
+// peerSidecarProtocol.
+const sidecarProtocolKey = "MempoolReactor.sidecarProtocol"
+
+// SidecarProtocol is the version of the sidecar protocol a node speaks, and
+// its capabilities.
+type SidecarProtocol struct {
+	Version      uint64
+	Capabilities SidecarCapabilities
+}
+
+// Has returns true if all of caps are among the protocol's capabilities.
+func (p SidecarProtocol) Has(caps SidecarCapabilities) bool {
+	return p.Capabilities&caps == caps
+}
+
+// localSidecarProtocol is the protocol this node speaks.
+var localSidecarProtocol = SidecarProtocol{
+	Version:      version.SidecarProtocol,
+	Capabilities: LocalSidecarCapabilities,
+}
+
+// sidecarProtocolFields are the fields of a MEVMessage carrying
+// localSidecarProtocol, encoded.
+var sidecarProtocolFields = marshalMEVMessage(&protomem.MEVMessage{})
+
+// sidecarMsgFields returns the fields of a MEVMessage carrying
+// localSidecarProtocol and, unless empty, chainID, encoded.
+func sidecarMsgFields(chainID string) []byte {
+	if chainID == "" {
+		return sidecarProtocolFields
+	}
+	return marshalMEVMessage(&protomem.MEVMessage{ChainId: chainID})
+}
+
+// marshalMEVMessage returns msg encoded, carrying localSidecarProtocol.
+func marshalMEVMessage(msg *protomem.MEVMessage) []byte {
+	msg.Version = localSidecarProtocol.Version
+	msg.Capabilities = uint64(localSidecarProtocol.Capabilities)
+	bz, err := msg.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// peerSidecarProtocol returns the protocol peer advertised in its last
+// sidecar message. Peers predating versioned messages, or which haven't sent
+// any, are assumed to speak version 0 with legacySidecarCapabilities, and
+// those speaking Skip Protocol's format version 0 with none.
+func (memR *Reactor) peerSidecarProtocol(peer p2p.Peer) SidecarProtocol {
+	if memR.speaksSkipFormat(peer.ID()) {
+		return SidecarProtocol{}
+	}
+	if p, ok := peer.Get(sidecarProtocolKey).(SidecarProtocol); ok && p.Version > 0 {
+		return p
+	}
+	return SidecarProtocol{Capabilities: legacySidecarCapabilities}
+}
+
+// recordSidecarProtocol records p as the protocol advertised by src.
+func (memR *Reactor) recordSidecarProtocol(src p2p.Peer, p SidecarProtocol) {
+	if prev, ok := src.Get(sidecarProtocolKey).(SidecarProtocol); ok && prev == p {
+		return
+	}
+	src.Set(sidecarProtocolKey, p)
+	memR.Logger.Debug("Sidecar peer advertised its protocol", "peer", src.ID(), "version", p.Version,
+		"capabilities", uint64(p.Capabilities))
+}
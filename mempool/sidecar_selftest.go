@@ -0,0 +1,234 @@
+package mempool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	tmrand "github.com/tendermint/tendermint/libs/rand"
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/p2p"
+	protomem "github.com/tendermint/tendermint/proto/tendermint/mempool"
+)
+
+const (
+	// sidecarSelfTestDelay is how long the sidecar peers are given to connect
+	// before the self-test run once the reactor starts.
+	sidecarSelfTestDelay = 30 * time.Second
+	// sidecarPingTimeout is how long a sidecar peer has to answer a ping.
+	sidecarPingTimeout = 5 * time.Second
+)
+
+var (
+	errSidecarPeerNotConnected = errors.New("not connected")
+	errNoSidecarChannel        = errors.New("the peer didn't negotiate the sidecar channel")
+	errSidecarPingNotSent      = errors.New("failed sending ping")
+	errSidecarPingTimeout      = errors.New("ping unanswered: the peer may not know this node as a sidecar " +
+		"peer, or run a version without pings")
+)
+
+// SidecarPeerCheck is the result of the self-test of a sidecar peer.
+type SidecarPeerCheck struct {
+	ID        p2p.ID
+	Relay     bool // whether the peer is the relay
+	Connected bool
+	Channel   bool          // whether the peer negotiated the sidecar channel
+	RTT       time.Duration // round-trip time of a ping, 0 if unanswered
+	Err       error         // why the check failed, nil if it passed
+}
+
+// SidecarSelfTest is the result of the self-test of the sidecar peers and
+// the relay, see Reactor.SidecarSelfTest.
+type SidecarSelfTest struct {
+	MEVDisabled bool // if so, the peers weren't checked
+	Peers       []SidecarPeerCheck
+}
+
+// Passed returns true if MEV is on, and all the peers passed.
+func (st SidecarSelfTest) Passed() bool {
+	if st.MEVDisabled {
+		return false
+	}
+	for _, check := range st.Peers {
+		if check.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// sidecarPings tracks the pings sent to sidecar peers awaiting an answer.
+type sidecarPings struct {
+	mtx     tmsync.Mutex
+	waiting map[uint64]sidecarPing
+}
+
+type sidecarPing struct {
+	peerID p2p.ID
+	pong   chan struct{}
+}
+
+func newSidecarPings() *sidecarPings {
+	return &sidecarPings{waiting: make(map[uint64]sidecarPing)}
+}
+
+// SidecarSelfTest checks that the relay and each sidecar peer are connected,
+// negotiated the sidecar channel, and answer a ping on it, which they only do
+// if they know this node as a sidecar peer too. The peers are checked
+// concurrently, each ping waiting at most 5s.
+//
+// Peers running a version without pings drop the connection when pinged, to
+// reconnect if persistent.
+//
+// Safe for concurrent use by multiple goroutines.
+func (memR *Reactor) SidecarSelfTest(ctx context.Context) SidecarSelfTest {
+	var st SidecarSelfTest
+	if memR.sidecar.MEVDisabled() {
+		st.MEVDisabled = true
+		return st
+	}
+	if memR.Switch == nil {
+		return st
+	}
+
+	relayerID := memR.relayerID()
+	ids := memR.Switch.SidecarPeerIDs()
+	if relayerID != "" && !memR.Switch.IsSidecarPeer(relayerID) {
+		ids = append(ids, relayerID)
+	}
+	st.Peers = make([]SidecarPeerCheck, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		st.Peers[i] = SidecarPeerCheck{ID: id, Relay: id == relayerID}
+		wg.Add(1)
+		go func(check *SidecarPeerCheck) {
+			defer wg.Done()
+			memR.checkSidecarPeer(ctx, check)
+		}(&st.Peers[i])
+	}
+	wg.Wait()
+	return st
+}
+
+// checkSidecarPeer fills in check, of the peer check.ID. Peers speaking Skip
+// Protocol's format, which has no pings, pass once they negotiated the sidecar
+// channel.
+func (memR *Reactor) checkSidecarPeer(ctx context.Context, check *SidecarPeerCheck) {
+	peer := memR.Switch.Peers().Get(check.ID)
+	if peer == nil {
+		check.Err = errSidecarPeerNotConnected
+		return
+	}
+	check.Connected = true
+	if !peerHasChannel(peer, SidecarChannel) {
+		check.Err = errNoSidecarChannel
+		return
+	}
+	check.Channel = true
+	if memR.speaksSkipFormat(peer.ID()) {
+		return
+	}
+	check.RTT, check.Err = memR.pingSidecarPeer(ctx, peer)
+}
+
+// pingSidecarPeer pings peer on the sidecar channel, returning the
+// round-trip time once it answers.
+func (memR *Reactor) pingSidecarPeer(ctx context.Context, peer p2p.Peer) (time.Duration, error) {
+	nonce := tmrand.Uint64()
+	pong := make(chan struct{})
+	memR.pings.mtx.Lock()
+	memR.pings.waiting[nonce] = sidecarPing{peerID: peer.ID(), pong: pong}
+	memR.pings.mtx.Unlock()
+	defer func() {
+		memR.pings.mtx.Lock()
+		delete(memR.pings.waiting, nonce)
+		memR.pings.mtx.Unlock()
+	}()
+
+	msg := protomem.MEVMessage{
+		Sum: &protomem.MEVMessage_Ping{Ping: &protomem.SidecarPing{Nonce: nonce}},
+	}
+	bz := marshalMEVMessage(&msg)
+	sent := time.Now()
+	if !memR.sendSidecar(peer, bz) {
+		return 0, errSidecarPingNotSent
+	}
+	timer := time.NewTimer(sidecarPingTimeout)
+	defer timer.Stop()
+	select {
+	case <-pong:
+		return time.Since(sent), nil
+	case <-timer.C:
+		return 0, errSidecarPingTimeout
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// receivePing answers a ping from src, or records its answer to ours.
+func (memR *Reactor) receivePing(src p2p.Peer, msg MEVPingMessage) {
+	if msg.Pong {
+		memR.pings.mtx.Lock()
+		defer memR.pings.mtx.Unlock()
+		if ping, ok := memR.pings.waiting[msg.Nonce]; ok && ping.peerID == src.ID() {
+			close(ping.pong)
+			delete(memR.pings.waiting, msg.Nonce)
+		}
+		return
+	}
+	pong := protomem.MEVMessage{
+		Sum: &protomem.MEVMessage_Pong{Pong: &protomem.SidecarPong{Nonce: msg.Nonce}},
+	}
+	bz := marshalMEVMessage(&pong)
+	memR.sendSidecar(src, bz)
+}
+
+// sidecarSelfTestRoutine self-tests the sidecar peers once they had time to
+// connect, logging the results, unless the reactor stops first.
+func (memR *Reactor) sidecarSelfTestRoutine() {
+	timer := time.NewTimer(sidecarSelfTestDelay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-memR.Quit():
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-memR.Quit():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	memR.logSidecarSelfTest(memR.SidecarSelfTest(ctx))
+}
+
+// logSidecarSelfTest logs a line for each peer checked by st, and a summary.
+func (memR *Reactor) logSidecarSelfTest(st SidecarSelfTest) {
+	if st.MEVDisabled {
+		memR.Logger.Info("MEV is disabled, skipping the sidecar self-test")
+		return
+	}
+	if len(st.Peers) == 0 {
+		return
+	}
+	failed := 0
+	for _, check := range st.Peers {
+		if check.Err != nil {
+			failed++
+			memR.Logger.Error("Sidecar peer failed the self-test", "peer", check.ID, "relay", check.Relay,
+				"err", check.Err)
+			continue
+		}
+		memR.Logger.Info("Sidecar peer passed the self-test", "peer", check.ID, "relay", check.Relay,
+			"rtt", check.RTT)
+	}
+	if failed > 0 {
+		memR.Logger.Error("Sidecar self-test failed", "failed", failed, "peers", len(st.Peers))
+		return
+	}
+	memR.Logger.Info("Sidecar self-test passed", "peers", len(st.Peers))
+}
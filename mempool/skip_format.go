@@ -0,0 +1,76 @@
+package mempool
+
+import (
+	"github.com/tendermint/tendermint/p2p"
+	protomem "github.com/tendermint/tendermint/proto/tendermint/mempool"
+)
+
+// Skip Protocol's mev-tendermint, which this sidecar descends from, gossips
+// sidecar txs on the same channel, in MEVMessages holding only the txs and
+// fields 2 to 5: the desired height, and the bundle's id, order and size. Its
+// nodes know none of the message types added since, and drop the peers
+// sending them. Peers known to speak it, see SetSkipFormatPeers, are bridged:
+// their messages are translated into this sidecar's, with a bid of 0, and
+// they're sent txs in their format, and nothing else.
+
+// SetSkipFormatPeers sets the sidecar peers speaking Skip Protocol's
+// mev-tendermint message format, see SidecarConfig.SkipFormatPeerIDs.
+//
+// Safe for concurrent use by multiple goroutines.
+func (memR *Reactor) SetSkipFormatPeers(ids []p2p.ID) {
+	peers := make(map[p2p.ID]struct{}, len(ids))
+	for _, id := range ids {
+		peers[id] = struct{}{}
+	}
+	memR.relayMtx.Lock()
+	memR.skipFormatPeers = peers
+	memR.relayMtx.Unlock()
+}
+
+// speaksSkipFormat returns true if the peer peerID speaks Skip Protocol's
+// message format.
+func (memR *Reactor) speaksSkipFormat(peerID p2p.ID) bool {
+	memR.relayMtx.RLock()
+	defer memR.relayMtx.RUnlock()
+	_, ok := memR.skipFormatPeers[peerID]
+	return ok
+}
+
+// skipFormatPeerIDs returns the node IDs of the peers speaking Skip Protocol's
+// format set by the config, which has been validated, see
+// SidecarConfig.ValidateBasic.
+func skipFormatPeerIDs(list []string) []p2p.ID {
+	ids := make([]p2p.ID, len(list))
+	for i, id := range list {
+		ids[i] = p2p.ID(id)
+	}
+	return ids
+}
+
+// encodeSkipTxMsg returns the message gossiping scTx in Skip Protocol's
+// format, leaving out its bid.
+func encodeSkipTxMsg(scTx *SidecarTx) []byte {
+	msg := protomem.MEVMessage{
+		Sum: &protomem.MEVMessage_Txs{
+			Txs: &protomem.Txs{Txs: [][]byte{scTx.tx}},
+		},
+		DesiredHeight: scTx.desiredHeight,
+		BundleId:      scTx.bundleId,
+		BundleOrder:   scTx.bundleOrder,
+		BundleSize:    scTx.bundleSize,
+	}
+	bz, err := msg.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// decodeSkipMsg returns the txs encoded in Skip Protocol's format in bz, the
+// legacy MEVTxs format, see decodeLegacyTxsMsg, as a MEVTxsMessage with a bid
+// of 0. Fields of this sidecar's messages past Skip Protocol's are ignored.
+func decodeSkipMsg(bz []byte) (MEVTxsMessage, error) {
+	msg, err := decodeLegacyTxsMsg(bz)
+	msg.Bid = 0
+	return msg, err
+}
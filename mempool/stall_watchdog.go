@@ -0,0 +1,160 @@
+package mempool
+
+import (
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+
+	cfg "github.com/tendermint/tendermint/config"
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+)
+
+// stallWatchdogInterval is how often the stall watchdog checks the MEV
+// pipeline.
+const stallWatchdogInterval = time.Second
+
+// Reasons the MEV pipeline is stalled, as reported by ErrSidecarStalled and
+// the SidecarStalled metric.
+const (
+	stallNoSidecarMessages = "no_sidecar_messages"
+	stallAuctionHeight     = "auction_height_stuck"
+)
+
+// stallWatchdog flags the MEV pipeline as stalled when no sidecar message
+// arrived for SidecarConfig.StallBlocks blocks while sidecar peers are
+// connected, or when the auction height didn't advance for
+// SidecarConfig.StallTimeout.
+type stallWatchdog struct {
+	mtx tmsync.Mutex
+
+	stallBlocks  int64
+	stallTimeout time.Duration
+	// the SidecarStalled gauges, labeled with each reason
+	noMessagesGauge    metrics.Gauge
+	auctionHeightGauge metrics.Gauge
+
+	// number of sidecar peers connected
+	sidecarPeers int
+	// whether a sidecar message arrived since the last check
+	messageReceived bool
+	// last height at which a sidecar message arrived, or no sidecar peer was
+	// connected, set on the first check
+	lastActiveHeight int64
+	started          bool
+	// last auction height seen, and when it was first seen
+	auctionHeight      int64
+	auctionHeightSince time.Time
+
+	reasons []string
+}
+
+func newStallWatchdog(config *cfg.SidecarConfig, m *Metrics, now time.Time) *stallWatchdog {
+	w := &stallWatchdog{
+		stallBlocks:        config.StallBlocks,
+		stallTimeout:       config.StallTimeout,
+		noMessagesGauge:    m.SidecarStalled.With("reason", stallNoSidecarMessages),
+		auctionHeightGauge: m.SidecarStalled.With("reason", stallAuctionHeight),
+		auctionHeightSince: now,
+	}
+	w.noMessagesGauge.Set(0)
+	w.auctionHeightGauge.Set(0)
+	return w
+}
+
+// peerAdded records a sidecar peer connecting.
+func (w *stallWatchdog) peerAdded() {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.sidecarPeers++
+}
+
+// peerRemoved records a sidecar peer disconnecting.
+func (w *stallWatchdog) peerRemoved() {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.sidecarPeers--
+}
+
+// sidecarMessageReceived records a message received from a sidecar peer.
+func (w *stallWatchdog) sidecarMessageReceived() {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.messageReceived = true
+}
+
+// check updates whether the pipeline is stalled, given the sidecar's height
+// and auction height as of now.
+func (w *stallWatchdog) check(height, auctionHeight int64, now time.Time) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	// no message is expected while no sidecar peer is connected
+	if !w.started || w.messageReceived || w.sidecarPeers == 0 {
+		w.lastActiveHeight = height
+		w.messageReceived = false
+		w.started = true
+	}
+	if auctionHeight != w.auctionHeight {
+		w.auctionHeight = auctionHeight
+		w.auctionHeightSince = now
+	}
+
+	w.reasons = nil
+	noMessages := w.stallBlocks > 0 && height-w.lastActiveHeight >= w.stallBlocks
+	if noMessages {
+		w.reasons = append(w.reasons, stallNoSidecarMessages)
+	}
+	auctionHeightStuck := w.stallTimeout > 0 && now.Sub(w.auctionHeightSince) >= w.stallTimeout
+	if auctionHeightStuck {
+		w.reasons = append(w.reasons, stallAuctionHeight)
+	}
+	w.noMessagesGauge.Set(boolToFloat(noMessages))
+	w.auctionHeightGauge.Set(boolToFloat(auctionHeightStuck))
+}
+
+// err returns ErrSidecarStalled if the last check found the pipeline
+// stalled, nil otherwise.
+func (w *stallWatchdog) err() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if len(w.reasons) == 0 {
+		return nil
+	}
+	return ErrSidecarStalled{Reasons: append([]string(nil), w.reasons...)}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// stallWatchdogRoutine checks the MEV pipeline until the reactor stops.
+func (memR *Reactor) stallWatchdogRoutine() {
+	ticker := time.NewTicker(stallWatchdogInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			memR.sidecar.updateMtx.RLock()
+			height, auctionHeight := memR.sidecar.height, memR.sidecar.heightForFiringAuction
+			memR.sidecar.updateMtx.RUnlock()
+			memR.watchdog.check(height, auctionHeight, now)
+		case <-memR.Quit():
+			return
+		}
+	}
+}
+
+// Stalled returns ErrSidecarStalled if the MEV pipeline is stalled, see
+// SidecarConfig.StallBlocks and StallTimeout, nil otherwise or if stall
+// detection is disabled.
+//
+// Safe for concurrent use by multiple goroutines.
+func (memR *Reactor) Stalled() error {
+	if memR.watchdog == nil {
+		return nil
+	}
+	return memR.watchdog.err()
+}
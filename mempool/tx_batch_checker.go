@@ -0,0 +1,115 @@
+package mempool
+
+import (
+	"bytes"
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/protoio"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
+)
+
+// TxBatchChecker checks txs received together in a single round trip to the
+// app, instead of one CheckTx call per tx.
+type TxBatchChecker interface {
+	// CheckTxBatch returns the result of checking each of txs, in order, as
+	// CheckTx would have.
+	CheckTxBatch(txs types.Txs) ([]abci.ResponseCheckTx, error)
+	// RecheckTxBatch is CheckTxBatch for txs rechecked after a commit, as a
+	// CheckTx of type Recheck would have.
+	RecheckTxBatch(txs types.Txs) ([]abci.ResponseCheckTx, error)
+}
+
+// QueryTxBatchChecker checks batches of txs through an ABCI query on a
+// dedicated path. The query data is the txs as a tendermint.types.Data
+// message. Apps supporting it run CheckTx on each tx in order, against the
+// same state as the mempool connection, and return the responses as
+// varint-delimited ResponseCheckTx messages (see EncodeTxBatchResponses).
+// Batches of txs rechecked after a commit are sent on the path followed by
+// "/recheck".
+type QueryTxBatchChecker struct {
+	proxyApp proxy.AppConnQuery
+	path     string
+}
+
+var _ TxBatchChecker = (*QueryTxBatchChecker)(nil)
+
+// NewQueryTxBatchChecker returns a QueryTxBatchChecker querying path.
+func NewQueryTxBatchChecker(proxyApp proxy.AppConnQuery, path string) *QueryTxBatchChecker {
+	return &QueryTxBatchChecker{
+		proxyApp: proxyApp,
+		path:     path,
+	}
+}
+
+// Supported returns true if the app answers an empty batch on the checker's
+// path, advertising support for batched CheckTx.
+func (bc *QueryTxBatchChecker) Supported() bool {
+	responses, err := bc.CheckTxBatch(types.Txs{})
+	return err == nil && len(responses) == 0
+}
+
+// CheckTxBatch implements TxBatchChecker.
+func (bc *QueryTxBatchChecker) CheckTxBatch(txs types.Txs) ([]abci.ResponseCheckTx, error) {
+	return bc.query(bc.path, txs)
+}
+
+// RecheckTxBatch implements TxBatchChecker.
+func (bc *QueryTxBatchChecker) RecheckTxBatch(txs types.Txs) ([]abci.ResponseCheckTx, error) {
+	return bc.query(bc.path+"/recheck", txs)
+}
+
+func (bc *QueryTxBatchChecker) query(path string, txs types.Txs) ([]abci.ResponseCheckTx, error) {
+	data := tmproto.Data{Txs: make([][]byte, len(txs))}
+	for i, tx := range txs {
+		data.Txs[i] = tx
+	}
+	bz, err := data.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := bc.proxyApp.QuerySync(abci.RequestQuery{
+		Path: path,
+		Data: bz,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.IsErr() {
+		return nil, fmt.Errorf("app refused batch of %d txs with code %d: %s", len(txs), res.Code, res.Log)
+	}
+	return decodeTxBatchResponses(res.Value, len(txs))
+}
+
+// EncodeTxBatchResponses encodes the responses to a batch of txs, as apps
+// supporting QueryTxBatchChecker return them.
+func EncodeTxBatchResponses(responses []abci.ResponseCheckTx) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := protoio.NewDelimitedWriter(buf)
+	for i := range responses {
+		if _, err := w.WriteMsg(&responses[i]); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeTxBatchResponses(bz []byte, numTxs int) ([]abci.ResponseCheckTx, error) {
+	responses := make([]abci.ResponseCheckTx, numTxs)
+	r := protoio.NewDelimitedReader(bytes.NewReader(bz), len(bz))
+	read := 0
+	for i := range responses {
+		n, err := r.ReadMsg(&responses[i])
+		if err != nil {
+			return nil, fmt.Errorf("reading response %d of %d: %w", i, numTxs, err)
+		}
+		read += n
+	}
+	if read != len(bz) {
+		return nil, fmt.Errorf("got %d bytes past the responses to %d txs", len(bz)-read, numTxs)
+	}
+	return responses, nil
+}
@@ -0,0 +1,127 @@
+package mempool
+
+import (
+	"sync/atomic"
+
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/types"
+)
+
+// TxEventType tells whether a TxEvent is about a tx being added or removed.
+type TxEventType uint8
+
+const (
+	TxAdded TxEventType = iota
+	TxRemoved
+)
+
+// TxEvent is a tx added to or removed from the mempool or the sidecar.
+type TxEvent struct {
+	Type   TxEventType
+	Tx     types.Tx
+	Reason RemovalReason // why the tx was removed, for TxRemoved events
+	Origin TxOrigin      // where the tx was received from
+
+	// whether the tx is a sidecar tx, in which case the fields below give
+	// the bundle it's part of
+	Sidecar       bool
+	DesiredHeight int64
+	BundleID      int64
+	BundleOrder   int64
+	BundleSize    int64
+}
+
+// TxFeed streams the txs added to and removed from the mempool and sidecar
+// to its subscribers, so external builders or analytics can mirror the
+// node's view without polling Reap. Events are published in the order the
+// changes happen. Publishing never blocks: a subscriber whose channel is full
+// misses events, and should resync from Reap once it sees any were dropped.
+type TxFeed struct {
+	mtx  tmsync.RWMutex
+	subs map[*TxSubscription]struct{}
+}
+
+// TxSubscription receives the events of a TxFeed.
+type TxSubscription struct {
+	out     chan TxEvent
+	dropped int64 // atomic
+}
+
+// NewTxFeed returns a TxFeed without subscribers.
+func NewTxFeed() *TxFeed {
+	return &TxFeed{
+		subs: make(map[*TxSubscription]struct{}),
+	}
+}
+
+// Subscribe returns a subscription buffering up to capacity events.
+func (feed *TxFeed) Subscribe(capacity int) *TxSubscription {
+	sub := &TxSubscription{out: make(chan TxEvent, capacity)}
+	feed.mtx.Lock()
+	feed.subs[sub] = struct{}{}
+	feed.mtx.Unlock()
+	return sub
+}
+
+// Unsubscribe stops sending events to sub and closes its channel.
+func (feed *TxFeed) Unsubscribe(sub *TxSubscription) {
+	feed.mtx.Lock()
+	defer feed.mtx.Unlock()
+	if _, ok := feed.subs[sub]; ok {
+		delete(feed.subs, sub)
+		close(sub.out)
+	}
+}
+
+// NumSubscribers returns the number of active subscriptions.
+func (feed *TxFeed) NumSubscribers() int {
+	feed.mtx.RLock()
+	defer feed.mtx.RUnlock()
+	return len(feed.subs)
+}
+
+func (feed *TxFeed) publish(event TxEvent) {
+	feed.mtx.RLock()
+	defer feed.mtx.RUnlock()
+	for sub := range feed.subs {
+		select {
+		case sub.out <- event:
+		default:
+			atomic.AddInt64(&sub.dropped, 1)
+		}
+	}
+}
+
+func (feed *TxFeed) publishMempoolTx(eventType TxEventType, memTx *MempoolTx, reason RemovalReason) {
+	if feed == nil {
+		return
+	}
+	feed.publish(TxEvent{Type: eventType, Tx: memTx.tx, Reason: reason, Origin: memTx.origin})
+}
+
+func (feed *TxFeed) publishSidecarTx(eventType TxEventType, scTx *SidecarTx, reason RemovalReason) {
+	if feed == nil {
+		return
+	}
+	feed.publish(TxEvent{
+		Type:          eventType,
+		Tx:            scTx.tx,
+		Reason:        reason,
+		Origin:        scTx.origin,
+		Sidecar:       true,
+		DesiredHeight: scTx.desiredHeight,
+		BundleID:      scTx.bundleId,
+		BundleOrder:   scTx.bundleOrder,
+		BundleSize:    scTx.bundleSize,
+	})
+}
+
+// Out returns the channel events are sent on. It's closed on Unsubscribe.
+func (sub *TxSubscription) Out() <-chan TxEvent {
+	return sub.out
+}
+
+// Dropped returns the number of events missed because the channel was full.
+func (sub *TxSubscription) Dropped() int64 {
+	return atomic.LoadInt64(&sub.dropped)
+}
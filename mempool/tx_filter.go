@@ -0,0 +1,127 @@
+package mempool
+
+import (
+	"fmt"
+
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+// TxFilter vets the txs submitted to the mempool and the sidecar before the
+// app checks them or they're added to their bundle, so operators can enforce
+// their own policies (size limits, denylists, ...) without changing the
+// reactor. Filters are run in the order they were added, and must be safe for
+// concurrent use.
+type TxFilter interface {
+	// FilterTx returns an error if tx, received as described by txInfo, must
+	// be refused. txInfo.BundleSize is 0 for mempool txs.
+	FilterTx(tx types.Tx, txInfo TxInfo) error
+}
+
+// TxFilterFunc is a function implementing TxFilter.
+type TxFilterFunc func(tx types.Tx, txInfo TxInfo) error
+
+var _ TxFilter = TxFilterFunc(nil)
+
+// FilterTx implements TxFilter.
+func (f TxFilterFunc) FilterTx(tx types.Tx, txInfo TxInfo) error {
+	return f(tx, txInfo)
+}
+
+// WithTxFilters adds filters vetting each tx before it's checked by the app.
+func WithTxFilters(filters ...TxFilter) CListMempoolOption {
+	return func(mem *CListMempool) { mem.AddTxFilters(filters...) }
+}
+
+// AddTxFilters adds filters vetting each tx before it's checked by the app.
+// It must be called before the mempool is used.
+func (mem *CListMempool) AddTxFilters(filters ...TxFilter) {
+	mem.txFilters = append(mem.txFilters, filters...)
+}
+
+// WithSidecarTxFilters adds filters vetting each tx before it's added to its
+// bundle.
+func WithSidecarTxFilters(filters ...TxFilter) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.AddTxFilters(filters...) }
+}
+
+// AddTxFilters adds filters vetting each tx before it's added to its bundle.
+// It must be called before the sidecar is used.
+func (sc *CListPriorityTxSidecar) AddTxFilters(filters ...TxFilter) {
+	sc.txFilters = append(sc.txFilters, filters...)
+}
+
+// filterTx returns ErrTxFiltered if any of filters refuses tx.
+func filterTx(filters []TxFilter, tx types.Tx, txInfo TxInfo) error {
+	for _, filter := range filters {
+		if err := filter.FilterTx(tx, txInfo); err != nil {
+			return ErrTxFiltered{err}
+		}
+	}
+	return nil
+}
+
+// MaxTxBytesFilter refuses txs larger than maxBytes.
+func MaxTxBytesFilter(maxBytes int) TxFilter {
+	return TxFilterFunc(func(tx types.Tx, _ TxInfo) error {
+		if len(tx) > maxBytes {
+			return fmt.Errorf("tx size is too big: %d, max: %d", len(tx), maxBytes)
+		}
+		return nil
+	})
+}
+
+// TxDenylist is a TxFilter refusing the txs, and the txs from the peers, it
+// lists. Entries can be added and removed while the node runs.
+type TxDenylist struct {
+	mtx   tmsync.RWMutex
+	txs   map[[TxKeySize]byte]struct{}
+	peers map[p2p.ID]struct{}
+}
+
+var _ TxFilter = (*TxDenylist)(nil)
+
+// NewTxDenylist returns an empty TxDenylist.
+func NewTxDenylist() *TxDenylist {
+	return &TxDenylist{
+		txs:   make(map[[TxKeySize]byte]struct{}),
+		peers: make(map[p2p.ID]struct{}),
+	}
+}
+
+// DenyTx refuses the tx with txKey, or allows it again if deny is false.
+func (dl *TxDenylist) DenyTx(txKey [TxKeySize]byte, deny bool) {
+	dl.mtx.Lock()
+	defer dl.mtx.Unlock()
+	if deny {
+		dl.txs[txKey] = struct{}{}
+	} else {
+		delete(dl.txs, txKey)
+	}
+}
+
+// DenyPeer refuses the txs received from the peer with id, or allows them
+// again if deny is false.
+func (dl *TxDenylist) DenyPeer(id p2p.ID, deny bool) {
+	dl.mtx.Lock()
+	defer dl.mtx.Unlock()
+	if deny {
+		dl.peers[id] = struct{}{}
+	} else {
+		delete(dl.peers, id)
+	}
+}
+
+// FilterTx implements TxFilter.
+func (dl *TxDenylist) FilterTx(tx types.Tx, txInfo TxInfo) error {
+	dl.mtx.RLock()
+	defer dl.mtx.RUnlock()
+	if _, ok := dl.txs[TxKey(tx)]; ok {
+		return fmt.Errorf("tx %X is denied", tx.Hash())
+	}
+	if _, ok := dl.peers[txInfo.SenderP2PID]; ok && txInfo.SenderP2PID != "" {
+		return fmt.Errorf("txs from peer %s are denied", txInfo.SenderP2PID)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package mempool
+
+import (
+	"fmt"
+
+	cfg "github.com/tendermint/tendermint/config"
+)
+
+// TxOrigin tells where a tx was received from. It's kept with the tx in the
+// mempool or the sidecar, and reported in its TxEvents and metrics.
+type TxOrigin uint8
+
+const (
+	OriginUnknown TxOrigin = iota // eg. reloaded from the WAL
+	OriginRPC                     // submitted through the RPC
+	OriginPeer                    // received on the mempool channel, or a lane's
+	OriginSidecar                 // received on the sidecar channel from a sidecar peer
+	OriginRelay                   // received on the sidecar channel from the relay
+)
+
+func (origin TxOrigin) String() string {
+	switch origin {
+	case OriginRPC:
+		return "rpc"
+	case OriginPeer:
+		return "p2p"
+	case OriginSidecar:
+		return "sidecar"
+	case OriginRelay:
+		return "relay"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseTxOrigin returns the origin named s, one of config.TxOrigins.
+func ParseTxOrigin(s string) (TxOrigin, error) {
+	for _, origin := range []TxOrigin{OriginRPC, OriginPeer, OriginSidecar, OriginRelay} {
+		if origin.String() == s {
+			return origin, nil
+		}
+	}
+	return OriginUnknown, fmt.Errorf("unknown tx origin %q", s)
+}
+
+// privateOrigins returns the set of origins whose txs aren't gossiped, see
+// config.MempoolConfig.PrivateOrigins.
+func privateOrigins(config *cfg.MempoolConfig) map[TxOrigin]bool {
+	names, err := config.PrivateOriginList()
+	if err != nil {
+		// checked by ValidateBasic
+		panic(err)
+	}
+	origins := make(map[TxOrigin]bool, len(names))
+	for _, name := range names {
+		origin, err := ParseTxOrigin(name)
+		if err != nil {
+			panic(err)
+		}
+		origins[origin] = true
+	}
+	return origins
+}
+
+// Origin returns where the tx was received from.
+func (memTx *MempoolTx) Origin() TxOrigin {
+	return memTx.origin
+}
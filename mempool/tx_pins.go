@@ -0,0 +1,59 @@
+package mempool
+
+import (
+	"github.com/tendermint/tendermint/types"
+)
+
+// TxPinner keeps the txs sidecar bundles rely on from leaving the mempool
+// before the bundles' height, so a backrun bundle isn't broken by its anchor
+// tx expiring or being replaced.
+type TxPinner interface {
+	// PinTx pins the tx with txKey until height is committed. The tx may
+	// only be added later.
+	PinTx(txKey [TxKeySize]byte, height int64)
+}
+
+var _ TxPinner = (*CListMempool)(nil)
+
+// WithTxPinner pins each tx added to the sidecar with pinner until the height
+// its bundle targets, eg. so its copy in the mempool doesn't expire before.
+func WithTxPinner(pinner TxPinner) CListSidecarOption {
+	return func(sc *CListPriorityTxSidecar) { sc.pinner = pinner }
+}
+
+// PinTx implements TxPinner: a pinned tx doesn't expire and can't be
+// replaced by fee until the mempool is updated to height. It's still removed
+// if it's committed, or invalid when rechecked. A tx in the mempool already,
+// or added while pinned, is counted by the SidecarCollidingTxs metric.
+//
+// Safe for concurrent use by multiple goroutines.
+func (mem *CListMempool) PinTx(txKey [TxKeySize]byte, height int64) {
+	if _, ok := mem.txsMap.Load(txKey); ok {
+		// the tx is public already
+		mem.metrics.SidecarCollidingTxs.Add(1)
+	}
+	mem.pinsMtx.Lock()
+	defer mem.pinsMtx.Unlock()
+	if mem.pins[txKey] < height {
+		mem.pins[txKey] = height
+	}
+}
+
+// isPinned returns true if tx is pinned past the current height.
+func (mem *CListMempool) isPinned(tx types.Tx) bool {
+	mem.pinsMtx.Lock()
+	defer mem.pinsMtx.Unlock()
+	height, ok := mem.pins[TxKey(tx)]
+	return ok && height > mem.height
+}
+
+// releasePins unpins the txs pinned until height or before.
+func (mem *CListMempool) releasePins(height int64) {
+	mem.pinsMtx.Lock()
+	defer mem.pinsMtx.Unlock()
+	for txKey, h := range mem.pins {
+		if h <= height {
+			delete(mem.pins, txKey)
+		}
+	}
+}
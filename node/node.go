@@ -14,6 +14,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
 	dbm "github.com/tendermint/tm-db"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	bcv0 "github.com/tendermint/tendermint/blockchain/v0"
@@ -27,6 +29,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	"github.com/tendermint/tendermint/libs/service"
+	tmsync "github.com/tendermint/tendermint/libs/sync"
 	"github.com/tendermint/tendermint/light"
 	mempl "github.com/tendermint/tendermint/mempool"
 	"github.com/tendermint/tendermint/p2p"
@@ -109,23 +112,64 @@ func DefaultNewNode(config *cfg.Config, logger log.Logger) (*Node, error) {
 	)
 }
 
-// MetricsProvider returns a consensus, p2p and mempool Metrics.
-type MetricsProvider func(chainID string) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics, *sm.Metrics)
+// MetricsProvider returns a consensus, p2p, mempool and state Metrics.
+type MetricsProvider func(chainID string, mev MEVMetricsLabels) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics, *sm.Metrics)
+
+// MEVMetricsLabels are the labels the mempool and state metrics, which
+// include the sidecar's and the auctions', carry besides the chain ID, so
+// the metrics of a fleet of nodes can be sliced by validator and role.
+type MEVMetricsLabels struct {
+	// address of the node's validator key, empty unless it's a validator
+	ValidatorAddress string
+	// see InstrumentationConfig.NodeMode
+	NodeMode string
+}
 
 // DefaultMetricsProvider returns Metrics build using Prometheus client library
-// if Prometheus is enabled. Otherwise, it returns no-op Metrics.
+// if Prometheus is enabled. Otherwise, it returns no-op Metrics, but for the
+// mempool and state ones if they're pushed to a statsd agent.
 func DefaultMetricsProvider(config *cfg.InstrumentationConfig) MetricsProvider {
-	return func(chainID string) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics, *sm.Metrics) {
+	return func(chainID string, mev MEVMetricsLabels) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics, *sm.Metrics) {
+		mevLabels := []string{
+			"chain_id", chainID,
+			"validator_address", mev.ValidatorAddress,
+			"node_mode", mev.NodeMode,
+		}
 		if config.Prometheus {
 			return cs.PrometheusMetrics(config.Namespace, "chain_id", chainID),
 				p2p.PrometheusMetrics(config.Namespace, "chain_id", chainID),
-				mempl.PrometheusMetrics(config.Namespace, "chain_id", chainID),
-				sm.PrometheusMetrics(config.Namespace, "chain_id", chainID)
+				mempl.PrometheusMetrics(config.Namespace, mevLabels...),
+				sm.PrometheusMetrics(config.Namespace, mevLabels...)
+		}
+		if config.StatsdAddr != "" {
+			return cs.NopMetrics(), p2p.NopMetrics(),
+				mempl.PrometheusMetrics(config.Namespace, mevLabels...),
+				sm.PrometheusMetrics(config.Namespace, mevLabels...)
 		}
 		return cs.NopMetrics(), p2p.NopMetrics(), mempl.NopMetrics(), sm.NopMetrics()
 	}
 }
 
+// mevMetricsLabels returns the labels of the MEV metrics of a node whose
+// validator key is pubKey, given the state it starts from.
+func mevMetricsLabels(config *cfg.Config, state sm.State, pubKey crypto.PubKey) MEVMetricsLabels {
+	mode := config.Instrumentation.NodeMode
+	if mode == "" && !config.Sidecar.IncludesBundles() {
+		mode = config.Sidecar.Mode
+	}
+	if mode == "" {
+		mode = cfg.NodeModeSentry
+		if state.Validators.HasAddress(pubKey.Address()) {
+			mode = cfg.NodeModeValidator
+		}
+	}
+	labels := MEVMetricsLabels{NodeMode: mode}
+	if mode == cfg.NodeModeValidator {
+		labels.ValidatorAddress = pubKey.Address().String()
+	}
+	return labels
+}
+
 // Option sets a parameter for the node.
 type Option func(*Node)
 
@@ -184,6 +228,29 @@ func StateProvider(stateProvider statesync.StateProvider) Option {
 	}
 }
 
+// TxFilters adds filters vetting the txs submitted to the mempool and the
+// sidecar, from peers or the RPC, before they're checked by the app or added
+// to their bundle. See mempool.TxFilter.
+func TxFilters(filters ...mempl.TxFilter) Option {
+	return func(n *Node) {
+		if mempool, ok := n.mempool.(*mempl.CListMempool); ok {
+			mempool.AddTxFilters(filters...)
+		}
+		if sidecar, ok := n.sidecar.(*mempl.CListPriorityTxSidecar); ok {
+			sidecar.AddTxFilters(filters...)
+		}
+	}
+}
+
+// CustomRelaySigner sets the key signing the node's registrations with the
+// relay, eg. an external signer, instead of the one in the relay_key_file.
+func CustomRelaySigner(signer mempl.RelaySigner) Option {
+	return func(n *Node) {
+		n.relaySigner = signer
+		n.mempoolReactor.SetRelaySigner(signer)
+	}
+}
+
 //------------------------------------------------------------------------------
 
 // Node is the highest level interface to a full Tendermint node.
@@ -211,6 +278,10 @@ type Node struct {
 	bcReactor         p2p.Reactor       // for fast-syncing
 	mempoolReactor    *mempl.Reactor    // for gossipping transactions
 	mempool           mempl.Mempool
+	sidecar           mempl.PriorityTxSidecar
+	sidecarConfigMtx  tmsync.Mutex            // serializes the reloads and updates of the [sidecar] config
+	relaySigner       mempl.RelaySigner       // set by CustomRelaySigner, nil to use the relay_key_file
+	txFeed            *mempl.TxFeed           // streams mempool and sidecar txs
 	stateSync         bool                    // whether the node should state sync on startup
 	stateSyncReactor  *statesync.Reactor      // for hosting and restoring state sync snapshots
 	stateSyncProvider statesync.StateProvider // provides state data for bootstrapping a node
@@ -219,12 +290,18 @@ type Node struct {
 	consensusReactor  *cs.Reactor             // for participating in the consensus
 	pexReactor        *pex.Reactor            // for exchanging peer addresses
 	evidencePool      *evidence.Pool          // tracking evidence
+	receiptStore      *sm.ReceiptStore        // receipts for bundles in our proposals
 	proxyApp          proxy.AppConns          // connection to the application
 	rpcListeners      []net.Listener          // rpc servers
 	txIndexer         txindex.TxIndexer
 	blockIndexer      indexer.BlockIndexer
 	indexerService    *txindex.IndexerService
 	prometheusSrv     *http.Server
+	statsdExporter    *statsdExporter          // pushes the MEV metrics, nil unless statsd_addr is set
+	bundleAuditLog    *mempl.BundleAuditLog    // nil unless audit_log_path is set
+	bundleMirror      *mempl.BundleMirror      // nil unless mirror_addr is set
+	mevStats          *mempl.MEVStats          // what became of the sidecar's bundles
+	tracerProvider    *sdktrace.TracerProvider // exports spans, nil unless tracing is enabled
 }
 
 func initDBs(config *cfg.Config, dbProvider DBProvider) (blockStore *store.BlockStore, stateDB dbm.DB, err error) {
@@ -362,19 +439,108 @@ func onlyValidatorIsUs(state sm.State, pubKey crypto.PubKey) bool {
 }
 
 func createMempoolAndSidecarAndMempoolReactor(config *cfg.Config, proxyApp proxy.AppConns,
-	state sm.State, memplMetrics *mempl.Metrics, logger log.Logger) (*mempl.Reactor, *mempl.CListMempool, *mempl.CListPriorityTxSidecar) {
+	state sm.State, memplMetrics *mempl.Metrics, txFeed *mempl.TxFeed, eventBus types.SidecarEventPublisher,
+	tracerProvider trace.TracerProvider, auditLog *mempl.BundleAuditLog, mirror *mempl.BundleMirror,
+	mevStats *mempl.MEVStats, logger log.Logger) (*mempl.Reactor, *mempl.CListMempool, *mempl.CListPriorityTxSidecar) {
 
+	mempoolOptions := []mempl.CListMempoolOption{
+		mempl.WithMetrics(memplMetrics),
+		mempl.WithTxFeed(txFeed),
+		mempl.WithPreCheck(sm.TxPreCheck(state)),
+		mempl.WithPostCheck(sm.TxPostCheck(state)),
+	}
+	if config.Sidecar.SelfBuild {
+		mempoolOptions = append(mempoolOptions, mempl.WithFeeAttribute(config.Sidecar.SelfBuildFeeAttribute))
+	}
+	if config.Mempool.SenderAttribute != "" {
+		mempoolOptions = append(mempoolOptions,
+			mempl.WithSenderOrdering(config.Mempool.SenderAttribute, config.Mempool.SequenceAttribute))
+	}
+	if config.Mempool.DeterministicReap {
+		mempoolOptions = append(mempoolOptions, mempl.WithDeterministicReap())
+	}
+	if config.Mempool.ReapTimeBudget > 0 {
+		mempoolOptions = append(mempoolOptions, mempl.WithReapTimeBudget(config.Mempool.ReapTimeBudget))
+	}
+	if config.Mempool.MaxPendingCheckTxs > 0 {
+		mempoolOptions = append(mempoolOptions, mempl.WithMaxPendingCheckTxs(config.Mempool.MaxPendingCheckTxs))
+	}
+	if config.Mempool.MaxTxsPerSender > 0 {
+		mempoolOptions = append(mempoolOptions, mempl.WithMaxTxsPerSender(config.Mempool.MaxTxsPerSender))
+	}
+	if config.Mempool.PriorityAttribute != "" {
+		mempoolOptions = append(mempoolOptions, mempl.WithPriorityOrdering(config.Mempool.PriorityAttribute))
+	}
+	if config.Mempool.Lanes != "" {
+		// already validated by ValidateBasic
+		lanes, _ := config.Mempool.LaneList()
+		mempoolOptions = append(mempoolOptions, mempl.WithLanes(lanes, config.Mempool.LaneAttribute))
+	}
+	if config.Mempool.ReplaceByFee {
+		// the fee attribute matches the self-build one, as checked by ValidateBasic
+		mempoolOptions = append(mempoolOptions,
+			mempl.WithFeeAttribute(config.Mempool.FeeAttribute), mempl.WithReplaceByFee())
+	}
+	var checkTxResults *mempl.CheckTxResultCache
+	if config.Mempool.CheckTxResultCacheSize > 0 {
+		checkTxResults = mempl.NewCheckTxResultCache(config.Mempool.CheckTxResultCacheSize)
+		mempoolOptions = append(mempoolOptions, mempl.WithCheckTxResultCache(checkTxResults))
+	}
+	if config.Mempool.CheckTxBatch {
+		batchChecker := mempl.NewQueryTxBatchChecker(proxyApp.Query(), config.Mempool.CheckTxBatchQueryPath)
+		if batchChecker.Supported() {
+			mempoolOptions = append(mempoolOptions, mempl.WithTxBatchChecker(batchChecker))
+			if config.Mempool.RecheckBatch {
+				mempoolOptions = append(mempoolOptions, mempl.WithBatchedRecheck())
+			}
+		} else {
+			logger.Info("App doesn't support batched CheckTx, checking txs one by one",
+				"path", config.Mempool.CheckTxBatchQueryPath)
+		}
+	}
+	// the prefixes were checked by ValidateBasic
+	protectedPrefixes, _ := config.Sidecar.ProtectedTxPrefixList()
+	if len(protectedPrefixes) > 0 || config.Sidecar.ProtectedTxAttribute != "" {
+		mempoolOptions = append(mempoolOptions,
+			mempl.WithProtectedTxs(protectedPrefixes, config.Sidecar.ProtectedTxAttribute))
+	}
 	mempool := mempl.NewCListMempool(
 		config.Mempool,
 		proxyApp.Mempool(),
 		state.LastBlockHeight,
-		mempl.WithMetrics(memplMetrics),
-		mempl.WithPreCheck(sm.TxPreCheck(state)),
-		mempl.WithPostCheck(sm.TxPostCheck(state)),
+		mempoolOptions...,
 	)
 
+	sidecarOptions := []mempl.CListSidecarOption{
+		mempl.WithSidecarConfig(config.Sidecar),
+		mempl.WithSidecarChainID(state.ChainID),
+		mempl.WithProposalDelay(config.Consensus.TimeoutCommit),
+		mempl.WithSidecarTxFeed(txFeed),
+		mempl.WithSidecarEventBus(eventBus),
+		mempl.WithSidecarMetrics(memplMetrics),
+		mempl.WithSidecarTracer(tracerProvider.Tracer("tendermint/mempool")),
+		mempl.WithMEVStats(mevStats),
+		// bundle txs also in the mempool stay there until their height
+		mempl.WithTxPinner(mempool),
+	}
+	// sentries and relays never include bundles, so don't have the app check
+	// them
+	if config.Sidecar.CheckBundles && config.Sidecar.IncludesBundles() {
+		sidecarOptions = append(sidecarOptions, mempl.WithBundleChecker(
+			mempl.NewQueryBundleChecker(proxyApp.Query(), config.Sidecar.CheckBundleQueryPath)))
+	}
+	if checkTxResults != nil {
+		sidecarOptions = append(sidecarOptions, mempl.WithSidecarCheckTxResultCache(checkTxResults))
+	}
+	if auditLog != nil {
+		sidecarOptions = append(sidecarOptions, mempl.WithBundleAuditLog(auditLog))
+	}
+	if mirror != nil {
+		sidecarOptions = append(sidecarOptions, mempl.WithBundleMirror(mirror))
+	}
 	sidecar := mempl.NewCListSidecar(
 		state.LastBlockHeight,
+		sidecarOptions...,
 	)
 
 	mempoolLogger := logger.With("module", "mempool")
@@ -537,6 +703,52 @@ func contains(s []string, str string) bool {
 	return false
 }
 
+// sidecarPeerList returns the IDs of the peers sidecar txs are gossiped with:
+// the personal peers, and the relay.
+func sidecarPeerList(config *cfg.SidecarConfig) []string {
+	peerList := splitAndTrimEmpty(config.PersonalPeerIDs, ",", " ")
+	if relayerID := config.RelayPeerID(); !contains(peerList, relayerID) && relayerID != "" {
+		peerList = append(peerList, relayerID)
+	}
+	return peerList
+}
+
+// validateMEVConfig validates the MEV configuration of the node whose ID is
+// nodeID, before anything is started.
+func validateMEVConfig(config *cfg.Config, nodeID p2p.ID) error {
+	if err := config.ValidateMEV(); err != nil {
+		return err
+	}
+	if contains(sidecarPeerList(config.Sidecar), string(nodeID)) {
+		return fmt.Errorf("sidecar personal_peer_ids and relayer_id can't include this node's own ID %s", nodeID)
+	}
+	return nil
+}
+
+// loadRelaySigner returns the relay key stored in the file set by config,
+// nil if none is.
+func loadRelaySigner(config *cfg.SidecarConfig) (mempl.RelaySigner, error) {
+	path := config.RelayKeyFilePath()
+	if path == "" {
+		return nil, nil
+	}
+	key, err := mempl.LoadFileRelayKey(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load relay key from %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// persistentPeerList returns the addresses of the peers this node keeps
+// connected to: the persistent peers, and the relay if its address is set.
+func persistentPeerList(config *cfg.Config) []string {
+	peerList := splitAndTrimEmpty(config.P2P.PersistentPeers, ",", " ")
+	if relayAddr := config.Sidecar.RelayAddr; relayAddr != "" && !contains(peerList, relayAddr) {
+		peerList = append(peerList, relayAddr)
+	}
+	return peerList
+}
+
 func createSwitch(config *cfg.Config,
 	transport p2p.Transport,
 	p2pMetrics *p2p.Metrics,
@@ -548,23 +760,25 @@ func createSwitch(config *cfg.Config,
 	evidenceReactor *evidence.Reactor,
 	nodeInfo p2p.NodeInfo,
 	nodeKey *p2p.NodeKey,
+	sidecarAddrBook *p2p.SidecarAddrBook,
 	p2pLogger log.Logger) *p2p.Switch {
-	peerList := splitAndTrimEmpty(config.Sidecar.PersonalPeerIDs, ",", " ")
-
-	if !contains(peerList, config.Sidecar.RelayerID) && config.Sidecar.RelayerID != "" {
-		peerList = append(peerList, config.Sidecar.RelayerID)
-	}
-	sidecarPeers, err := p2p.NewSidecarPeers(peerList)
+	sidecarPeers, err := p2p.NewSidecarPeers(sidecarPeerList(config.Sidecar))
 	if err != nil {
 		p2pLogger.Error(fmt.Sprintf("Error initializing sidecar peers: %s", err))
 	}
 
+	options := []p2p.SwitchOption{
+		p2p.WithMetrics(p2pMetrics),
+		p2p.SwitchPeerFilters(peerFilters...),
+	}
+	if sidecarAddrBook != nil {
+		options = append(options, p2p.SwitchSidecarAddrBook(sidecarAddrBook))
+	}
 	sw := p2p.NewSwitch(
 		config.P2P,
 		sidecarPeers,
 		transport,
-		p2p.WithMetrics(p2pMetrics),
-		p2p.SwitchPeerFilters(peerFilters...),
+		options...,
 	)
 	sw.SetLogger(p2pLogger)
 	sw.AddReactor("MEMPOOL", mempoolReactor)
@@ -707,6 +921,18 @@ func NewNode(config *cfg.Config,
 		return nil, err
 	}
 
+	// The MEV profile of the chain fills in the [sidecar] settings left at
+	// their defaults, before they're validated and used.
+	if ok, err := config.Sidecar.ApplyProfile(genDoc.ChainID); err != nil {
+		return nil, fmt.Errorf("invalid MEV configuration: %w", err)
+	} else if ok {
+		logger.Info("Applied MEV profile", "chain_id", genDoc.ChainID, "relay", config.Sidecar.RelayPeerID(),
+			"auction_cutoff", config.Sidecar.AuctionCutoff, "auction_window", config.Sidecar.AuctionWindow)
+	}
+	if err := validateMEVConfig(config, nodeKey.ID()); err != nil {
+		return nil, fmt.Errorf("invalid MEV configuration: %w", err)
+	}
+
 	// Create the proxyApp and establish connections to the ABCI app (consensus, mempool, query).
 	proxyApp, err := createAndStartProxyAppConns(clientCreator, logger)
 	if err != nil {
@@ -773,10 +999,44 @@ func NewNode(config *cfg.Config,
 
 	logNodeStartupInfo(state, pubKey, logger, consensusLogger)
 
-	csMetrics, p2pMetrics, memplMetrics, smMetrics := metricsProvider(genDoc.ChainID)
+	csMetrics, p2pMetrics, memplMetrics, smMetrics := metricsProvider(
+		genDoc.ChainID, mevMetricsLabels(config, state, pubKey))
+
+	tracerProvider, sdkTracerProvider, err := createTracerProvider(
+		config.Instrumentation, genDoc.ChainID, nodeKey.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	var bundleAuditLog *mempl.BundleAuditLog
+	if config.Sidecar.AuditLogPath != "" {
+		bundleAuditLog, err = mempl.NewBundleAuditLog(config.Sidecar.AuditLogFile(),
+			config.Sidecar.AuditLogMaxFileSize, config.Sidecar.AuditLogMaxTotalSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open bundle audit log: %w", err)
+		}
+		bundleAuditLog.SetLogger(logger.With("module", "mempool"))
+	}
+	var bundleMirror *mempl.BundleMirror
+	if config.Sidecar.MirrorAddr != "" {
+		bundleMirror, err = mempl.NewBundleMirror(config.Sidecar.MirrorAddr, config.Sidecar.MirrorRedactTxs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bundle mirror: %w", err)
+		}
+		bundleMirror.SetLogger(logger.With("module", "mempool"))
+	}
 
 	// Make MempoolReactor
-	mempoolReactor, mempool, sidecar := createMempoolAndSidecarAndMempoolReactor(config, proxyApp, state, memplMetrics, logger)
+	txFeed := mempl.NewTxFeed()
+	mevStats := mempl.NewMEVStats(mempl.DefaultMEVStatsHeights)
+	mempoolReactor, mempool, sidecar := createMempoolAndSidecarAndMempoolReactor(
+		config, proxyApp, state, memplMetrics, txFeed, eventBus, tracerProvider,
+		bundleAuditLog, bundleMirror, mevStats, logger)
+	relaySigner, err := loadRelaySigner(config.Sidecar)
+	if err != nil {
+		return nil, err
+	}
+	mempoolReactor.SetRelaySigner(relaySigner)
 
 	// Make Evidence Reactor
 	evidenceReactor, evidencePool, err := createEvidenceReactor(config, dbProvider, stateDB, blockStore, logger)
@@ -785,6 +1045,43 @@ func NewNode(config *cfg.Config,
 	}
 
 	// make block executor for consensus and blockchain reactors to execute blocks
+	blockExecOptions := []sm.BlockExecutorOption{
+		sm.BlockExecutorWithMetrics(smMetrics),
+		sm.BlockExecutorWithTracer(tracerProvider.Tracer("tendermint/state")),
+	}
+	if config.Sidecar.CheckProposalInvariants {
+		blockExecOptions = append(blockExecOptions, sm.BlockExecutorWithInvariantChecks(false))
+	}
+	if config.Sidecar.SelfBuild && config.Sidecar.IncludesBundles() {
+		blockExecOptions = append(blockExecOptions,
+			sm.BlockExecutorWithBundleBuilder(mempl.NewSelfBuilder(mempool, sidecar, config.Sidecar.SelfBuildMaxTxs)))
+	}
+	if config.Sidecar.SimulateProposals && config.Sidecar.IncludesBundles() {
+		blockExecOptions = append(blockExecOptions,
+			sm.BlockExecutorWithProposalSimulator(
+				sm.NewQueryProposalSimulator(proxyApp.Query(), config.Sidecar.SimulationQueryPath)))
+	}
+	if config.Sidecar.DryRun {
+		blockExecOptions = append(blockExecOptions, sm.BlockExecutorWithMEVDryRun())
+	}
+	var receiptStore *sm.ReceiptStore
+	if config.Sidecar.BundleReceipts {
+		var onCommit func([]types.BundleReceipt)
+		if config.Sidecar.PushReceipts {
+			onCommit = mempoolReactor.BroadcastReceipts
+		}
+		receiptStore = sm.NewReceiptStore(config.Sidecar.ReceiptRetainHeights, onCommit)
+		blockExecOptions = append(blockExecOptions, sm.BlockExecutorWithReceipts(nodeKey.PrivKey, receiptStore))
+	}
+	if config.Instrumentation.ProfileThreshold > 0 {
+		blockExecOptions = append(blockExecOptions,
+			sm.BlockExecutorWithSlowProposalProfiler(sm.NewSlowProposalProfiler(
+				config.Instrumentation.ProfileDirPath(),
+				config.Instrumentation.ProfileThreshold,
+				config.Instrumentation.ProfileCPUDuration,
+				logger.With("module", "state"),
+			)))
+	}
 	blockExec := sm.NewBlockExecutor(
 		stateStore,
 		logger.With("module", "state"),
@@ -792,7 +1089,7 @@ func NewNode(config *cfg.Config,
 		mempool,
 		evidencePool,
 		sidecar,
-		sm.BlockExecutorWithMetrics(smMetrics),
+		blockExecOptions...,
 	)
 
 	// Make BlockchainReactor. Don't start fast sync if we're doing a state sync first.
@@ -834,16 +1131,22 @@ func NewNode(config *cfg.Config,
 	transport, peerFilters := createTransport(config, nodeInfo, nodeKey, proxyApp)
 
 	// Setup Switch.
-	if config.Sidecar.RelayerID == "" {
+	if config.Sidecar.RelayPeerID() == "" {
 		logger.Error("Relayer ID not set -- Will not participate in mev auctions")
 	}
+	var sidecarAddrBook *p2p.SidecarAddrBook
+	if path := config.Sidecar.AddrBookFilePath(); path != "" {
+		if sidecarAddrBook, err = p2p.NewSidecarAddrBook(path); err != nil {
+			return nil, fmt.Errorf("could not load sidecar addrbook: %w", err)
+		}
+	}
 	p2pLogger := logger.With("module", "p2p")
 	sw := createSwitch(
 		config, transport, p2pMetrics, peerFilters, mempoolReactor, bcReactor,
-		stateSyncReactor, consensusReactor, evidenceReactor, nodeInfo, nodeKey, p2pLogger,
+		stateSyncReactor, consensusReactor, evidenceReactor, nodeInfo, nodeKey, sidecarAddrBook, p2pLogger,
 	)
 
-	err = sw.AddPersistentPeers(splitAndTrimEmpty(config.P2P.PersistentPeers, ",", " "))
+	err = sw.AddPersistentPeers(persistentPeerList(config))
 	if err != nil {
 		return nil, fmt.Errorf("could not add peers from persistent_peers field: %w", err)
 	}
@@ -876,9 +1179,14 @@ func NewNode(config *cfg.Config,
 	}
 
 	if config.RPC.PprofListenAddress != "" {
+		// serves pprof, registered on the default mux, and the sidecar's
+		// internals
+		debugMux := http.NewServeMux()
+		debugMux.Handle("/", http.DefaultServeMux)
+		debugMux.Handle("/debug/sidecar", mempl.SidecarDumpHandler(sidecar))
 		go func() {
 			logger.Info("Starting pprof server", "laddr", config.RPC.PprofListenAddress)
-			logger.Error("pprof server error", "err", http.ListenAndServe(config.RPC.PprofListenAddress, nil))
+			logger.Error("pprof server error", "err", http.ListenAndServe(config.RPC.PprofListenAddress, debugMux))
 		}()
 	}
 
@@ -898,6 +1206,8 @@ func NewNode(config *cfg.Config,
 		bcReactor:        bcReactor,
 		mempoolReactor:   mempoolReactor,
 		mempool:          mempool,
+		sidecar:          sidecar,
+		txFeed:           txFeed,
 		consensusState:   consensusState,
 		consensusReactor: consensusReactor,
 		stateSyncReactor: stateSyncReactor,
@@ -905,11 +1215,16 @@ func NewNode(config *cfg.Config,
 		stateSyncGenesis: state, // Shouldn't be necessary, but need a way to pass the genesis state
 		pexReactor:       pexReactor,
 		evidencePool:     evidencePool,
+		receiptStore:     receiptStore,
 		proxyApp:         proxyApp,
 		txIndexer:        txIndexer,
 		indexerService:   indexerService,
 		blockIndexer:     blockIndexer,
 		eventBus:         eventBus,
+		tracerProvider:   sdkTracerProvider,
+		bundleAuditLog:   bundleAuditLog,
+		bundleMirror:     bundleMirror,
+		mevStats:         mevStats,
 	}
 	node.BaseService = *service.NewBaseService(logger, "Node", node)
 
@@ -925,13 +1240,13 @@ func NewNode(config *cfg.Config,
 func (n *Node) getPrivateIds() []string {
 	// Add private IDs to addrbook to block those peers being added
 	privateIDs := splitAndTrimEmpty(n.config.P2P.PrivatePeerIDs, ",", " ")
-	relayerID := n.config.Sidecar.RelayerID
+	relayerID := n.config.Sidecar.RelayPeerID()
 	if len(relayerID) > 0 {
 		contains := false
 		for _, v := range privateIDs {
 			contains = v == relayerID || contains
 		}
-		if contains {
+		if !contains {
 			privateIDs = append(privateIDs, relayerID)
 		}
 	}
@@ -959,11 +1274,35 @@ func (n *Node) OnStart() error {
 		}
 		n.rpcListeners = listeners
 	}
+	if n.config.Sidecar.GRPCListenAddress != "" {
+		listener, err := n.startBundleSubmission()
+		if err != nil {
+			return err
+		}
+		n.rpcListeners = append(n.rpcListeners, listener)
+	}
 
 	if n.config.Instrumentation.Prometheus &&
 		n.config.Instrumentation.PrometheusListenAddr != "" {
 		n.prometheusSrv = n.startPrometheusServer(n.config.Instrumentation.PrometheusListenAddr)
 	}
+	if n.bundleAuditLog != nil {
+		if err := n.bundleAuditLog.Start(); err != nil {
+			return err
+		}
+	}
+	if n.bundleMirror != nil {
+		if err := n.bundleMirror.Start(); err != nil {
+			return err
+		}
+	}
+	if n.config.Instrumentation.StatsdAddr != "" {
+		exporter := newStatsdExporter(n.config.Instrumentation, n.Logger.With("module", "statsd"))
+		if err := exporter.Start(); err != nil {
+			return err
+		}
+		n.statsdExporter = exporter
+	}
 
 	// Start the transport.
 	addr, err := p2p.NewNetAddressString(p2p.IDAddressString(n.nodeKey.ID(), n.config.P2P.ListenAddress))
@@ -990,7 +1329,7 @@ func (n *Node) OnStart() error {
 	}
 
 	// Always connect to persistent peers
-	err = n.sw.DialPeersAsync(splitAndTrimEmpty(n.config.P2P.PersistentPeers, ",", " "))
+	err = n.sw.DialPeersAsync(persistentPeerList(n.config))
 	if err != nil {
 		return fmt.Errorf("could not dial peers from persistent_peers field: %w", err)
 	}
@@ -1061,6 +1400,29 @@ func (n *Node) OnStop() {
 			n.Logger.Error("Prometheus HTTP server Shutdown", "err", err)
 		}
 	}
+	if n.statsdExporter != nil {
+		if err := n.statsdExporter.Stop(); err != nil {
+			n.Logger.Error("Error stopping statsd exporter", "err", err)
+		}
+	}
+	if n.bundleAuditLog != nil {
+		if err := n.bundleAuditLog.Stop(); err != nil {
+			n.Logger.Error("Error stopping bundle audit log", "err", err)
+		}
+	}
+	if n.bundleMirror != nil {
+		if err := n.bundleMirror.Stop(); err != nil {
+			n.Logger.Error("Error stopping bundle mirror", "err", err)
+		}
+	}
+	if n.tracerProvider != nil {
+		// flushes the spans left, unless the collector doesn't answer in time
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := n.tracerProvider.Shutdown(ctx); err != nil {
+			n.Logger.Error("Tracer provider Shutdown", "err", err)
+		}
+		cancel()
+	}
 	if n.blockStore != nil {
 		if err := n.blockStore.Close(); err != nil {
 			n.Logger.Error("problem closing blockstore", "err", err)
@@ -1079,7 +1441,7 @@ func (n *Node) ConfigureRPC() error {
 	if err != nil {
 		return fmt.Errorf("can't get pubkey: %w", err)
 	}
-	rpccore.SetEnvironment(&rpccore.Environment{
+	rpcEnv := &rpccore.Environment{
 		ProxyAppQuery:   n.proxyApp.Query(),
 		ProxyAppMempool: n.proxyApp.Mempool(),
 
@@ -1089,6 +1451,7 @@ func (n *Node) ConfigureRPC() error {
 		ConsensusState: n.consensusState,
 		P2PPeers:       n.sw,
 		P2PTransport:   n,
+		SidecarConfig:  n,
 
 		PubKey:           pubKey,
 		GenDoc:           n.genesisDoc,
@@ -1097,11 +1460,19 @@ func (n *Node) ConfigureRPC() error {
 		ConsensusReactor: n.consensusReactor,
 		EventBus:         n.eventBus,
 		Mempool:          n.mempool,
+		Sidecar:          n.sidecar,
+		ReceiptStore:     n.receiptStore,
+		MempoolReactor:   n.mempoolReactor,
+		MEVStats:         n.mevStats,
 
 		Logger: n.Logger.With("module", "rpc"),
 
 		Config: *n.config.RPC,
-	})
+	}
+	if sidecar, ok := n.sidecar.(*mempl.CListPriorityTxSidecar); ok {
+		rpcEnv.SidecarDumper = sidecar
+	}
+	rpccore.SetEnvironment(rpcEnv)
 	if err := rpccore.InitGenesisChunks(); err != nil {
 		return err
 	}
@@ -1120,6 +1491,15 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 	if n.config.RPC.Unsafe {
 		rpccore.AddUnsafeRoutes()
 	}
+	if n.config.RPC.EthSendBundle {
+		rpccore.AddEthBundleRoutes()
+	}
+	if n.config.RPC.BroadcastBundle {
+		rpccore.AddBroadcastBundleRoutes()
+	}
+	if n.config.RPC.BundleRoutesOnListenAddress {
+		rpccore.AddBundleRoutesToRoutes()
+	}
 
 	config := rpcserver.DefaultConfig()
 	config.MaxBodyBytes = n.config.RPC.MaxBodyBytes
@@ -1224,10 +1604,46 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 
 	}
 
+	// the bundle submission endpoints are served apart, so they can be kept
+	// off a public listener
+	bundleListenAddr := n.config.RPC.BundleListenAddress
+	if bundleListenAddr != "" && len(rpccore.BundleRoutes) > 0 {
+		mux := http.NewServeMux()
+		rpcLogger := n.Logger.With("module", "rpc-server", "listener", "bundle")
+		rpcserver.RegisterRPCFuncs(mux, rpccore.BundleRoutes, rpcLogger)
+		listener, err := rpcserver.Listen(bundleListenAddr, config)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			if err := rpcserver.Serve(listener, mux, rpcLogger, config); err != nil {
+				n.Logger.Error("Error serving the bundle submission server", "err", err)
+			}
+		}()
+		listeners = append(listeners, listener)
+	}
+
 	return listeners, nil
 
 }
 
+// startBundleSubmission serves the BundleSubmission gRPC service on
+// SidecarConfig.GRPCListenAddress, for the relay to submit bundles directly.
+func (n *Node) startBundleSubmission() (net.Listener, error) {
+	config := rpcserver.DefaultConfig()
+	config.MaxOpenConnections = n.config.RPC.GRPCMaxOpenConnections
+	listener, err := rpcserver.Listen(n.config.Sidecar.GRPCListenAddress, config)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := mempl.StartBundleSubmissionServer(listener, n.mempoolReactor); err != nil {
+			n.Logger.Error("Error starting the bundle submission gRPC server", "err", err)
+		}
+	}()
+	return listener, nil
+}
+
 // startPrometheusServer starts a Prometheus HTTP server, listening for metrics
 // collectors on addr.
 func (n *Node) startPrometheusServer(addr string) *http.Server {
@@ -1279,6 +1695,167 @@ func (n *Node) Mempool() mempl.Mempool {
 	return n.mempool
 }
 
+// Sidecar returns the Node's sidecar.
+func (n *Node) Sidecar() mempl.PriorityTxSidecar {
+	return n.sidecar
+}
+
+// ReloadSidecarConfig re-reads the [sidecar] section of the config file and
+// applies the settings that can change while the node runs: the sidecar
+// peers, the relay, the bid floor and whether MEV is disabled. The others are
+// left as they were until the node restarts.
+func (n *Node) ReloadSidecarConfig() error {
+	n.sidecarConfigMtx.Lock()
+	defer n.sidecarConfigMtx.Unlock()
+	return n.reloadSidecarConfig()
+}
+
+// AddSidecarPeer adds id to the sidecar personal_peer_ids in the config file,
+// and applies it without restarting the node.
+func (n *Node) AddSidecarPeer(id p2p.ID) error {
+	if id == n.nodeKey.ID() {
+		return fmt.Errorf("can't add this node's own ID %s as a sidecar peer", id)
+	}
+	return n.updateSidecarConfig(func(config *cfg.SidecarConfig) error {
+		peers := splitAndTrimEmpty(config.PersonalPeerIDs, ",", " ")
+		if contains(peers, string(id)) {
+			return fmt.Errorf("%s is already a sidecar peer", id)
+		}
+		config.PersonalPeerIDs = strings.Join(append(peers, string(id)), ",")
+		return nil
+	})
+}
+
+// RemoveSidecarPeer removes id from the sidecar personal_peer_ids in the
+// config file, and applies it without restarting the node.
+func (n *Node) RemoveSidecarPeer(id p2p.ID) error {
+	return n.updateSidecarConfig(func(config *cfg.SidecarConfig) error {
+		peers := splitAndTrimEmpty(config.PersonalPeerIDs, ",", " ")
+		kept := make([]string, 0, len(peers))
+		for _, peer := range peers {
+			if peer != string(id) {
+				kept = append(kept, peer)
+			}
+		}
+		if len(kept) == len(peers) {
+			return fmt.Errorf("%s isn't a sidecar peer", id)
+		}
+		config.PersonalPeerIDs = strings.Join(kept, ",")
+		return nil
+	})
+}
+
+// SetRelay sets the relay in the config file to relay, either an id@host:port
+// address to dial or a node ID, registering with apiKey if set, and applies
+// it without restarting the node. An empty relay removes it, leaving that of
+// the chain's MEV profile if any.
+func (n *Node) SetRelay(relay, apiKey string) error {
+	return n.updateSidecarConfig(func(config *cfg.SidecarConfig) error {
+		config.RelayerID, config.RelayAddr = "", ""
+		if strings.Contains(relay, "@") {
+			config.RelayAddr = relay
+		} else {
+			config.RelayerID = relay
+		}
+		if config.RelayPeerID() == string(n.nodeKey.ID()) {
+			return fmt.Errorf("can't set this node's own ID %s as the relay", n.nodeKey.ID())
+		}
+		config.RelayAPIKey = apiKey
+		return nil
+	})
+}
+
+// SetMEVDisabled sets mev_disabled in the config file, and applies it without
+// restarting the node: while disabled, the node takes no sidecar tx, runs no
+// auction and proposes like a node without MEV, which makes for an emergency
+// rollback without swapping binaries.
+func (n *Node) SetMEVDisabled(disabled bool) error {
+	return n.updateSidecarConfig(func(config *cfg.SidecarConfig) error {
+		config.MEVDisabled = disabled
+		return nil
+	})
+}
+
+// updateSidecarConfig applies update to the [sidecar] section of the config
+// file, and reloads it.
+func (n *Node) updateSidecarConfig(update func(*cfg.SidecarConfig) error) error {
+	n.sidecarConfigMtx.Lock()
+	defer n.sidecarConfigMtx.Unlock()
+	if err := cfg.UpdateSidecarConfigFile(n.config.RootDir, update); err != nil {
+		return err
+	}
+	return n.reloadSidecarConfig()
+}
+
+func (n *Node) reloadSidecarConfig() error {
+	config, err := cfg.LoadSidecarConfig(n.config.RootDir, n.genesisDoc.ChainID)
+	if err != nil {
+		return err
+	}
+	relaySigner := n.relaySigner
+	if relaySigner == nil {
+		if relaySigner, err = loadRelaySigner(config); err != nil {
+			return err
+		}
+	}
+	sidecarPeers, err := p2p.NewSidecarPeers(sidecarPeerList(config))
+	if err != nil {
+		return fmt.Errorf("invalid sidecar peers: %w", err)
+	}
+	relayerID := config.RelayPeerID()
+	if relayerID != "" {
+		// the relay is kept out of the address book, as at startup
+		if err := n.sw.AddPrivatePeerIDs([]string{relayerID}); err != nil {
+			return fmt.Errorf("invalid relayer_id: %w", err)
+		}
+	}
+	if config.RelayAddr != "" {
+		// a relay dialed before keeps being dialed until the node restarts
+		if err := n.sw.AddPersistentPeers([]string{config.RelayAddr}); err != nil {
+			return fmt.Errorf("invalid relay_addr: %w", err)
+		}
+	}
+
+	allowlist, err := config.RelayAllowlist()
+	if err != nil {
+		return fmt.Errorf("invalid relay_pub_keys: %w", err)
+	}
+	relayAllowlist := make([]p2p.ID, len(allowlist))
+	for i, id := range allowlist {
+		relayAllowlist[i] = p2p.ID(id)
+	}
+	skipFormatPeerIDs := config.SkipFormatPeerIDList()
+	skipFormatPeers := make([]p2p.ID, len(skipFormatPeerIDs))
+	for i, id := range skipFormatPeerIDs {
+		skipFormatPeers[i] = p2p.ID(id)
+	}
+
+	n.sw.SetSidecarPeers(sidecarPeers)
+	n.mempoolReactor.SetRelayAllowlist(relayAllowlist)
+	n.mempoolReactor.SetSkipFormatPeers(skipFormatPeers)
+	n.mempoolReactor.SetRelay(p2p.ID(relayerID), config.RelayAPIKey)
+	n.mempoolReactor.SetRelaySigner(relaySigner)
+	n.mempoolReactor.SetMEVDisabled(config.MEVDisabled)
+	if sidecar, ok := n.sidecar.(*mempl.CListPriorityTxSidecar); ok {
+		sidecar.SetMinBid(config.MinBid)
+	}
+	if config.RelayAddr != "" {
+		if err := n.sw.DialPeersAsync([]string{config.RelayAddr}); err != nil {
+			return fmt.Errorf("could not dial relay_addr: %w", err)
+		}
+	}
+	n.Logger.Info("Reloaded sidecar config", "personal_peer_ids", config.PersonalPeerIDs,
+		"relayer_id", relayerID, "relay_addr", config.RelayAddr, "min_bid", config.MinBid,
+		"mev_disabled", config.MEVDisabled)
+	return nil
+}
+
+// TxFeed returns the Node's feed of txs added to and removed from the mempool
+// and sidecar.
+func (n *Node) TxFeed() *mempl.TxFeed {
+	return n.txFeed
+}
+
 // PEXReactor returns the Node's PEXReactor. It returns nil if PEX is disabled.
 func (n *Node) PEXReactor() *pex.Reactor {
 	return n.pexReactor
@@ -1374,11 +1951,20 @@ func makeNodeInfo(
 		},
 		Moniker: config.Moniker,
 		Other: p2p.DefaultNodeInfoOther{
-			TxIndex:    txIndexerStatus,
-			RPCAddress: config.RPC.ListenAddress,
+			TxIndex:     txIndexerStatus,
+			RPCAddress:  config.RPC.ListenAddress,
+			MEVDisabled: config.Sidecar.MEVDisabled,
 		},
 	}
 
+	if config.Mempool.Lanes != "" {
+		lanes, err := config.Mempool.LaneList()
+		if err != nil {
+			return nodeInfo, err
+		}
+		nodeInfo.Channels = append(nodeInfo.Channels, mempl.LaneChannels(lanes)...)
+	}
+
 	if config.P2P.PexReactor {
 		nodeInfo.Channels = append(nodeInfo.Channels, pex.PexChannel)
 	}
@@ -3,12 +3,17 @@ package node
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -19,6 +24,7 @@ import (
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/evidence"
 	"github.com/tendermint/tendermint/libs/log"
+	tmnet "github.com/tendermint/tendermint/libs/net"
 	tmrand "github.com/tendermint/tendermint/libs/rand"
 	mempl "github.com/tendermint/tendermint/mempool"
 	"github.com/tendermint/tendermint/p2p"
@@ -75,6 +81,34 @@ func TestNodeStartStop(t *testing.T) {
 	}
 }
 
+func TestNodeBundleListener(t *testing.T) {
+	config := cfg.ResetTestRoot("node_bundle_listener_test")
+	defer os.RemoveAll(config.RootDir)
+	port, err := tmnet.GetFreePort()
+	require.NoError(t, err)
+	config.RPC.EthSendBundle = true
+	config.RPC.BundleListenAddress = fmt.Sprintf("tcp://127.0.0.1:%d", port)
+
+	n, err := DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+	require.NoError(t, n.Start())
+	defer func() {
+		require.NoError(t, n.Stop())
+	}()
+
+	// eth_sendBundle is served on bundle_laddr only
+	routes := func(laddr string) string {
+		resp, err := http.Get("http://" + strings.TrimPrefix(laddr, "tcp://"))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+	assert.Contains(t, routes(config.RPC.BundleListenAddress), "eth_sendBundle")
+	assert.NotContains(t, routes(config.RPC.ListenAddress), "eth_sendBundle")
+}
+
 func TestSplitAndTrimEmpty(t *testing.T) {
 	testCases := []struct {
 		s        string
@@ -94,6 +128,98 @@ func TestSplitAndTrimEmpty(t *testing.T) {
 	}
 }
 
+func TestMEVMetricsLabels(t *testing.T) {
+	state, _, privVals := state(1, 1)
+	valPubKey, err := privVals[0].GetPubKey()
+	require.NoError(t, err)
+	otherPubKey := ed25519.GenPrivKey().PubKey()
+	config := cfg.TestConfig()
+
+	// the mode defaults to whether the node validates
+	assert.Equal(t, MEVMetricsLabels{
+		ValidatorAddress: valPubKey.Address().String(),
+		NodeMode:         cfg.NodeModeValidator,
+	}, mevMetricsLabels(config, state, valPubKey))
+	assert.Equal(t, MEVMetricsLabels{NodeMode: cfg.NodeModeSentry}, mevMetricsLabels(config, state, otherPubKey))
+
+	// or else to the sidecar's mode, for sentries and relays
+	config.Sidecar.Mode = cfg.NodeModeRelay
+	assert.Equal(t, MEVMetricsLabels{NodeMode: cfg.NodeModeRelay}, mevMetricsLabels(config, state, valPubKey))
+
+	config.Instrumentation.NodeMode = cfg.NodeModeSentry
+	assert.Equal(t, MEVMetricsLabels{NodeMode: cfg.NodeModeSentry}, mevMetricsLabels(config, state, valPubKey))
+}
+
+func TestStatsdExporter(t *testing.T) {
+	agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer agent.Close()
+	received := func() []string {
+		buf := make([]byte, statsdMaxPacketSize)
+		require.NoError(t, agent.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := agent.ReadFrom(buf)
+		require.NoError(t, err)
+		return strings.Split(string(buf[:n]), "\n")
+	}
+
+	registry := prometheus.NewRegistry()
+	txs := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tm", Subsystem: "mempool", Name: "txs",
+	}, []string{"node_mode", "validator_address"})
+	drift := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tm", Subsystem: "state", Name: "drift",
+	})
+	latency := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "tm", Subsystem: "state", Name: "latency",
+	})
+	// not part of the MEV subsystem
+	peers := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tm", Subsystem: "p2p", Name: "peers",
+	})
+	registry.MustRegister(txs, drift, latency, peers)
+	peers.Set(1)
+
+	exporter := func(protocol string) *statsdExporter {
+		e := newStatsdExporterWithGatherer(agent.LocalAddr().String(), protocol, time.Hour,
+			registry, []string{"tm_mempool_", "tm_state_"})
+		require.NoError(t, e.Start())
+		t.Cleanup(func() { e.Stop() }) //nolint:errcheck // ignore for tests
+		return e
+	}
+
+	e := exporter(cfg.StatsdProtocolStatsd)
+	txs.WithLabelValues("validator", "").Add(3)
+	drift.Set(-2)
+	latency.Observe(0.5)
+	require.NoError(t, e.push())
+	assert.Equal(t, []string{
+		"tm_mempool_txs.validator.none:3|c",
+		"tm_state_drift:0|g",
+		"tm_state_drift:-2|g",
+		"tm_state_latency_count:1|c",
+		"tm_state_latency_sum:0.5|c",
+	}, received())
+
+	// counters are pushed as their increments, if any
+	txs.WithLabelValues("validator", "").Add(2)
+	drift.Set(1)
+	require.NoError(t, e.push())
+	assert.Equal(t, []string{
+		"tm_mempool_txs.validator.none:2|c",
+		"tm_state_drift:1|g",
+	}, received())
+
+	// DogStatsD carries the labels as tags
+	e = exporter(cfg.StatsdProtocolDogStatsd)
+	require.NoError(t, e.push())
+	assert.Equal(t, []string{
+		"tm_mempool_txs:5|c|#node_mode:validator,validator_address:",
+		"tm_state_drift:1|g",
+		"tm_state_latency_count:1|c",
+		"tm_state_latency_sum:0.5|c",
+	}, received())
+}
+
 func TestNodeDelayedStart(t *testing.T) {
 	config := cfg.ResetTestRoot("node_delayed_start_test")
 	defer os.RemoveAll(config.RootDir)
@@ -432,6 +558,146 @@ func TestNodeNewNodeCustomReactors(t *testing.T) {
 	assert.Contains(t, channels, cr.Channels[0].ID)
 }
 
+func TestNodeNewNodeTxFilters(t *testing.T) {
+	config := cfg.ResetTestRoot("node_new_node_tx_filters_test")
+	defer os.RemoveAll(config.RootDir)
+
+	nodeKey, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+	require.NoError(t, err)
+
+	n, err := NewNode(config,
+		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir()),
+		DefaultGenesisDocProviderFunc(config),
+		DefaultDBProvider,
+		DefaultMetricsProvider(config.Instrumentation),
+		log.TestingLogger(),
+		TxFilters(mempl.MaxTxBytesFilter(4)),
+	)
+	require.NoError(t, err)
+
+	err = n.Mempool().CheckTx(types.Tx("too big"), nil, mempl.TxInfo{})
+	assert.IsType(t, mempl.ErrTxFiltered{}, err)
+	err = n.Sidecar().AddTx(types.Tx("too big"), mempl.TxInfo{DesiredHeight: 1, BundleSize: 1})
+	assert.IsType(t, mempl.ErrTxFiltered{}, err)
+}
+
+func TestNodeReloadSidecarConfig(t *testing.T) {
+	config := cfg.ResetTestRoot("node_reload_sidecar_config_test")
+	defer os.RemoveAll(config.RootDir)
+
+	n, err := DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+	relayerID := p2p.ID("7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e")
+	peerID := p2p.ID("d2b7b8a9c6e0f4f5e1a0c9d8b7a6f5e4d3c2b1a0")
+	require.False(t, n.Switch().IsSidecarPeer(relayerID))
+
+	// the sidecar peers, relay and bid floor are read again from the file
+	sidecarConfig := *config.Sidecar
+	sidecarConfig.RelayAddr = string(relayerID) + "@127.0.0.1:1"
+	sidecarConfig.RelayAPIKey = "secret"
+	sidecarConfig.PersonalPeerIDs = string(peerID)
+	sidecarConfig.MinBid = 10
+	reloaded := *config
+	reloaded.Sidecar = &sidecarConfig
+	configFile := filepath.Join(config.RootDir, "config", "config.toml")
+	cfg.WriteConfigFile(configFile, &reloaded)
+	require.NoError(t, n.ReloadSidecarConfig())
+	assert.True(t, n.Switch().IsSidecarPeer(relayerID))
+	assert.True(t, n.Switch().IsSidecarPeer(peerID))
+	err = n.Sidecar().AddTx(types.Tx("cheap"), mempl.TxInfo{DesiredHeight: 1, BundleSize: 1, Bid: 9})
+	assert.IsType(t, mempl.ErrBidTooLow{}, err)
+
+	// an invalid file is refused, and the settings left as they were
+	sidecarConfig.PersonalPeerIDs = "sentry"
+	cfg.WriteConfigFile(configFile, &reloaded)
+	assert.Error(t, n.ReloadSidecarConfig())
+	assert.True(t, n.Switch().IsSidecarPeer(peerID))
+}
+
+func TestNodeUpdateSidecarConfig(t *testing.T) {
+	config := cfg.ResetTestRoot("node_update_sidecar_config_test")
+	defer os.RemoveAll(config.RootDir)
+
+	n, err := DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+	relayerID := p2p.ID("7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e")
+	peerID := p2p.ID("d2b7b8a9c6e0f4f5e1a0c9d8b7a6f5e4d3c2b1a0")
+
+	// changes are applied, and persisted to the config file
+	require.NoError(t, n.AddSidecarPeer(peerID))
+	require.NoError(t, n.SetRelay(string(relayerID)+"@127.0.0.1:1", "secret"))
+	assert.True(t, n.Switch().IsSidecarPeer(peerID))
+	assert.True(t, n.Switch().IsSidecarPeer(relayerID))
+	persisted, err := cfg.LoadSidecarConfig(config.RootDir, n.GenesisDoc().ChainID)
+	require.NoError(t, err)
+	assert.Equal(t, string(peerID), persisted.PersonalPeerIDs)
+	assert.Equal(t, string(relayerID)+"@127.0.0.1:1", persisted.RelayAddr)
+	assert.Equal(t, "secret", persisted.RelayAPIKey)
+
+	// invalid changes are refused, and neither applied nor persisted
+	assert.Error(t, n.AddSidecarPeer(peerID))
+	assert.Error(t, n.AddSidecarPeer("sentry"))
+	assert.Error(t, n.AddSidecarPeer(n.NodeInfo().ID()))
+	assert.Error(t, n.SetRelay("relay", ""))
+	assert.True(t, n.Switch().IsSidecarPeer(relayerID))
+
+	require.NoError(t, n.RemoveSidecarPeer(peerID))
+	require.NoError(t, n.SetRelay("", ""))
+	assert.False(t, n.Switch().IsSidecarPeer(peerID))
+	assert.False(t, n.Switch().IsSidecarPeer(relayerID))
+	assert.Error(t, n.RemoveSidecarPeer(peerID))
+	persisted, err = cfg.LoadSidecarConfig(config.RootDir, n.GenesisDoc().ChainID)
+	require.NoError(t, err)
+	assert.Empty(t, persisted.PersonalPeerIDs)
+	assert.Empty(t, persisted.RelayPeerID())
+
+	// the MEV kill switch turns off bundle acceptance
+	require.NoError(t, n.SetMEVDisabled(true))
+	err = n.Sidecar().AddTx(types.Tx("bundle"), mempl.TxInfo{DesiredHeight: 1, BundleSize: 1})
+	assert.Equal(t, mempl.ErrMEVDisabled, err)
+	persisted, err = cfg.LoadSidecarConfig(config.RootDir, n.GenesisDoc().ChainID)
+	require.NoError(t, err)
+	assert.True(t, persisted.MEVDisabled)
+	require.NoError(t, n.SetMEVDisabled(false))
+	err = n.Sidecar().AddTx(types.Tx("bundle"), mempl.TxInfo{DesiredHeight: 1, BundleSize: 1})
+	assert.NoError(t, err)
+}
+
+func TestNodeRelayKey(t *testing.T) {
+	config := cfg.ResetTestRoot("node_relay_key_test")
+	defer os.RemoveAll(config.RootDir)
+	config.Sidecar.RelayerID = "7a0fcd1aa9d7b47ed3e5fa8b3ad1f7e30a4e0e3e"
+	config.Sidecar.RelayKeyFile = "config/relay_key.json"
+
+	// the relay key must exist if set
+	_, err := DefaultNewNode(config, log.TestingLogger())
+	assert.Error(t, err)
+
+	require.NoError(t, mempl.GenFileRelayKey().SaveAs(config.Sidecar.RelayKeyFilePath()))
+	_, err = DefaultNewNode(config, log.TestingLogger())
+	assert.NoError(t, err)
+}
+
+func TestNodeValidatesMEVConfig(t *testing.T) {
+	config := cfg.ResetTestRoot("node_validates_mev_config_test")
+	defer os.RemoveAll(config.RootDir)
+	nodeKey, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+	require.NoError(t, err)
+
+	// the node can't be its own sidecar peer
+	config.Sidecar.PersonalPeerIDs = string(nodeKey.ID())
+	_, err = DefaultNewNode(config, log.TestingLogger())
+	assert.Error(t, err)
+
+	// nor time its auctions against a skipped timeout_commit
+	config.Sidecar.PersonalPeerIDs = ""
+	config.Sidecar.AuctionWindow = time.Second
+	_, err = DefaultNewNode(config, log.TestingLogger())
+	assert.Error(t, err)
+}
+
 func state(nVals int, height int64) (sm.State, dbm.DB, []types.PrivValidator) {
 	privVals := make([]types.PrivValidator, nVals)
 	vals := make([]types.GenesisValidator, nVals)
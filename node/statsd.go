@@ -0,0 +1,263 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/libs/service"
+	mempl "github.com/tendermint/tendermint/mempool"
+	sm "github.com/tendermint/tendermint/state"
+)
+
+// statsdMaxPacketSize is the largest UDP packet sent to the agent, so that
+// it isn't fragmented on an ethernet link.
+const statsdMaxPacketSize = 1432
+
+// statsdExporter pushes the metrics gathered from a Prometheus gatherer to a
+// statsd or DogStatsD agent, for nodes whose metrics can't be scraped, eg.
+// validators behind sentries. The metrics keep their Prometheus names, so
+// that dashboards work the same for both.
+//
+// Counters are pushed as the increments since the last push. Histograms and
+// summaries are pushed as their _count and _sum counters only, their
+// buckets and quantiles having no statsd equivalent.
+type statsdExporter struct {
+	service.BaseService
+
+	addr     string
+	protocol string
+	interval time.Duration
+	gatherer prometheus.Gatherer
+	// only the families whose names start with one of these are pushed
+	prefixes []string
+
+	conn net.Conn
+	// value of each counter at the last push, by series
+	last map[string]float64
+}
+
+// newStatsdExporter returns an exporter pushing the metrics of the MEV
+// subsystem, the mempool's and the state's, from the default gatherer as
+// configured by config.
+func newStatsdExporter(config *cfg.InstrumentationConfig, logger log.Logger) *statsdExporter {
+	e := newStatsdExporterWithGatherer(
+		config.StatsdAddr,
+		config.StatsdProtocol,
+		config.StatsdInterval,
+		prometheus.DefaultGatherer,
+		[]string{
+			config.Namespace + "_" + mempl.MetricsSubsystem + "_",
+			config.Namespace + "_" + sm.MetricsSubsystem + "_",
+		},
+	)
+	e.SetLogger(logger)
+	return e
+}
+
+func newStatsdExporterWithGatherer(
+	addr, protocol string,
+	interval time.Duration,
+	gatherer prometheus.Gatherer,
+	prefixes []string,
+) *statsdExporter {
+	e := &statsdExporter{
+		addr:     addr,
+		protocol: protocol,
+		interval: interval,
+		gatherer: gatherer,
+		prefixes: prefixes,
+		last:     make(map[string]float64),
+	}
+	e.BaseService = *service.NewBaseService(nil, "StatsdExporter", e)
+	return e
+}
+
+// OnStart implements service.Service.
+func (e *statsdExporter) OnStart() error {
+	// UDP, so this doesn't wait on, nor fail if there's no agent listening
+	conn, err := net.Dial("udp", e.addr)
+	if err != nil {
+		return fmt.Errorf("failed to dial statsd agent: %w", err)
+	}
+	e.conn = conn
+	go e.pushRoutine()
+	return nil
+}
+
+// OnStop implements service.Service.
+func (e *statsdExporter) OnStop() {
+	if err := e.conn.Close(); err != nil {
+		e.Logger.Error("failed closing connection to statsd agent", "err", err)
+	}
+}
+
+func (e *statsdExporter) pushRoutine() {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := e.push(); err != nil {
+				e.Logger.Error("failed pushing metrics to statsd agent", "addr", e.addr, "err", err)
+			}
+		case <-e.Quit():
+			return
+		}
+	}
+}
+
+// push sends the metrics gathered to the agent, in as few packets as fit.
+func (e *statsdExporter) push() error {
+	families, err := e.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+
+	var packet bytes.Buffer
+	send := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := e.conn.Write(packet.Bytes())
+		packet.Reset()
+		return err
+	}
+	for _, line := range e.lines(families) {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > statsdMaxPacketSize {
+			if err := send(); err != nil {
+				return err
+			}
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	return send()
+}
+
+// lines returns the lines pushed for families, updating the counters' last
+// values.
+func (e *statsdExporter) lines(families []*dto.MetricFamily) []string {
+	var lines []string
+	for _, family := range families {
+		if !e.exported(family.GetName()) {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			name, tags := e.series(family.GetName(), m.GetLabel())
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				lines = e.appendCount(lines, name, tags, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				lines = appendGauge(lines, name, tags, m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				lines = appendGauge(lines, name, tags, m.GetUntyped().GetValue())
+			case dto.MetricType_HISTOGRAM:
+				h := m.GetHistogram()
+				lines = e.appendCount(lines, name+"_count", tags, float64(h.GetSampleCount()))
+				lines = e.appendCount(lines, name+"_sum", tags, h.GetSampleSum())
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				lines = e.appendCount(lines, name+"_count", tags, float64(s.GetSampleCount()))
+				lines = e.appendCount(lines, name+"_sum", tags, s.GetSampleSum())
+			}
+		}
+	}
+	return lines
+}
+
+func (e *statsdExporter) exported(name string) bool {
+	for _, prefix := range e.prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// series returns the name and tags of the metric name with labels: DogStatsD
+// carries the labels as tags, while plain statsd, which has none, has their
+// values appended to the name, in the order of the labels' names, which the
+// gatherer sorts them in.
+func (e *statsdExporter) series(name string, labels []*dto.LabelPair) (string, string) {
+	if e.protocol == cfg.StatsdProtocolDogStatsd {
+		tags := make([]string, 0, len(labels))
+		for _, l := range labels {
+			tags = append(tags, l.GetName()+":"+statsdSanitize(l.GetValue()))
+		}
+		if len(tags) == 0 {
+			return name, ""
+		}
+		return name, "|#" + strings.Join(tags, ",")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	for _, l := range labels {
+		sb.WriteByte('.')
+		if l.GetValue() == "" {
+			sb.WriteString("none")
+		} else {
+			sb.WriteString(statsdSanitize(l.GetValue()))
+		}
+	}
+	return sb.String(), ""
+}
+
+// appendCount appends the increment of the counter since the last push, if
+// any. A counter lower than at the last push was reset, eg. by the node
+// restarting, and counts from zero.
+func (e *statsdExporter) appendCount(lines []string, name, tags string, value float64) []string {
+	key := name + tags
+	delta := value - e.last[key]
+	if delta < 0 {
+		delta = value
+	}
+	e.last[key] = value
+	if delta == 0 {
+		return lines
+	}
+	return append(lines, name+":"+statsdValue(delta)+"|c"+tags)
+}
+
+// appendGauge appends the value of the gauge. A signed value adjusts a gauge
+// in statsd, so a negative one is set by zeroing it first.
+func appendGauge(lines []string, name, tags string, value float64) []string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return lines
+	}
+	if value < 0 {
+		lines = append(lines, name+":0|g"+tags)
+	}
+	return append(lines, name+":"+statsdValue(value)+"|g"+tags)
+}
+
+func statsdValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// statsdSanitize replaces the characters of the statsd line protocol, the
+// dots separating the parts of plain statsd names, and any other not
+// commonly accepted in names and tags, in s with '_'.
+func statsdSanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '_', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
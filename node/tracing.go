@@ -0,0 +1,54 @@
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/version"
+)
+
+// createTracerProvider returns the provider of the tracers of the bundle
+// path, exporting spans to the collector at config.TracingEndpoint, or a
+// provider of no-op tracers if it's empty. The spans of each node are told
+// apart by its chain and node ID.
+func createTracerProvider(
+	config *cfg.InstrumentationConfig,
+	chainID string,
+	nodeID p2p.ID,
+) (trace.TracerProvider, *sdktrace.TracerProvider, error) {
+	if config.TracingEndpoint == "" {
+		return trace.NewNoopTracerProvider(), nil, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.TracingEndpoint)}
+	if config.TracingInsecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	// spans are only sent once batched, so this doesn't wait on the collector
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create span exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.TracingSampleRate))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String("tendermint"),
+			semconv.ServiceVersionKey.String(version.TMCoreSemVer),
+			semconv.ServiceInstanceIDKey.String(string(nodeID)),
+			attribute.String("chain_id", chainID),
+		)),
+	)
+	return provider, provider, nil
+}
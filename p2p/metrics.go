@@ -25,6 +25,8 @@ type Metrics struct {
 	PeerPendingSendBytes metrics.Gauge
 	// Number of transactions submitted by each peer.
 	NumTxs metrics.Gauge
+	// Number of failed handshakes with each sidecar peer dialed.
+	SidecarPeerHandshakeFailures metrics.Counter
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
@@ -66,6 +68,12 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "num_txs",
 			Help:      "Number of transactions submitted by each peer.",
 		}, append(labels, "peer_id")).With(labelsAndValues...),
+		SidecarPeerHandshakeFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sidecar_peer_handshake_failures",
+			Help:      "Number of failed handshakes with each sidecar peer dialed.",
+		}, append(labels, "peer_id")).With(labelsAndValues...),
 	}
 }
 
@@ -77,5 +85,7 @@ func NopMetrics() *Metrics {
 		PeerSendBytesTotal:    discard.NewCounter(),
 		PeerPendingSendBytes:  discard.NewGauge(),
 		NumTxs:                discard.NewGauge(),
+
+		SidecarPeerHandshakeFailures: discard.NewCounter(),
 	}
 }
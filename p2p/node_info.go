@@ -99,6 +99,9 @@ type DefaultNodeInfo struct {
 type DefaultNodeInfoOther struct {
 	TxIndex    string `json:"tx_index"`
 	RPCAddress string `json:"rpc_address"`
+	// MEVDisabled is set by nodes that don't take part in MEV auctions, so
+	// peers shouldn't gossip sidecar txs to them.
+	MEVDisabled bool `json:"mev_disabled"`
 }
 
 // ID returns the node's peer ID.
@@ -243,8 +246,9 @@ func (info DefaultNodeInfo) ToProto() *tmp2p.DefaultNodeInfo {
 	dni.Channels = info.Channels
 	dni.Moniker = info.Moniker
 	dni.Other = tmp2p.DefaultNodeInfoOther{
-		TxIndex:    info.Other.TxIndex,
-		RPCAddress: info.Other.RPCAddress,
+		TxIndex:     info.Other.TxIndex,
+		RPCAddress:  info.Other.RPCAddress,
+		MEVDisabled: info.Other.MEVDisabled,
 	}
 
 	return dni
@@ -267,8 +271,9 @@ func DefaultNodeInfoFromToProto(pb *tmp2p.DefaultNodeInfo) (DefaultNodeInfo, err
 		Channels:      pb.Channels,
 		Moniker:       pb.Moniker,
 		Other: DefaultNodeInfoOther{
-			TxIndex:    pb.Other.TxIndex,
-			RPCAddress: pb.Other.RPCAddress,
+			TxIndex:     pb.Other.TxIndex,
+			RPCAddress:  pb.Other.RPCAddress,
+			MEVDisabled: pb.Other.MEVDisabled,
 		},
 	}
 
@@ -0,0 +1,192 @@
+package p2p
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/libs/tempfile"
+)
+
+const (
+	// sidecarDialInterval is how often the switch checks for sidecar peers
+	// to redial.
+	sidecarDialInterval = 2 * time.Second
+	// sidecarDialBaseBackoff and sidecarDialMaxBackoff bound the wait before
+	// redialing a sidecar peer, doubling with each failed dial. Sidecar peers
+	// are never given up on.
+	sidecarDialBaseBackoff = 2 * time.Second
+	sidecarDialMaxBackoff  = time.Minute
+)
+
+// SidecarAddrBook keeps the addresses of the sidecar peers, the relay
+// included, apart from the address book filled by PEX, so they're neither
+// pruned nor gossiped, and outlive restarts. The switch records the address
+// of each sidecar peer once connected, and redials those it lost with its own
+// backoff, never giving up, see SwitchSidecarAddrBook.
+//
+// Safe for concurrent use by multiple goroutines.
+type SidecarAddrBook struct {
+	mtx      tmsync.Mutex
+	filePath string
+	addrs    map[ID]*sidecarKnownAddress
+}
+
+type sidecarKnownAddress struct {
+	Addr        *NetAddress `json:"addr"`
+	LastSuccess time.Time   `json:"last_success"`
+
+	// failed dials since the last success, and when the last one was made
+	attempts    int
+	lastAttempt time.Time
+}
+
+// NewSidecarAddrBook returns the sidecar address book persisted to filePath,
+// loading the addresses stored in it if it exists.
+func NewSidecarAddrBook(filePath string) (*SidecarAddrBook, error) {
+	book := &SidecarAddrBook{
+		filePath: filePath,
+		addrs:    make(map[ID]*sidecarKnownAddress),
+	}
+	bz, err := ioutil.ReadFile(filePath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return book, nil
+	case err != nil:
+		return nil, err
+	}
+	var addrs []*sidecarKnownAddress
+	if err := json.Unmarshal(bz, &addrs); err != nil {
+		return nil, fmt.Errorf("failed to read sidecar address book %s: %w", filePath, err)
+	}
+	for _, ka := range addrs {
+		if ka.Addr == nil {
+			return nil, fmt.Errorf("failed to read sidecar address book %s: missing addr", filePath)
+		}
+		book.addrs[ka.Addr.ID] = ka
+	}
+	return book, nil
+}
+
+// Addr returns the address of the peer id, or nil if it's unknown.
+func (book *SidecarAddrBook) Addr(id ID) *NetAddress {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+	if ka, ok := book.addrs[id]; ok {
+		return ka.Addr
+	}
+	return nil
+}
+
+// MarkGood records addr as the address of a sidecar peer just connected, and
+// persists it.
+func (book *SidecarAddrBook) MarkGood(addr *NetAddress) error {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+	book.addrs[addr.ID] = &sidecarKnownAddress{Addr: addr, LastSuccess: time.Now()}
+	return book.save()
+}
+
+// markAttempt records a dial of the peer id, and returns the address to dial
+// if its backoff is over at now, or nil.
+func (book *SidecarAddrBook) markAttempt(id ID, now time.Time) *NetAddress {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+	ka, ok := book.addrs[id]
+	if !ok || now.Before(ka.lastAttempt.Add(sidecarDialBackoff(ka.attempts))) {
+		return nil
+	}
+	ka.attempts++
+	ka.lastAttempt = now
+	return ka.Addr
+}
+
+// save writes the addresses to the file.
+//
+// book.mtx must be held by the caller.
+func (book *SidecarAddrBook) save() error {
+	addrs := make([]*sidecarKnownAddress, 0, len(book.addrs))
+	for _, ka := range book.addrs {
+		addrs = append(addrs, ka)
+	}
+	bz, err := json.MarshalIndent(addrs, "", "\t")
+	if err != nil {
+		return err
+	}
+	return tempfile.WriteFileAtomic(book.filePath, bz, 0644)
+}
+
+// sidecarDialBackoff returns how long to wait before redialing a sidecar
+// peer after attempts failed dials.
+func sidecarDialBackoff(attempts int) time.Duration {
+	if attempts == 0 {
+		return 0
+	}
+	backoff := sidecarDialBaseBackoff
+	for i := 1; i < attempts && backoff < sidecarDialMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > sidecarDialMaxBackoff {
+		return sidecarDialMaxBackoff
+	}
+	return backoff
+}
+
+// SwitchSidecarAddrBook sets the address book the sidecar peers' addresses
+// are kept in, so they're redialed whenever disconnected.
+func SwitchSidecarAddrBook(book *SidecarAddrBook) SwitchOption {
+	return func(sw *Switch) { sw.sidecarAddrBook = book }
+}
+
+// recordSidecarPeer keeps the address of p, a sidecar peer just added, in the
+// sidecar address book, if any.
+func (sw *Switch) recordSidecarPeer(p Peer) {
+	if sw.sidecarAddrBook == nil || !p.IsSidecarPeer() {
+		return
+	}
+	addr := p.SocketAddr() // socket address for outbound peers
+	if !p.IsOutbound() {
+		// self-reported address for inbound peers
+		var err error
+		if addr, err = p.NodeInfo().NetAddress(); err != nil || addr.Valid() != nil {
+			sw.Logger.Debug("Not keeping the self-reported address of an inbound sidecar peer",
+				"peer", p.ID(), "addr", addr, "err", err)
+			return
+		}
+	}
+	if err := sw.sidecarAddrBook.MarkGood(addr); err != nil {
+		sw.Logger.Error("Failed saving sidecar address book", "err", err)
+	}
+}
+
+// sidecarDialRoutine redials the sidecar peers disconnected, whose addresses
+// are known, until the switch stops.
+func (sw *Switch) sidecarDialRoutine() {
+	ticker := time.NewTicker(sidecarDialInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, id := range sw.SidecarPeerIDs() {
+				if sw.peers.Has(id) || sw.dialing.Has(string(id)) || sw.reconnecting.Has(string(id)) {
+					continue
+				}
+				addr := sw.sidecarAddrBook.markAttempt(id, now)
+				if addr == nil {
+					continue
+				}
+				go func() {
+					if err := sw.DialPeerWithAddress(addr); err != nil {
+						sw.Logger.Info("Failed redialing sidecar peer", "addr", addr, "err", err)
+					}
+				}()
+			}
+		case <-sw.Quit():
+			return
+		}
+	}
+}
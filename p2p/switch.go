@@ -3,6 +3,7 @@ package p2p
 import (
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -89,8 +90,12 @@ type Switch struct {
 
 	rng *rand.Rand // seed for randomizing dial times and orders
 
-	metrics      *Metrics
-	sidecarPeers SidecarPeers
+	metrics *Metrics
+
+	sidecarPeersMtx sync.RWMutex
+	sidecarPeers    SidecarPeers
+	// keeps the sidecar peers' addresses, to redial them, if set
+	sidecarAddrBook *SidecarAddrBook
 }
 
 // NetAddress returns the address the switch is listening on.
@@ -252,6 +257,10 @@ func (sw *Switch) OnStart() error {
 	// Start accepting Peers.
 	go sw.acceptRoutine()
 
+	if sw.sidecarAddrBook != nil {
+		go sw.sidecarDialRoutine()
+	}
+
 	return nil
 }
 
@@ -474,6 +483,12 @@ func isPrivateAddr(err error) bool {
 	return ok && te.PrivateAddr()
 }
 
+// isHandshakeFailure returns true if the peer was rejected for failing the
+// secret connection or NodeInfo handshake.
+func isHandshakeFailure(e ErrRejected) bool {
+	return e.IsAuthFailure() || e.IsNodeInfoInvalid() || e.IsIncompatible()
+}
+
 // DialPeersAsync dials a list of peers asynchronously in random order.
 // Used to dial peers from config on startup or from unsafe-RPC (trusted sources).
 // It ignores ErrNetAddressLookup. However, if there are other errors, first
@@ -629,10 +644,40 @@ func (sw *Switch) AddPrivatePeerIDs(ids []string) error {
 // Check whether a particular PID is a skip peer
 // and should receive private auction-winning txs
 func (sw *Switch) IsSidecarPeer(pid ID) bool {
+	sw.sidecarPeersMtx.RLock()
+	defer sw.sidecarPeersMtx.RUnlock()
 	_, isPeer := sw.sidecarPeers[pid]
 	return isPeer
 }
 
+// SidecarPeerIDs returns the IDs of the sidecar peers, sorted.
+func (sw *Switch) SidecarPeerIDs() []ID {
+	sw.sidecarPeersMtx.RLock()
+	defer sw.sidecarPeersMtx.RUnlock()
+	ids := make([]ID, 0, len(sw.sidecarPeers))
+	for id := range sw.sidecarPeers {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// SetSidecarPeers replaces the sidecar peers, eg. to rotate a relay without
+// restarting the node. A peer's sidecar status is set once it connects, so
+// the connected peers it changes for are disconnected, to reconnect with it;
+// persistent ones are redialed.
+func (sw *Switch) SetSidecarPeers(sp SidecarPeers) {
+	sw.sidecarPeersMtx.Lock()
+	sw.sidecarPeers = sp
+	sw.sidecarPeersMtx.Unlock()
+
+	for _, peer := range sw.peers.List() {
+		if peer.IsSidecarPeer() != sw.IsSidecarPeer(peer.ID()) {
+			sw.StopPeerForError(peer, "sidecar peers changed")
+		}
+	}
+}
+
 func (sw *Switch) IsPeerPersistent(na *NetAddress) bool {
 	for _, pa := range sw.persistentPeersAddrs {
 		if pa.Equals(na) {
@@ -758,6 +803,9 @@ func (sw *Switch) addOutboundPeerWithConfig(
 	})
 	if err != nil {
 		if e, ok := err.(ErrRejected); ok {
+			if isHandshakeFailure(e) && sw.IsSidecarPeer(addr.ID) {
+				sw.metrics.SidecarPeerHandshakeFailures.With("peer_id", string(addr.ID)).Add(1)
+			}
 			if e.IsSelf() {
 				// Remove the given address from the address book and add to our addresses
 				// to avoid dialing in the future.
@@ -855,6 +903,7 @@ func (sw *Switch) addPeer(p Peer) error {
 		return err
 	}
 	sw.metrics.Peers.Add(float64(1))
+	sw.recordSidecarPeer(p)
 
 	// Start all the reactor protocols on the peer.
 	for _, reactor := range sw.reactors {
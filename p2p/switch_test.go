@@ -8,12 +8,16 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -191,6 +195,79 @@ func TestSidecarPeerMap(t *testing.T) {
 	assert.True(t, s2.IsSidecarPeer(s1.nodeInfo.ID()))
 }
 
+func TestSwitchSetSidecarPeers(t *testing.T) {
+	sw := MakeSwitch(cfg, 1, "127.0.0.1", "123.123.123", initSwitchFunc)
+	require.NoError(t, sw.Start())
+	t.Cleanup(func() {
+		if err := sw.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	rp := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg}
+	rp.Start()
+	t.Cleanup(rp.Stop)
+	require.NoError(t, sw.DialPeerWithAddress(rp.Addr()))
+	require.False(t, sw.Peers().Get(rp.ID()).IsSidecarPeer())
+
+	// peers whose sidecar status changes are disconnected, to reconnect with it
+	sw.SetSidecarPeers(SidecarPeers{rp.ID(): {}})
+	assert.True(t, sw.IsSidecarPeer(rp.ID()))
+	assert.Zero(t, sw.Peers().Size())
+	require.NoError(t, sw.DialPeerWithAddress(rp.Addr()))
+	assert.True(t, sw.Peers().Get(rp.ID()).IsSidecarPeer())
+
+	// the others are left connected
+	sw.SetSidecarPeers(SidecarPeers{rp.ID(): {}, "0123456789abcdef0123456789abcdef01234567": {}})
+	assert.Equal(t, 1, sw.Peers().Size())
+}
+
+func TestSwitchSidecarAddrBook(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sidecar_addrbook")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	bookFile := filepath.Join(dir, "sidecar_addrbook.json")
+	book, err := NewSidecarAddrBook(bookFile)
+	require.NoError(t, err)
+
+	rp := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg}
+	rp.Start()
+	t.Cleanup(rp.Stop)
+	other := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg}
+	other.Start()
+	t.Cleanup(other.Stop)
+	sw := MakeSwitchWithSidecarPeers(cfg, 1, "127.0.0.1", "123.123.123", initSwitchFunc,
+		SidecarPeers{rp.ID(): {}}, SwitchSidecarAddrBook(book))
+	require.NoError(t, sw.Start())
+	t.Cleanup(func() {
+		if err := sw.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// the addresses of sidecar peers are kept once connected, and persisted
+	require.NoError(t, sw.DialPeerWithAddress(rp.Addr()))
+	require.NoError(t, sw.DialPeerWithAddress(other.Addr()))
+	assert.Equal(t, rp.Addr(), book.Addr(rp.ID()))
+	assert.Nil(t, book.Addr(other.ID()))
+	loaded, err := NewSidecarAddrBook(bookFile)
+	require.NoError(t, err)
+	require.NotNil(t, loaded.Addr(rp.ID()))
+	assert.True(t, rp.Addr().Equals(loaded.Addr(rp.ID())))
+
+	// and redialed if lost, though not persistent
+	sw.StopPeerForError(sw.Peers().Get(rp.ID()), "test")
+	sw.StopPeerForError(sw.Peers().Get(other.ID()), "test")
+	assert.Eventually(t, func() bool { return sw.Peers().Has(rp.ID()) }, 5*time.Second, 100*time.Millisecond)
+	assert.False(t, sw.Peers().Has(other.ID()))
+}
+
+func TestSidecarDialBackoff(t *testing.T) {
+	assert.Zero(t, sidecarDialBackoff(0))
+	assert.Equal(t, sidecarDialBaseBackoff, sidecarDialBackoff(1))
+	assert.Equal(t, 2*sidecarDialBaseBackoff, sidecarDialBackoff(2))
+	assert.Equal(t, sidecarDialMaxBackoff, sidecarDialBackoff(100))
+}
+
 func TestSwitchFiltersOutItself(t *testing.T) {
 	s1 := MakeSwitch(cfg, 1, "127.0.0.1", "123.123.123", initSwitchFunc)
 
@@ -218,6 +295,44 @@ func TestSwitchFiltersOutItself(t *testing.T) {
 	assertNoPeersAfterTimeout(t, s1, 100*time.Millisecond)
 }
 
+// labeledCounter is a generic.Counter keeping the labels it's counted with.
+type labeledCounter struct {
+	*generic.Counter
+	labelValues []string
+}
+
+func (c *labeledCounter) With(labelValues ...string) metrics.Counter {
+	c.labelValues = append(c.labelValues, labelValues...)
+	return c
+}
+
+func TestSwitchCountsSidecarPeerHandshakeFailures(t *testing.T) {
+	// the remote peers have no channel in common with the switch
+	sidecarPeer := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg, channels: []byte{0x42}}
+	sidecarPeer.Start()
+	defer sidecarPeer.Stop()
+	otherPeer := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg, channels: []byte{0x42}}
+	otherPeer.Start()
+	defer otherPeer.Stop()
+
+	failures := &labeledCounter{Counter: generic.NewCounter("sidecar_peer_handshake_failures")}
+	p2pMetrics := NopMetrics()
+	p2pMetrics.SidecarPeerHandshakeFailures = failures
+	sw := MakeSwitchWithSidecarPeers(cfg, 1, "127.0.0.1", "123.123.123", initSwitchFunc,
+		SidecarPeers{sidecarPeer.ID(): {}}, WithMetrics(p2pMetrics))
+
+	err := sw.DialPeerWithAddress(sidecarPeer.Addr())
+	if assert.Error(t, err) {
+		assert.True(t, err.(ErrRejected).IsIncompatible())
+	}
+	assert.EqualValues(t, 1, failures.Value())
+	assert.Equal(t, []string{"peer_id", string(sidecarPeer.ID())}, failures.labelValues)
+
+	// failures with other peers aren't counted
+	assert.Error(t, sw.DialPeerWithAddress(otherPeer.Addr()))
+	assert.EqualValues(t, 1, failures.Value())
+}
+
 func TestSwitchPeerFilter(t *testing.T) {
 	var (
 		filters = []PeerFilterFunc{
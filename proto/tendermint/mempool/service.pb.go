@@ -0,0 +1,623 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: tendermint/mempool/service.proto
+
+package mempool
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+type RequestSubmitBundle struct {
+	Envelope *BundleEnvelope `protobuf:"bytes,1,opt,name=envelope,proto3" json:"envelope,omitempty"`
+}
+
+func (m *RequestSubmitBundle) Reset()         { *m = RequestSubmitBundle{} }
+func (m *RequestSubmitBundle) String() string { return proto.CompactTextString(m) }
+func (*RequestSubmitBundle) ProtoMessage()    {}
+func (*RequestSubmitBundle) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dccc23ceb1e26093, []int{0}
+}
+func (m *RequestSubmitBundle) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RequestSubmitBundle) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RequestSubmitBundle.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RequestSubmitBundle) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RequestSubmitBundle.Merge(m, src)
+}
+func (m *RequestSubmitBundle) XXX_Size() int {
+	return m.Size()
+}
+func (m *RequestSubmitBundle) XXX_DiscardUnknown() {
+	xxx_messageInfo_RequestSubmitBundle.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RequestSubmitBundle proto.InternalMessageInfo
+
+func (m *RequestSubmitBundle) GetEnvelope() *BundleEnvelope {
+	if m != nil {
+		return m.Envelope
+	}
+	return nil
+}
+
+type ResponseSubmitBundle struct {
+	// height the bundle was added to the sidecar for
+	Height int64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	// hash of the bundle's txs, see types.Txs.Hash
+	Hash []byte `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *ResponseSubmitBundle) Reset()         { *m = ResponseSubmitBundle{} }
+func (m *ResponseSubmitBundle) String() string { return proto.CompactTextString(m) }
+func (*ResponseSubmitBundle) ProtoMessage()    {}
+func (*ResponseSubmitBundle) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dccc23ceb1e26093, []int{1}
+}
+func (m *ResponseSubmitBundle) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ResponseSubmitBundle) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ResponseSubmitBundle.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ResponseSubmitBundle) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResponseSubmitBundle.Merge(m, src)
+}
+func (m *ResponseSubmitBundle) XXX_Size() int {
+	return m.Size()
+}
+func (m *ResponseSubmitBundle) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResponseSubmitBundle.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResponseSubmitBundle proto.InternalMessageInfo
+
+func (m *ResponseSubmitBundle) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *ResponseSubmitBundle) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*RequestSubmitBundle)(nil), "tendermint.mempool.RequestSubmitBundle")
+	proto.RegisterType((*ResponseSubmitBundle)(nil), "tendermint.mempool.ResponseSubmitBundle")
+}
+
+func init() { proto.RegisterFile("tendermint/mempool/service.proto", fileDescriptor_dccc23ceb1e26093) }
+
+var fileDescriptor_dccc23ceb1e26093 = []byte{
+	// 261 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0xbd, 0x6a, 0xf3, 0x30,
+	0x14, 0x86, 0xad, 0xef, 0x2b, 0xa1, 0xa8, 0x19, 0x8a, 0x5a, 0x4a, 0xc8, 0x20, 0x8c, 0x97, 0x7a,
+	0x92, 0x21, 0x9d, 0xba, 0x74, 0x30, 0xf4, 0x06, 0x14, 0xba, 0x74, 0x8b, 0x93, 0x43, 0x24, 0xb0,
+	0x7e, 0x6a, 0x49, 0x81, 0xde, 0x45, 0x2f, 0xab, 0x63, 0xc6, 0x8e, 0xc5, 0xbe, 0x91, 0x82, 0x6c,
+	0xda, 0x04, 0x7b, 0x3b, 0x42, 0xcf, 0xfb, 0x70, 0xce, 0x8b, 0x53, 0x0f, 0x7a, 0x07, 0x8d, 0x92,
+	0xda, 0x17, 0x0a, 0x94, 0x35, 0xa6, 0x2e, 0x1c, 0x34, 0x07, 0xb9, 0x05, 0x66, 0x1b, 0xe3, 0x0d,
+	0x21, 0x7f, 0x04, 0x1b, 0x88, 0x25, 0x9d, 0x48, 0xf9, 0x77, 0x0b, 0xae, 0xcf, 0x64, 0x2f, 0xf8,
+	0x86, 0xc3, 0x5b, 0x00, 0xe7, 0xd7, 0xa1, 0x52, 0xd2, 0x97, 0x41, 0xef, 0x6a, 0x20, 0x4f, 0xf8,
+	0x12, 0xf4, 0x01, 0x6a, 0x63, 0x61, 0x81, 0x52, 0x94, 0x5f, 0xad, 0x32, 0x36, 0xb6, 0xb3, 0x9e,
+	0x7e, 0x1e, 0x48, 0xfe, 0x9b, 0xc9, 0x4a, 0x7c, 0xcb, 0xc1, 0x59, 0xa3, 0x1d, 0x9c, 0x79, 0xef,
+	0xf0, 0x4c, 0x80, 0xdc, 0x0b, 0x1f, 0xad, 0xff, 0xf9, 0xf0, 0x22, 0x04, 0x5f, 0x88, 0x8d, 0x13,
+	0x8b, 0x7f, 0x29, 0xca, 0xe7, 0x3c, 0xce, 0xab, 0x80, 0xaf, 0xfb, 0x54, 0x34, 0x38, 0x27, 0x8d,
+	0x26, 0x1b, 0x3c, 0x3f, 0xf3, 0xdd, 0x4f, 0x6d, 0x35, 0x71, 0xd0, 0x32, 0x9f, 0x06, 0xc7, 0x2b,
+	0x96, 0xeb, 0xcf, 0x96, 0xa2, 0x63, 0x4b, 0xd1, 0x77, 0x4b, 0xd1, 0x47, 0x47, 0x93, 0x63, 0x47,
+	0x93, 0xaf, 0x8e, 0x26, 0xaf, 0x8f, 0x7b, 0xe9, 0x45, 0xa8, 0xd8, 0xd6, 0xa8, 0xe2, 0xa4, 0xd6,
+	0x93, 0x31, 0x76, 0x5a, 0x8c, 0x2b, 0xaf, 0x66, 0xf1, 0xe7, 0xe1, 0x67, 0x00, 0x1c, 0x0b, 0x58,
+	0xfe, 0xc5, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// BundleSubmissionClient is the client API for BundleSubmission service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BundleSubmissionClient interface {
+	SubmitBundle(ctx context.Context, in *RequestSubmitBundle, opts ...grpc.CallOption) (*ResponseSubmitBundle, error)
+}
+
+type bundleSubmissionClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBundleSubmissionClient(cc *grpc.ClientConn) BundleSubmissionClient {
+	return &bundleSubmissionClient{cc}
+}
+
+func (c *bundleSubmissionClient) SubmitBundle(ctx context.Context, in *RequestSubmitBundle, opts ...grpc.CallOption) (*ResponseSubmitBundle, error) {
+	out := new(ResponseSubmitBundle)
+	err := c.cc.Invoke(ctx, "/tendermint.mempool.BundleSubmission/SubmitBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BundleSubmissionServer is the server API for BundleSubmission service.
+type BundleSubmissionServer interface {
+	SubmitBundle(context.Context, *RequestSubmitBundle) (*ResponseSubmitBundle, error)
+}
+
+// UnimplementedBundleSubmissionServer can be embedded to have forward compatible implementations.
+type UnimplementedBundleSubmissionServer struct {
+}
+
+func (*UnimplementedBundleSubmissionServer) SubmitBundle(ctx context.Context, req *RequestSubmitBundle) (*ResponseSubmitBundle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitBundle not implemented")
+}
+
+func RegisterBundleSubmissionServer(s *grpc.Server, srv BundleSubmissionServer) {
+	s.RegisterService(&_BundleSubmission_serviceDesc, srv)
+}
+
+func _BundleSubmission_SubmitBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestSubmitBundle)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BundleSubmissionServer).SubmitBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tendermint.mempool.BundleSubmission/SubmitBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BundleSubmissionServer).SubmitBundle(ctx, req.(*RequestSubmitBundle))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _BundleSubmission_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tendermint.mempool.BundleSubmission",
+	HandlerType: (*BundleSubmissionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitBundle",
+			Handler:    _BundleSubmission_SubmitBundle_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "tendermint/mempool/service.proto",
+}
+
+func (m *RequestSubmitBundle) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RequestSubmitBundle) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RequestSubmitBundle) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Envelope != nil {
+		{
+			size, err := m.Envelope.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintService(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ResponseSubmitBundle) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ResponseSubmitBundle) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ResponseSubmitBundle) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintService(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Height != 0 {
+		i = encodeVarintService(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintService(dAtA []byte, offset int, v uint64) int {
+	offset -= sovService(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *RequestSubmitBundle) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Envelope != nil {
+		l = m.Envelope.Size()
+		n += 1 + l + sovService(uint64(l))
+	}
+	return n
+}
+
+func (m *ResponseSubmitBundle) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovService(uint64(m.Height))
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovService(uint64(l))
+	}
+	return n
+}
+
+func sovService(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozService(x uint64) (n int) {
+	return sovService(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *RequestSubmitBundle) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowService
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RequestSubmitBundle: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RequestSubmitBundle: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Envelope", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Envelope == nil {
+				m.Envelope = &BundleEnvelope{}
+			}
+			if err := m.Envelope.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipService(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthService
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ResponseSubmitBundle) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowService
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResponseSubmitBundle: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResponseSubmitBundle: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hash == nil {
+				m.Hash = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipService(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthService
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipService(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowService
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthService
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupService
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthService
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthService        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowService          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupService = fmt.Errorf("proto: unexpected end of group")
+)
@@ -5,7 +5,10 @@ package mempool
 
 import (
 	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
+	crypto "github.com/tendermint/tendermint/proto/tendermint/crypto"
+	types "github.com/tendermint/tendermint/proto/tendermint/types"
 	io "io"
 	math "math"
 	math_bits "math/bits"
@@ -22,6 +25,34 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+// BundlePlacement is where in the block a bundle asks to be placed.
+type BundlePlacement int32
+
+const (
+	// wherever the proposer's "mev" lane puts bundles
+	BundlePlacement_BUNDLE_PLACEMENT_MEV_LANE BundlePlacement = 0
+	// ahead of every other tx
+	BundlePlacement_BUNDLE_PLACEMENT_TOP_OF_BLOCK BundlePlacement = 1
+)
+
+var BundlePlacement_name = map[int32]string{
+	0: "BUNDLE_PLACEMENT_MEV_LANE",
+	1: "BUNDLE_PLACEMENT_TOP_OF_BLOCK",
+}
+
+var BundlePlacement_value = map[string]int32{
+	"BUNDLE_PLACEMENT_MEV_LANE":     0,
+	"BUNDLE_PLACEMENT_TOP_OF_BLOCK": 1,
+}
+
+func (x BundlePlacement) String() string {
+	return proto.EnumName(BundlePlacement_name, int32(x))
+}
+
+func (BundlePlacement) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{0}
+}
+
 type Txs struct {
 	Txs [][]byte `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
 }
@@ -66,24 +97,23 @@ func (m *Txs) GetTxs() [][]byte {
 	return nil
 }
 
-type Message struct {
-	// Types that are valid to be assigned to Sum:
-	//	*Message_Txs
-	Sum isMessage_Sum `protobuf_oneof:"sum"`
+// BundleReceipts are passed back from the proposer towards the relay.
+type BundleReceipts struct {
+	Receipts []*types.BundleReceipt `protobuf:"bytes,1,rep,name=receipts,proto3" json:"receipts,omitempty"`
 }
 
-func (m *Message) Reset()         { *m = Message{} }
-func (m *Message) String() string { return proto.CompactTextString(m) }
-func (*Message) ProtoMessage()    {}
-func (*Message) Descriptor() ([]byte, []int) {
+func (m *BundleReceipts) Reset()         { *m = BundleReceipts{} }
+func (m *BundleReceipts) String() string { return proto.CompactTextString(m) }
+func (*BundleReceipts) ProtoMessage()    {}
+func (*BundleReceipts) Descriptor() ([]byte, []int) {
 	return fileDescriptor_2af51926fdbcbc05, []int{1}
 }
-func (m *Message) XXX_Unmarshal(b []byte) error {
+func (m *BundleReceipts) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *BundleReceipts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Message.Marshal(b, m, deterministic)
+		return xxx_messageInfo_BundleReceipts.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -93,73 +123,131 @@ func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *Message) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Message.Merge(m, src)
+func (m *BundleReceipts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BundleReceipts.Merge(m, src)
 }
-func (m *Message) XXX_Size() int {
+func (m *BundleReceipts) XXX_Size() int {
 	return m.Size()
 }
-func (m *Message) XXX_DiscardUnknown() {
-	xxx_messageInfo_Message.DiscardUnknown(m)
+func (m *BundleReceipts) XXX_DiscardUnknown() {
+	xxx_messageInfo_BundleReceipts.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Message proto.InternalMessageInfo
+var xxx_messageInfo_BundleReceipts proto.InternalMessageInfo
 
-type isMessage_Sum interface {
-	isMessage_Sum()
-	MarshalTo([]byte) (int, error)
-	Size() int
+func (m *BundleReceipts) GetReceipts() []*types.BundleReceipt {
+	if m != nil {
+		return m.Receipts
+	}
+	return nil
 }
 
-type Message_Txs struct {
-	Txs *Txs `protobuf:"bytes,1,opt,name=txs,proto3,oneof" json:"txs,omitempty"`
+// RelayRegistration registers a validator's node with the relay, and is only
+// ever sent to the relay, once connected. The relay authenticates the node
+// itself by its node key, through the p2p handshake.
+type RelayRegistration struct {
+	// API key the relay issued to the validator
+	ApiKey string `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// ID of the registering node, signed over so the registration can't be
+	// presented by any other node
+	NodeID string `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	// relay key of the node, and its signature of the
+	// CanonicalRelayRegistration, if the node has one
+	PubKey    *crypto.PublicKey `protobuf:"bytes,3,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Signature []byte            `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	// how many heights past its upcoming auction the node accepts bundles for,
+	// so the relay knows the window to submit in; 0 for no limit. Not signed
+	// over.
+	MaxBundleHeightsAhead int64 `protobuf:"varint,5,opt,name=max_bundle_heights_ahead,json=maxBundleHeightsAhead,proto3" json:"max_bundle_heights_ahead,omitempty"`
 }
 
-func (*Message_Txs) isMessage_Sum() {}
+func (m *RelayRegistration) Reset()         { *m = RelayRegistration{} }
+func (m *RelayRegistration) String() string { return proto.CompactTextString(m) }
+func (*RelayRegistration) ProtoMessage()    {}
+func (*RelayRegistration) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{2}
+}
+func (m *RelayRegistration) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RelayRegistration) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RelayRegistration.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RelayRegistration) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RelayRegistration.Merge(m, src)
+}
+func (m *RelayRegistration) XXX_Size() int {
+	return m.Size()
+}
+func (m *RelayRegistration) XXX_DiscardUnknown() {
+	xxx_messageInfo_RelayRegistration.DiscardUnknown(m)
+}
 
-func (m *Message) GetSum() isMessage_Sum {
+var xxx_messageInfo_RelayRegistration proto.InternalMessageInfo
+
+func (m *RelayRegistration) GetApiKey() string {
 	if m != nil {
-		return m.Sum
+		return m.ApiKey
+	}
+	return ""
+}
+
+func (m *RelayRegistration) GetNodeID() string {
+	if m != nil {
+		return m.NodeID
+	}
+	return ""
+}
+
+func (m *RelayRegistration) GetPubKey() *crypto.PublicKey {
+	if m != nil {
+		return m.PubKey
 	}
 	return nil
 }
 
-func (m *Message) GetTxs() *Txs {
-	if x, ok := m.GetSum().(*Message_Txs); ok {
-		return x.Txs
+func (m *RelayRegistration) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
 	}
 	return nil
 }
 
-// XXX_OneofWrappers is for the internal use of the proto package.
-func (*Message) XXX_OneofWrappers() []interface{} {
-	return []interface{}{
-		(*Message_Txs)(nil),
+func (m *RelayRegistration) GetMaxBundleHeightsAhead() int64 {
+	if m != nil {
+		return m.MaxBundleHeightsAhead
 	}
+	return 0
 }
 
-type MEVMessage struct {
-	// Types that are valid to be assigned to Sum:
-	//	*MEVMessage_Txs
-	Sum           isMEVMessage_Sum `protobuf_oneof:"sum"`
-	DesiredHeight int64            `protobuf:"varint,2,opt,name=desired_height,json=desiredHeight,proto3" json:"desired_height,omitempty"`
-	BundleId      int64            `protobuf:"varint,3,opt,name=bundle_id,json=bundleId,proto3" json:"bundle_id,omitempty"`
-	BundleOrder   int64            `protobuf:"varint,4,opt,name=bundle_order,json=bundleOrder,proto3" json:"bundle_order,omitempty"`
-	BundleSize    int64            `protobuf:"varint,5,opt,name=bundle_size,json=bundleSize,proto3" json:"bundle_size,omitempty"`
+// CanonicalRelayRegistration is what the signature of a RelayRegistration
+// covers.
+type CanonicalRelayRegistration struct {
+	ApiKey string `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	NodeID string `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 }
 
-func (m *MEVMessage) Reset()         { *m = MEVMessage{} }
-func (m *MEVMessage) String() string { return proto.CompactTextString(m) }
-func (*MEVMessage) ProtoMessage()    {}
-func (*MEVMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2af51926fdbcbc05, []int{2}
+func (m *CanonicalRelayRegistration) Reset()         { *m = CanonicalRelayRegistration{} }
+func (m *CanonicalRelayRegistration) String() string { return proto.CompactTextString(m) }
+func (*CanonicalRelayRegistration) ProtoMessage()    {}
+func (*CanonicalRelayRegistration) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{3}
 }
-func (m *MEVMessage) XXX_Unmarshal(b []byte) error {
+func (m *CanonicalRelayRegistration) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *MEVMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *CanonicalRelayRegistration) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_MEVMessage.Marshal(b, m, deterministic)
+		return xxx_messageInfo_CanonicalRelayRegistration.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -169,359 +257,1801 @@ func (m *MEVMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *MEVMessage) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MEVMessage.Merge(m, src)
+func (m *CanonicalRelayRegistration) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CanonicalRelayRegistration.Merge(m, src)
 }
-func (m *MEVMessage) XXX_Size() int {
+func (m *CanonicalRelayRegistration) XXX_Size() int {
 	return m.Size()
 }
-func (m *MEVMessage) XXX_DiscardUnknown() {
-	xxx_messageInfo_MEVMessage.DiscardUnknown(m)
+func (m *CanonicalRelayRegistration) XXX_DiscardUnknown() {
+	xxx_messageInfo_CanonicalRelayRegistration.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_MEVMessage proto.InternalMessageInfo
+var xxx_messageInfo_CanonicalRelayRegistration proto.InternalMessageInfo
 
-type isMEVMessage_Sum interface {
-	isMEVMessage_Sum()
-	MarshalTo([]byte) (int, error)
-	Size() int
+func (m *CanonicalRelayRegistration) GetApiKey() string {
+	if m != nil {
+		return m.ApiKey
+	}
+	return ""
 }
 
-type MEVMessage_Txs struct {
-	Txs *Txs `protobuf:"bytes,1,opt,name=txs,proto3,oneof" json:"txs,omitempty"`
+func (m *CanonicalRelayRegistration) GetNodeID() string {
+	if m != nil {
+		return m.NodeID
+	}
+	return ""
 }
 
-func (*MEVMessage_Txs) isMEVMessage_Sum() {}
+// SidecarPing asks a sidecar peer to answer with a SidecarPong carrying the
+// same nonce, checking the sidecar channel round-trips.
+type SidecarPing struct {
+	Nonce uint64 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
 
-func (m *MEVMessage) GetSum() isMEVMessage_Sum {
+func (m *SidecarPing) Reset()         { *m = SidecarPing{} }
+func (m *SidecarPing) String() string { return proto.CompactTextString(m) }
+func (*SidecarPing) ProtoMessage()    {}
+func (*SidecarPing) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{4}
+}
+func (m *SidecarPing) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SidecarPing) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SidecarPing.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SidecarPing) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SidecarPing.Merge(m, src)
+}
+func (m *SidecarPing) XXX_Size() int {
+	return m.Size()
+}
+func (m *SidecarPing) XXX_DiscardUnknown() {
+	xxx_messageInfo_SidecarPing.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SidecarPing proto.InternalMessageInfo
+
+func (m *SidecarPing) GetNonce() uint64 {
 	if m != nil {
-		return m.Sum
+		return m.Nonce
 	}
-	return nil
+	return 0
 }
 
-func (m *MEVMessage) GetTxs() *Txs {
-	if x, ok := m.GetSum().(*MEVMessage_Txs); ok {
-		return x.Txs
+type SidecarPong struct {
+	Nonce uint64 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (m *SidecarPong) Reset()         { *m = SidecarPong{} }
+func (m *SidecarPong) String() string { return proto.CompactTextString(m) }
+func (*SidecarPong) ProtoMessage()    {}
+func (*SidecarPong) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{5}
+}
+func (m *SidecarPong) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SidecarPong) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SidecarPong.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return nil
+}
+func (m *SidecarPong) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SidecarPong.Merge(m, src)
+}
+func (m *SidecarPong) XXX_Size() int {
+	return m.Size()
+}
+func (m *SidecarPong) XXX_DiscardUnknown() {
+	xxx_messageInfo_SidecarPong.DiscardUnknown(m)
 }
 
-func (m *MEVMessage) GetDesiredHeight() int64 {
+var xxx_messageInfo_SidecarPong proto.InternalMessageInfo
+
+func (m *SidecarPong) GetNonce() uint64 {
 	if m != nil {
-		return m.DesiredHeight
+		return m.Nonce
 	}
 	return 0
 }
 
-func (m *MEVMessage) GetBundleId() int64 {
-	if m != nil {
-		return m.BundleId
+// BundleExtension is custom metadata a relay or a chain attaches to a bundle,
+// eg. a strategy tag or a refund address, under a key of its choice. Nodes
+// pass on the extensions they don't know as they are.
+type BundleExtension struct {
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *BundleExtension) Reset()         { *m = BundleExtension{} }
+func (m *BundleExtension) String() string { return proto.CompactTextString(m) }
+func (*BundleExtension) ProtoMessage()    {}
+func (*BundleExtension) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{6}
+}
+func (m *BundleExtension) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BundleExtension) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BundleExtension.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return 0
+}
+func (m *BundleExtension) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BundleExtension.Merge(m, src)
+}
+func (m *BundleExtension) XXX_Size() int {
+	return m.Size()
+}
+func (m *BundleExtension) XXX_DiscardUnknown() {
+	xxx_messageInfo_BundleExtension.DiscardUnknown(m)
 }
 
-func (m *MEVMessage) GetBundleOrder() int64 {
+var xxx_messageInfo_BundleExtension proto.InternalMessageInfo
+
+func (m *BundleExtension) GetKey() string {
 	if m != nil {
-		return m.BundleOrder
+		return m.Key
 	}
-	return 0
+	return ""
 }
 
-func (m *MEVMessage) GetBundleSize() int64 {
+func (m *BundleExtension) GetValue() []byte {
 	if m != nil {
-		return m.BundleSize
+		return m.Value
 	}
-	return 0
+	return nil
 }
 
-// XXX_OneofWrappers is for the internal use of the proto package.
-func (*MEVMessage) XXX_OneofWrappers() []interface{} {
-	return []interface{}{
-		(*MEVMessage_Txs)(nil),
-	}
+// BundleEnvelope carries a whole bundle in a single message: its txs in
+// order, its metadata, and the signature of the relay that built it over its
+// CanonicalBundleEnvelope, so it stays attributable as it's passed on.
+type BundleEnvelope struct {
+	Txs [][]byte `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	// the bundle may be included at any height from min_height to max_height,
+	// both included
+	MinHeight int64           `protobuf:"varint,2,opt,name=min_height,json=minHeight,proto3" json:"min_height,omitempty"`
+	MaxHeight int64           `protobuf:"varint,3,opt,name=max_height,json=maxHeight,proto3" json:"max_height,omitempty"`
+	Bid       int64           `protobuf:"varint,4,opt,name=bid,proto3" json:"bid,omitempty"`
+	Placement BundlePlacement `protobuf:"varint,5,opt,name=placement,proto3,enum=tendermint.mempool.BundlePlacement" json:"placement,omitempty"`
+	// orders the bundle among those of the same height
+	BundleId   int64             `protobuf:"varint,6,opt,name=bundle_id,json=bundleId,proto3" json:"bundle_id,omitempty"`
+	PubKey     *crypto.PublicKey `protobuf:"bytes,7,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Signature  []byte            `protobuf:"bytes,8,opt,name=signature,proto3" json:"signature,omitempty"`
+	Extensions []BundleExtension `protobuf:"bytes,9,rep,name=extensions,proto3" json:"extensions"`
+	// chain the bundle is for, empty for any
+	ChainId string `protobuf:"bytes,10,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
 }
 
-func init() {
-	proto.RegisterType((*Txs)(nil), "tendermint.mempool.Txs")
-	proto.RegisterType((*Message)(nil), "tendermint.mempool.Message")
-	proto.RegisterType((*MEVMessage)(nil), "tendermint.mempool.MEVMessage")
+func (m *BundleEnvelope) Reset()         { *m = BundleEnvelope{} }
+func (m *BundleEnvelope) String() string { return proto.CompactTextString(m) }
+func (*BundleEnvelope) ProtoMessage()    {}
+func (*BundleEnvelope) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{7}
+}
+func (m *BundleEnvelope) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BundleEnvelope) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BundleEnvelope.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BundleEnvelope) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BundleEnvelope.Merge(m, src)
+}
+func (m *BundleEnvelope) XXX_Size() int {
+	return m.Size()
+}
+func (m *BundleEnvelope) XXX_DiscardUnknown() {
+	xxx_messageInfo_BundleEnvelope.DiscardUnknown(m)
 }
 
-func init() { proto.RegisterFile("tendermint/mempool/types.proto", fileDescriptor_2af51926fdbcbc05) }
+var xxx_messageInfo_BundleEnvelope proto.InternalMessageInfo
 
-var fileDescriptor_2af51926fdbcbc05 = []byte{
-	// 286 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x2b, 0x49, 0xcd, 0x4b,
-	0x49, 0x2d, 0xca, 0xcd, 0xcc, 0x2b, 0xd1, 0xcf, 0x4d, 0xcd, 0x2d, 0xc8, 0xcf, 0xcf, 0xd1, 0x2f,
-	0xa9, 0x2c, 0x48, 0x2d, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x12, 0x42, 0xc8, 0xeb, 0x41,
-	0xe5, 0x95, 0xc4, 0xb9, 0x98, 0x43, 0x2a, 0x8a, 0x85, 0x04, 0xb8, 0x98, 0x4b, 0x2a, 0x8a, 0x25,
-	0x18, 0x15, 0x98, 0x35, 0x78, 0x82, 0x40, 0x4c, 0x25, 0x5b, 0x2e, 0x76, 0xdf, 0xd4, 0xe2, 0xe2,
-	0xc4, 0xf4, 0x54, 0x21, 0x6d, 0x98, 0x24, 0xa3, 0x06, 0xb7, 0x91, 0xb8, 0x1e, 0xa6, 0x29, 0x7a,
-	0x21, 0x15, 0xc5, 0x1e, 0x0c, 0x60, 0x7d, 0x4e, 0xac, 0x5c, 0xcc, 0xc5, 0xa5, 0xb9, 0x4a, 0x27,
-	0x18, 0xb9, 0xb8, 0x7c, 0x5d, 0xc3, 0xc8, 0x31, 0x42, 0x48, 0x95, 0x8b, 0x2f, 0x25, 0xb5, 0x38,
-	0xb3, 0x28, 0x35, 0x25, 0x3e, 0x23, 0x35, 0x33, 0x3d, 0xa3, 0x44, 0x82, 0x49, 0x81, 0x51, 0x83,
-	0x39, 0x88, 0x17, 0x2a, 0xea, 0x01, 0x16, 0x14, 0x92, 0xe6, 0xe2, 0x4c, 0x2a, 0xcd, 0x4b, 0xc9,
-	0x49, 0x8d, 0xcf, 0x4c, 0x91, 0x60, 0x06, 0xab, 0xe0, 0x80, 0x08, 0x78, 0xa6, 0x08, 0x29, 0x72,
-	0xf1, 0x40, 0x25, 0xf3, 0x8b, 0x52, 0x52, 0x8b, 0x24, 0x58, 0xc0, 0xf2, 0xdc, 0x10, 0x31, 0x7f,
-	0x90, 0x90, 0x90, 0x3c, 0x17, 0x94, 0x1b, 0x5f, 0x9c, 0x59, 0x95, 0x2a, 0xc1, 0x0a, 0x56, 0xc1,
-	0x05, 0x11, 0x0a, 0xce, 0xac, 0x4a, 0x85, 0x7a, 0xc5, 0x29, 0xf8, 0xc4, 0x23, 0x39, 0xc6, 0x0b,
-	0x8f, 0xe4, 0x18, 0x1f, 0x3c, 0x92, 0x63, 0x9c, 0xf0, 0x58, 0x8e, 0xe1, 0xc2, 0x63, 0x39, 0x86,
-	0x1b, 0x8f, 0xe5, 0x18, 0xa2, 0x2c, 0xd3, 0x33, 0x4b, 0x32, 0x4a, 0x93, 0xf4, 0x92, 0xf3, 0x73,
-	0xf5, 0x91, 0xc2, 0x1e, 0x89, 0x09, 0x0e, 0x78, 0x7d, 0xcc, 0x78, 0x49, 0x62, 0x03, 0xcb, 0x18,
-	0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0x93, 0x24, 0x04, 0x86, 0xb4, 0x01, 0x00, 0x00,
+func (m *BundleEnvelope) GetTxs() [][]byte {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
 }
 
-func (m *Txs) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *BundleEnvelope) GetMinHeight() int64 {
+	if m != nil {
+		return m.MinHeight
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Txs) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *BundleEnvelope) GetMaxHeight() int64 {
+	if m != nil {
+		return m.MaxHeight
+	}
+	return 0
 }
 
-func (m *Txs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Txs) > 0 {
-		for iNdEx := len(m.Txs) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Txs[iNdEx])
-			copy(dAtA[i:], m.Txs[iNdEx])
-			i = encodeVarintTypes(dAtA, i, uint64(len(m.Txs[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-		}
+func (m *BundleEnvelope) GetBid() int64 {
+	if m != nil {
+		return m.Bid
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *Message) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *BundleEnvelope) GetPlacement() BundlePlacement {
+	if m != nil {
+		return m.Placement
 	}
-	return dAtA[:n], nil
+	return BundlePlacement_BUNDLE_PLACEMENT_MEV_LANE
 }
 
-func (m *Message) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *BundleEnvelope) GetBundleId() int64 {
+	if m != nil {
+		return m.BundleId
+	}
+	return 0
 }
 
-func (m *Message) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Sum != nil {
-		{
-			size := m.Sum.Size()
-			i -= size
-			if _, err := m.Sum.MarshalTo(dAtA[i:]); err != nil {
-				return 0, err
-			}
-		}
+func (m *BundleEnvelope) GetPubKey() *crypto.PublicKey {
+	if m != nil {
+		return m.PubKey
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *Message_Txs) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *BundleEnvelope) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
 }
 
-func (m *Message_Txs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	if m.Txs != nil {
-		{
-			size, err := m.Txs.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTypes(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0xa
+func (m *BundleEnvelope) GetExtensions() []BundleExtension {
+	if m != nil {
+		return m.Extensions
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
-func (m *MEVMessage) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+
+func (m *BundleEnvelope) GetChainId() string {
+	if m != nil {
+		return m.ChainId
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *MEVMessage) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// CanonicalBundleEnvelope is what the signature of a BundleEnvelope covers.
+type CanonicalBundleEnvelope struct {
+	// hash of the txs, see types.Txs.Hash
+	TxsHash    []byte            `protobuf:"bytes,1,opt,name=txs_hash,json=txsHash,proto3" json:"txs_hash,omitempty"`
+	MinHeight  int64             `protobuf:"varint,2,opt,name=min_height,json=minHeight,proto3" json:"min_height,omitempty"`
+	MaxHeight  int64             `protobuf:"varint,3,opt,name=max_height,json=maxHeight,proto3" json:"max_height,omitempty"`
+	Bid        int64             `protobuf:"varint,4,opt,name=bid,proto3" json:"bid,omitempty"`
+	Placement  BundlePlacement   `protobuf:"varint,5,opt,name=placement,proto3,enum=tendermint.mempool.BundlePlacement" json:"placement,omitempty"`
+	BundleId   int64             `protobuf:"varint,6,opt,name=bundle_id,json=bundleId,proto3" json:"bundle_id,omitempty"`
+	Extensions []BundleExtension `protobuf:"bytes,7,rep,name=extensions,proto3" json:"extensions"`
+	ChainId    string            `protobuf:"bytes,8,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
 }
 
-func (m *MEVMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.BundleSize != 0 {
-		i = encodeVarintTypes(dAtA, i, uint64(m.BundleSize))
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.BundleOrder != 0 {
-		i = encodeVarintTypes(dAtA, i, uint64(m.BundleOrder))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.BundleId != 0 {
-		i = encodeVarintTypes(dAtA, i, uint64(m.BundleId))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.DesiredHeight != 0 {
-		i = encodeVarintTypes(dAtA, i, uint64(m.DesiredHeight))
-		i--
-		dAtA[i] = 0x10
-	}
-	if m.Sum != nil {
-		{
-			size := m.Sum.Size()
-			i -= size
-			if _, err := m.Sum.MarshalTo(dAtA[i:]); err != nil {
-				return 0, err
-			}
+func (m *CanonicalBundleEnvelope) Reset()         { *m = CanonicalBundleEnvelope{} }
+func (m *CanonicalBundleEnvelope) String() string { return proto.CompactTextString(m) }
+func (*CanonicalBundleEnvelope) ProtoMessage()    {}
+func (*CanonicalBundleEnvelope) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{8}
+}
+func (m *CanonicalBundleEnvelope) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CanonicalBundleEnvelope) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CanonicalBundleEnvelope.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
 }
-
-func (m *MEVMessage_Txs) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *CanonicalBundleEnvelope) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CanonicalBundleEnvelope.Merge(m, src)
+}
+func (m *CanonicalBundleEnvelope) XXX_Size() int {
+	return m.Size()
+}
+func (m *CanonicalBundleEnvelope) XXX_DiscardUnknown() {
+	xxx_messageInfo_CanonicalBundleEnvelope.DiscardUnknown(m)
 }
 
-func (m *MEVMessage_Txs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	if m.Txs != nil {
-		{
-			size, err := m.Txs.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTypes(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0xa
+var xxx_messageInfo_CanonicalBundleEnvelope proto.InternalMessageInfo
+
+func (m *CanonicalBundleEnvelope) GetTxsHash() []byte {
+	if m != nil {
+		return m.TxsHash
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
-func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
-	offset -= sovTypes(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+
+func (m *CanonicalBundleEnvelope) GetMinHeight() int64 {
+	if m != nil {
+		return m.MinHeight
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return 0
 }
-func (m *Txs) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Txs) > 0 {
-		for _, b := range m.Txs {
-			l = len(b)
-			n += 1 + l + sovTypes(uint64(l))
-		}
+
+func (m *CanonicalBundleEnvelope) GetMaxHeight() int64 {
+	if m != nil {
+		return m.MaxHeight
 	}
-	return n
+	return 0
 }
 
-func (m *Message) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Sum != nil {
-		n += m.Sum.Size()
+func (m *CanonicalBundleEnvelope) GetBid() int64 {
+	if m != nil {
+		return m.Bid
 	}
-	return n
+	return 0
 }
 
-func (m *Message_Txs) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Txs != nil {
-		l = m.Txs.Size()
-		n += 1 + l + sovTypes(uint64(l))
+func (m *CanonicalBundleEnvelope) GetPlacement() BundlePlacement {
+	if m != nil {
+		return m.Placement
 	}
-	return n
+	return BundlePlacement_BUNDLE_PLACEMENT_MEV_LANE
 }
-func (m *MEVMessage) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Sum != nil {
-		n += m.Sum.Size()
-	}
-	if m.DesiredHeight != 0 {
-		n += 1 + sovTypes(uint64(m.DesiredHeight))
-	}
-	if m.BundleId != 0 {
-		n += 1 + sovTypes(uint64(m.BundleId))
-	}
-	if m.BundleOrder != 0 {
-		n += 1 + sovTypes(uint64(m.BundleOrder))
+
+func (m *CanonicalBundleEnvelope) GetBundleId() int64 {
+	if m != nil {
+		return m.BundleId
 	}
-	if m.BundleSize != 0 {
-		n += 1 + sovTypes(uint64(m.BundleSize))
+	return 0
+}
+
+func (m *CanonicalBundleEnvelope) GetExtensions() []BundleExtension {
+	if m != nil {
+		return m.Extensions
 	}
-	return n
+	return nil
 }
 
-func (m *MEVMessage_Txs) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *CanonicalBundleEnvelope) GetChainId() string {
+	if m != nil {
+		return m.ChainId
 	}
-	var l int
-	_ = l
-	if m.Txs != nil {
-		l = m.Txs.Size()
-		n += 1 + l + sovTypes(uint64(l))
+	return ""
+}
+
+type Message struct {
+	// Types that are valid to be assigned to Sum:
+	//	*Message_Txs
+	Sum isMessage_Sum `protobuf_oneof:"sum"`
+}
+
+func (m *Message) Reset()         { *m = Message{} }
+func (m *Message) String() string { return proto.CompactTextString(m) }
+func (*Message) ProtoMessage()    {}
+func (*Message) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{9}
+}
+func (m *Message) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Message.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return n
+}
+func (m *Message) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Message.Merge(m, src)
+}
+func (m *Message) XXX_Size() int {
+	return m.Size()
+}
+func (m *Message) XXX_DiscardUnknown() {
+	xxx_messageInfo_Message.DiscardUnknown(m)
 }
 
-func sovTypes(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
+var xxx_messageInfo_Message proto.InternalMessageInfo
+
+type isMessage_Sum interface {
+	isMessage_Sum()
+	MarshalTo([]byte) (int, error)
+	Size() int
 }
-func sozTypes(x uint64) (n int) {
-	return sovTypes(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+
+type Message_Txs struct {
+	Txs *Txs `protobuf:"bytes,1,opt,name=txs,proto3,oneof" json:"txs,omitempty"`
+}
+
+func (*Message_Txs) isMessage_Sum() {}
+
+func (m *Message) GetSum() isMessage_Sum {
+	if m != nil {
+		return m.Sum
+	}
+	return nil
+}
+
+func (m *Message) GetTxs() *Txs {
+	if x, ok := m.GetSum().(*Message_Txs); ok {
+		return x.Txs
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Message) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Message_Txs)(nil),
+	}
+}
+
+type MEVMessage struct {
+	// Types that are valid to be assigned to Sum:
+	//	*MEVMessage_Txs
+	//	*MEVMessage_Receipts
+	//	*MEVMessage_Registration
+	//	*MEVMessage_Ping
+	//	*MEVMessage_Pong
+	//	*MEVMessage_Envelope
+	Sum isMEVMessage_Sum `protobuf_oneof:"sum"`
+	// the tx metadata, for the txs sent one by one to peers not taking
+	// envelopes
+	DesiredHeight int64 `protobuf:"varint,2,opt,name=desired_height,json=desiredHeight,proto3" json:"desired_height,omitempty"`
+	BundleId      int64 `protobuf:"varint,3,opt,name=bundle_id,json=bundleId,proto3" json:"bundle_id,omitempty"`
+	BundleOrder   int64 `protobuf:"varint,4,opt,name=bundle_order,json=bundleOrder,proto3" json:"bundle_order,omitempty"`
+	BundleSize    int64 `protobuf:"varint,5,opt,name=bundle_size,json=bundleSize,proto3" json:"bundle_size,omitempty"`
+	// value the bundle pays the proposer, as reported by the relay
+	Bid int64 `protobuf:"varint,6,opt,name=bid,proto3" json:"bid,omitempty"`
+	// version of the sidecar protocol the sender speaks, and the bitmask of
+	// the optional features it supports, sent with every message so peers can
+	// adapt to each other per connection. Both are 0 from senders predating
+	// them.
+	Version      uint64 `protobuf:"varint,11,opt,name=version,proto3" json:"version,omitempty"`
+	Capabilities uint64 `protobuf:"varint,12,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	// chain the txs are for, so nodes of other chains sharing the relay refuse
+	// them. Empty from senders predating it, taken as any chain.
+	ChainId string `protobuf:"bytes,14,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+}
+
+func (m *MEVMessage) Reset()         { *m = MEVMessage{} }
+func (m *MEVMessage) String() string { return proto.CompactTextString(m) }
+func (*MEVMessage) ProtoMessage()    {}
+func (*MEVMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{10}
+}
+func (m *MEVMessage) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MEVMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MEVMessage.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MEVMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MEVMessage.Merge(m, src)
+}
+func (m *MEVMessage) XXX_Size() int {
+	return m.Size()
+}
+func (m *MEVMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_MEVMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MEVMessage proto.InternalMessageInfo
+
+type isMEVMessage_Sum interface {
+	isMEVMessage_Sum()
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
+
+type MEVMessage_Txs struct {
+	Txs *Txs `protobuf:"bytes,1,opt,name=txs,proto3,oneof" json:"txs,omitempty"`
+}
+type MEVMessage_Receipts struct {
+	Receipts *BundleReceipts `protobuf:"bytes,7,opt,name=receipts,proto3,oneof" json:"receipts,omitempty"`
+}
+type MEVMessage_Registration struct {
+	Registration *RelayRegistration `protobuf:"bytes,8,opt,name=registration,proto3,oneof" json:"registration,omitempty"`
+}
+type MEVMessage_Ping struct {
+	Ping *SidecarPing `protobuf:"bytes,9,opt,name=ping,proto3,oneof" json:"ping,omitempty"`
+}
+type MEVMessage_Pong struct {
+	Pong *SidecarPong `protobuf:"bytes,10,opt,name=pong,proto3,oneof" json:"pong,omitempty"`
+}
+type MEVMessage_Envelope struct {
+	Envelope *BundleEnvelope `protobuf:"bytes,13,opt,name=envelope,proto3,oneof" json:"envelope,omitempty"`
+}
+
+func (*MEVMessage_Txs) isMEVMessage_Sum()          {}
+func (*MEVMessage_Receipts) isMEVMessage_Sum()     {}
+func (*MEVMessage_Registration) isMEVMessage_Sum() {}
+func (*MEVMessage_Ping) isMEVMessage_Sum()         {}
+func (*MEVMessage_Pong) isMEVMessage_Sum()         {}
+func (*MEVMessage_Envelope) isMEVMessage_Sum()     {}
+
+func (m *MEVMessage) GetSum() isMEVMessage_Sum {
+	if m != nil {
+		return m.Sum
+	}
+	return nil
+}
+
+func (m *MEVMessage) GetTxs() *Txs {
+	if x, ok := m.GetSum().(*MEVMessage_Txs); ok {
+		return x.Txs
+	}
+	return nil
+}
+
+func (m *MEVMessage) GetReceipts() *BundleReceipts {
+	if x, ok := m.GetSum().(*MEVMessage_Receipts); ok {
+		return x.Receipts
+	}
+	return nil
+}
+
+func (m *MEVMessage) GetRegistration() *RelayRegistration {
+	if x, ok := m.GetSum().(*MEVMessage_Registration); ok {
+		return x.Registration
+	}
+	return nil
+}
+
+func (m *MEVMessage) GetPing() *SidecarPing {
+	if x, ok := m.GetSum().(*MEVMessage_Ping); ok {
+		return x.Ping
+	}
+	return nil
+}
+
+func (m *MEVMessage) GetPong() *SidecarPong {
+	if x, ok := m.GetSum().(*MEVMessage_Pong); ok {
+		return x.Pong
+	}
+	return nil
+}
+
+func (m *MEVMessage) GetEnvelope() *BundleEnvelope {
+	if x, ok := m.GetSum().(*MEVMessage_Envelope); ok {
+		return x.Envelope
+	}
+	return nil
+}
+
+func (m *MEVMessage) GetDesiredHeight() int64 {
+	if m != nil {
+		return m.DesiredHeight
+	}
+	return 0
+}
+
+func (m *MEVMessage) GetBundleId() int64 {
+	if m != nil {
+		return m.BundleId
+	}
+	return 0
+}
+
+func (m *MEVMessage) GetBundleOrder() int64 {
+	if m != nil {
+		return m.BundleOrder
+	}
+	return 0
+}
+
+func (m *MEVMessage) GetBundleSize() int64 {
+	if m != nil {
+		return m.BundleSize
+	}
+	return 0
+}
+
+func (m *MEVMessage) GetBid() int64 {
+	if m != nil {
+		return m.Bid
+	}
+	return 0
+}
+
+func (m *MEVMessage) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *MEVMessage) GetCapabilities() uint64 {
+	if m != nil {
+		return m.Capabilities
+	}
+	return 0
+}
+
+func (m *MEVMessage) GetChainId() string {
+	if m != nil {
+		return m.ChainId
+	}
+	return ""
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*MEVMessage) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*MEVMessage_Txs)(nil),
+		(*MEVMessage_Receipts)(nil),
+		(*MEVMessage_Registration)(nil),
+		(*MEVMessage_Ping)(nil),
+		(*MEVMessage_Pong)(nil),
+		(*MEVMessage_Envelope)(nil),
+	}
+}
+
+func init() {
+	proto.RegisterEnum("tendermint.mempool.BundlePlacement", BundlePlacement_name, BundlePlacement_value)
+	proto.RegisterType((*Txs)(nil), "tendermint.mempool.Txs")
+	proto.RegisterType((*BundleReceipts)(nil), "tendermint.mempool.BundleReceipts")
+	proto.RegisterType((*RelayRegistration)(nil), "tendermint.mempool.RelayRegistration")
+	proto.RegisterType((*CanonicalRelayRegistration)(nil), "tendermint.mempool.CanonicalRelayRegistration")
+	proto.RegisterType((*SidecarPing)(nil), "tendermint.mempool.SidecarPing")
+	proto.RegisterType((*SidecarPong)(nil), "tendermint.mempool.SidecarPong")
+	proto.RegisterType((*BundleExtension)(nil), "tendermint.mempool.BundleExtension")
+	proto.RegisterType((*BundleEnvelope)(nil), "tendermint.mempool.BundleEnvelope")
+	proto.RegisterType((*CanonicalBundleEnvelope)(nil), "tendermint.mempool.CanonicalBundleEnvelope")
+	proto.RegisterType((*Message)(nil), "tendermint.mempool.Message")
+	proto.RegisterType((*MEVMessage)(nil), "tendermint.mempool.MEVMessage")
+}
+
+func init() { proto.RegisterFile("tendermint/mempool/types.proto", fileDescriptor_2af51926fdbcbc05) }
+
+var fileDescriptor_2af51926fdbcbc05 = []byte{
+	// 924 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x56, 0x5f, 0x6b, 0xdb, 0x56,
+	0x14, 0xb7, 0x2a, 0xc7, 0x7f, 0x8e, 0xdd, 0xd4, 0xbb, 0x74, 0x44, 0xc9, 0x12, 0xc7, 0x55, 0x08,
+	0x98, 0x0d, 0x6c, 0xc8, 0x28, 0xa3, 0x8c, 0x41, 0xe3, 0xd4, 0xc3, 0x21, 0x71, 0x62, 0x6e, 0xb2,
+	0x3e, 0xf4, 0x45, 0x5c, 0x4b, 0x17, 0xf9, 0x52, 0xe9, 0x4a, 0xe8, 0xca, 0x41, 0xee, 0x77, 0x18,
+	0xec, 0x63, 0x95, 0x3d, 0xf5, 0x71, 0x4f, 0xdd, 0x48, 0xbe, 0xc1, 0x3e, 0xc1, 0xd0, 0x95, 0x14,
+	0x4b, 0x76, 0xbb, 0x42, 0xe9, 0x4b, 0xdf, 0xee, 0x3d, 0xbf, 0xf3, 0x3b, 0x3e, 0x7f, 0x7e, 0xf7,
+	0x58, 0xd0, 0x0e, 0x29, 0xb7, 0x68, 0xe0, 0x32, 0x1e, 0xf6, 0x5d, 0xea, 0xfa, 0x9e, 0xe7, 0xf4,
+	0xc3, 0x85, 0x4f, 0x45, 0xcf, 0x0f, 0xbc, 0xd0, 0x43, 0x68, 0x89, 0xf7, 0x52, 0x7c, 0xe7, 0xb1,
+	0xed, 0xd9, 0x9e, 0x84, 0xfb, 0xf1, 0x29, 0xf1, 0xdc, 0xd9, 0xcd, 0x45, 0x32, 0x83, 0x85, 0x1f,
+	0x7a, 0xfd, 0xd7, 0x74, 0x91, 0xc6, 0xd9, 0x39, 0xcc, 0xa1, 0x32, 0x7e, 0x7f, 0x3a, 0xe7, 0x96,
+	0x43, 0x8d, 0x80, 0x9a, 0x94, 0xf9, 0x61, 0xe2, 0xa6, 0x6f, 0x81, 0x7a, 0x1d, 0x09, 0xd4, 0x02,
+	0x35, 0x8c, 0x84, 0xa6, 0x74, 0xd4, 0x6e, 0x13, 0xc7, 0x47, 0x7d, 0x0c, 0x9b, 0x03, 0x49, 0xc0,
+	0x89, 0xbf, 0x40, 0x3f, 0x43, 0x2d, 0xe5, 0x26, 0x8e, 0x8d, 0xa3, 0xfd, 0x5e, 0x2e, 0xd9, 0xa4,
+	0x88, 0x02, 0x07, 0xdf, 0x13, 0xf4, 0xbf, 0x15, 0xf8, 0x06, 0x53, 0x87, 0x2c, 0x30, 0xb5, 0x99,
+	0x08, 0x03, 0x12, 0x32, 0x8f, 0xa3, 0x2d, 0xa8, 0x12, 0x9f, 0x19, 0xaf, 0xe9, 0x42, 0x53, 0x3a,
+	0x4a, 0xb7, 0x8e, 0x2b, 0xc4, 0x67, 0x67, 0x74, 0x81, 0x0e, 0xa0, 0xca, 0x3d, 0x8b, 0x1a, 0xcc,
+	0xd2, 0x1e, 0xc4, 0xc0, 0x00, 0x6e, 0xdf, 0xef, 0x57, 0x2e, 0x3c, 0x8b, 0x9e, 0xbe, 0xc0, 0x95,
+	0x18, 0x3a, 0xb5, 0xd0, 0x53, 0xa8, 0xfa, 0xf3, 0xa9, 0x64, 0xab, 0x1d, 0xa5, 0xdb, 0x38, 0xda,
+	0xcd, 0xe7, 0x93, 0xb4, 0xa4, 0x37, 0x99, 0x4f, 0x1d, 0x66, 0x9e, 0xd1, 0x05, 0xae, 0xf8, 0xf3,
+	0x69, 0x1c, 0x7b, 0x17, 0xea, 0x82, 0xd9, 0x9c, 0x84, 0xf3, 0x80, 0x6a, 0xe5, 0x8e, 0xd2, 0x6d,
+	0xe2, 0xa5, 0x01, 0xfd, 0x04, 0x9a, 0x4b, 0x22, 0x23, 0x6d, 0xd6, 0x8c, 0x32, 0x7b, 0x16, 0x0a,
+	0x83, 0xcc, 0x28, 0xb1, 0xb4, 0x8d, 0x8e, 0xd2, 0x55, 0xf1, 0xb7, 0x2e, 0x89, 0x92, 0x32, 0x47,
+	0x09, 0x7a, 0x1c, 0x83, 0xfa, 0x2b, 0xd8, 0x39, 0x21, 0xdc, 0xe3, 0xcc, 0x24, 0xce, 0x17, 0xae,
+	0x54, 0x3f, 0x80, 0xc6, 0x15, 0xb3, 0xa8, 0x49, 0x82, 0x09, 0xe3, 0x36, 0x7a, 0x0c, 0x1b, 0xdc,
+	0xe3, 0x26, 0x95, 0xa1, 0xca, 0x38, 0xb9, 0xe4, 0x9d, 0xbc, 0x8f, 0x3a, 0x3d, 0x83, 0x47, 0x49,
+	0xee, 0xc3, 0x28, 0xa4, 0x5c, 0xc4, 0xa9, 0xb5, 0x40, 0x5d, 0xa6, 0x15, 0x1f, 0x63, 0xea, 0x0d,
+	0x71, 0xe6, 0x54, 0x66, 0xd4, 0xc4, 0xc9, 0x45, 0xff, 0x5d, 0xcd, 0x24, 0x31, 0xe4, 0x37, 0xd4,
+	0xf1, 0x7c, 0xba, 0x2e, 0x1b, 0xb4, 0x07, 0xe0, 0x32, 0x9e, 0xf6, 0x4d, 0xf2, 0x55, 0x5c, 0x77,
+	0x19, 0x4f, 0x5a, 0x25, 0x61, 0x12, 0x65, 0xb0, 0x9a, 0xc2, 0x24, 0x4a, 0xe1, 0x16, 0xa8, 0x53,
+	0x66, 0xc9, 0xa1, 0xa8, 0x38, 0x3e, 0xa2, 0x63, 0xa8, 0xfb, 0x0e, 0x31, 0xa9, 0x4b, 0x79, 0x28,
+	0xfb, 0xbf, 0x79, 0x74, 0xd0, 0x5b, 0x7f, 0x22, 0xa9, 0xee, 0x26, 0x99, 0x2b, 0x5e, 0xb2, 0xd0,
+	0x77, 0x50, 0x4f, 0xa7, 0xc9, 0x2c, 0xad, 0x22, 0x43, 0xd7, 0x12, 0x43, 0x51, 0x43, 0xd5, 0xcf,
+	0xd5, 0x50, 0x6d, 0x55, 0x43, 0xa7, 0x00, 0x34, 0x6b, 0xaf, 0xd0, 0xea, 0xf2, 0xad, 0xfc, 0x4f,
+	0xd6, 0xf7, 0xa3, 0x18, 0x94, 0xdf, 0xbe, 0xdf, 0x2f, 0xe1, 0x1c, 0x19, 0x6d, 0x43, 0xcd, 0x9c,
+	0x11, 0xc6, 0xe3, 0xdc, 0x41, 0x4e, 0xa8, 0x2a, 0xef, 0xa7, 0x96, 0xfe, 0xe7, 0x03, 0xd8, 0xba,
+	0x57, 0xdc, 0xca, 0x60, 0xb6, 0xa1, 0x16, 0x46, 0xc2, 0x98, 0x11, 0x31, 0x93, 0x83, 0x6d, 0xe2,
+	0x6a, 0x18, 0x89, 0x11, 0x11, 0xb3, 0xaf, 0x6e, 0x42, 0xc5, 0x66, 0x56, 0xbf, 0x54, 0x33, 0x6b,
+	0xc5, 0x66, 0xfe, 0x02, 0xd5, 0x31, 0x15, 0x82, 0xd8, 0x14, 0xfd, 0x90, 0x89, 0x3a, 0x96, 0xc3,
+	0xd6, 0x87, 0x7e, 0xe9, 0x3a, 0x12, 0xa3, 0x92, 0xd4, 0xfb, 0x60, 0x03, 0x54, 0x31, 0x77, 0xf5,
+	0x7f, 0xcb, 0x00, 0xe3, 0xe1, 0xcb, 0xcf, 0x09, 0x81, 0x9e, 0xe7, 0xf6, 0x6a, 0xa2, 0x41, 0xfd,
+	0xe3, 0xe5, 0x65, 0xdb, 0x78, 0x54, 0x5a, 0x2e, 0x57, 0x74, 0x06, 0xcd, 0x20, 0xb7, 0x6c, 0x64,
+	0x6d, 0x8d, 0xa3, 0xc3, 0x0f, 0x45, 0x59, 0xdb, 0x4c, 0xa3, 0x12, 0x2e, 0x90, 0xd1, 0x53, 0x28,
+	0xfb, 0x8c, 0xdb, 0x5a, 0xbd, 0xa3, 0xac, 0xae, 0xf8, 0x2c, 0x48, 0x6e, 0x17, 0x8d, 0x4a, 0x58,
+	0xba, 0x4b, 0x9a, 0xc7, 0x6d, 0x0d, 0x3e, 0x4d, 0xf3, 0x52, 0x5a, 0xbc, 0xa5, 0x9e, 0x43, 0x8d,
+	0xa6, 0xa2, 0xd5, 0x1e, 0x7e, 0xaa, 0xf8, 0x4c, 0xde, 0x71, 0xf1, 0x19, 0x0b, 0x1d, 0xc2, 0xa6,
+	0x45, 0x05, 0x0b, 0xa8, 0x55, 0xd4, 0xf4, 0xc3, 0xd4, 0x9a, 0x0a, 0xb7, 0xa0, 0x31, 0x75, 0x45,
+	0x63, 0x4f, 0xa0, 0x99, 0x82, 0x5e, 0x60, 0xd1, 0x20, 0x95, 0x77, 0x23, 0xb1, 0x5d, 0xc6, 0x26,
+	0xb4, 0x0f, 0xe9, 0xd5, 0x10, 0xec, 0x0d, 0x4d, 0xff, 0x0a, 0x20, 0x31, 0x5d, 0xb1, 0x37, 0x34,
+	0x7b, 0x19, 0x95, 0xe5, 0xcb, 0xd0, 0xa0, 0x7a, 0x43, 0x83, 0x58, 0x7a, 0x5a, 0x43, 0xee, 0xe0,
+	0xec, 0x8a, 0x74, 0x68, 0x9a, 0xc4, 0x27, 0x53, 0xe6, 0xb0, 0x90, 0x51, 0xa1, 0x35, 0x25, 0x5c,
+	0xb0, 0x15, 0xc4, 0xba, 0x59, 0x10, 0x6b, 0x2a, 0xba, 0xef, 0xaf, 0xe0, 0xd1, 0xca, 0xa3, 0x42,
+	0x7b, 0xb0, 0x3d, 0xf8, 0xed, 0xe2, 0xc5, 0xf9, 0xd0, 0x98, 0x9c, 0x1f, 0x9f, 0x0c, 0xc7, 0xc3,
+	0x8b, 0x6b, 0x63, 0x3c, 0x7c, 0x69, 0x9c, 0x1f, 0x5f, 0x0c, 0x5b, 0x25, 0xf4, 0x04, 0xf6, 0xd6,
+	0xe0, 0xeb, 0xcb, 0x89, 0x71, 0xf9, 0xab, 0x31, 0x38, 0xbf, 0x3c, 0x39, 0x6b, 0x29, 0x83, 0xab,
+	0xb7, 0xb7, 0x6d, 0xe5, 0xdd, 0x6d, 0x5b, 0xf9, 0xe7, 0xb6, 0xad, 0xfc, 0x71, 0xd7, 0x2e, 0xbd,
+	0xbb, 0x6b, 0x97, 0xfe, 0xba, 0x6b, 0x97, 0x5e, 0x3d, 0xb3, 0x59, 0x38, 0x9b, 0x4f, 0x7b, 0xa6,
+	0xe7, 0xf6, 0xf3, 0x1f, 0x17, 0xcb, 0x63, 0xf2, 0x89, 0xb2, 0xfe, 0x81, 0x33, 0xad, 0x48, 0xe4,
+	0xc7, 0xff, 0x06, 0x00, 0xe6, 0x2b, 0xf1, 0xa7, 0xfd, 0x08, 0x00, 0x00,
+}
+
+func (m *Txs) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Txs) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Txs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Txs) > 0 {
+		for iNdEx := len(m.Txs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Txs[iNdEx])
+			copy(dAtA[i:], m.Txs[iNdEx])
+			i = encodeVarintTypes(dAtA, i, uint64(len(m.Txs[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BundleReceipts) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BundleReceipts) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BundleReceipts) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Receipts) > 0 {
+		for iNdEx := len(m.Receipts) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Receipts[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTypes(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RelayRegistration) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RelayRegistration) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RelayRegistration) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.MaxBundleHeightsAhead != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.MaxBundleHeightsAhead))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.PubKey != nil {
+		{
+			size, err := m.PubKey.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.NodeID) > 0 {
+		i -= len(m.NodeID)
+		copy(dAtA[i:], m.NodeID)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.NodeID)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ApiKey) > 0 {
+		i -= len(m.ApiKey)
+		copy(dAtA[i:], m.ApiKey)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.ApiKey)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CanonicalRelayRegistration) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CanonicalRelayRegistration) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CanonicalRelayRegistration) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.NodeID) > 0 {
+		i -= len(m.NodeID)
+		copy(dAtA[i:], m.NodeID)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.NodeID)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ApiKey) > 0 {
+		i -= len(m.ApiKey)
+		copy(dAtA[i:], m.ApiKey)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.ApiKey)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SidecarPing) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SidecarPing) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SidecarPing) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Nonce != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Nonce))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SidecarPong) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SidecarPong) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SidecarPong) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Nonce != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Nonce))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BundleExtension) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BundleExtension) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BundleExtension) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BundleEnvelope) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BundleEnvelope) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BundleEnvelope) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ChainId) > 0 {
+		i -= len(m.ChainId)
+		copy(dAtA[i:], m.ChainId)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.ChainId)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.Extensions) > 0 {
+		for iNdEx := len(m.Extensions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Extensions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTypes(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.PubKey != nil {
+		{
+			size, err := m.PubKey.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.BundleId != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.BundleId))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Placement != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Placement))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Bid != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Bid))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.MaxHeight != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.MaxHeight))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.MinHeight != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.MinHeight))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Txs) > 0 {
+		for iNdEx := len(m.Txs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Txs[iNdEx])
+			copy(dAtA[i:], m.Txs[iNdEx])
+			i = encodeVarintTypes(dAtA, i, uint64(len(m.Txs[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CanonicalBundleEnvelope) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CanonicalBundleEnvelope) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CanonicalBundleEnvelope) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ChainId) > 0 {
+		i -= len(m.ChainId)
+		copy(dAtA[i:], m.ChainId)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.ChainId)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Extensions) > 0 {
+		for iNdEx := len(m.Extensions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Extensions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTypes(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if m.BundleId != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.BundleId))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Placement != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Placement))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Bid != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Bid))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.MaxHeight != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.MaxHeight))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.MinHeight != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.MinHeight))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.TxsHash) > 0 {
+		i -= len(m.TxsHash)
+		copy(dAtA[i:], m.TxsHash)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.TxsHash)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Message) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Message) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Sum != nil {
+		{
+			size := m.Sum.Size()
+			i -= size
+			if _, err := m.Sum.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Message_Txs) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message_Txs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Txs != nil {
+		{
+			size, err := m.Txs.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+func (m *MEVMessage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MEVMessage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MEVMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ChainId) > 0 {
+		i -= len(m.ChainId)
+		copy(dAtA[i:], m.ChainId)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.ChainId)))
+		i--
+		dAtA[i] = 0x72
+	}
+	if m.Sum != nil {
+		{
+			size := m.Sum.Size()
+			i -= size
+			if _, err := m.Sum.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if m.Capabilities != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Capabilities))
+		i--
+		dAtA[i] = 0x60
+	}
+	if m.Version != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x58
+	}
+	if m.Bid != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Bid))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.BundleSize != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.BundleSize))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.BundleOrder != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.BundleOrder))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.BundleId != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.BundleId))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.DesiredHeight != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.DesiredHeight))
+		i--
+		dAtA[i] = 0x10
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MEVMessage_Txs) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MEVMessage_Txs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Txs != nil {
+		{
+			size, err := m.Txs.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+func (m *MEVMessage_Receipts) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MEVMessage_Receipts) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Receipts != nil {
+		{
+			size, err := m.Receipts.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *MEVMessage_Registration) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MEVMessage_Registration) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Registration != nil {
+		{
+			size, err := m.Registration.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	return len(dAtA) - i, nil
+}
+func (m *MEVMessage_Ping) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MEVMessage_Ping) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Ping != nil {
+		{
+			size, err := m.Ping.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *MEVMessage_Pong) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MEVMessage_Pong) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Pong != nil {
+		{
+			size, err := m.Pong.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	return len(dAtA) - i, nil
+}
+func (m *MEVMessage_Envelope) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MEVMessage_Envelope) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Envelope != nil {
+		{
+			size, err := m.Envelope.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x6a
+	}
+	return len(dAtA) - i, nil
+}
+func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
+	offset -= sovTypes(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Txs) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Txs) > 0 {
+		for _, b := range m.Txs {
+			l = len(b)
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *BundleReceipts) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Receipts) > 0 {
+		for _, e := range m.Receipts {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *RelayRegistration) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ApiKey)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.NodeID)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.PubKey != nil {
+		l = m.PubKey.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.MaxBundleHeightsAhead != 0 {
+		n += 1 + sovTypes(uint64(m.MaxBundleHeightsAhead))
+	}
+	return n
+}
+
+func (m *CanonicalRelayRegistration) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ApiKey)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.NodeID)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
+func (m *SidecarPing) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Nonce != 0 {
+		n += 1 + sovTypes(uint64(m.Nonce))
+	}
+	return n
+}
+
+func (m *SidecarPong) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Nonce != 0 {
+		n += 1 + sovTypes(uint64(m.Nonce))
+	}
+	return n
+}
+
+func (m *BundleExtension) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
+func (m *BundleEnvelope) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Txs) > 0 {
+		for _, b := range m.Txs {
+			l = len(b)
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	if m.MinHeight != 0 {
+		n += 1 + sovTypes(uint64(m.MinHeight))
+	}
+	if m.MaxHeight != 0 {
+		n += 1 + sovTypes(uint64(m.MaxHeight))
+	}
+	if m.Bid != 0 {
+		n += 1 + sovTypes(uint64(m.Bid))
+	}
+	if m.Placement != 0 {
+		n += 1 + sovTypes(uint64(m.Placement))
+	}
+	if m.BundleId != 0 {
+		n += 1 + sovTypes(uint64(m.BundleId))
+	}
+	if m.PubKey != nil {
+		l = m.PubKey.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if len(m.Extensions) > 0 {
+		for _, e := range m.Extensions {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	l = len(m.ChainId)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
+func (m *CanonicalBundleEnvelope) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TxsHash)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.MinHeight != 0 {
+		n += 1 + sovTypes(uint64(m.MinHeight))
+	}
+	if m.MaxHeight != 0 {
+		n += 1 + sovTypes(uint64(m.MaxHeight))
+	}
+	if m.Bid != 0 {
+		n += 1 + sovTypes(uint64(m.Bid))
+	}
+	if m.Placement != 0 {
+		n += 1 + sovTypes(uint64(m.Placement))
+	}
+	if m.BundleId != 0 {
+		n += 1 + sovTypes(uint64(m.BundleId))
+	}
+	if len(m.Extensions) > 0 {
+		for _, e := range m.Extensions {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	l = len(m.ChainId)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
+func (m *Message) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Sum != nil {
+		n += m.Sum.Size()
+	}
+	return n
+}
+
+func (m *Message_Txs) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Txs != nil {
+		l = m.Txs.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *MEVMessage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Sum != nil {
+		n += m.Sum.Size()
+	}
+	if m.DesiredHeight != 0 {
+		n += 1 + sovTypes(uint64(m.DesiredHeight))
+	}
+	if m.BundleId != 0 {
+		n += 1 + sovTypes(uint64(m.BundleId))
+	}
+	if m.BundleOrder != 0 {
+		n += 1 + sovTypes(uint64(m.BundleOrder))
+	}
+	if m.BundleSize != 0 {
+		n += 1 + sovTypes(uint64(m.BundleSize))
+	}
+	if m.Bid != 0 {
+		n += 1 + sovTypes(uint64(m.Bid))
+	}
+	if m.Version != 0 {
+		n += 1 + sovTypes(uint64(m.Version))
+	}
+	if m.Capabilities != 0 {
+		n += 1 + sovTypes(uint64(m.Capabilities))
+	}
+	l = len(m.ChainId)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
+func (m *MEVMessage_Txs) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Txs != nil {
+		l = m.Txs.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *MEVMessage_Receipts) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Receipts != nil {
+		l = m.Receipts.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *MEVMessage_Registration) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Registration != nil {
+		l = m.Registration.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *MEVMessage_Ping) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Ping != nil {
+		l = m.Ping.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *MEVMessage_Pong) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Pong != nil {
+		l = m.Pong.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *MEVMessage_Envelope) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Envelope != nil {
+		l = m.Envelope.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
+func sovTypes(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozTypes(x uint64) (n int) {
+	return sovTypes(uint64((x << 1) ^ uint64((int64(x) >> 63))))
 }
 func (m *Txs) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
@@ -533,30 +2063,1243 @@ func (m *Txs) Unmarshal(dAtA []byte) error {
 			if shift >= 64 {
 				return ErrIntOverflowTypes
 			}
-			if iNdEx >= l {
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Txs: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Txs: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Txs", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Txs = append(m.Txs, make([]byte, postIndex-iNdEx))
+			copy(m.Txs[len(m.Txs)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BundleReceipts) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BundleReceipts: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BundleReceipts: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Receipts", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Receipts = append(m.Receipts, &types.BundleReceipt{})
+			if err := m.Receipts[len(m.Receipts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RelayRegistration) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RelayRegistration: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RelayRegistration: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ApiKey", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ApiKey = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NodeID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NodeID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PubKey", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PubKey == nil {
+				m.PubKey = &crypto.PublicKey{}
+			}
+			if err := m.PubKey.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxBundleHeightsAhead", wireType)
+			}
+			m.MaxBundleHeightsAhead = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxBundleHeightsAhead |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CanonicalRelayRegistration) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CanonicalRelayRegistration: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CanonicalRelayRegistration: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ApiKey", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ApiKey = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NodeID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NodeID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SidecarPing) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SidecarPing: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SidecarPing: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonce", wireType)
+			}
+			m.Nonce = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Nonce |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SidecarPong) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SidecarPong: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SidecarPong: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonce", wireType)
+			}
+			m.Nonce = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Nonce |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BundleExtension) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BundleExtension: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BundleExtension: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BundleEnvelope) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BundleEnvelope: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BundleEnvelope: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Txs", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Txs = append(m.Txs, make([]byte, postIndex-iNdEx))
+			copy(m.Txs[len(m.Txs)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinHeight", wireType)
+			}
+			m.MinHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxHeight", wireType)
+			}
+			m.MaxHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bid", wireType)
+			}
+			m.Bid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Bid |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Placement", wireType)
+			}
+			m.Placement = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Placement |= BundlePlacement(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BundleId", wireType)
+			}
+			m.BundleId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BundleId |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PubKey", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PubKey == nil {
+				m.PubKey = &crypto.PublicKey{}
+			}
+			if err := m.PubKey.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Extensions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Extensions = append(m.Extensions, BundleExtension{})
+			if err := m.Extensions[len(m.Extensions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChainId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChainId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CanonicalBundleEnvelope) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CanonicalBundleEnvelope: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CanonicalBundleEnvelope: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxsHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TxsHash = append(m.TxsHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.TxsHash == nil {
+				m.TxsHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinHeight", wireType)
+			}
+			m.MinHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxHeight", wireType)
+			}
+			m.MaxHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bid", wireType)
+			}
+			m.Bid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Bid |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Placement", wireType)
+			}
+			m.Placement = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Placement |= BundlePlacement(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BundleId", wireType)
+			}
+			m.BundleId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BundleId |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Extensions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			m.Extensions = append(m.Extensions, BundleExtension{})
+			if err := m.Extensions[len(m.Extensions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Txs: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Txs: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Txs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ChainId", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -566,23 +3309,23 @@ func (m *Txs) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTypes
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTypes
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Txs = append(m.Txs, make([]byte, postIndex-iNdEx))
-			copy(m.Txs[len(m.Txs)-1], dAtA[iNdEx:postIndex])
+			m.ChainId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -830,6 +3573,270 @@ func (m *MEVMessage) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bid", wireType)
+			}
+			m.Bid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Bid |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Receipts", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &BundleReceipts{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &MEVMessage_Receipts{v}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Registration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &RelayRegistration{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &MEVMessage_Registration{v}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ping", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &SidecarPing{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &MEVMessage_Ping{v}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pong", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &SidecarPong{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &MEVMessage_Pong{v}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
+			}
+			m.Capabilities = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Capabilities |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Envelope", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &BundleEnvelope{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &MEVMessage_Envelope{v}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChainId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChainId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
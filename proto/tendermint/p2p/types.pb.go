@@ -244,8 +244,9 @@ func (m *DefaultNodeInfo) GetOther() DefaultNodeInfoOther {
 }
 
 type DefaultNodeInfoOther struct {
-	TxIndex    string `protobuf:"bytes,1,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
-	RPCAddress string `protobuf:"bytes,2,opt,name=rpc_address,json=rpcAddress,proto3" json:"rpc_address,omitempty"`
+	TxIndex     string `protobuf:"bytes,1,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
+	RPCAddress  string `protobuf:"bytes,2,opt,name=rpc_address,json=rpcAddress,proto3" json:"rpc_address,omitempty"`
+	MEVDisabled bool   `protobuf:"varint,3,opt,name=mev_disabled,json=mevDisabled,proto3" json:"mev_disabled,omitempty"`
 }
 
 func (m *DefaultNodeInfoOther) Reset()         { *m = DefaultNodeInfoOther{} }
@@ -295,6 +296,13 @@ func (m *DefaultNodeInfoOther) GetRPCAddress() string {
 	return ""
 }
 
+func (m *DefaultNodeInfoOther) GetMEVDisabled() bool {
+	if m != nil {
+		return m.MEVDisabled
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*NetAddress)(nil), "tendermint.p2p.NetAddress")
 	proto.RegisterType((*ProtocolVersion)(nil), "tendermint.p2p.ProtocolVersion")
@@ -305,37 +313,39 @@ func init() {
 func init() { proto.RegisterFile("tendermint/p2p/types.proto", fileDescriptor_c8a29e659aeca578) }
 
 var fileDescriptor_c8a29e659aeca578 = []byte{
-	// 479 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x52, 0x3d, 0x8f, 0xda, 0x40,
-	0x10, 0xc5, 0xc6, 0x7c, 0xdc, 0x10, 0x8e, 0xcb, 0x0a, 0x45, 0x3e, 0x0a, 0x1b, 0xa1, 0x14, 0x54,
-	0x20, 0x39, 0x4a, 0x91, 0x2e, 0x21, 0x34, 0x34, 0x77, 0xd6, 0x2a, 0x4a, 0x91, 0xc6, 0x02, 0xef,
-	0x1e, 0xac, 0x30, 0xbb, 0xab, 0xf5, 0x5e, 0x42, 0xfe, 0x45, 0x7e, 0xd6, 0x95, 0x57, 0xa6, 0xb2,
-	0x22, 0x53, 0xe6, 0x4f, 0x44, 0x5e, 0xfb, 0x12, 0x1f, 0x4a, 0x37, 0x6f, 0xbe, 0xde, 0xcc, 0xd3,
-	0x83, 0x91, 0xa6, 0x9c, 0x50, 0x75, 0x60, 0x5c, 0xcf, 0x65, 0x20, 0xe7, 0xfa, 0xbb, 0xa4, 0xe9,
-	0x4c, 0x2a, 0xa1, 0x05, 0xba, 0xfc, 0x57, 0x9b, 0xc9, 0x40, 0x8e, 0x86, 0x5b, 0xb1, 0x15, 0xa6,
-	0x34, 0x2f, 0xa2, 0xb2, 0x6b, 0x12, 0x02, 0xdc, 0x50, 0xfd, 0x81, 0x10, 0x45, 0xd3, 0x14, 0xbd,
-	0x02, 0x9b, 0x11, 0xd7, 0x1a, 0x5b, 0xd3, 0x8b, 0x45, 0x3b, 0xcf, 0x7c, 0x7b, 0xb5, 0xc4, 0x36,
-	0x23, 0x26, 0x2f, 0x5d, 0xbb, 0x96, 0x0f, 0xb1, 0xcd, 0x24, 0x42, 0xe0, 0x48, 0xa1, 0xb4, 0xdb,
-	0x1c, 0x5b, 0xd3, 0x3e, 0x36, 0xf1, 0xe4, 0x13, 0x0c, 0xc2, 0x62, 0x75, 0x2c, 0x92, 0xcf, 0x54,
-	0xa5, 0x4c, 0x70, 0x74, 0x0d, 0x4d, 0x19, 0x48, 0xb3, 0xd7, 0x59, 0x74, 0xf2, 0xcc, 0x6f, 0x86,
-	0x41, 0x88, 0x8b, 0x1c, 0x1a, 0x42, 0x6b, 0x93, 0x88, 0x78, 0x6f, 0x96, 0x3b, 0xb8, 0x04, 0xe8,
-	0x0a, 0x9a, 0x6b, 0x29, 0xcd, 0x5a, 0x07, 0x17, 0xe1, 0xe4, 0xb7, 0x0d, 0x83, 0x25, 0xbd, 0x5b,
-	0xdf, 0x27, 0xfa, 0x46, 0x10, 0xba, 0xe2, 0x77, 0x02, 0x85, 0x70, 0x25, 0x2b, 0xa6, 0xe8, 0x6b,
-	0x49, 0x65, 0x38, 0x7a, 0x81, 0x3f, 0x7b, 0xfe, 0xfc, 0xec, 0xec, 0xa2, 0x85, 0xf3, 0x90, 0xf9,
-	0x0d, 0x3c, 0x90, 0x67, 0x87, 0xbe, 0x83, 0x01, 0x29, 0x49, 0x22, 0x2e, 0x08, 0x8d, 0x18, 0xa9,
-	0x9e, 0x7e, 0x99, 0x67, 0x7e, 0xbf, 0xce, 0xbf, 0xc4, 0x7d, 0x52, 0x83, 0x04, 0xf9, 0xd0, 0x4b,
-	0x58, 0xaa, 0x29, 0x8f, 0xd6, 0x84, 0x28, 0x73, 0xfa, 0x05, 0x86, 0x32, 0x55, 0xc8, 0x8b, 0x5c,
-	0xe8, 0x70, 0xaa, 0xbf, 0x09, 0xb5, 0x77, 0x1d, 0x53, 0x7c, 0x82, 0x45, 0xe5, 0xe9, 0xfc, 0x56,
-	0x59, 0xa9, 0x20, 0x1a, 0x41, 0x37, 0xde, 0xad, 0x39, 0xa7, 0x49, 0xea, 0xb6, 0xc7, 0xd6, 0xf4,
-	0x05, 0xfe, 0x8b, 0x8b, 0xa9, 0x83, 0xe0, 0x6c, 0x4f, 0x95, 0xdb, 0x29, 0xa7, 0x2a, 0x88, 0xde,
-	0x43, 0x4b, 0xe8, 0x1d, 0x55, 0x6e, 0xd7, 0x88, 0xf1, 0xfa, 0x5c, 0x8c, 0x33, 0x1d, 0x6f, 0x8b,
-	0xde, 0x4a, 0x91, 0x72, 0x70, 0xb2, 0x81, 0xe1, 0xff, 0x9a, 0xd0, 0x35, 0x74, 0xf5, 0x31, 0x62,
-	0x9c, 0xd0, 0x63, 0xe9, 0x12, 0xdc, 0xd1, 0xc7, 0x55, 0x01, 0xd1, 0x1c, 0x7a, 0x4a, 0xc6, 0xe6,
-	0x79, 0x9a, 0xa6, 0x95, 0x6c, 0x97, 0x79, 0xe6, 0x03, 0x0e, 0x3f, 0x56, 0xfe, 0xc2, 0xa0, 0x64,
-	0x5c, 0xc5, 0x8b, 0xdb, 0x87, 0xdc, 0xb3, 0x1e, 0x73, 0xcf, 0xfa, 0x95, 0x7b, 0xd6, 0x8f, 0x93,
-	0xd7, 0x78, 0x3c, 0x79, 0x8d, 0x9f, 0x27, 0xaf, 0xf1, 0xe5, 0xed, 0x96, 0xe9, 0xdd, 0xfd, 0x66,
-	0x16, 0x8b, 0xc3, 0xbc, 0x66, 0xf0, 0xba, 0xd7, 0x8d, 0x8d, 0x9f, 0x9b, 0x7f, 0xd3, 0x36, 0xd9,
-	0x37, 0x7f, 0x02, 0x00, 0x00, 0xff, 0xff, 0x6c, 0xb3, 0x68, 0x97, 0x15, 0x03, 0x00, 0x00,
+	// 511 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0x31, 0x6f, 0xda, 0x40,
+	0x14, 0xc6, 0xc6, 0x09, 0xe4, 0x91, 0x84, 0xf4, 0x84, 0x2a, 0x87, 0xc1, 0x46, 0xa8, 0x03, 0x13,
+	0x48, 0xae, 0x3a, 0x74, 0x6b, 0x29, 0x1d, 0x18, 0x9a, 0x58, 0xa7, 0x2a, 0x43, 0x17, 0x0b, 0x7c,
+	0x17, 0x38, 0x61, 0xee, 0x4e, 0xe7, 0x0b, 0xa5, 0xff, 0xa2, 0x53, 0x7f, 0x53, 0xc6, 0x8c, 0x9d,
+	0xac, 0xca, 0x8c, 0xfd, 0x13, 0x95, 0xcf, 0xa6, 0x25, 0xa8, 0xdb, 0xfb, 0xbe, 0x77, 0xef, 0x7d,
+	0xef, 0x7d, 0x7a, 0x07, 0x5d, 0x4d, 0x39, 0xa1, 0x6a, 0xcd, 0xb8, 0x1e, 0xc9, 0x40, 0x8e, 0xf4,
+	0x37, 0x49, 0xd3, 0xa1, 0x54, 0x42, 0x0b, 0x74, 0xf9, 0x2f, 0x37, 0x94, 0x81, 0xec, 0x76, 0x16,
+	0x62, 0x21, 0x4c, 0x6a, 0x54, 0x44, 0xe5, 0xab, 0x7e, 0x08, 0x70, 0x43, 0xf5, 0x7b, 0x42, 0x14,
+	0x4d, 0x53, 0xf4, 0x12, 0x6c, 0x46, 0x5c, 0xab, 0x67, 0x0d, 0xce, 0xc6, 0xa7, 0x79, 0xe6, 0xdb,
+	0xd3, 0x09, 0xb6, 0x19, 0x31, 0xbc, 0x74, 0xed, 0x03, 0x3e, 0xc4, 0x36, 0x93, 0x08, 0x81, 0x23,
+	0x85, 0xd2, 0x6e, 0xbd, 0x67, 0x0d, 0x2e, 0xb0, 0x89, 0xfb, 0x9f, 0xa1, 0x1d, 0x16, 0xad, 0x63,
+	0x91, 0xdc, 0x51, 0x95, 0x32, 0xc1, 0xd1, 0x35, 0xd4, 0x65, 0x20, 0x4d, 0x5f, 0x67, 0xdc, 0xc8,
+	0x33, 0xbf, 0x1e, 0x06, 0x21, 0x2e, 0x38, 0xd4, 0x81, 0x93, 0x79, 0x22, 0xe2, 0x95, 0x69, 0xee,
+	0xe0, 0x12, 0xa0, 0x2b, 0xa8, 0xcf, 0xa4, 0x34, 0x6d, 0x1d, 0x5c, 0x84, 0xfd, 0xdf, 0x36, 0xb4,
+	0x27, 0xf4, 0x7e, 0xf6, 0x90, 0xe8, 0x1b, 0x41, 0xe8, 0x94, 0xdf, 0x0b, 0x14, 0xc2, 0x95, 0xac,
+	0x94, 0xa2, 0x4d, 0x29, 0x65, 0x34, 0x5a, 0x81, 0x3f, 0x7c, 0xbe, 0xfc, 0xf0, 0x68, 0xa2, 0xb1,
+	0xf3, 0x98, 0xf9, 0x35, 0xdc, 0x96, 0x47, 0x83, 0xbe, 0x85, 0x36, 0x29, 0x45, 0x22, 0x2e, 0x08,
+	0x8d, 0x18, 0xa9, 0x96, 0x7e, 0x91, 0x67, 0xfe, 0xc5, 0xa1, 0xfe, 0x04, 0x5f, 0x90, 0x03, 0x48,
+	0x90, 0x0f, 0xad, 0x84, 0xa5, 0x9a, 0xf2, 0x68, 0x46, 0x88, 0x32, 0xa3, 0x9f, 0x61, 0x28, 0xa9,
+	0xc2, 0x5e, 0xe4, 0x42, 0x83, 0x53, 0xfd, 0x55, 0xa8, 0x95, 0xeb, 0x98, 0xe4, 0x1e, 0x16, 0x99,
+	0xfd, 0xf8, 0x27, 0x65, 0xa6, 0x82, 0xa8, 0x0b, 0xcd, 0x78, 0x39, 0xe3, 0x9c, 0x26, 0xa9, 0x7b,
+	0xda, 0xb3, 0x06, 0xe7, 0xf8, 0x2f, 0x2e, 0xaa, 0xd6, 0x82, 0xb3, 0x15, 0x55, 0x6e, 0xa3, 0xac,
+	0xaa, 0x20, 0x7a, 0x07, 0x27, 0x42, 0x2f, 0xa9, 0x72, 0x9b, 0xc6, 0x8c, 0x57, 0xc7, 0x66, 0x1c,
+	0xf9, 0x78, 0x5b, 0xbc, 0xad, 0x1c, 0x29, 0x0b, 0xfb, 0x3f, 0x2c, 0xe8, 0xfc, 0xef, 0x15, 0xba,
+	0x86, 0xa6, 0xde, 0x46, 0x8c, 0x13, 0xba, 0x2d, 0xcf, 0x04, 0x37, 0xf4, 0x76, 0x5a, 0x40, 0x34,
+	0x82, 0x96, 0x92, 0xb1, 0xd9, 0x9e, 0xa6, 0x69, 0xe5, 0xdb, 0x65, 0x9e, 0xf9, 0x80, 0xc3, 0x0f,
+	0xd5, 0x81, 0x61, 0x50, 0x32, 0xae, 0x62, 0x14, 0xc0, 0xf9, 0x9a, 0x6e, 0x22, 0xc2, 0xd2, 0xd9,
+	0x3c, 0xa1, 0xc4, 0x58, 0xd6, 0x1c, 0xb7, 0xf3, 0xcc, 0x6f, 0x7d, 0xfa, 0x78, 0x37, 0xa9, 0x68,
+	0xdc, 0x5a, 0xd3, 0xcd, 0x1e, 0x8c, 0x6f, 0x1f, 0x73, 0xcf, 0x7a, 0xca, 0x3d, 0xeb, 0x57, 0xee,
+	0x59, 0xdf, 0x77, 0x5e, 0xed, 0x69, 0xe7, 0xd5, 0x7e, 0xee, 0xbc, 0xda, 0x97, 0x37, 0x0b, 0xa6,
+	0x97, 0x0f, 0xf3, 0x61, 0x2c, 0xd6, 0xa3, 0x83, 0x5f, 0x71, 0x10, 0x96, 0xb7, 0xff, 0xfc, 0xc7,
+	0xcc, 0x4f, 0x0d, 0xfb, 0xfa, 0xcf, 0x00, 0x1e, 0xe6, 0x59, 0xf4, 0x4a, 0x03, 0x00, 0x00,
 }
 
 func (m *NetAddress) Marshal() (dAtA []byte, err error) {
@@ -523,6 +533,16 @@ func (m *DefaultNodeInfoOther) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.MEVDisabled {
+		i--
+		if m.MEVDisabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
 	if len(m.RPCAddress) > 0 {
 		i -= len(m.RPCAddress)
 		copy(dAtA[i:], m.RPCAddress)
@@ -640,6 +660,9 @@ func (m *DefaultNodeInfoOther) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTypes(uint64(l))
 	}
+	if m.MEVDisabled {
+		n += 2
+	}
 	return n
 }
 
@@ -1292,6 +1315,26 @@ func (m *DefaultNodeInfoOther) Unmarshal(dAtA []byte) error {
 			}
 			m.RPCAddress = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MEVDisabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MEVDisabled = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -0,0 +1,1022 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: tendermint/types/bundle_receipt.proto
+
+package types
+
+import (
+	encoding_binary "encoding/binary"
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	crypto "github.com/tendermint/tendermint/proto/tendermint/crypto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// BundleReceipt is signed by a proposer for each bundle it included in a
+// block, as proof of inclusion for whoever submitted the bundle.
+type BundleReceipt struct {
+	Height     int64            `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	BlockHash  []byte           `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	BundleId   int64            `protobuf:"varint,3,opt,name=bundle_id,json=bundleId,proto3" json:"bundle_id,omitempty"`
+	BundleHash []byte           `protobuf:"bytes,4,opt,name=bundle_hash,json=bundleHash,proto3" json:"bundle_hash,omitempty"`
+	Position   int64            `protobuf:"varint,5,opt,name=position,proto3" json:"position,omitempty"`
+	Size_      int64            `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
+	PubKey     crypto.PublicKey `protobuf:"bytes,7,opt,name=pub_key,json=pubKey,proto3" json:"pub_key"`
+	Signature  []byte           `protobuf:"bytes,8,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *BundleReceipt) Reset()         { *m = BundleReceipt{} }
+func (m *BundleReceipt) String() string { return proto.CompactTextString(m) }
+func (*BundleReceipt) ProtoMessage()    {}
+func (*BundleReceipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ff071fe6ad34073c, []int{0}
+}
+func (m *BundleReceipt) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BundleReceipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BundleReceipt.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BundleReceipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BundleReceipt.Merge(m, src)
+}
+func (m *BundleReceipt) XXX_Size() int {
+	return m.Size()
+}
+func (m *BundleReceipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_BundleReceipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BundleReceipt proto.InternalMessageInfo
+
+func (m *BundleReceipt) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *BundleReceipt) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *BundleReceipt) GetBundleId() int64 {
+	if m != nil {
+		return m.BundleId
+	}
+	return 0
+}
+
+func (m *BundleReceipt) GetBundleHash() []byte {
+	if m != nil {
+		return m.BundleHash
+	}
+	return nil
+}
+
+func (m *BundleReceipt) GetPosition() int64 {
+	if m != nil {
+		return m.Position
+	}
+	return 0
+}
+
+func (m *BundleReceipt) GetSize_() int64 {
+	if m != nil {
+		return m.Size_
+	}
+	return 0
+}
+
+func (m *BundleReceipt) GetPubKey() crypto.PublicKey {
+	if m != nil {
+		return m.PubKey
+	}
+	return crypto.PublicKey{}
+}
+
+func (m *BundleReceipt) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// CanonicalBundleReceipt is what the signature of a BundleReceipt covers.
+type CanonicalBundleReceipt struct {
+	Height     int64  `protobuf:"fixed64,1,opt,name=height,proto3" json:"height,omitempty"`
+	BlockHash  []byte `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	BundleId   int64  `protobuf:"fixed64,3,opt,name=bundle_id,json=bundleId,proto3" json:"bundle_id,omitempty"`
+	BundleHash []byte `protobuf:"bytes,4,opt,name=bundle_hash,json=bundleHash,proto3" json:"bundle_hash,omitempty"`
+	Position   int64  `protobuf:"fixed64,5,opt,name=position,proto3" json:"position,omitempty"`
+	Size_      int64  `protobuf:"fixed64,6,opt,name=size,proto3" json:"size,omitempty"`
+	ChainID    string `protobuf:"bytes,7,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+}
+
+func (m *CanonicalBundleReceipt) Reset()         { *m = CanonicalBundleReceipt{} }
+func (m *CanonicalBundleReceipt) String() string { return proto.CompactTextString(m) }
+func (*CanonicalBundleReceipt) ProtoMessage()    {}
+func (*CanonicalBundleReceipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ff071fe6ad34073c, []int{1}
+}
+func (m *CanonicalBundleReceipt) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CanonicalBundleReceipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CanonicalBundleReceipt.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CanonicalBundleReceipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CanonicalBundleReceipt.Merge(m, src)
+}
+func (m *CanonicalBundleReceipt) XXX_Size() int {
+	return m.Size()
+}
+func (m *CanonicalBundleReceipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_CanonicalBundleReceipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CanonicalBundleReceipt proto.InternalMessageInfo
+
+func (m *CanonicalBundleReceipt) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *CanonicalBundleReceipt) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *CanonicalBundleReceipt) GetBundleId() int64 {
+	if m != nil {
+		return m.BundleId
+	}
+	return 0
+}
+
+func (m *CanonicalBundleReceipt) GetBundleHash() []byte {
+	if m != nil {
+		return m.BundleHash
+	}
+	return nil
+}
+
+func (m *CanonicalBundleReceipt) GetPosition() int64 {
+	if m != nil {
+		return m.Position
+	}
+	return 0
+}
+
+func (m *CanonicalBundleReceipt) GetSize_() int64 {
+	if m != nil {
+		return m.Size_
+	}
+	return 0
+}
+
+func (m *CanonicalBundleReceipt) GetChainID() string {
+	if m != nil {
+		return m.ChainID
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*BundleReceipt)(nil), "tendermint.types.BundleReceipt")
+	proto.RegisterType((*CanonicalBundleReceipt)(nil), "tendermint.types.CanonicalBundleReceipt")
+}
+
+func init() {
+	proto.RegisterFile("tendermint/types/bundle_receipt.proto", fileDescriptor_ff071fe6ad34073c)
+}
+
+var fileDescriptor_ff071fe6ad34073c = []byte{
+	// 391 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x92, 0xbd, 0x8a, 0xdb, 0x40,
+	0x14, 0x85, 0x35, 0xb6, 0xa3, 0x9f, 0x71, 0x02, 0x62, 0x08, 0x46, 0x38, 0x8e, 0x6c, 0x0c, 0x09,
+	0xae, 0x24, 0x48, 0x8a, 0x14, 0xe9, 0xe4, 0x14, 0x31, 0x6e, 0x12, 0x95, 0x69, 0x8c, 0x7e, 0x06,
+	0x69, 0xb0, 0x3c, 0x23, 0xa4, 0x51, 0xa1, 0xbc, 0xc4, 0xee, 0x63, 0xb9, 0x74, 0xb9, 0x95, 0x59,
+	0x64, 0xf6, 0x3d, 0x16, 0xcd, 0x68, 0xd7, 0x66, 0x61, 0xb7, 0xd8, 0xed, 0xee, 0x9c, 0x73, 0xcf,
+	0x95, 0xee, 0xc7, 0x85, 0x5f, 0x38, 0xa6, 0x31, 0x2e, 0x76, 0x84, 0x72, 0x97, 0xd7, 0x39, 0x2e,
+	0xdd, 0xb0, 0xa2, 0x71, 0x86, 0x37, 0x05, 0x8e, 0x30, 0xc9, 0xb9, 0x93, 0x17, 0x8c, 0x33, 0x64,
+	0x9e, 0xdb, 0x1c, 0xd1, 0x36, 0xfe, 0x98, 0xb0, 0x84, 0x09, 0xd3, 0x6d, 0x2b, 0xd9, 0x37, 0x9e,
+	0x5c, 0x8c, 0x8b, 0x8a, 0x3a, 0xe7, 0xcc, 0xdd, 0xe2, 0xba, 0x94, 0xee, 0xfc, 0xaa, 0x07, 0x3f,
+	0x78, 0x62, 0xbc, 0x2f, 0xa7, 0xa3, 0x11, 0x54, 0x53, 0x4c, 0x92, 0x94, 0x5b, 0x60, 0x06, 0x16,
+	0x7d, 0xbf, 0x7b, 0xa1, 0xcf, 0x10, 0x86, 0x19, 0x8b, 0xb6, 0x9b, 0x34, 0x28, 0x53, 0xab, 0x37,
+	0x03, 0x8b, 0xf7, 0xbe, 0x21, 0x94, 0xdf, 0x41, 0x99, 0xa2, 0x4f, 0xd0, 0xe8, 0x7e, 0x93, 0xc4,
+	0x56, 0x5f, 0x24, 0x75, 0x29, 0xac, 0x62, 0x34, 0x85, 0xc3, 0xce, 0x14, 0xe1, 0x81, 0x08, 0x43,
+	0x29, 0x89, 0xf4, 0x18, 0xea, 0x39, 0x2b, 0x09, 0x27, 0x8c, 0x5a, 0xef, 0x64, 0xf8, 0xe1, 0x8d,
+	0x10, 0x1c, 0x94, 0xe4, 0x3f, 0xb6, 0x54, 0xa1, 0x8b, 0x1a, 0xfd, 0x84, 0x5a, 0x5e, 0x85, 0x9b,
+	0x2d, 0xae, 0x2d, 0x6d, 0x06, 0x16, 0xc3, 0x6f, 0x13, 0xe7, 0x02, 0x87, 0x5c, 0xd3, 0xf9, 0x53,
+	0x85, 0x19, 0x89, 0xd6, 0xb8, 0xf6, 0x06, 0xfb, 0xe3, 0x54, 0xf1, 0xd5, 0xbc, 0x0a, 0xd7, 0xb8,
+	0x46, 0x13, 0x68, 0x94, 0x24, 0xa1, 0x01, 0xaf, 0x0a, 0x6c, 0xe9, 0x72, 0x91, 0x47, 0x61, 0x7e,
+	0x07, 0xe0, 0x68, 0x19, 0x50, 0x46, 0x49, 0x14, 0x64, 0x2f, 0xa1, 0x31, 0x5f, 0x8d, 0xc6, 0x7c,
+	0x0b, 0x1a, 0xf3, 0x19, 0x34, 0x66, 0x87, 0xe6, 0x2b, 0xd4, 0xa3, 0x34, 0x20, 0xb4, 0xfd, 0x58,
+	0xcb, 0xc6, 0xf0, 0x86, 0xcd, 0x71, 0xaa, 0x2d, 0x5b, 0x6d, 0xf5, 0xcb, 0xd7, 0x84, 0xb9, 0x8a,
+	0xbd, 0xbf, 0xfb, 0xc6, 0x06, 0x87, 0xc6, 0x06, 0xb7, 0x8d, 0x0d, 0xae, 0x4f, 0xb6, 0x72, 0x38,
+	0xd9, 0xca, 0xcd, 0xc9, 0x56, 0xfe, 0xfd, 0x48, 0x08, 0x4f, 0xab, 0xd0, 0x89, 0xd8, 0xce, 0xbd,
+	0xbc, 0xc5, 0x73, 0x29, 0x8f, 0xec, 0xe9, 0x9d, 0x86, 0xaa, 0xd0, 0xbf, 0xdf, 0x0f, 0x00, 0x8b,
+	0x6c, 0x81, 0x91, 0xc2, 0x02, 0x00, 0x00,
+}
+
+func (m *BundleReceipt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BundleReceipt) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BundleReceipt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintBundleReceipt(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x42
+	}
+	{
+		size, err := m.PubKey.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintBundleReceipt(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x3a
+	if m.Size_ != 0 {
+		i = encodeVarintBundleReceipt(dAtA, i, uint64(m.Size_))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Position != 0 {
+		i = encodeVarintBundleReceipt(dAtA, i, uint64(m.Position))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.BundleHash) > 0 {
+		i -= len(m.BundleHash)
+		copy(dAtA[i:], m.BundleHash)
+		i = encodeVarintBundleReceipt(dAtA, i, uint64(len(m.BundleHash)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.BundleId != 0 {
+		i = encodeVarintBundleReceipt(dAtA, i, uint64(m.BundleId))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.BlockHash) > 0 {
+		i -= len(m.BlockHash)
+		copy(dAtA[i:], m.BlockHash)
+		i = encodeVarintBundleReceipt(dAtA, i, uint64(len(m.BlockHash)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Height != 0 {
+		i = encodeVarintBundleReceipt(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CanonicalBundleReceipt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CanonicalBundleReceipt) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CanonicalBundleReceipt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ChainID) > 0 {
+		i -= len(m.ChainID)
+		copy(dAtA[i:], m.ChainID)
+		i = encodeVarintBundleReceipt(dAtA, i, uint64(len(m.ChainID)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.Size_ != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.Size_))
+		i--
+		dAtA[i] = 0x31
+	}
+	if m.Position != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.Position))
+		i--
+		dAtA[i] = 0x29
+	}
+	if len(m.BundleHash) > 0 {
+		i -= len(m.BundleHash)
+		copy(dAtA[i:], m.BundleHash)
+		i = encodeVarintBundleReceipt(dAtA, i, uint64(len(m.BundleHash)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.BundleId != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.BundleId))
+		i--
+		dAtA[i] = 0x19
+	}
+	if len(m.BlockHash) > 0 {
+		i -= len(m.BlockHash)
+		copy(dAtA[i:], m.BlockHash)
+		i = encodeVarintBundleReceipt(dAtA, i, uint64(len(m.BlockHash)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Height != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.Height))
+		i--
+		dAtA[i] = 0x9
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintBundleReceipt(dAtA []byte, offset int, v uint64) int {
+	offset -= sovBundleReceipt(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *BundleReceipt) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovBundleReceipt(uint64(m.Height))
+	}
+	l = len(m.BlockHash)
+	if l > 0 {
+		n += 1 + l + sovBundleReceipt(uint64(l))
+	}
+	if m.BundleId != 0 {
+		n += 1 + sovBundleReceipt(uint64(m.BundleId))
+	}
+	l = len(m.BundleHash)
+	if l > 0 {
+		n += 1 + l + sovBundleReceipt(uint64(l))
+	}
+	if m.Position != 0 {
+		n += 1 + sovBundleReceipt(uint64(m.Position))
+	}
+	if m.Size_ != 0 {
+		n += 1 + sovBundleReceipt(uint64(m.Size_))
+	}
+	l = m.PubKey.Size()
+	n += 1 + l + sovBundleReceipt(uint64(l))
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovBundleReceipt(uint64(l))
+	}
+	return n
+}
+
+func (m *CanonicalBundleReceipt) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 9
+	}
+	l = len(m.BlockHash)
+	if l > 0 {
+		n += 1 + l + sovBundleReceipt(uint64(l))
+	}
+	if m.BundleId != 0 {
+		n += 9
+	}
+	l = len(m.BundleHash)
+	if l > 0 {
+		n += 1 + l + sovBundleReceipt(uint64(l))
+	}
+	if m.Position != 0 {
+		n += 9
+	}
+	if m.Size_ != 0 {
+		n += 9
+	}
+	l = len(m.ChainID)
+	if l > 0 {
+		n += 1 + l + sovBundleReceipt(uint64(l))
+	}
+	return n
+}
+
+func sovBundleReceipt(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozBundleReceipt(x uint64) (n int) {
+	return sovBundleReceipt(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *BundleReceipt) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBundleReceipt
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BundleReceipt: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BundleReceipt: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockHash = append(m.BlockHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.BlockHash == nil {
+				m.BlockHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BundleId", wireType)
+			}
+			m.BundleId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BundleId |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BundleHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BundleHash = append(m.BundleHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.BundleHash == nil {
+				m.BundleHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Position", wireType)
+			}
+			m.Position = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Position |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+			}
+			m.Size_ = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Size_ |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PubKey", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.PubKey.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBundleReceipt(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CanonicalBundleReceipt) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBundleReceipt
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CanonicalBundleReceipt: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CanonicalBundleReceipt: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Height = int64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockHash = append(m.BlockHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.BlockHash == nil {
+				m.BlockHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BundleId", wireType)
+			}
+			m.BundleId = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BundleId = int64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BundleHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BundleHash = append(m.BundleHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.BundleHash == nil {
+				m.BundleHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Position", wireType)
+			}
+			m.Position = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Position = int64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 6:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+			}
+			m.Size_ = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Size_ = int64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChainID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChainID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBundleReceipt(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthBundleReceipt
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipBundleReceipt(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowBundleReceipt
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowBundleReceipt
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthBundleReceipt
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupBundleReceipt
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthBundleReceipt
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthBundleReceipt        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowBundleReceipt          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupBundleReceipt = fmt.Errorf("proto: unexpected end of group")
+)
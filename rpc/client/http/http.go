@@ -154,6 +154,7 @@ func NewWithClient(remote, wsEndpoint string, client *http.Client) (*HTTP, error
 }
 
 var _ rpcclient.Client = (*HTTP)(nil)
+var _ rpcclient.MEVClient = (*HTTP)(nil)
 
 // SetLogger sets a logger.
 func (c *HTTP) SetLogger(l log.Logger) {
@@ -565,6 +566,35 @@ func (c *baseRPCClient) BroadcastEvidence(
 	return result, nil
 }
 
+func (c *baseRPCClient) BundleReceipts(
+	ctx context.Context,
+	height *int64,
+) (*ctypes.ResultBundleReceipts, error) {
+	result := new(ctypes.ResultBundleReceipts)
+	params := make(map[string]interface{})
+	if height != nil {
+		params["height"] = height
+	}
+	_, err := c.caller.Call(ctx, "bundle_receipts", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *baseRPCClient) MEVStats(ctx context.Context, blocks *int64) (*ctypes.ResultMEVStats, error) {
+	result := new(ctypes.ResultMEVStats)
+	params := make(map[string]interface{})
+	if blocks != nil {
+		params["blocks"] = blocks
+	}
+	_, err := c.caller.Call(ctx, "mev_stats", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 //-----------------------------------------------------------------------------
 // WSEvents
 
@@ -143,6 +143,13 @@ type EvidenceClient interface {
 	BroadcastEvidence(context.Context, types.Evidence) (*ctypes.ResultBroadcastEvidence, error)
 }
 
+// MEVClient gives the outcome of the MEV auctions of the sidecar's bundles.
+// It's not part of Client, as only nodes running the sidecar serve it.
+type MEVClient interface {
+	BundleReceipts(ctx context.Context, height *int64) (*ctypes.ResultBundleReceipts, error)
+	MEVStats(ctx context.Context, blocks *int64) (*ctypes.ResultMEVStats, error)
+}
+
 // RemoteClient is a Client, which can also return the remote network address.
 type RemoteClient interface {
 	Client
@@ -61,6 +61,7 @@ func New(node *nm.Node) *Local {
 }
 
 var _ rpcclient.Client = (*Local)(nil)
+var _ rpcclient.MEVClient = (*Local)(nil)
 
 // SetLogger allows to set a logger on the client.
 func (c *Local) SetLogger(l log.Logger) {
@@ -205,6 +206,14 @@ func (c *Local) BroadcastEvidence(ctx context.Context, ev types.Evidence) (*ctyp
 	return core.BroadcastEvidence(c.ctx, ev)
 }
 
+func (c *Local) BundleReceipts(ctx context.Context, height *int64) (*ctypes.ResultBundleReceipts, error) {
+	return core.BundleReceipts(c.ctx, height)
+}
+
+func (c *Local) MEVStats(ctx context.Context, blocks *int64) (*ctypes.ResultMEVStats, error) {
+	return core.MEVStats(c.ctx, blocks)
+}
+
 func (c *Local) Subscribe(
 	ctx context.Context,
 	subscriber,
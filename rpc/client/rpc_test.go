@@ -365,6 +365,21 @@ func TestBroadcastTxCommit(t *testing.T) {
 	}
 }
 
+func TestBroadcastTxCommitSidecarTx(t *testing.T) {
+	sidecar := node.Sidecar()
+	c := getHTTPClient()
+
+	// a tx submitted privately is waited for without reaching the mempool
+	_, _, tx := MakeTxKV()
+	err := sidecar.AddTx(tx, mempl.TxInfo{DesiredHeight: sidecar.HeightForFiringAuction(), BundleSize: 1})
+	require.NoError(t, err)
+	bres, err := c.BroadcastTxCommit(context.Background(), tx)
+	require.NoError(t, err)
+	assert.True(t, bres.DeliverTx.IsOK())
+	assert.Greater(t, bres.Height, int64(0))
+	assert.Equal(t, 0, node.Mempool().Size())
+}
+
 func TestUnconfirmedTxs(t *testing.T) {
 	_, _, tx := MakeTxKV()
 
@@ -69,6 +69,19 @@ type peers interface {
 	Peers() p2p.IPeerSet
 }
 
+type sidecarConfig interface {
+	ReloadSidecarConfig() error
+	AddSidecarPeer(p2p.ID) error
+	RemoveSidecarPeer(p2p.ID) error
+	SetRelay(relay, apiKey string) error
+	SetMEVDisabled(disabled bool) error
+}
+
+type sidecarDumper interface {
+	Dump() mempl.SidecarDump
+	Load(mempl.SidecarDump) (int, error)
+}
+
 //----------------------------------------------
 // Environment contains objects and interfaces used by the RPC. It is expected
 // to be setup once during startup.
@@ -84,6 +97,7 @@ type Environment struct {
 	ConsensusState Consensus
 	P2PPeers       peers
 	P2PTransport   transport
+	SidecarConfig  sidecarConfig // reloads and updates the [sidecar] config
 
 	// objects
 	PubKey           crypto.PubKey
@@ -93,6 +107,11 @@ type Environment struct {
 	ConsensusReactor *consensus.Reactor
 	EventBus         *types.EventBus // thread safe
 	Mempool          mempl.Mempool
+	Sidecar          mempl.PriorityTxSidecar
+	SidecarDumper    sidecarDumper    // dumps the sidecar to a file, and loads it back
+	ReceiptStore     *sm.ReceiptStore // nil unless bundle receipts are enabled
+	MempoolReactor   *mempl.Reactor   // reports whether the MEV pipeline stalled
+	MEVStats         *mempl.MEVStats  // rolls up what became of the sidecar's bundles
 
 	Logger log.Logger
 
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// EthBundle is a bundle as submitted to Flashbots' eth_sendBundle: its txs,
+// hex encoded, the block it targets, a hex quantity, and optionally the unix
+// times it's valid between. Other fields, eg. revertingTxHashes, are ignored.
+type EthBundle struct {
+	Txs          []string `json:"txs"`
+	BlockNumber  string   `json:"blockNumber"`
+	MinTimestamp int64    `json:"minTimestamp,omitempty"`
+	MaxTimestamp int64    `json:"maxTimestamp,omitempty"`
+}
+
+// UnmarshalJSON decodes the bundle with encoding/json, as searcher bots send
+// its timestamps as JSON numbers, which tmjson refuses.
+func (b *EthBundle) UnmarshalJSON(bz []byte) error {
+	type ethBundle EthBundle
+	return json.Unmarshal(bz, (*ethBundle)(b))
+}
+
+// Parse returns the bundle's txs and the height it targets, if it's valid at
+// now.
+func (b EthBundle) Parse(now time.Time) (types.Txs, int64, error) {
+	if len(b.Txs) == 0 {
+		return nil, 0, errors.New("txs are required")
+	}
+	txs := make(types.Txs, len(b.Txs))
+	for i, s := range b.Txs {
+		tx, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid tx %d: %w", i, err)
+		}
+		txs[i] = tx
+	}
+	if !strings.HasPrefix(b.BlockNumber, "0x") {
+		return nil, 0, fmt.Errorf("blockNumber must be a 0x-prefixed hex quantity, got %q", b.BlockNumber)
+	}
+	height, err := strconv.ParseInt(b.BlockNumber[2:], 16, 64)
+	if err != nil || height <= 0 {
+		return nil, 0, fmt.Errorf("invalid blockNumber %q", b.BlockNumber)
+	}
+	if b.MinTimestamp > 0 && now.Unix() < b.MinTimestamp {
+		return nil, 0, fmt.Errorf("bundle not valid before %d", b.MinTimestamp)
+	}
+	if b.MaxTimestamp > 0 && now.Unix() > b.MaxTimestamp {
+		return nil, 0, fmt.Errorf("bundle expired at %d", b.MaxTimestamp)
+	}
+	return txs, height, nil
+}
+
+// EthSendBundle adds a bundle submitted as to Flashbots' eth_sendBundle to
+// the sidecar, so searcher bots written for Ethereum can target this chain
+// with few changes. The bundle's blockNumber is the height of the auction it
+// targets, and its min and max timestamps are checked against the node's
+// clock on submission, the block's time not being known yet. The bundle bids
+// nothing, and is given the ID after the highest the sidecar holds for its
+// height. Refused on nodes with a relay configured, see
+// mempool.Reactor.SubmitBundle.
+// Only routed if rpc.eth_send_bundle is set, see BundleRoutes.
+func EthSendBundle(ctx *rpctypes.Context, bundle EthBundle) (*ctypes.ResultEthSendBundle, error) {
+	txs, height, err := bundle.Parse(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	bundleID, err := env.MempoolReactor.SubmitBundle(txs, height)
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultEthSendBundle{
+		BundleHash: "0x" + hex.EncodeToString(txs.Hash()),
+		BundleID:   bundleID,
+	}, nil
+}
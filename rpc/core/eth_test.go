@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestEthBundleParse(t *testing.T) {
+	var bundle EthBundle
+	// as sent by searcher bots, timestamps unquoted
+	err := tmjson.Unmarshal([]byte(`{"txs":["0x6b3d76","6b32"],"blockNumber":"0x2a",`+
+		`"minTimestamp":100,"maxTimestamp":200,"revertingTxHashes":[]}`), &bundle)
+	require.NoError(t, err)
+
+	txs, height, err := bundle.Parse(time.Unix(150, 0))
+	require.NoError(t, err)
+	assert.Equal(t, types.Txs{types.Tx("k=v"), types.Tx("k2")}, txs)
+	assert.EqualValues(t, 42, height)
+
+	_, _, err = bundle.Parse(time.Unix(99, 0))
+	assert.Error(t, err)
+	_, _, err = bundle.Parse(time.Unix(201, 0))
+	assert.Error(t, err)
+
+	testCases := []struct {
+		name   string
+		bundle EthBundle
+	}{
+		{"no txs", EthBundle{BlockNumber: "0x1"}},
+		{"tx not hex", EthBundle{Txs: []string{"0xzz"}, BlockNumber: "0x1"}},
+		{"decimal block number", EthBundle{Txs: []string{"0x00"}, BlockNumber: "1"}},
+		{"zero block number", EthBundle{Txs: []string{"0x00"}, BlockNumber: "0x0"}},
+	}
+	for _, tc := range testCases {
+		_, _, err := tc.bundle.Parse(time.Now())
+		assert.Error(t, err, tc.name)
+	}
+}
@@ -6,8 +6,13 @@ import (
 )
 
 // Health gets node health. Returns empty result (200 OK) on success, no
-// response - in case of an error.
+// response - in case of an error, eg. if the MEV pipeline stalled.
 // More: https://docs.tendermint.com/master/rpc/#/Info/health
 func Health(ctx *rpctypes.Context) (*ctypes.ResultHealth, error) {
+	if env != nil && env.MempoolReactor != nil {
+		if err := env.MempoolReactor.Stalled(); err != nil {
+			return nil, err
+		}
+	}
 	return &ctypes.ResultHealth{}, nil
 }
@@ -10,6 +10,7 @@ import (
 	mempl "github.com/tendermint/tendermint/mempool"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/state/txindex/null"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -20,7 +21,7 @@ import (
 // CheckTx nor DeliverTx results.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_async
 func BroadcastTxAsync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
-	err := env.Mempool.CheckTx(tx, nil, mempl.TxInfo{})
+	err := env.Mempool.CheckTx(tx, nil, mempl.TxInfo{Origin: mempl.OriginRPC})
 
 	if err != nil {
 		return nil, err
@@ -35,7 +36,7 @@ func BroadcastTxSync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcas
 	resCh := make(chan *abci.Response, 1)
 	err := env.Mempool.CheckTx(tx, func(res *abci.Response) {
 		resCh <- res
-	}, mempl.TxInfo{})
+	}, mempl.TxInfo{Origin: mempl.OriginRPC})
 	if err != nil {
 		return nil, err
 	}
@@ -51,6 +52,11 @@ func BroadcastTxSync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcas
 }
 
 // BroadcastTxCommit returns with the responses from CheckTx and DeliverTx.
+// Inclusion is tracked through the txs of committed blocks, not the mempool,
+// so a tx already submitted privately through the sidecar isn't added to the
+// public mempool: its inclusion in a bundle is waited for instead, with an
+// empty CheckTx response. Likewise, a tx already committed (eg. in a bundle)
+// returns its indexed result if the mempool refuses it as a duplicate.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_commit
 func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 	subscriber := ctx.RemoteAddr()
@@ -77,12 +83,27 @@ func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadc
 		}
 	}()
 
+	if env.Sidecar != nil {
+		if _, _, ok := env.Sidecar.GetTxByKey(mempl.TxKey(tx)); ok {
+			return waitForTxCommit(tx, deliverTxSub, &abci.ResponseCheckTx{})
+		}
+	}
+
 	// Broadcast tx and wait for CheckTx result
 	checkTxResCh := make(chan *abci.Response, 1)
 	err = env.Mempool.CheckTx(tx, func(res *abci.Response) {
 		checkTxResCh <- res
-	}, mempl.TxInfo{})
+	}, mempl.TxInfo{Origin: mempl.OriginRPC})
 	if err != nil {
+		if err == mempl.ErrTxInCache {
+			if r := committedTxResult(tx); r != nil {
+				return &ctypes.ResultBroadcastTxCommit{
+					DeliverTx: r.Result,
+					Hash:      tx.Hash(),
+					Height:    r.Height,
+				}, nil
+			}
+		}
 		env.Logger.Error("Error on broadcastTxCommit", "err", err)
 		return nil, fmt.Errorf("error on broadcastTxCommit: %v", err)
 	}
@@ -96,7 +117,30 @@ func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadc
 		}, nil
 	}
 
-	// Wait for the tx to be included in a block or timeout.
+	return waitForTxCommit(tx, deliverTxSub, checkTxRes)
+}
+
+// committedTxResult returns the indexed result of tx, or nil if it wasn't
+// committed or indexing is disabled.
+func committedTxResult(tx types.Tx) *abci.TxResult {
+	if _, ok := env.TxIndexer.(*null.TxIndex); ok {
+		return nil
+	}
+	r, err := env.TxIndexer.Get(tx.Hash())
+	if err != nil {
+		env.Logger.Error("Error looking up committed tx", "err", err)
+		return nil
+	}
+	return r
+}
+
+// waitForTxCommit waits for tx to be included in a block, as signaled on
+// deliverTxSub, or for the broadcast_tx_commit timeout.
+func waitForTxCommit(
+	tx types.Tx,
+	deliverTxSub types.Subscription,
+	checkTxRes *abci.ResponseCheckTx,
+) (*ctypes.ResultBroadcastTxCommit, error) {
 	select {
 	case msg := <-deliverTxSub.Out(): // The tx was included in a block.
 		deliverTxRes := msg.Data().(types.EventDataTx)
@@ -113,7 +157,7 @@ func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadc
 		} else {
 			reason = deliverTxSub.Err().Error()
 		}
-		err = fmt.Errorf("deliverTxSub was cancelled (reason: %s)", reason)
+		err := fmt.Errorf("deliverTxSub was cancelled (reason: %s)", reason)
 		env.Logger.Error("Error on broadcastTxCommit", "err", err)
 		return &ctypes.ResultBroadcastTxCommit{
 			CheckTx:   *checkTxRes,
@@ -121,7 +165,7 @@ func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadc
 			Hash:      tx.Hash(),
 		}, err
 	case <-time.After(env.Config.TimeoutBroadcastTxCommit):
-		err = errors.New("timed out waiting for tx to be included in a block")
+		err := errors.New("timed out waiting for tx to be included in a block")
 		env.Logger.Error("Error on broadcastTxCommit", "err", err)
 		return &ctypes.ResultBroadcastTxCommit{
 			CheckTx:   *checkTxRes,
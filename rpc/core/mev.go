@@ -0,0 +1,217 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	mempl "github.com/tendermint/tendermint/mempool"
+	"github.com/tendermint/tendermint/p2p"
+	protomem "github.com/tendermint/tendermint/proto/tendermint/mempool"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+// BundleReceipts gets the signed receipts for the bundles included in the
+// block at a given height, if this node proposed it. If no height is
+// provided, it will fetch receipts for the latest block.
+func BundleReceipts(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBundleReceipts, error) {
+	if env.ReceiptStore == nil {
+		return nil, errors.New("bundle receipts are disabled on this node")
+	}
+	height, err := getHeight(env.BlockStore.Height(), heightPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ctypes.ResultBundleReceipts{
+		Height:   height,
+		Receipts: env.ReceiptStore.Load(height),
+	}, nil
+}
+
+// defaultMEVStatsBlocks is how many blocks MEVStats rolls up by default.
+const defaultMEVStatsBlocks = 100
+
+// MEVStats rolls up what became of the bundles this node's sidecar received
+// for the last blocks heights, 100 by default and at most 1000: how many were
+// included, the total of their bids, the fraction of those received that were
+// included, and how long bundles took to arrive whole on average. Bundles
+// still in the sidecar aren't accounted for yet.
+func MEVStats(ctx *rpctypes.Context, blocksPtr *int64) (*ctypes.ResultMEVStats, error) {
+	if env.MEVStats == nil {
+		return nil, errors.New("MEV stats are disabled on this node")
+	}
+	blocks := int64(defaultMEVStatsBlocks)
+	if blocksPtr != nil {
+		blocks = *blocksPtr
+	}
+	if blocks <= 0 {
+		return nil, fmt.Errorf("blocks must be greater than 0, but got %d", blocks)
+	}
+
+	summary := env.MEVStats.Summary(env.BlockStore.Height(), blocks)
+	return &ctypes.ResultMEVStats{
+		FromHeight:      summary.FromHeight,
+		ToHeight:        summary.ToHeight,
+		BundlesReceived: summary.BundlesReceived,
+		BundlesIncluded: summary.BundlesIncluded,
+		TotalBids:       summary.TotalBids,
+		WinRate:         summary.WinRate,
+		AvgLatency:      summary.AvgLatency,
+	}, nil
+}
+
+// BroadcastBundle adds the bundle in envelope, a proto-encoded
+// BundleEnvelope signed by the relay that built it, to the sidecar, for the
+// upcoming auction if within the envelope's height range, or else the first
+// height of the range. The envelope is gossiped to the node's sidecar peers
+// as if received from one of them. Only routed if rpc.broadcast_bundle is
+// set, see BundleRoutes.
+func BroadcastBundle(ctx *rpctypes.Context, envelope []byte) (*ctypes.ResultBroadcastBundle, error) {
+	var pb protomem.BundleEnvelope
+	if err := pb.Unmarshal(envelope); err != nil {
+		return nil, fmt.Errorf("invalid envelope: %w", err)
+	}
+	bundle, err := mempl.BundleEnvelopeFromProto(&pb)
+	if err != nil {
+		return nil, fmt.Errorf("invalid envelope: %w", err)
+	}
+	height, err := env.MempoolReactor.SubmitEnvelope(bundle)
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultBroadcastBundle{Hash: bundle.Txs.Hash(), Height: height}, nil
+}
+
+// UnsafeReloadSidecar re-reads the [sidecar] section of the config file and
+// applies the sidecar peers, relay and bid floor, without restarting the node.
+func UnsafeReloadSidecar(ctx *rpctypes.Context) (*ctypes.ResultUnsafeReloadSidecar, error) {
+	if env.SidecarConfig == nil {
+		return nil, errors.New("sidecar config can't be reloaded on this node")
+	}
+	if err := env.SidecarConfig.ReloadSidecarConfig(); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultUnsafeReloadSidecar{}, nil
+}
+
+// UnsafeAddSidecarPeer adds a sidecar peer to personal_peer_ids, persisting
+// it to the config file, and applies it without restarting the node.
+func UnsafeAddSidecarPeer(ctx *rpctypes.Context, peerID string) (*ctypes.ResultUnsafeUpdateSidecar, error) {
+	if env.SidecarConfig == nil {
+		return nil, errors.New("sidecar config can't be updated on this node")
+	}
+	if err := env.SidecarConfig.AddSidecarPeer(p2p.ID(peerID)); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultUnsafeUpdateSidecar{}, nil
+}
+
+// UnsafeRemoveSidecarPeer removes a sidecar peer from personal_peer_ids,
+// persisting it to the config file, and applies it without restarting the
+// node.
+func UnsafeRemoveSidecarPeer(ctx *rpctypes.Context, peerID string) (*ctypes.ResultUnsafeUpdateSidecar, error) {
+	if env.SidecarConfig == nil {
+		return nil, errors.New("sidecar config can't be updated on this node")
+	}
+	if err := env.SidecarConfig.RemoveSidecarPeer(p2p.ID(peerID)); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultUnsafeUpdateSidecar{}, nil
+}
+
+// UnsafeSetRelay sets the relay, as an id@host:port address to dial or a node
+// ID, and the API key to register with it, persisting them to the config
+// file, and applies them without restarting the node. An empty relay removes
+// it.
+func UnsafeSetRelay(ctx *rpctypes.Context, relay, apiKey string) (*ctypes.ResultUnsafeUpdateSidecar, error) {
+	if env.SidecarConfig == nil {
+		return nil, errors.New("sidecar config can't be updated on this node")
+	}
+	if err := env.SidecarConfig.SetRelay(relay, apiKey); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultUnsafeUpdateSidecar{}, nil
+}
+
+// UnsafeSetMEVDisabled turns MEV off, or back on, persisting it to the config
+// file, without restarting the node. While off, the node takes no sidecar tx,
+// runs no auction and proposes like a node without MEV.
+func UnsafeSetMEVDisabled(ctx *rpctypes.Context, disabled bool) (*ctypes.ResultUnsafeUpdateSidecar, error) {
+	if env.SidecarConfig == nil {
+		return nil, errors.New("sidecar config can't be updated on this node")
+	}
+	if err := env.SidecarConfig.SetMEVDisabled(disabled); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultUnsafeUpdateSidecar{}, nil
+}
+
+// UnsafeSidecarSelfTest checks that the relay and each sidecar peer are
+// connected, negotiated the sidecar channel, and answer a ping on it.
+func UnsafeSidecarSelfTest(ctx *rpctypes.Context) (*ctypes.ResultSidecarSelfTest, error) {
+	if env.MempoolReactor == nil {
+		return nil, errors.New("sidecar can't be self-tested on this node")
+	}
+	st := env.MempoolReactor.SidecarSelfTest(ctx.Context())
+	result := &ctypes.ResultSidecarSelfTest{
+		Passed:      st.Passed(),
+		MEVDisabled: st.MEVDisabled,
+		Peers:       make([]ctypes.SidecarPeerSelfTest, len(st.Peers)),
+	}
+	for i, check := range st.Peers {
+		result.Peers[i] = ctypes.SidecarPeerSelfTest{
+			ID:             check.ID,
+			Relay:          check.Relay,
+			Connected:      check.Connected,
+			SidecarChannel: check.Channel,
+			RTT:            check.RTT,
+		}
+		if check.Err != nil {
+			result.Peers[i].Error = check.Err.Error()
+		}
+	}
+	return result, nil
+}
+
+// UnsafeDumpSidecar writes a dump of the sidecar's internals, its txs
+// included, to the file at path on the node's host, to reproduce ordering
+// bugs offline, see UnsafeLoadSidecar.
+func UnsafeDumpSidecar(ctx *rpctypes.Context, path string) (*ctypes.ResultUnsafeDumpSidecar, error) {
+	if env.SidecarDumper == nil {
+		return nil, errors.New("sidecar can't be dumped on this node")
+	}
+	if path == "" {
+		return nil, errors.New("path is required")
+	}
+	dump := env.SidecarDumper.Dump()
+	if err := mempl.WriteSidecarDumpFile(path, dump); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultUnsafeDumpSidecar{
+		NumTxs:     len(dump.Txs),
+		NumBundles: len(dump.Bundles),
+	}, nil
+}
+
+// UnsafeLoadSidecar loads the dump written to the file at path on the node's
+// host into the sidecar, which must be empty, as if its txs were received
+// again, eg. on a test node to reproduce ordering bugs offline. Txs the
+// sidecar refuses are skipped.
+func UnsafeLoadSidecar(ctx *rpctypes.Context, path string) (*ctypes.ResultUnsafeLoadSidecar, error) {
+	if env.SidecarDumper == nil {
+		return nil, errors.New("sidecar can't be loaded on this node")
+	}
+	dump, err := mempl.ReadSidecarDumpFile(path)
+	if err != nil {
+		return nil, err
+	}
+	added, err := env.SidecarDumper.Load(dump)
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultUnsafeLoadSidecar{
+		Added:   added,
+		Skipped: len(dump.Txs) - added,
+	}, nil
+}
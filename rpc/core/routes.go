@@ -46,6 +46,10 @@ var Routes = map[string]*rpc.RPCFunc{
 
 	// evidence API
 	"broadcast_evidence": rpc.NewRPCFunc(BroadcastEvidence, "evidence"),
+
+	// mev API
+	"bundle_receipts": rpc.NewRPCFunc(BundleReceipts, "height"),
+	"mev_stats":       rpc.NewRPCFunc(MEVStats, "blocks"),
 }
 
 // AddUnsafeRoutes adds unsafe routes.
@@ -54,4 +58,33 @@ func AddUnsafeRoutes() {
 	Routes["dial_seeds"] = rpc.NewRPCFunc(UnsafeDialSeeds, "seeds")
 	Routes["dial_peers"] = rpc.NewRPCFunc(UnsafeDialPeers, "peers,persistent,unconditional,private")
 	Routes["unsafe_flush_mempool"] = rpc.NewRPCFunc(UnsafeFlushMempool, "")
+	Routes["unsafe_reload_sidecar"] = rpc.NewRPCFunc(UnsafeReloadSidecar, "")
+	Routes["unsafe_add_sidecar_peer"] = rpc.NewRPCFunc(UnsafeAddSidecarPeer, "peer_id")
+	Routes["unsafe_remove_sidecar_peer"] = rpc.NewRPCFunc(UnsafeRemoveSidecarPeer, "peer_id")
+	Routes["unsafe_set_relay"] = rpc.NewRPCFunc(UnsafeSetRelay, "relay,api_key")
+	Routes["unsafe_set_mev_disabled"] = rpc.NewRPCFunc(UnsafeSetMEVDisabled, "disabled")
+	Routes["unsafe_sidecar_self_test"] = rpc.NewRPCFunc(UnsafeSidecarSelfTest, "")
+	Routes["unsafe_dump_sidecar"] = rpc.NewRPCFunc(UnsafeDumpSidecar, "path")
+	Routes["unsafe_load_sidecar"] = rpc.NewRPCFunc(UnsafeLoadSidecar, "path")
+}
+
+// BundleRoutes are the bundle submission routes, served apart from Routes on
+// rpc.bundle_laddr, and with them only if rpc.bundle_routes_on_laddr is set.
+var BundleRoutes = map[string]*rpc.RPCFunc{}
+
+// AddEthBundleRoutes adds eth_sendBundle, see EthSendBundle.
+func AddEthBundleRoutes() {
+	BundleRoutes["eth_sendBundle"] = rpc.NewRPCFunc(EthSendBundle, "bundle")
+}
+
+// AddBroadcastBundleRoutes adds broadcast_bundle, see BroadcastBundle.
+func AddBroadcastBundleRoutes() {
+	BundleRoutes["broadcast_bundle"] = rpc.NewRPCFunc(BroadcastBundle, "envelope")
+}
+
+// AddBundleRoutesToRoutes adds the BundleRoutes added so far to Routes.
+func AddBundleRoutesToRoutes() {
+	for name, route := range BundleRoutes {
+		Routes[name] = route
+	}
 }
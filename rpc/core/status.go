@@ -8,10 +8,11 @@ import (
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
 	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
 )
 
 // Status returns Tendermint status including node info, pubkey, latest block
-// hash, app hash, block height and time.
+// hash, app hash, block height and time, and how the node takes part in MEV.
 // More: https://docs.tendermint.com/master/rpc/#/Info/status
 func Status(ctx *rpctypes.Context) (*ctypes.ResultStatus, error) {
 	var (
@@ -69,6 +70,15 @@ func Status(ctx *rpctypes.Context) (*ctypes.ResultStatus, error) {
 			PubKey:      env.PubKey,
 			VotingPower: votingPower,
 		},
+		MEVInfo: ctypes.MEVInfo{SidecarProtocol: version.SidecarProtocol},
+	}
+	if env.MempoolReactor != nil {
+		info := env.MempoolReactor.MEVInfo()
+		result.MEVInfo.Mode = info.Mode
+		result.MEVInfo.MEVDisabled = info.MEVDisabled
+		result.MEVInfo.DryRun = info.DryRun
+		result.MEVInfo.RelayConnected = info.RelayConnected
+		result.MEVInfo.AuctionHeight = info.AuctionHeight
 	}
 
 	return result, nil
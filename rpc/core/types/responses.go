@@ -55,6 +55,64 @@ type ResultBlockResults struct {
 	ConsensusParamUpdates *abci.ConsensusParams     `json:"consensus_param_updates"`
 }
 
+// Signed receipts for the bundles of a block this node proposed
+type ResultBundleReceipts struct {
+	Height   int64                 `json:"height"`
+	Receipts []types.BundleReceipt `json:"receipts"`
+}
+
+// MEV statistics of the last blocks, from the bundles this node received
+type ResultMEVStats struct {
+	FromHeight      int64         `json:"from_height"`
+	ToHeight        int64         `json:"to_height"`
+	BundlesReceived int64         `json:"bundles_received"`
+	BundlesIncluded int64         `json:"bundles_included"`
+	TotalBids       int64         `json:"total_bids"`
+	WinRate         float64       `json:"win_rate"`
+	AvgLatency      time.Duration `json:"avg_latency"`
+}
+
+// Result of the self-test of the sidecar peers and the relay
+type ResultSidecarSelfTest struct {
+	Passed      bool                  `json:"passed"`
+	MEVDisabled bool                  `json:"mev_disabled"`
+	Peers       []SidecarPeerSelfTest `json:"peers"`
+}
+
+// Result of the self-test of a sidecar peer
+type SidecarPeerSelfTest struct {
+	ID             p2p.ID        `json:"id"`
+	Relay          bool          `json:"relay"`
+	Connected      bool          `json:"connected"`
+	SidecarChannel bool          `json:"sidecar_channel"`
+	RTT            time.Duration `json:"rtt"`
+	Error          string        `json:"error,omitempty"`
+}
+
+// Result of dumping the sidecar to a file
+type ResultUnsafeDumpSidecar struct {
+	NumTxs     int `json:"num_txs"`
+	NumBundles int `json:"num_bundles"`
+}
+
+// Result of loading a dump into the sidecar
+type ResultUnsafeLoadSidecar struct {
+	Added   int `json:"added"`
+	Skipped int `json:"skipped"`
+}
+
+// Result of broadcasting a bundle envelope
+type ResultBroadcastBundle struct {
+	Hash   bytes.HexBytes `json:"hash"`   // hash of the bundle's txs
+	Height int64          `json:"height"` // height the bundle was added to the sidecar for
+}
+
+// Result of submitting a bundle with eth_sendBundle, named as Flashbots'
+type ResultEthSendBundle struct {
+	BundleHash string `json:"bundleHash"` // 0x-prefixed hash of the bundle's txs
+	BundleID   int64  `json:"bundleId"`
+}
+
 // NewResultCommit is a helper to initialize the ResultCommit with
 // the embedded struct
 func NewResultCommit(header *types.Header, commit *types.Commit,
@@ -96,6 +154,17 @@ type ResultStatus struct {
 	NodeInfo      p2p.DefaultNodeInfo `json:"node_info"`
 	SyncInfo      SyncInfo            `json:"sync_info"`
 	ValidatorInfo ValidatorInfo       `json:"validator_info"`
+	MEVInfo       MEVInfo             `json:"mev_info"`
+}
+
+// How the node takes part in MEV
+type MEVInfo struct {
+	SidecarProtocol uint64 `json:"sidecar_protocol"`
+	Mode            string `json:"mode"`
+	MEVDisabled     bool   `json:"mev_disabled"`
+	DryRun          bool   `json:"dry_run"`
+	RelayConnected  bool   `json:"relay_connected"`
+	AuctionHeight   int64  `json:"auction_height"`
 }
 
 // Is TxIndexing enabled
@@ -236,11 +305,13 @@ type ResultBroadcastEvidence struct {
 
 // empty results
 type (
-	ResultUnsafeFlushMempool struct{}
-	ResultUnsafeProfile      struct{}
-	ResultSubscribe          struct{}
-	ResultUnsubscribe        struct{}
-	ResultHealth             struct{}
+	ResultUnsafeFlushMempool  struct{}
+	ResultUnsafeReloadSidecar struct{}
+	ResultUnsafeUpdateSidecar struct{}
+	ResultUnsafeProfile       struct{}
+	ResultSubscribe           struct{}
+	ResultUnsubscribe         struct{}
+	ResultHealth              struct{}
 )
 
 // Event data from a subscription
@@ -1,11 +1,18 @@
 package state
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
 	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
 	"github.com/tendermint/tendermint/libs/fail"
 	"github.com/tendermint/tendermint/libs/log"
@@ -41,6 +48,31 @@ type BlockExecutor struct {
 	logger log.Logger
 
 	metrics *Metrics
+	// blocks proposed, and those containing a bundle, since started
+	numProposals, numProposalsWithBundles int64
+
+	// builds a bundle when the sidecar has none to offer, optional
+	bundleBuilder BundleBuilder
+
+	// simulates proposals carrying bundles before they're made, optional
+	simulator ProposalSimulator
+
+	// signs and keeps receipts for the bundles of our proposals, optional
+	receiptKey crypto.PrivKey
+	receipts   *ReceiptStore
+
+	// re-check each assembled proposal, see checkProposalInvariants
+	checkInvariants           bool
+	panicOnInvariantViolation bool
+
+	// traces the assembly of proposals and their auctions
+	tracer trace.Tracer
+
+	// captures profiles when assembling a proposal is slow, optional
+	profiler *SlowProposalProfiler
+
+	// runs the auctions but leaves their bundles out of the proposals
+	mevDryRun bool
 }
 
 type BlockExecutorOption func(executor *BlockExecutor)
@@ -51,6 +83,70 @@ func BlockExecutorWithMetrics(metrics *Metrics) BlockExecutorOption {
 	}
 }
 
+// BlockExecutorWithTracer traces the assembly of each proposal, and the
+// outcome of its auction, with a span started by tracer.
+func BlockExecutorWithTracer(tracer trace.Tracer) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.tracer = tracer
+	}
+}
+
+// BlockExecutorWithSlowProposalProfiler has profiler capture profiles when
+// reaping the txs of a proposal and running its auction is slow.
+func BlockExecutorWithSlowProposalProfiler(profiler *SlowProposalProfiler) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.profiler = profiler
+	}
+}
+
+// BlockExecutorWithMEVDryRun runs the auction of each proposal, recording
+// its outcome in the logs, metrics and events as usual, but makes the
+// proposal without its bundles, nor signs receipts for them. See
+// SidecarConfig.DryRun.
+func BlockExecutorWithMEVDryRun() BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.mevDryRun = true
+	}
+}
+
+// BlockExecutorWithBundleBuilder makes proposals that got no complete bundle
+// from the sidecar lead with a bundle built by builder instead.
+func BlockExecutorWithBundleBuilder(builder BundleBuilder) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.bundleBuilder = builder
+	}
+}
+
+// BlockExecutorWithProposalSimulator makes proposals carrying bundles get
+// simulated by simulator first. If the simulation fails, the proposal is
+// made without any bundle.
+func BlockExecutorWithProposalSimulator(simulator ProposalSimulator) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.simulator = simulator
+	}
+}
+
+// BlockExecutorWithReceipts makes the executor sign a receipt with privKey for
+// each bundle included in its proposals, and keep those of committed blocks
+// in store.
+func BlockExecutorWithReceipts(privKey crypto.PrivKey, store *ReceiptStore) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.receiptKey = privKey
+		blockExec.receipts = store
+	}
+}
+
+// BlockExecutorWithInvariantChecks enables checking every assembled proposal
+// for bundle contiguity, ordering, dedup and budget compliance. Violations are
+// logged and counted, or cause a panic if panicOnViolation is set (meant for
+// tests).
+func BlockExecutorWithInvariantChecks(panicOnViolation bool) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.checkInvariants = true
+		blockExec.panicOnInvariantViolation = panicOnViolation
+	}
+}
+
 // NewBlockExecutor returns a new BlockExecutor with a NopEventBus.
 // Call SetEventBus to provide one.
 func NewBlockExecutor(
@@ -71,6 +167,7 @@ func NewBlockExecutor(
 		sidecar:  sidecar,
 		logger:   logger,
 		metrics:  NopMetrics(),
+		tracer:   trace.NewNoopTracerProvider().Tracer(""),
 	}
 
 	for _, option := range options {
@@ -100,6 +197,10 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 	proposerAddr []byte,
 ) (*types.Block, *types.PartSet) {
 
+	_, span := blockExec.tracer.Start(context.Background(), "state.CreateProposalBlock",
+		trace.WithAttributes(attribute.Int64("height", height)))
+	defer span.End()
+
 	maxBytes := state.ConsensusParams.Block.MaxBytes
 	maxGas := state.ConsensusParams.Block.MaxGas
 
@@ -114,10 +215,221 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 		"sidecar_size", blockExec.sidecar.Size(),
 		"mempool_size", blockExec.mempool.Size(),
 	)
-	sidecarTxs := blockExec.sidecar.ReapMaxTxs()
+	reapStart := time.Now()
+	sidecarTxs, candidates := blockExec.sidecar.ReapAuction()
+	if len(sidecarTxs) == 0 && blockExec.bundleBuilder != nil {
+		builtTxs, built := blockExec.bundleBuilder.BuildBundle(maxDataBytes, maxGas)
+		sidecarTxs, candidates = builtTxs, append(candidates, built...)
+	}
 	txs := blockExec.mempool.ReapMaxBytesMaxGas(maxDataBytes, maxGas, sidecarTxs)
 
-	return state.MakeBlock(height, txs, commit, evidence, proposerAddr)
+	// sidecar txs are reaped right after protected txs, so any that didn't fit
+	// are at the end
+	sidecarStart, numSidecarTxs := locateSidecarTxs(txs, sidecarTxs)
+	if numSidecarTxs > 0 && blockExec.simulator != nil {
+		if err := blockExec.simulator.SimulateProposal(height, txs); err != nil {
+			blockExec.logger.Error("proposal simulation failed, proposing without bundles",
+				"height", height, "err", err)
+			txs = blockExec.mempool.ReapMaxBytesMaxGas(maxDataBytes, maxGas, nil)
+			sidecarTxs, sidecarStart, numSidecarTxs = nil, 0, 0
+			for i := range candidates {
+				if candidates[i].Status == types.AuctionBundleIncluded {
+					candidates[i].Status = types.AuctionBundleRejected
+				}
+			}
+		}
+	}
+	// in a dry run the auction is reported as it would have gone, but the
+	// block checked and counted is the one proposed, without bundles
+	auctionSidecarTxs, proposedCandidates := numSidecarTxs, candidates
+	if blockExec.mevDryRun {
+		if numSidecarTxs > 0 {
+			blockExec.logger.Info("MEV dry run, proposing without bundles",
+				"height", height, "sidecar_txs", numSidecarTxs)
+			txs = blockExec.mempool.ReapMaxBytesMaxGas(maxDataBytes, maxGas, nil)
+		}
+		sidecarTxs, sidecarStart, numSidecarTxs, proposedCandidates = nil, 0, 0, nil
+	}
+	if blockExec.checkInvariants {
+		blockExec.checkProposal(height, proposalAssembly{
+			txs:           txs,
+			sidecarTxs:    sidecarTxs,
+			candidates:    proposedCandidates,
+			sidecarStart:  sidecarStart,
+			numSidecarTxs: numSidecarTxs,
+			maxDataBytes:  maxDataBytes,
+			maxGas:        maxGas,
+		})
+	}
+	blockExec.fireAuction(height, candidates, auctionSidecarTxs)
+	blockExec.recordProposal(proposedCandidates)
+	traceAuction(span, height, candidates)
+	if blockExec.profiler != nil {
+		blockExec.profiler.observe(height, time.Since(reapStart))
+	}
+
+	block, partSet := state.MakeBlock(height, txs, commit, evidence, proposerAddr)
+	if blockExec.receipts != nil && !blockExec.mevDryRun {
+		blockExec.signReceipts(state.ChainID, block, candidates, sidecarStart)
+	}
+	return block, partSet
+}
+
+// signReceipts signs a receipt for each bundle of the auction included in
+// block from tx sidecarStart on, and keeps them until the block is either
+// committed or not.
+func (blockExec *BlockExecutor) signReceipts(
+	chainID string,
+	block *types.Block,
+	candidates []types.AuctionBundle,
+	sidecarStart int,
+) {
+	receipts := types.NewBundleReceipts(block, candidates, int64(sidecarStart))
+	for i := range receipts {
+		if err := receipts[i].Sign(chainID, blockExec.receiptKey); err != nil {
+			blockExec.logger.Error("failed signing bundle receipts", "height", block.Height, "err", err)
+			return
+		}
+		blockExec.logger.Debug("signed bundle receipt",
+			"bundle_height", receipts[i].Height, "bundle_id", receipts[i].BundleID,
+			"bundle_hash", receipts[i].BundleHash, "position", receipts[i].Position)
+	}
+	blockExec.receipts.AddPending(block.Hash(), receipts)
+}
+
+// checkProposal runs the proposal invariant checks, reporting any violation.
+func (blockExec *BlockExecutor) checkProposal(height int64, p proposalAssembly) {
+	for _, violation := range checkProposalInvariants(p) {
+		if blockExec.panicOnInvariantViolation {
+			panic(fmt.Sprintf("height %d: %v", height, violation))
+		}
+		blockExec.logger.Error("proposal invariant violated",
+			"height", height, "invariant", violation.Invariant, "reason", violation.Reason)
+		blockExec.metrics.ProposalInvariantViolations.With("invariant", violation.Invariant).Add(1)
+	}
+}
+
+// recordProposal counts a proposed block, given its auction's candidates, in
+// the metrics.
+func (blockExec *BlockExecutor) recordProposal(candidates []types.AuctionBundle) {
+	blockExec.metrics.Proposals.Add(1)
+	for _, candidate := range candidates {
+		if candidate.Status == types.AuctionBundleIncluded {
+			atomic.AddInt64(&blockExec.numProposalsWithBundles, 1)
+			blockExec.metrics.ProposalsWithBundles.Add(1)
+			break
+		}
+	}
+	numProposals := atomic.AddInt64(&blockExec.numProposals, 1)
+	blockExec.metrics.ProposalsWithBundlesRatio.Set(
+		float64(atomic.LoadInt64(&blockExec.numProposalsWithBundles)) / float64(numProposals))
+}
+
+// recordAuction records the economics of auction, as bid in the bundles'
+// metadata, in the metrics.
+func (blockExec *BlockExecutor) recordAuction(auction types.EventDataAuctionFired) {
+	var winningBid, captured int64
+	if auction.Winner != nil {
+		winningBid = auction.Winner.Bid
+	}
+	for _, candidate := range auction.Candidates {
+		if candidate.Status == types.AuctionBundleIncluded {
+			captured += candidate.Bid
+		}
+		blockExec.metrics.AuctionBundles.With("status", candidate.Status).Add(1)
+	}
+	blockExec.metrics.AuctionWinningBid.Set(float64(winningBid))
+	blockExec.metrics.AuctionCandidates.Set(float64(len(auction.Candidates)))
+	blockExec.metrics.AuctionCapturedValue.Add(float64(captured))
+}
+
+// traceAuction adds an event for each of the auction's candidates to span,
+// with the same attributes as the mempool's bundle spans.
+func traceAuction(span trace.Span, height int64, candidates []types.AuctionBundle) {
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(attribute.Int("auction_candidates", len(candidates)))
+	for _, candidate := range candidates {
+		span.AddEvent("auction candidate", trace.WithAttributes(
+			attribute.Int64("bundle_height", height),
+			attribute.Int64("bundle_id", candidate.BundleId),
+			attribute.String("bundle_hash", candidate.Hash.String()),
+			attribute.String("status", candidate.Status),
+			attribute.Int64("bid", candidate.Bid),
+		))
+	}
+}
+
+// fireAuction records the outcome of the auction for height, given that only
+// the first numSidecarTxs txs of the selected bundles made it into the
+// proposal.
+func (blockExec *BlockExecutor) fireAuction(height int64, candidates []types.AuctionBundle, numSidecarTxs int) {
+	// no bundles were submitted for this height, so there was no auction
+	if len(candidates) == 0 {
+		return
+	}
+
+	auction := types.EventDataAuctionFired{
+		Height:     height,
+		Candidates: candidates,
+		DryRun:     blockExec.mevDryRun,
+	}
+
+	var reaped int64
+	for i := range auction.Candidates {
+		candidate := &auction.Candidates[i]
+		if candidate.Status != types.AuctionBundleIncluded {
+			continue
+		}
+		// bundles are reaped whole, so those past the sidecar txs that made it
+		// in were left out entirely
+		reaped += candidate.Size
+		if reaped > int64(numSidecarTxs) {
+			candidate.Status = types.AuctionBundleTruncated
+			continue
+		}
+		if auction.Winner == nil {
+			auction.Winner = candidate
+		}
+		auction.BytesUsed += candidate.Bytes
+	}
+
+	included := make([]int64, 0, len(auction.Candidates))
+	for _, candidate := range auction.Candidates {
+		if candidate.Status == types.AuctionBundleIncluded {
+			included = append(included, candidate.BundleId)
+		}
+		// same fields as the mempool's bundle logs
+		blockExec.logger.Debug(
+			"auction candidate",
+			"bundle_height", height,
+			"bundle_id", candidate.BundleId,
+			"bundle_hash", candidate.Hash,
+			"status", candidate.Status,
+			"bid", candidate.Bid,
+			"self_built", candidate.SelfBuilt,
+		)
+	}
+	winnerID, winnerBid := int64(-1), int64(0)
+	if auction.Winner != nil {
+		winnerID, winnerBid = auction.Winner.BundleId, auction.Winner.Bid
+	}
+	blockExec.recordAuction(auction)
+	blockExec.logger.Info(
+		"auction fired",
+		"height", height,
+		"num_candidates", len(auction.Candidates),
+		"included_bundles", included,
+		"winner_bundle_id", winnerID,
+		"winner_bid", winnerBid,
+		"bytes_used", auction.BytesUsed,
+		"dry_run", auction.DryRun,
+	)
+
+	if err := blockExec.eventBus.PublishEventAuctionFired(auction); err != nil {
+		blockExec.logger.Error("failed publishing auction fired event", "err", err)
+	}
 }
 
 // ValidateBlock validates the given block against the given state.
@@ -209,6 +521,10 @@ func (blockExec *BlockExecutor) ApplyBlock(
 	// NOTE: if we crash between Commit and Save, events wont be fired during replay
 	fireEvents(blockExec.logger, blockExec.eventBus, block, abciResponses, validatorUpdates)
 
+	if blockExec.receipts != nil {
+		blockExec.receipts.Commit(block.Height, block.Hash())
+	}
+
 	return state, retainHeight, nil
 }
 
@@ -260,6 +576,7 @@ func (blockExec *BlockExecutor) Commit(
 		block.Txs,
 		deliverTxResponses,
 	)
+	blockExec.reportAuctionHeightDrift(block.Height + 1)
 
 	// Update mempool.
 	err = blockExec.mempool.Update(
@@ -273,9 +590,53 @@ func (blockExec *BlockExecutor) Commit(
 	return res.Data, res.RetainHeight, err
 }
 
+// ResetSidecar drops everything in the sidecar and moves it to the height of
+// state. Bundles gathered before or while the node was catching up (state
+// sync, fast sync or WAL replay) may target heights it has since moved past.
+func (blockExec *BlockExecutor) ResetSidecar(state State) {
+	blockExec.sidecar.Lock()
+	defer blockExec.sidecar.Unlock()
+
+	blockExec.sidecar.Reset(state.LastBlockHeight)
+	blockExec.reportAuctionHeightDrift(state.LastBlockHeight + 1)
+
+	blockExec.logger.Info(
+		"reset sidecar",
+		"height", state.LastBlockHeight,
+		"auction_height", blockExec.sidecar.HeightForFiringAuction(),
+	)
+}
+
+// reportAuctionHeightDrift reports how far the sidecar's auction height is
+// from height, the one consensus moved to. Any drift means the sidecar
+// stopped following the chain, and bundles no longer make it into blocks.
+func (blockExec *BlockExecutor) reportAuctionHeightDrift(height int64) {
+	drift := blockExec.sidecar.HeightForFiringAuction() - height
+	blockExec.metrics.AuctionHeightDrift.Set(float64(drift))
+}
+
 //---------------------------------------------------------
 // Helper functions for executing blocks and updating state
 
+// locateSidecarTxs returns the index of the first of sidecarTxs in txs, and
+// how many of them follow from there, in order.
+func locateSidecarTxs(txs types.Txs, sidecarTxs []*mempl.MempoolTx) (int, int) {
+	if len(sidecarTxs) == 0 {
+		return 0, 0
+	}
+	for start := range txs {
+		if !bytes.Equal(txs[start], sidecarTxs[0].Tx()) {
+			continue
+		}
+		n := 0
+		for n < len(sidecarTxs) && start+n < len(txs) && bytes.Equal(txs[start+n], sidecarTxs[n].Tx()) {
+			n++
+		}
+		return start, n
+	}
+	return len(txs), 0
+}
+
 // Executes block's transactions on proxyAppConn.
 // Returns a list of transaction results and updates to the validator set
 func execBlockOnProxyApp(
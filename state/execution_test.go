@@ -1,20 +1,31 @@
 package state_test
 
 import (
+	"bytes"
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	cfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
 	"github.com/tendermint/tendermint/crypto/tmhash"
 	"github.com/tendermint/tendermint/libs/log"
+	tmrand "github.com/tendermint/tendermint/libs/rand"
+	mempl "github.com/tendermint/tendermint/mempool"
 	mmock "github.com/tendermint/tendermint/mempool/mock"
 	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	tmversion "github.com/tendermint/tendermint/proto/tendermint/version"
@@ -57,6 +68,45 @@ func TestApplyBlock(t *testing.T) {
 	assert.EqualValues(t, 1, state.Version.Consensus.App, "App version wasn't updated")
 }
 
+// stuckSidecar is a sidecar whose auction height never moves.
+type stuckSidecar struct {
+	mmock.PriorityTxSidecar
+	auctionHeight int64
+}
+
+func (sc stuckSidecar) HeightForFiringAuction() int64 { return sc.auctionHeight }
+
+func TestApplyBlockReportsAuctionHeightDrift(t *testing.T) {
+	app := &testApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+	state, stateDB, _ := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB)
+	metrics := sm.NopMetrics()
+	metrics.AuctionHeightDrift = generic.NewGauge("auction_height_drift")
+	drift := func() float64 { return metrics.AuctionHeightDrift.(*generic.Gauge).Value() }
+
+	// the sidecar follows the chain
+	sidecar := mempl.NewCListSidecar(state.LastBlockHeight)
+	blockExec := sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		mmock.Mempool{}, sm.EmptyEvidencePool{}, sidecar, sm.BlockExecutorWithMetrics(metrics))
+	block := makeBlock(state, 1)
+	blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: block.MakePartSet(testPartSize).Header()}
+	state, _, err = blockExec.ApplyBlock(state, blockID, block)
+	require.NoError(t, err)
+	assert.Zero(t, drift())
+
+	// it's stuck at the auction for height 1, consensus moved to height 2
+	blockExec = sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		mmock.Mempool{}, sm.EmptyEvidencePool{}, stuckSidecar{auctionHeight: 1}, sm.BlockExecutorWithMetrics(metrics))
+	blockExec.ResetSidecar(state)
+	assert.EqualValues(t, -1, drift())
+}
+
 // TestBeginBlockValidators ensures we send absent validators list.
 func TestBeginBlockValidators(t *testing.T) {
 	app := &testApp{}
@@ -466,3 +516,437 @@ func makeBlockID(hash []byte, partSetSize uint32, partSetHash []byte) types.Bloc
 		},
 	}
 }
+
+func TestCreateProposalBlockFiresAuction(t *testing.T) {
+	app := &testApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+	state, stateDB, _ := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB)
+	// leave room for a single one-tx bundle
+	state.ConsensusParams.Block.MaxBytes = types.MaxOverheadForBlock + types.MaxHeaderBytes + types.MaxCommitBytes(1) + 200
+
+	mempool := mempl.NewCListMempool(cfg.TestMempoolConfig(), proxyApp.Mempool(), state.LastBlockHeight)
+	sidecar := mempl.NewCListSidecar(state.LastBlockHeight)
+	auctionHeight := sidecar.HeightForFiringAuction()
+	for bundleID, bundleSize := range []int64{1, 2, 3} {
+		for order := int64(0); order < bundleSize; order++ {
+			// leave the last bundle incomplete
+			if bundleID == 2 && order == bundleSize-1 {
+				break
+			}
+			err := sidecar.AddTx(tmrand.Bytes(100), mempl.TxInfo{
+				DesiredHeight: auctionHeight,
+				BundleId:      int64(bundleID),
+				BundleOrder:   order,
+				BundleSize:    bundleSize,
+				Bid:           int64(10 * (bundleID + 1)),
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	eventBus := types.NewEventBus()
+	err = eventBus.Start()
+	require.NoError(t, err)
+	defer eventBus.Stop() //nolint:errcheck // ignore for tests
+
+	sub, err := eventBus.Subscribe(context.Background(), "TestCreateProposalBlockFiresAuction",
+		types.EventQueryAuctionFired, 1)
+	require.NoError(t, err)
+
+	blockExec := sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		mempool, sm.EmptyEvidencePool{}, sidecar, sm.BlockExecutorWithInvariantChecks(true))
+	blockExec.SetEventBus(eventBus)
+
+	proposerAddr, _ := state.Validators.GetByIndex(0)
+	commit := types.NewCommit(0, 0, types.BlockID{}, nil)
+	block, _ := blockExec.CreateProposalBlock(auctionHeight, state, commit, proposerAddr)
+	require.Len(t, block.Txs, 1)
+
+	select {
+	case msg := <-sub.Out():
+		auction, ok := msg.Data().(types.EventDataAuctionFired)
+		require.True(t, ok, "got %T, wanted EventDataAuctionFired", msg.Data())
+		assert.Equal(t, auctionHeight, auction.Height)
+		require.Len(t, auction.Candidates, 3)
+		assert.Equal(t, types.AuctionBundleIncluded, auction.Candidates[0].Status)
+		assert.Equal(t, types.AuctionBundleTruncated, auction.Candidates[1].Status)
+		assert.Equal(t, types.AuctionBundleIncomplete, auction.Candidates[2].Status)
+		require.NotNil(t, auction.Winner)
+		assert.EqualValues(t, 0, auction.Winner.BundleId)
+		assert.EqualValues(t, 10, auction.Winner.Bid)
+		assert.EqualValues(t, 100, auction.BytesUsed)
+		assert.Equal(t, block.Txs[:1].Hash(), auction.Winner.Hash.Bytes())
+		assert.Nil(t, auction.Candidates[2].Hash)
+	case <-time.After(time.Second):
+		t.Fatal("did not receive AuctionFired event")
+	}
+}
+
+func TestCreateProposalBlockInvariantChecks(t *testing.T) {
+	app := &testApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+	state, stateDB, _ := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB)
+	// leave room for only the first tx of a two-tx bundle, which is left out
+	// whole
+	state.ConsensusParams.Block.MaxBytes = types.MaxOverheadForBlock + types.MaxHeaderBytes + types.MaxCommitBytes(1) + 200
+
+	mempool := mempl.NewCListMempool(cfg.TestMempoolConfig(), proxyApp.Mempool(), state.LastBlockHeight)
+	sidecar := mempl.NewCListSidecar(state.LastBlockHeight)
+	auctionHeight := sidecar.HeightForFiringAuction()
+	for order := int64(0); order < 2; order++ {
+		err := sidecar.AddTx(tmrand.Bytes(100), mempl.TxInfo{
+			DesiredHeight: auctionHeight,
+			BundleId:      0,
+			BundleOrder:   order,
+			BundleSize:    2,
+		})
+		require.NoError(t, err)
+	}
+
+	proposerAddr, _ := state.Validators.GetByIndex(0)
+	commit := types.NewCommit(0, 0, types.BlockID{}, nil)
+
+	metrics := sm.PrometheusMetrics("TestCreateProposalBlockInvariantChecks")
+	blockExec := sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		mempool, sm.EmptyEvidencePool{}, sidecar,
+		sm.BlockExecutorWithMetrics(metrics), sm.BlockExecutorWithInvariantChecks(false))
+	block, _ := blockExec.CreateProposalBlock(auctionHeight, state, commit, proposerAddr)
+	assert.Empty(t, block.Txs)
+
+	blockExec = sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		mempool, sm.EmptyEvidencePool{}, sidecar, sm.BlockExecutorWithInvariantChecks(true))
+	assert.NotPanics(t, func() {
+		blockExec.CreateProposalBlock(auctionHeight, state, commit, proposerAddr)
+	})
+
+	// a split bundle would be reported
+	sidecarTxs, candidates := sidecar.ReapAuction()
+	violations := sm.CheckProposalInvariants(types.Txs{sidecarTxs[0].Tx()}, sidecarTxs, candidates, -1, -1)
+	require.Len(t, violations, 1)
+	assert.Equal(t, sm.InvariantBundleContiguity, violations[0].Invariant)
+}
+
+func TestCreateProposalBlockMetrics(t *testing.T) {
+	app := &testApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+	state, stateDB, _ := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB)
+	proposerAddr, _ := state.Validators.GetByIndex(0)
+	commit := types.NewCommit(0, 0, types.BlockID{}, nil)
+
+	mempool := mempl.NewCListMempool(cfg.TestMempoolConfig(), proxyApp.Mempool(), state.LastBlockHeight)
+	sidecar := mempl.NewCListSidecar(state.LastBlockHeight)
+	auctionHeight := sidecar.HeightForFiringAuction()
+	err = sidecar.AddTx(types.Tx("bundle"), mempl.TxInfo{DesiredHeight: auctionHeight, BundleSize: 1, Bid: 7})
+	require.NoError(t, err)
+
+	metrics := sm.NopMetrics()
+	metrics.Proposals = generic.NewCounter("proposals")
+	metrics.ProposalsWithBundles = generic.NewCounter("proposals_with_bundles")
+	metrics.ProposalsWithBundlesRatio = generic.NewGauge("proposals_with_bundles_ratio")
+	metrics.AuctionWinningBid = generic.NewGauge("auction_winning_bid")
+	metrics.AuctionCandidates = generic.NewGauge("auction_candidates")
+	metrics.AuctionCapturedValue = generic.NewCounter("auction_captured_value")
+	blockExec := sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		mempool, sm.EmptyEvidencePool{}, sidecar, sm.BlockExecutorWithMetrics(metrics))
+
+	// one proposal with the bundle, then one without
+	block, _ := blockExec.CreateProposalBlock(auctionHeight, state, commit, proposerAddr)
+	assert.Len(t, block.Txs, 1)
+	sidecar.Lock()
+	err = sidecar.Update(auctionHeight, block.Txs, []*abci.ResponseDeliverTx{{}})
+	sidecar.Unlock()
+	require.NoError(t, err)
+	block, _ = blockExec.CreateProposalBlock(auctionHeight+1, state, commit, proposerAddr)
+	assert.Empty(t, block.Txs)
+
+	assert.EqualValues(t, 2, metrics.Proposals.(*generic.Counter).Value())
+	assert.EqualValues(t, 1, metrics.ProposalsWithBundles.(*generic.Counter).Value())
+	assert.EqualValues(t, 0.5, metrics.ProposalsWithBundlesRatio.(*generic.Gauge).Value())
+	// only the first proposal ran an auction
+	assert.EqualValues(t, 7, metrics.AuctionWinningBid.(*generic.Gauge).Value())
+	assert.EqualValues(t, 1, metrics.AuctionCandidates.(*generic.Gauge).Value())
+	assert.EqualValues(t, 7, metrics.AuctionCapturedValue.(*generic.Counter).Value())
+}
+
+func TestCreateProposalBlockSpan(t *testing.T) {
+	app := &testApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+	state, stateDB, _ := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB)
+	proposerAddr, _ := state.Validators.GetByIndex(0)
+	commit := types.NewCommit(0, 0, types.BlockID{}, nil)
+
+	mempool := mempl.NewCListMempool(cfg.TestMempoolConfig(), proxyApp.Mempool(), state.LastBlockHeight)
+	sidecar := mempl.NewCListSidecar(state.LastBlockHeight)
+	auctionHeight := sidecar.HeightForFiringAuction()
+	err = sidecar.AddTx(types.Tx("bundle"), mempl.TxInfo{DesiredHeight: auctionHeight, BundleId: 3, BundleSize: 1})
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	blockExec := sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		mempool, sm.EmptyEvidencePool{}, sidecar, sm.BlockExecutorWithTracer(provider.Tracer("test")))
+	blockExec.CreateProposalBlock(auctionHeight, state, commit, proposerAddr)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "state.CreateProposalBlock", spans[0].Name())
+	events := spans[0].Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "auction candidate", events[0].Name)
+	assert.Contains(t, events[0].Attributes, attribute.Int64("bundle_id", 3))
+	assert.Contains(t, events[0].Attributes, attribute.String("status", types.AuctionBundleIncluded))
+}
+
+// simulateApp fails simulations of proposals leading with a "bad" tx
+type simulateApp struct {
+	testApp
+}
+
+func (app *simulateApp) Query(req abci.RequestQuery) abci.ResponseQuery {
+	var data tmproto.Data
+	if err := data.Unmarshal(req.Data); err != nil {
+		return abci.ResponseQuery{Code: 1, Log: err.Error()}
+	}
+	if len(data.Txs) > 0 && bytes.HasPrefix(data.Txs[0], []byte("bad")) {
+		return abci.ResponseQuery{Code: 2, Log: "bad bundle"}
+	}
+	return abci.ResponseQuery{}
+}
+
+func TestCreateProposalBlockProfiles(t *testing.T) {
+	app := &testApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+	state, stateDB, _ := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB)
+	proposerAddr, _ := state.Validators.GetByIndex(0)
+	commit := types.NewCommit(0, 0, types.BlockID{}, nil)
+	mempool := mempl.NewCListMempool(cfg.TestMempoolConfig(), proxyApp.Mempool(), state.LastBlockHeight)
+	sidecar := mempl.NewCListSidecar(state.LastBlockHeight)
+
+	// every proposal is slow
+	dir := t.TempDir()
+	profiler := sm.NewSlowProposalProfiler(dir, time.Nanosecond, 10*time.Millisecond, log.TestingLogger())
+	blockExec := sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		mempool, sm.EmptyEvidencePool{}, sidecar, sm.BlockExecutorWithSlowProposalProfiler(profiler))
+	blockExec.CreateProposalBlock(1, state, commit, proposerAddr)
+
+	assert.Eventually(t, func() bool {
+		heap, _ := filepath.Glob(filepath.Join(dir, "heap-1-*.pprof"))
+		cpu, _ := filepath.Glob(filepath.Join(dir, "cpu-1-*.pprof"))
+		if len(heap) != 1 || len(cpu) != 1 {
+			return false
+		}
+		info, err := os.Stat(cpu[0])
+		return err == nil && info.Size() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestCreateProposalBlockSimulation(t *testing.T) {
+	app := &simulateApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+	state, stateDB, _ := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB)
+	proposerAddr, _ := state.Validators.GetByIndex(0)
+	commit := types.NewCommit(0, 0, types.BlockID{}, nil)
+
+	mempool := mempl.NewCListMempool(cfg.TestMempoolConfig(), proxyApp.Mempool(), state.LastBlockHeight)
+	err = mempool.CheckTx(types.Tx("public"), nil, mempl.TxInfo{})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		bundleTx   types.Tx
+		wantTxs    types.Txs
+		wantStatus string
+	}{
+		{types.Tx("good"), types.Txs{types.Tx("good"), types.Tx("public")}, types.AuctionBundleIncluded},
+		{types.Tx("bad"), types.Txs{types.Tx("public")}, types.AuctionBundleRejected},
+	}
+	for _, tc := range testCases {
+		sidecar := mempl.NewCListSidecar(state.LastBlockHeight)
+		auctionHeight := sidecar.HeightForFiringAuction()
+		err := sidecar.AddTx(tc.bundleTx, mempl.TxInfo{DesiredHeight: auctionHeight, BundleSize: 1})
+		require.NoError(t, err)
+
+		auctionBundles := &labeledCounter{Counter: generic.NewCounter("auction_bundles")}
+		metrics := sm.NopMetrics()
+		metrics.AuctionBundles = auctionBundles
+		blockExec := sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+			mempool, sm.EmptyEvidencePool{}, sidecar,
+			sm.BlockExecutorWithMetrics(metrics),
+			sm.BlockExecutorWithInvariantChecks(true),
+			sm.BlockExecutorWithProposalSimulator(
+				sm.NewQueryProposalSimulator(proxyApp.Query(), "/mev/simulate_proposal")))
+		block, _ := blockExec.CreateProposalBlock(auctionHeight, state, commit, proposerAddr)
+		assert.Equal(t, tc.wantTxs, block.Txs, "bundle tx %q", tc.bundleTx)
+		assert.EqualValues(t, 1, auctionBundles.Value(), "bundle tx %q", tc.bundleTx)
+		assert.Equal(t, []string{"status", tc.wantStatus}, auctionBundles.labelValues, "bundle tx %q", tc.bundleTx)
+	}
+}
+
+// labeledCounter is a generic.Counter keeping the labels it's counted with.
+type labeledCounter struct {
+	*generic.Counter
+	labelValues []string
+}
+
+func (c *labeledCounter) With(labelValues ...string) metrics.Counter {
+	c.labelValues = append(c.labelValues, labelValues...)
+	return c
+}
+
+func TestCreateProposalBlockSignsReceipts(t *testing.T) {
+	app := &testApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+	state, stateDB, _ := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB)
+	proposerAddr, _ := state.Validators.GetByIndex(0)
+	commit := types.NewCommit(0, 0, types.BlockID{}, nil)
+
+	// a protected tx goes ahead of the bundle
+	mempool := mempl.NewCListMempool(cfg.TestMempoolConfig(), proxyApp.Mempool(), state.LastBlockHeight,
+		mempl.WithProtectedTxs([][]byte{[]byte("oracle")}, ""))
+	require.NoError(t, mempool.CheckTx(types.Tx("oracle vote"), nil, mempl.TxInfo{}))
+	sidecar := mempl.NewCListSidecar(state.LastBlockHeight)
+	auctionHeight := sidecar.HeightForFiringAuction()
+	for order := int64(0); order < 2; order++ {
+		err := sidecar.AddTx(tmrand.Bytes(10), mempl.TxInfo{
+			DesiredHeight: auctionHeight,
+			BundleOrder:   order,
+			BundleSize:    2,
+		})
+		require.NoError(t, err)
+	}
+
+	var pushed []types.BundleReceipt
+	receiptStore := sm.NewReceiptStore(10, func(receipts []types.BundleReceipt) { pushed = receipts })
+	privKey := ed25519.GenPrivKey()
+	blockExec := sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		mempool, sm.EmptyEvidencePool{}, sidecar, sm.BlockExecutorWithReceipts(privKey, receiptStore),
+		sm.BlockExecutorWithInvariantChecks(true))
+
+	// receipts of proposals that aren't committed are dropped
+	block, _ := blockExec.CreateProposalBlock(auctionHeight, state, commit, proposerAddr)
+	receiptStore.Commit(auctionHeight, []byte("other block"))
+	assert.Empty(t, receiptStore.Load(auctionHeight))
+	assert.Nil(t, pushed)
+
+	block, _ = blockExec.CreateProposalBlock(auctionHeight, state, commit, proposerAddr)
+	receiptStore.Commit(auctionHeight, block.Hash())
+	receipts := receiptStore.Load(auctionHeight)
+	require.Len(t, receipts, 1)
+	assert.Equal(t, receipts, pushed)
+
+	receipt := receipts[0]
+	assert.NoError(t, receipt.ValidateBasic())
+	assert.NoError(t, receipt.Verify(state.ChainID))
+	assert.Equal(t, block.Hash(), receipt.BlockHash)
+	assert.Equal(t, types.Tx("oracle vote"), block.Txs[0])
+	assert.EqualValues(t, 1, receipt.Position)
+	assert.EqualValues(t, 2, receipt.Size)
+	assert.Equal(t, block.Txs[1:3].Hash(), receipt.BundleHash.Bytes())
+
+	// only the last retained heights are kept
+	receiptStore.Commit(auctionHeight+10, []byte("later block"))
+	assert.Empty(t, receiptStore.Load(auctionHeight))
+}
+
+func TestCreateProposalBlockMEVDryRun(t *testing.T) {
+	app := &testApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+	state, stateDB, _ := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB)
+	proposerAddr, _ := state.Validators.GetByIndex(0)
+	commit := types.NewCommit(0, 0, types.BlockID{}, nil)
+
+	mempool := mempl.NewCListMempool(cfg.TestMempoolConfig(), proxyApp.Mempool(), state.LastBlockHeight)
+	require.NoError(t, mempool.CheckTx(types.Tx("public"), nil, mempl.TxInfo{}))
+	sidecar := mempl.NewCListSidecar(state.LastBlockHeight)
+	auctionHeight := sidecar.HeightForFiringAuction()
+	err = sidecar.AddTx(types.Tx("bundle"), mempl.TxInfo{DesiredHeight: auctionHeight, BundleSize: 1, Bid: 10})
+	require.NoError(t, err)
+
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	defer eventBus.Stop() //nolint:errcheck // ignore for tests
+	sub, err := eventBus.Subscribe(context.Background(), "TestCreateProposalBlockMEVDryRun",
+		types.EventQueryAuctionFired, 1)
+	require.NoError(t, err)
+
+	receiptStore := sm.NewReceiptStore(10, nil)
+	metrics := sm.NopMetrics()
+	metrics.Proposals = generic.NewCounter("proposals")
+	metrics.ProposalsWithBundles = generic.NewCounter("proposals_with_bundles")
+	blockExec := sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		mempool, sm.EmptyEvidencePool{}, sidecar, sm.BlockExecutorWithMEVDryRun(),
+		sm.BlockExecutorWithReceipts(ed25519.GenPrivKey(), receiptStore),
+		sm.BlockExecutorWithMetrics(metrics), sm.BlockExecutorWithInvariantChecks(true))
+	blockExec.SetEventBus(eventBus)
+
+	// the auction is run, and reported as such, but the proposal left alone,
+	// and checked and counted as the block without bundles it is
+	var block *types.Block
+	require.NotPanics(t, func() {
+		block, _ = blockExec.CreateProposalBlock(auctionHeight, state, commit, proposerAddr)
+	})
+	assert.Equal(t, types.Txs{types.Tx("public")}, block.Txs)
+	assert.EqualValues(t, 1, metrics.Proposals.(*generic.Counter).Value())
+	assert.Zero(t, metrics.ProposalsWithBundles.(*generic.Counter).Value())
+	select {
+	case msg := <-sub.Out():
+		auction := msg.Data().(types.EventDataAuctionFired)
+		assert.True(t, auction.DryRun)
+		require.NotNil(t, auction.Winner)
+		assert.EqualValues(t, 10, auction.Winner.Bid)
+		assert.Equal(t, types.AuctionBundleIncluded, auction.Winner.Status)
+	case <-time.After(time.Second):
+		t.Fatal("did not receive AuctionFired event")
+	}
+
+	// nor receipts signed for its bundles
+	receiptStore.Commit(auctionHeight, block.Hash())
+	assert.Empty(t, receiptStore.Load(auctionHeight))
+}
@@ -4,6 +4,7 @@ import (
 	dbm "github.com/tendermint/tm-db"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	mempl "github.com/tendermint/tendermint/mempool"
 	tmstate "github.com/tendermint/tendermint/proto/tendermint/state"
 	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/types"
@@ -46,3 +47,24 @@ func SaveValidatorsInfo(db dbm.DB, height, lastHeightChanged int64, valSet *type
 	stateStore := dbStore{db}
 	return stateStore.saveValidatorsInfo(height, lastHeightChanged, valSet)
 }
+
+// CheckProposalInvariants is an alias for checkProposalInvariants exported
+// from invariants.go, exclusively and explicitly for testing, with the
+// proposal's sidecar txs located in txs.
+func CheckProposalInvariants(
+	txs types.Txs,
+	sidecarTxs []*mempl.MempoolTx,
+	candidates []types.AuctionBundle,
+	maxDataBytes, maxGas int64,
+) []ErrProposalInvariant {
+	sidecarStart, numSidecarTxs := locateSidecarTxs(txs, sidecarTxs)
+	return checkProposalInvariants(proposalAssembly{
+		txs:           txs,
+		sidecarTxs:    sidecarTxs,
+		candidates:    candidates,
+		sidecarStart:  sidecarStart,
+		numSidecarTxs: numSidecarTxs,
+		maxDataBytes:  maxDataBytes,
+		maxGas:        maxGas,
+	})
+}
@@ -0,0 +1,135 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+
+	mempl "github.com/tendermint/tendermint/mempool"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Invariants checked on every assembled proposal when enabled, used as the
+// "invariant" label of the ProposalInvariantViolations metric.
+const (
+	InvariantBundleContiguity = "bundle_contiguity"
+	InvariantBundleOrdering   = "bundle_ordering"
+	InvariantTxDedup          = "tx_dedup"
+	InvariantBlockBudget      = "block_budget"
+)
+
+// ErrProposalInvariant is returned by checkProposalInvariants when a
+// proposal breaks one of the guarantees made to bundle submitters.
+type ErrProposalInvariant struct {
+	Invariant string
+	Reason    string
+}
+
+func (e ErrProposalInvariant) Error() string {
+	return fmt.Sprintf("proposal invariant %s violated: %s", e.Invariant, e.Reason)
+}
+
+// proposalAssembly is everything that went into assembling a proposal's txs.
+type proposalAssembly struct {
+	txs           types.Txs
+	sidecarTxs    []*mempl.MempoolTx
+	candidates    []types.AuctionBundle
+	sidecarStart  int // index of the first sidecar tx in txs, after protected txs
+	numSidecarTxs int
+	maxDataBytes  int64
+	maxGas        int64
+}
+
+// checkProposalInvariants verifies that the reaped bundles sit whole and in
+// bundle order at the top of the block, right after any protected txs, that
+// no tx appears twice, and that
+// the txs fit the block's byte and gas budget. It must run before the auction
+// is fired, since firing marks truncated bundles.
+func checkProposalInvariants(p proposalAssembly) []ErrProposalInvariant {
+	var violations []ErrProposalInvariant
+
+	// bundles are reaped in ascending bundleId order, and only whole bundles
+	// are handed to the mempool reap
+	var (
+		lastBundleID  = int64(-1)
+		bundleTxs     int64
+		boundaries    = map[int64]bool{0: true}
+		sizeMismatch  bool
+		orderMismatch bool
+	)
+	for _, candidate := range p.candidates {
+		if candidate.Status != types.AuctionBundleIncluded {
+			continue
+		}
+		if candidate.BundleId <= lastBundleID && !orderMismatch {
+			orderMismatch = true
+			violations = append(violations, ErrProposalInvariant{
+				Invariant: InvariantBundleOrdering,
+				Reason: fmt.Sprintf("bundle %d reaped after bundle %d",
+					candidate.BundleId, lastBundleID),
+			})
+		}
+		lastBundleID = candidate.BundleId
+		bundleTxs += candidate.Size
+		boundaries[bundleTxs] = true
+	}
+	if bundleTxs != int64(len(p.sidecarTxs)) {
+		sizeMismatch = true
+		violations = append(violations, ErrProposalInvariant{
+			Invariant: InvariantBundleOrdering,
+			Reason: fmt.Sprintf("reaped %d sidecar txs, but included bundles hold %d",
+				len(p.sidecarTxs), bundleTxs),
+		})
+	}
+
+	// the sidecar txs that made it in are contiguous, and no bundle is split
+	for i := 0; i < p.numSidecarTxs; i++ {
+		if !bytes.Equal(p.txs[p.sidecarStart+i], p.sidecarTxs[i].Tx()) {
+			violations = append(violations, ErrProposalInvariant{
+				Invariant: InvariantBundleContiguity,
+				Reason:    fmt.Sprintf("block tx %d is not sidecar tx %d", p.sidecarStart+i, i),
+			})
+			break
+		}
+	}
+	if !sizeMismatch && !boundaries[int64(p.numSidecarTxs)] {
+		violations = append(violations, ErrProposalInvariant{
+			Invariant: InvariantBundleContiguity,
+			Reason: fmt.Sprintf("only the first %d of %d sidecar txs fit, splitting a bundle",
+				p.numSidecarTxs, len(p.sidecarTxs)),
+		})
+	}
+
+	seen := make(map[string]int, len(p.txs))
+	for i, tx := range p.txs {
+		key := string(tx.Hash())
+		if j, ok := seen[key]; ok {
+			violations = append(violations, ErrProposalInvariant{
+				Invariant: InvariantTxDedup,
+				Reason:    fmt.Sprintf("txs %d and %d are both %X", j, i, tx.Hash()),
+			})
+			continue
+		}
+		seen[key] = i
+	}
+
+	if size := types.ComputeProtoSizeForTxs(p.txs); p.maxDataBytes > -1 && size > p.maxDataBytes {
+		violations = append(violations, ErrProposalInvariant{
+			Invariant: InvariantBlockBudget,
+			Reason:    fmt.Sprintf("txs take %d bytes, max is %d", size, p.maxDataBytes),
+		})
+	}
+	// only the gas of sidecar txs is known here; mempool txs are checked by
+	// the mempool reap itself
+	var sidecarGas int64
+	for _, memTx := range p.sidecarTxs[:p.numSidecarTxs] {
+		sidecarGas += memTx.GasWanted()
+	}
+	if p.maxGas > -1 && sidecarGas > p.maxGas {
+		violations = append(violations, ErrProposalInvariant{
+			Invariant: InvariantBlockBudget,
+			Reason:    fmt.Sprintf("sidecar txs want %d gas, max is %d", sidecarGas, p.maxGas),
+		})
+	}
+
+	return violations
+}
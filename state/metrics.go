@@ -17,13 +17,36 @@ const (
 type Metrics struct {
 	// Time between BeginBlock and EndBlock.
 	BlockProcessingTime metrics.Histogram
+	// Number of proposal invariant violations, labeled by invariant.
+	ProposalInvariantViolations metrics.Counter
+	// Number of blocks this node proposed.
+	Proposals metrics.Counter
+	// Number of blocks this node proposed containing a bundle.
+	ProposalsWithBundles metrics.Counter
+	// Fraction of the blocks this node proposed containing a bundle.
+	ProposalsWithBundlesRatio metrics.Gauge
+	// Bid of the winning bundle of the last auction this node ran, 0 if no
+	// bundle was included.
+	AuctionWinningBid metrics.Gauge
+	// Number of bundles competing in the last auction this node ran.
+	AuctionCandidates metrics.Gauge
+	// Sum of the bids of all bundles included in blocks this node proposed.
+	AuctionCapturedValue metrics.Counter
+	// Number of bundles competing in the auctions this node ran, by their
+	// status, eg. rejected when simulating the proposal failed.
+	AuctionBundles metrics.Counter
+	// Height of the sidecar's next auction minus the height consensus moved
+	// to, 0 while the sidecar keeps up with the chain.
+	AuctionHeightDrift metrics.Gauge
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
 // Optionally, labels can be provided along with their values ("foo",
 // "fooValue").
 func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
-	labels := []string{}
+	// exactly sized, so the labels appended to it for each metric don't
+	// share their backing array
+	labels := make([]string, 0, len(labelsAndValues)/2)
 	for i := 0; i < len(labelsAndValues); i += 2 {
 		labels = append(labels, labelsAndValues[i])
 	}
@@ -35,12 +58,75 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Help:      "Time between BeginBlock and EndBlock in ms.",
 			Buckets:   stdprometheus.LinearBuckets(1, 10, 10),
 		}, labels).With(labelsAndValues...),
+		ProposalInvariantViolations: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "proposal_invariant_violations",
+			Help:      "Number of proposal invariant violations, labeled by invariant.",
+		}, append(labels, "invariant")).With(labelsAndValues...),
+		Proposals: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "proposals",
+			Help:      "Number of blocks this node proposed.",
+		}, labels).With(labelsAndValues...),
+		ProposalsWithBundles: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "proposals_with_bundles",
+			Help:      "Number of blocks this node proposed containing a bundle.",
+		}, labels).With(labelsAndValues...),
+		ProposalsWithBundlesRatio: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "proposals_with_bundles_ratio",
+			Help:      "Fraction of the blocks this node proposed containing a bundle.",
+		}, labels).With(labelsAndValues...),
+		AuctionWinningBid: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "auction_winning_bid",
+			Help:      "Bid of the winning bundle of the last auction this node ran.",
+		}, labels).With(labelsAndValues...),
+		AuctionCandidates: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "auction_candidates",
+			Help:      "Number of bundles competing in the last auction this node ran.",
+		}, labels).With(labelsAndValues...),
+		AuctionCapturedValue: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "auction_captured_value",
+			Help:      "Sum of the bids of all bundles included in blocks this node proposed.",
+		}, labels).With(labelsAndValues...),
+		AuctionBundles: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "auction_bundles",
+			Help:      "Number of bundles competing in the auctions this node ran, by status.",
+		}, append(labels, "status")).With(labelsAndValues...),
+		AuctionHeightDrift: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "auction_height_drift",
+			Help:      "Height of the sidecar's next auction minus the height consensus moved to.",
+		}, labels).With(labelsAndValues...),
 	}
 }
 
 // NopMetrics returns no-op Metrics.
 func NopMetrics() *Metrics {
 	return &Metrics{
-		BlockProcessingTime: discard.NewHistogram(),
+		BlockProcessingTime:         discard.NewHistogram(),
+		ProposalInvariantViolations: discard.NewCounter(),
+		Proposals:                   discard.NewCounter(),
+		ProposalsWithBundles:        discard.NewCounter(),
+		ProposalsWithBundlesRatio:   discard.NewGauge(),
+		AuctionWinningBid:           discard.NewGauge(),
+		AuctionCandidates:           discard.NewGauge(),
+		AuctionCapturedValue:        discard.NewCounter(),
+		AuctionBundles:              discard.NewCounter(),
+		AuctionHeightDrift:          discard.NewGauge(),
 	}
 }
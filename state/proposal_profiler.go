@@ -0,0 +1,94 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sync/atomic"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// SlowProposalProfiler captures profiles of the node when reaping the txs
+// of a proposal and running its auction is slow, to diagnose latency spikes
+// at proposal time: a heap profile right away, and a CPU profile of what
+// follows, catching spikes that last. A single capture runs at a time.
+type SlowProposalProfiler struct {
+	dir         string
+	threshold   time.Duration
+	cpuDuration time.Duration
+	logger      log.Logger
+
+	// set while a capture is running
+	capturing int32
+}
+
+// NewSlowProposalProfiler returns a profiler writing to dir, when a proposal
+// takes longer than threshold, a heap profile and a CPU profile of the next
+// cpuDuration.
+func NewSlowProposalProfiler(
+	dir string,
+	threshold, cpuDuration time.Duration,
+	logger log.Logger,
+) *SlowProposalProfiler {
+	return &SlowProposalProfiler{
+		dir:         dir,
+		threshold:   threshold,
+		cpuDuration: cpuDuration,
+		logger:      logger,
+	}
+}
+
+// observe starts capturing profiles in the background if the proposal for
+// height took longer than the threshold, and no capture is running yet.
+func (p *SlowProposalProfiler) observe(height int64, took time.Duration) {
+	if took < p.threshold || !atomic.CompareAndSwapInt32(&p.capturing, 0, 1) {
+		return
+	}
+	p.logger.Info("slow proposal, capturing profiles",
+		"height", height, "took", took, "threshold", p.threshold, "dir", p.dir)
+	go func() {
+		defer atomic.StoreInt32(&p.capturing, 0)
+		if err := p.capture(height, time.Now()); err != nil {
+			p.logger.Error("failed capturing profiles of slow proposal", "height", height, "err", err)
+		}
+	}()
+}
+
+// capture writes the heap profile, then the CPU profile, named after height
+// and now.
+func (p *SlowProposalProfiler) capture(height int64, now time.Time) error {
+	if err := os.MkdirAll(p.dir, 0700); err != nil {
+		return err
+	}
+	suffix := fmt.Sprintf("%d-%s.pprof", height, now.UTC().Format("20060102T150405"))
+
+	heap, err := os.Create(filepath.Join(p.dir, "heap-"+suffix))
+	if err != nil {
+		return err
+	}
+	err = pprof.Lookup("heap").WriteTo(heap, 0)
+	if cerr := heap.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	cpuPath := filepath.Join(p.dir, "cpu-"+suffix)
+	cpu, err := os.Create(cpuPath)
+	if err != nil {
+		return err
+	}
+	defer cpu.Close() //nolint:errcheck // the profile is complete once stopped
+	// fails if a CPU profile is being taken already, eg. through pprof_laddr
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		os.Remove(cpuPath) //nolint:errcheck // the error starting the profile matters more
+		return err
+	}
+	time.Sleep(p.cpuDuration)
+	pprof.StopCPUProfile()
+	return nil
+}
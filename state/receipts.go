@@ -0,0 +1,74 @@
+package state
+
+import (
+	tmsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/types"
+)
+
+// ReceiptStore keeps the signed bundle receipts of the blocks this node
+// proposed, in memory, for the last retainHeights committed heights.
+//
+// Receipts are first kept as pending for the block they were made for, since
+// a proposal may never be committed. Only those of committed blocks are
+// retrievable.
+type ReceiptStore struct {
+	mtx tmsync.RWMutex
+
+	retainHeights int64
+	pending       map[string][]types.BundleReceipt // block hash -> receipts
+	committed     map[int64][]types.BundleReceipt  // height -> receipts
+	onCommit      func([]types.BundleReceipt)
+}
+
+// NewReceiptStore returns a ReceiptStore keeping receipts of the last
+// retainHeights heights. If set, onCommit is called with the receipts of
+// every committed block with any.
+func NewReceiptStore(retainHeights int64, onCommit func([]types.BundleReceipt)) *ReceiptStore {
+	return &ReceiptStore{
+		retainHeights: retainHeights,
+		pending:       make(map[string][]types.BundleReceipt),
+		committed:     make(map[int64][]types.BundleReceipt),
+		onCommit:      onCommit,
+	}
+}
+
+// AddPending keeps the receipts made for a proposed block until it's either
+// committed or another block is committed at its height.
+func (rs *ReceiptStore) AddPending(blockHash []byte, receipts []types.BundleReceipt) {
+	if len(receipts) == 0 {
+		return
+	}
+	rs.mtx.Lock()
+	defer rs.mtx.Unlock()
+	rs.pending[string(blockHash)] = receipts
+}
+
+// Commit makes the pending receipts of the block committed at height
+// retrievable, and drops all other pending receipts, which were made for
+// proposals at height or below.
+func (rs *ReceiptStore) Commit(height int64, blockHash []byte) {
+	rs.mtx.Lock()
+	receipts, ok := rs.pending[string(blockHash)]
+	rs.pending = make(map[string][]types.BundleReceipt)
+	if ok {
+		rs.committed[height] = receipts
+	}
+	for h := range rs.committed {
+		if h <= height-rs.retainHeights {
+			delete(rs.committed, h)
+		}
+	}
+	rs.mtx.Unlock()
+
+	if ok && rs.onCommit != nil {
+		rs.onCommit(receipts)
+	}
+}
+
+// Load returns the receipts of the block committed at height, if this node
+// proposed it.
+func (rs *ReceiptStore) Load(height int64) []types.BundleReceipt {
+	rs.mtx.RLock()
+	defer rs.mtx.RUnlock()
+	return rs.committed[height]
+}
@@ -1,6 +1,7 @@
 package state
 
 import (
+	mempl "github.com/tendermint/tendermint/mempool"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -59,3 +60,12 @@ func (EmptyEvidencePool) AddEvidence(types.Evidence) error                { retu
 func (EmptyEvidencePool) Update(State, types.EvidenceList)                {}
 func (EmptyEvidencePool) CheckEvidence(evList types.EvidenceList) error   { return nil }
 func (EmptyEvidencePool) ReportConflictingVotes(voteA, voteB *types.Vote) {}
+
+//-----------------------------------------------------------------------------
+// bundle builder
+
+// BundleBuilder builds a top-of-block bundle locally, for proposals without
+// usable bundles from a relay. See mempool.SelfBuilder.
+type BundleBuilder interface {
+	BuildBundle(maxBytes, maxGas int64) ([]*mempl.MempoolTx, []types.AuctionBundle)
+}
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
+)
+
+// ProposalSimulator checks an assembled proposal against the app before it's
+// broadcast.
+type ProposalSimulator interface {
+	// SimulateProposal returns an error if executing txs as the block at
+	// height would fail.
+	SimulateProposal(height int64, txs types.Txs) error
+}
+
+// QueryProposalSimulator simulates proposals through an ABCI query on a
+// dedicated path. The query data is the proposal's txs as a
+// tendermint.types.Data message, and the query height is the last committed
+// height. Apps supporting it execute the txs in order on a scratch copy of
+// that state, without committing anything, and return a non-zero code if the
+// block would be invalid.
+type QueryProposalSimulator struct {
+	proxyApp proxy.AppConnQuery
+	path     string
+}
+
+var _ ProposalSimulator = (*QueryProposalSimulator)(nil)
+
+// NewQueryProposalSimulator returns a QueryProposalSimulator querying path.
+func NewQueryProposalSimulator(proxyApp proxy.AppConnQuery, path string) *QueryProposalSimulator {
+	return &QueryProposalSimulator{
+		proxyApp: proxyApp,
+		path:     path,
+	}
+}
+
+// SimulateProposal implements ProposalSimulator.
+func (sim *QueryProposalSimulator) SimulateProposal(height int64, txs types.Txs) error {
+	data := tmproto.Data{Txs: make([][]byte, len(txs))}
+	for i, tx := range txs {
+		data.Txs[i] = tx
+	}
+	bz, err := data.Marshal()
+	if err != nil {
+		return err
+	}
+
+	res, err := sim.proxyApp.QuerySync(abci.RequestQuery{
+		Path:   sim.path,
+		Data:   bz,
+		Height: height - 1,
+	})
+	if err != nil {
+		return err
+	}
+	if res.IsErr() {
+		return fmt.Errorf("simulation of proposal at height %d failed with code %d: %s", height, res.Code, res.Log)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+)
+
+// BundleExtension is custom metadata a relay or a chain attaches to a bundle,
+// eg. a strategy tag or a refund address, under a key of its choice. Nodes
+// don't interpret extensions, only pass them on.
+type BundleExtension struct {
+	Key   string           `json:"key"`
+	Value tmbytes.HexBytes `json:"value"`
+}
+
+// ValidateBundleExtensions returns an error if an extension has no key, or if
+// two share a key.
+func ValidateBundleExtensions(exts []BundleExtension) error {
+	keys := make(map[string]struct{}, len(exts))
+	for _, ext := range exts {
+		if ext.Key == "" {
+			return errors.New("bundle extension without a key")
+		}
+		if _, ok := keys[ext.Key]; ok {
+			return fmt.Errorf("duplicate bundle extension %q", ext.Key)
+		}
+		keys[ext.Key] = struct{}{}
+	}
+	return nil
+}
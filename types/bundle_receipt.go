@@ -0,0 +1,181 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/tendermint/tendermint/crypto"
+	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/libs/protoio"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+)
+
+var (
+	ErrBundleReceiptInvalidSignature = errors.New("invalid bundle receipt signature")
+)
+
+// BundleReceipt is signed by a proposer for each bundle it included in a
+// block, so whoever submitted the bundle can prove it was included: once the
+// block at Height is committed with hash BlockHash, its txs from Position on
+// hash to BundleHash.
+type BundleReceipt struct {
+	Height     int64            `json:"height"`
+	BlockHash  tmbytes.HexBytes `json:"block_hash"`
+	BundleID   int64            `json:"bundle_id"`
+	BundleHash tmbytes.HexBytes `json:"bundle_hash"`
+	Position   int64            `json:"position"` // index of the bundle's first tx in the block
+	Size       int64            `json:"size"`     // number of txs in the bundle
+	PubKey     crypto.PubKey    `json:"pub_key"`
+	Signature  []byte           `json:"signature"`
+}
+
+// NewBundleReceipts returns unsigned receipts for the bundles of an auction
+// that made it into block, in block order, the first bundle starting at tx
+// index position. Bundles that aren't found where expected are skipped.
+func NewBundleReceipts(block *Block, candidates []AuctionBundle, position int64) []BundleReceipt {
+	receipts := make([]BundleReceipt, 0)
+	blockHash := block.Hash()
+
+	for _, candidate := range candidates {
+		if candidate.Status != AuctionBundleIncluded {
+			continue
+		}
+		end := position + candidate.Size
+		if end > int64(len(block.Txs)) ||
+			!bytes.Equal(block.Txs[position:end].Hash(), candidate.Hash) {
+			break
+		}
+		receipts = append(receipts, BundleReceipt{
+			Height:     block.Height,
+			BlockHash:  blockHash,
+			BundleID:   candidate.BundleId,
+			BundleHash: candidate.Hash,
+			Position:   position,
+			Size:       candidate.Size,
+		})
+		position = end
+	}
+	return receipts
+}
+
+// BundleReceiptSignBytes returns the proto-encoding of the canonicalized
+// receipt, for signing. Panics if the marshaling fails.
+func BundleReceiptSignBytes(chainID string, receipt *BundleReceipt) []byte {
+	pb := tmproto.CanonicalBundleReceipt{
+		Height:     receipt.Height,
+		BlockHash:  receipt.BlockHash,
+		BundleId:   receipt.BundleID,
+		BundleHash: receipt.BundleHash,
+		Position:   receipt.Position,
+		Size_:      receipt.Size,
+		ChainID:    chainID,
+	}
+	bz, err := protoio.MarshalDelimited(&pb)
+	if err != nil {
+		panic(err)
+	}
+
+	return bz
+}
+
+// Sign signs the receipt with privKey.
+func (receipt *BundleReceipt) Sign(chainID string, privKey crypto.PrivKey) error {
+	sig, err := privKey.Sign(BundleReceiptSignBytes(chainID, receipt))
+	if err != nil {
+		return err
+	}
+	receipt.PubKey = privKey.PubKey()
+	receipt.Signature = sig
+	return nil
+}
+
+// Verify checks the receipt was signed by its PubKey.
+func (receipt *BundleReceipt) Verify(chainID string) error {
+	if !receipt.PubKey.VerifySignature(BundleReceiptSignBytes(chainID, receipt), receipt.Signature) {
+		return ErrBundleReceiptInvalidSignature
+	}
+	return nil
+}
+
+// ValidateBasic performs basic validation.
+func (receipt *BundleReceipt) ValidateBasic() error {
+	if receipt.Height <= 0 {
+		return errors.New("non positive Height")
+	}
+	if len(receipt.BlockHash) != tmhash.Size {
+		return fmt.Errorf("expected BlockHash size to be %d bytes, got %d bytes",
+			tmhash.Size, len(receipt.BlockHash))
+	}
+	if receipt.BundleID < 0 {
+		return errors.New("negative BundleID")
+	}
+	if len(receipt.BundleHash) != tmhash.Size {
+		return fmt.Errorf("expected BundleHash size to be %d bytes, got %d bytes",
+			tmhash.Size, len(receipt.BundleHash))
+	}
+	if receipt.Position < 0 {
+		return errors.New("negative Position")
+	}
+	if receipt.Size <= 0 {
+		return errors.New("non positive Size")
+	}
+	if receipt.PubKey == nil {
+		return errors.New("missing PubKey")
+	}
+	if len(receipt.Signature) == 0 {
+		return errors.New("signature is missing")
+	}
+	if len(receipt.Signature) > MaxSignatureSize {
+		return fmt.Errorf("signature is too big (max: %d)", MaxSignatureSize)
+	}
+	return nil
+}
+
+// ToProto converts BundleReceipt to protobuf
+func (receipt *BundleReceipt) ToProto() (*tmproto.BundleReceipt, error) {
+	if receipt == nil {
+		return nil, errors.New("nil BundleReceipt")
+	}
+	pk, err := cryptoenc.PubKeyToProto(receipt.PubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmproto.BundleReceipt{
+		Height:     receipt.Height,
+		BlockHash:  receipt.BlockHash,
+		BundleId:   receipt.BundleID,
+		BundleHash: receipt.BundleHash,
+		Position:   receipt.Position,
+		Size_:      receipt.Size,
+		PubKey:     pk,
+		Signature:  receipt.Signature,
+	}, nil
+}
+
+// BundleReceiptFromProto converts a protobuf BundleReceipt to BundleReceipt and
+// validates it.
+func BundleReceiptFromProto(pb *tmproto.BundleReceipt) (*BundleReceipt, error) {
+	if pb == nil {
+		return nil, errors.New("nil BundleReceipt")
+	}
+	pk, err := cryptoenc.PubKeyFromProto(pb.PubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &BundleReceipt{
+		Height:     pb.Height,
+		BlockHash:  pb.BlockHash,
+		BundleID:   pb.BundleId,
+		BundleHash: pb.BundleHash,
+		Position:   pb.Position,
+		Size:       pb.Size_,
+		PubKey:     pk,
+		Signature:  pb.Signature,
+	}
+	return receipt, receipt.ValidateBasic()
+}
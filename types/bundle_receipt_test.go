@@ -0,0 +1,108 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+func makeReceiptBlock(txs []Tx) *Block {
+	block := MakeBlock(3, txs, randCommit(time.Now()), nil)
+	block.ValidatorsHash = tmhash.Sum([]byte("validators_hash"))
+	return block
+}
+
+func TestNewBundleReceipts(t *testing.T) {
+	txs := []Tx{Tx("a0"), Tx("b0"), Tx("b1"), Tx("c0"), Tx("public")}
+	block := makeReceiptBlock(txs)
+
+	candidates := []AuctionBundle{
+		{BundleId: 0, Size: 1, Status: AuctionBundleIncluded, Hash: Txs{Tx("a0")}.Hash()},
+		{BundleId: 1, Size: 3, Status: AuctionBundleIncomplete},
+		{BundleId: 2, Size: 2, Status: AuctionBundleIncluded, Hash: Txs{Tx("b0"), Tx("b1")}.Hash()},
+		{BundleId: 3, Size: 1, Status: AuctionBundleTruncated, Hash: Txs{Tx("d0")}.Hash()},
+		// doesn't match the block
+		{BundleId: 4, Size: 1, Status: AuctionBundleIncluded, Hash: Txs{Tx("e0")}.Hash()},
+	}
+	receipts := NewBundleReceipts(block, candidates, 0)
+	require.Len(t, receipts, 2)
+
+	assert.EqualValues(t, 0, receipts[0].BundleID)
+	assert.EqualValues(t, 0, receipts[0].Position)
+	assert.EqualValues(t, 2, receipts[1].BundleID)
+	assert.EqualValues(t, 1, receipts[1].Position)
+	assert.EqualValues(t, 2, receipts[1].Size)
+	for _, receipt := range receipts {
+		assert.EqualValues(t, 3, receipt.Height)
+		assert.Equal(t, block.Hash(), receipt.BlockHash)
+	}
+
+	// bundles follow a protected tx
+	block = makeReceiptBlock(append([]Tx{Tx("oracle")}, txs...))
+	receipts = NewBundleReceipts(block, candidates, 1)
+	require.Len(t, receipts, 2)
+	assert.EqualValues(t, 1, receipts[0].Position)
+	assert.EqualValues(t, 2, receipts[1].Position)
+}
+
+func TestBundleReceiptSignVerify(t *testing.T) {
+	block := makeReceiptBlock([]Tx{Tx("a0")})
+	receipts := NewBundleReceipts(block, []AuctionBundle{
+		{BundleId: 0, Size: 1, Status: AuctionBundleIncluded, Hash: Txs{Tx("a0")}.Hash()},
+	}, 0)
+	require.Len(t, receipts, 1)
+	receipt := receipts[0]
+
+	privKey := ed25519.GenPrivKey()
+	require.NoError(t, receipt.Sign("test_chain_id", privKey))
+	assert.Equal(t, privKey.PubKey(), receipt.PubKey)
+	require.NoError(t, receipt.ValidateBasic())
+	assert.NoError(t, receipt.Verify("test_chain_id"))
+	assert.Equal(t, ErrBundleReceiptInvalidSignature, receipt.Verify("other_chain_id"))
+
+	pb, err := receipt.ToProto()
+	require.NoError(t, err)
+	fromProto, err := BundleReceiptFromProto(pb)
+	require.NoError(t, err)
+	assert.Equal(t, receipt, *fromProto)
+
+	tampered := receipt
+	tampered.Position = 1
+	assert.Equal(t, ErrBundleReceiptInvalidSignature, tampered.Verify("test_chain_id"))
+}
+
+func TestBundleReceiptValidateBasic(t *testing.T) {
+	block := makeReceiptBlock([]Tx{Tx("a0")})
+	receipt := NewBundleReceipts(block, []AuctionBundle{
+		{BundleId: 0, Size: 1, Status: AuctionBundleIncluded, Hash: Txs{Tx("a0")}.Hash()},
+	}, 0)[0]
+	require.NoError(t, receipt.Sign("test_chain_id", ed25519.GenPrivKey()))
+
+	testCases := []struct {
+		testName string
+		malleate func(*BundleReceipt)
+	}{
+		{"Zero Height", func(r *BundleReceipt) { r.Height = 0 }},
+		{"Short BlockHash", func(r *BundleReceipt) { r.BlockHash = []byte{1} }},
+		{"Negative BundleID", func(r *BundleReceipt) { r.BundleID = -1 }},
+		{"Missing BundleHash", func(r *BundleReceipt) { r.BundleHash = nil }},
+		{"Negative Position", func(r *BundleReceipt) { r.Position = -1 }},
+		{"Zero Size", func(r *BundleReceipt) { r.Size = 0 }},
+		{"Missing PubKey", func(r *BundleReceipt) { r.PubKey = nil }},
+		{"Missing Signature", func(r *BundleReceipt) { r.Signature = nil }},
+		{"Too big Signature", func(r *BundleReceipt) { r.Signature = make([]byte, MaxSignatureSize+1) }},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			r := receipt
+			tc.malleate(&r)
+			assert.Error(t, r.ValidateBasic())
+		})
+	}
+}
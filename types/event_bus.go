@@ -227,6 +227,39 @@ func (b *EventBus) PublishEventValidatorSetUpdates(data EventDataValidatorSetUpd
 	return b.Publish(EventValidatorSetUpdates, data)
 }
 
+func (b *EventBus) PublishEventAuctionFired(data EventDataAuctionFired) error {
+	return b.Publish(EventAuctionFired, data)
+}
+
+// PublishEventSidecarTxAdded publishes a bundle tx added to the sidecar. Along
+// with the event type, it's queryable by the bundle's height and id.
+func (b *EventBus) PublishEventSidecarTxAdded(data EventDataSidecarTx) error {
+	return b.publishSidecarTx(EventSidecarTxAdded, data)
+}
+
+// PublishEventSidecarTxRemoved publishes a bundle tx removed from the
+// sidecar. Along with the event type, it's queryable by the bundle's height
+// and id, and by the reason the tx was removed.
+func (b *EventBus) PublishEventSidecarTxRemoved(data EventDataSidecarTx) error {
+	return b.publishSidecarTx(EventSidecarTxRemoved, data)
+}
+
+func (b *EventBus) publishSidecarTx(eventType string, data EventDataSidecarTx) error {
+	// no explicit deadline for publishing events
+	ctx := context.Background()
+
+	events := map[string][]string{
+		EventTypeKey:       {eventType},
+		TxHashKey:          {fmt.Sprintf("%X", data.Tx.Hash())},
+		SidecarHeightKey:   {fmt.Sprintf("%d", data.Height)},
+		SidecarBundleIDKey: {fmt.Sprintf("%d", data.BundleID)},
+	}
+	if data.Reason != "" {
+		events[SidecarReasonKey] = []string{data.Reason}
+	}
+	return b.pubsub.PublishWithEvents(ctx, data, events)
+}
+
 //-----------------------------------------------------------------------------
 type NopEventBus struct{}
 
@@ -306,3 +339,15 @@ func (NopEventBus) PublishEventLock(data EventDataRoundState) error {
 func (NopEventBus) PublishEventValidatorSetUpdates(data EventDataValidatorSetUpdates) error {
 	return nil
 }
+
+func (NopEventBus) PublishEventAuctionFired(data EventDataAuctionFired) error {
+	return nil
+}
+
+func (NopEventBus) PublishEventSidecarTxAdded(data EventDataSidecarTx) error {
+	return nil
+}
+
+func (NopEventBus) PublishEventSidecarTxRemoved(data EventDataSidecarTx) error {
+	return nil
+}
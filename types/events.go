@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 	tmjson "github.com/tendermint/tendermint/libs/json"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
@@ -36,6 +37,15 @@ const (
 	EventUnlock           = "Unlock"
 	EventValidBlock       = "ValidBlock"
 	EventVote             = "Vote"
+
+	// Internal mev-tendermint events.
+	// These are fired by the proposer and are useful to reconstruct
+	// auction decisions.
+	EventAuctionFired = "AuctionFired"
+	// These are fired for every bundle tx added to or removed from the
+	// sidecar.
+	EventSidecarTxAdded   = "SidecarTxAdded"
+	EventSidecarTxRemoved = "SidecarTxRemoved"
 )
 
 // ENCODING / DECODING
@@ -56,6 +66,8 @@ func init() {
 	tmjson.RegisterType(EventDataVote{}, "tendermint/event/Vote")
 	tmjson.RegisterType(EventDataValidatorSetUpdates{}, "tendermint/event/ValidatorSetUpdates")
 	tmjson.RegisterType(EventDataString(""), "tendermint/event/ProposalString")
+	tmjson.RegisterType(EventDataAuctionFired{}, "tendermint/event/AuctionFired")
+	tmjson.RegisterType(EventDataSidecarTx{}, "tendermint/event/SidecarTx")
 }
 
 // Most event messages are basic types (a block, a transaction)
@@ -125,6 +137,73 @@ type EventDataValidatorSetUpdates struct {
 	ValidatorUpdates []*Validator `json:"validator_updates"`
 }
 
+// Statuses of a bundle considered by an auction.
+const (
+	// AuctionBundleIncluded means the bundle was placed in the proposal.
+	AuctionBundleIncluded = "included"
+	// AuctionBundleIncomplete means not all of the bundle's txs were received.
+	AuctionBundleIncomplete = "incomplete"
+	// AuctionBundleLate means the bundle arrived after the auction cutoff.
+	AuctionBundleLate = "late"
+	// AuctionBundleTruncated means the bundle did not fit whole in the block,
+	// so none of its txs were included.
+	AuctionBundleTruncated = "truncated"
+	// AuctionBundleRejected means simulating the proposal with the bundle
+	// failed, so the proposal was made without bundles.
+	AuctionBundleRejected = "rejected"
+	// AuctionBundleVetoed means the app refused the bundle when it was
+	// completed, so it never entered the auction.
+	AuctionBundleVetoed = "vetoed"
+)
+
+// AuctionBundle describes a sidecar bundle considered by an auction.
+type AuctionBundle struct {
+	BundleId int64  `json:"bundle_id"`
+	Size     int64  `json:"size"`  // number of txs
+	Bytes    int64  `json:"bytes"` // total size of the bundle's txs
+	Bid      int64  `json:"bid"`
+	Status   string `json:"status"`
+	// Hash commits to the bundle's txs in order (the merkle root of the txs,
+	// as in Txs.Hash). Only set for bundles that were fully reaped.
+	Hash tmbytes.HexBytes `json:"hash,omitempty"`
+	// SelfBuilt is set for bundles the proposer built from its own mempool
+	SelfBuilt bool `json:"self_built,omitempty"`
+	// Extensions are those of the envelope the bundle was received in, if any
+	Extensions []BundleExtension `json:"extensions,omitempty"`
+}
+
+// EventDataAuctionFired is fired every time this node runs an auction as the
+// proposer.
+type EventDataAuctionFired struct {
+	Height int64 `json:"height"`
+
+	// all bundles for this height, in auction order
+	Candidates []AuctionBundle `json:"candidates"`
+	// the highest ranked included bundle, nil if no bundle was included
+	Winner *AuctionBundle `json:"winner"`
+	// total size of all included bundles
+	BytesUsed int64 `json:"bytes_used"`
+	// whether the auction was a dry run, its bundles left out of the
+	// proposal regardless of their status
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// EventDataSidecarTx is fired every time a bundle tx is added to or removed
+// from the sidecar.
+type EventDataSidecarTx struct {
+	Tx Tx `json:"tx"`
+
+	// the bundle the tx is part of
+	Height      int64 `json:"height"`
+	BundleID    int64 `json:"bundle_id"`
+	BundleOrder int64 `json:"bundle_order"`
+	BundleSize  int64 `json:"bundle_size"`
+	// where the tx came from, eg. "sidecar_peer"
+	Origin string `json:"origin"`
+	// why the tx was removed, eg. "committed" or "vetoed", empty when added
+	Reason string `json:"reason,omitempty"`
+}
+
 // PUBSUB
 
 const (
@@ -140,9 +219,20 @@ const (
 	// BlockHeightKey is a reserved key used for indexing BeginBlock and Endblock
 	// events.
 	BlockHeightKey = "block.height"
+
+	// SidecarHeightKey is a reserved key, used to specify the height of the
+	// bundle of a sidecar tx. see EventBus#PublishEventSidecarTxAdded
+	SidecarHeightKey = "sidecar.height"
+	// SidecarBundleIDKey is a reserved key, used to specify the id of the
+	// bundle of a sidecar tx. see EventBus#PublishEventSidecarTxAdded
+	SidecarBundleIDKey = "sidecar.bundle_id"
+	// SidecarReasonKey is a reserved key, used to specify why a tx was removed
+	// from the sidecar. see EventBus#PublishEventSidecarTxRemoved
+	SidecarReasonKey = "sidecar.reason"
 )
 
 var (
+	EventQueryAuctionFired        = QueryForEvent(EventAuctionFired)
 	EventQueryCompleteProposal    = QueryForEvent(EventCompleteProposal)
 	EventQueryLock                = QueryForEvent(EventLock)
 	EventQueryNewBlock            = QueryForEvent(EventNewBlock)
@@ -152,6 +242,8 @@ var (
 	EventQueryNewRoundStep        = QueryForEvent(EventNewRoundStep)
 	EventQueryPolka               = QueryForEvent(EventPolka)
 	EventQueryRelock              = QueryForEvent(EventRelock)
+	EventQuerySidecarTxAdded      = QueryForEvent(EventSidecarTxAdded)
+	EventQuerySidecarTxRemoved    = QueryForEvent(EventSidecarTxRemoved)
 	EventQueryTimeoutPropose      = QueryForEvent(EventTimeoutPropose)
 	EventQueryTimeoutWait         = QueryForEvent(EventTimeoutWait)
 	EventQueryTx                  = QueryForEvent(EventTx)
@@ -176,6 +268,14 @@ type BlockEventPublisher interface {
 	PublishEventNewEvidence(evidence EventDataNewEvidence) error
 	PublishEventTx(EventDataTx) error
 	PublishEventValidatorSetUpdates(EventDataValidatorSetUpdates) error
+	PublishEventAuctionFired(EventDataAuctionFired) error
+}
+
+// SidecarEventPublisher publishes the events of bundle txs added to and
+// removed from the sidecar.
+type SidecarEventPublisher interface {
+	PublishEventSidecarTxAdded(EventDataSidecarTx) error
+	PublishEventSidecarTxRemoved(EventDataSidecarTx) error
 }
 
 type TxEventPublisher interface {
@@ -22,4 +22,8 @@ var (
 	// BlockProtocol versions all block data structures and processing.
 	// This includes validity of blocks and state updates.
 	BlockProtocol uint64 = 11
+
+	// SidecarProtocol versions the MEV sidecar's msgs, exchanged with
+	// sidecar peers and the relay.
+	SidecarProtocol uint64 = 1
 )